@@ -13,9 +13,14 @@ import (
 	"github.com/fraud-detection-system/backend/internal/cache"
 	"github.com/fraud-detection-system/backend/internal/config"
 	"github.com/fraud-detection-system/backend/internal/database"
+	"github.com/fraud-detection-system/backend/internal/health"
+	"github.com/fraud-detection-system/backend/internal/encryption"
+	"github.com/fraud-detection-system/backend/internal/events"
+	"github.com/fraud-detection-system/backend/internal/metrics"
 	"github.com/fraud-detection-system/backend/internal/queue"
 	"github.com/fraud-detection-system/backend/internal/repository"
 	"github.com/fraud-detection-system/backend/internal/service"
+	"github.com/fraud-detection-system/backend/internal/tracing"
 	"github.com/fraud-detection-system/backend/internal/utils"
 )
 
@@ -29,9 +34,17 @@ func main() {
 
 	// Initialize logger
 	utils.InitLogger(cfg.App.LogLevel)
+	utils.SetRedactor(utils.NewRedactor(cfg.Logging.RedactionMode, cfg.Logging.SafeFields))
 	logger := utils.GetLogger()
 	logger.Info("Starting API Gateway...")
 
+	// Initialize tracing
+	shutdownTracing, err := tracing.Init(context.Background(), cfg, "api-gateway")
+	if err != nil {
+		logger.WithError(err).Fatal("Failed to initialize tracing")
+	}
+	defer shutdownTracing(context.Background())
+
 	// Initialize database
 	db, err := database.NewDatabase(cfg)
 	if err != nil {
@@ -40,6 +53,18 @@ func main() {
 	defer db.Close()
 	logger.Info("Database connected")
 
+	// "<binary> migrate ..." manages the schema and exits
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if err := database.RunMigrateCommand(context.Background(), db.DB, os.Args[2:], os.Stdout); err != nil {
+			logger.WithError(err).Fatal("Migration command failed")
+		}
+		return
+	}
+	if err := database.EnsureMigrated(context.Background(), db.DB, cfg.Database.AutoMigrate); err != nil {
+		logger.WithError(err).Fatal("Database schema is not up to date")
+	}
+	db.RegisterMetrics()
+
 	// Initialize cache
 	redisCache, err := cache.NewRedisCache(cfg)
 	if err != nil {
@@ -53,32 +78,78 @@ func main() {
 	defer producer.Close()
 	logger.Info("Kafka producer initialized")
 
+	// Initialize encryption
+	keyProvider, err := encryption.NewLocalKeyProvider(cfg.Encryption)
+	if err != nil {
+		logger.WithError(err).Fatal("Failed to load encryption keys")
+	}
+	fieldCipher := encryption.NewFieldCipher(keyProvider)
+
 	// Initialize repositories
-	userRepo := repository.NewUserRepository(db.DB)
-	messageRepo := repository.NewMessageRepository(db.DB)
-	verificationRepo := repository.NewVerificationRepository(db.DB)
-	reportRepo := repository.NewReportRepository(db.DB)
+	userRepo := repository.NewUserRepository(db.DB, fieldCipher)
+	passwordResetRepo := repository.NewPasswordResetRepository(db.DB)
+	recoveryCodeRepo := repository.NewRecoveryCodeRepository(db.DB)
+	messageRepo := repository.NewMessageRepository(db.DB, fieldCipher)
+	verificationRepo := repository.NewVerificationRepository(db.DB).WithReader(db.Reader())
+	outboxRepo := repository.NewOutboxRepository(db.DB)
+	reportRepo := repository.NewReportRepository(db.DB, fieldCipher).WithReader(db.Reader())
 	rbiRepo := repository.NewRBIRepository(db.DB)
+	analyticsRepo := repository.NewAnalyticsRepository(db.DB).WithReader(db.Reader())
+	uow := repository.NewUnitOfWork(db.DB, userRepo, messageRepo, verificationRepo, reportRepo, rbiRepo, outboxRepo, analyticsRepo)
 
 	// Initialize services
-	authService := service.NewAuthService(userRepo, cfg)
-	mlClient := service.NewMLClient(cfg)
-	rbiService := service.NewRBIComplianceService(rbiRepo)
-	headerService := service.NewHeaderVerificationService(rbiRepo)
-	verificationService := service.NewVerificationService(
+	authService := service.NewAuthService(userRepo, passwordResetRepo, recoveryCodeRepo, redisCache, cfg)
+	mlClient, err := service.NewMLClient(cfg)
+	if err != nil {
+		logger.WithError(err).Fatal("Failed to initialize ML client")
+	}
+	defer mlClient.Close()
+	verdictCache := service.NewVerdictCache(redisCache, cfg)
+	rbiService := service.NewRBIComplianceService(rbiRepo, uow, verdictCache, cfg)
+	headerService := service.NewHeaderVerificationService(rbiRepo, uow, verdictCache, cfg)
+	accountService := service.NewAccountService(
+		uow,
+		userRepo,
+		authService,
 		messageRepo,
 		verificationRepo,
+		reportRepo,
+		redisCache,
+		cfg,
+	)
+	verificationService := service.NewVerificationService(
+		verificationRepo,
+		uow,
 		mlClient,
 		rbiService,
 		headerService,
+		verdictCache,
 		redisCache,
+		cfg,
 	)
+	reportService := service.NewReportService(uow, cfg)
+	searchService := service.NewSearchService(messageRepo)
+	analyticsService := service.NewAnalyticsService(uow, analyticsRepo)
 
 	// Initialize handlers
-	healthHandler := handlers.NewHealthHandler(db, redisCache, mlClient)
+	checker := health.NewChecker("api-gateway", cfg.Health.CheckTimeout, cfg.Health.CacheTTL, cfg.Health.Criticality)
+	checker.Register("database", true, db.HealthCheck)
+	checker.Register("redis", true, redisCache.HealthCheck)
+	// With the fallback scorer verification keeps working without the ML
+	// service, so an outage only degrades the service
+	checker.Register("ml", !cfg.ML.FallbackEnabled, mlClient.HealthCheck)
+	checker.AddInfo("ml_circuit_breakers", func() interface{} { return mlClient.CircuitStates() })
+	checker.Register("kafka", false, health.Kafka(cfg.Kafka.Brokers))
+	healthHandler := handlers.NewHealthHandler(checker, redisCache)
 	authHandler := handlers.NewAuthHandler(authService)
 	verificationHandler := handlers.NewVerificationHandler(verificationService)
-	reportHandler := handlers.NewReportHandler(reportRepo)
+	reportHandler := handlers.NewReportHandler(reportService, reportRepo)
+	accountHandler := handlers.NewAccountHandler(accountService)
+	outboxHandler := handlers.NewOutboxHandler(outboxRepo)
+	eventsHandler := handlers.NewEventsHandler(events.DefaultRegistry())
+	searchHandler := handlers.NewSearchHandler(searchService)
+	analyticsHandler := handlers.NewAnalyticsHandler(analyticsService)
+	registryHandler := handlers.NewRegistryHandler(rbiService, headerService)
 
 	// Setup router
 	router := routes.SetupRouter(&routes.RouterConfig{
@@ -88,6 +159,12 @@ func main() {
 		AuthHandler:         authHandler,
 		VerificationHandler: verificationHandler,
 		ReportHandler:       reportHandler,
+		AccountHandler:      accountHandler,
+		OutboxHandler:       outboxHandler,
+		EventsHandler:       eventsHandler,
+		SearchHandler:       searchHandler,
+		AnalyticsHandler:    analyticsHandler,
+		RegistryHandler:     registryHandler,
 	})
 
 	// Create HTTP server
@@ -99,9 +176,22 @@ func main() {
 		WriteTimeout: cfg.Server.WriteTimeout,
 	}
 
+	// Serve metrics on their own port, away from the public API
+	var metricsServer *http.Server
+	if cfg.Metrics.Enabled {
+		metricsServer = metrics.NewServer(":" + cfg.Metrics.Port)
+		go func() {
+			logger.WithField("port", cfg.Metrics.Port).Info("Metrics server started")
+			if err := metricsServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+				logger.WithError(err).Error("Metrics server error")
+			}
+		}()
+	}
+
 	// Start server in a goroutine
 	go func() {
 		logger.WithField("address", addr).Info("API Gateway started")
+		checker.MarkStarted()
 		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
 			logger.WithError(err).Fatal("Failed to start server")
 		}
@@ -118,10 +208,12 @@ func main() {
 	ctx, cancel := context.WithTimeout(context.Background(), cfg.Server.ShutdownTimeout)
 	defer cancel()
 
+	if metricsServer != nil {
+		metricsServer.Shutdown(ctx)
+	}
 	if err := srv.Shutdown(ctx); err != nil {
 		logger.WithError(err).Fatal("Server forced to shutdown")
 	}
 
 	logger.Info("API Gateway stopped")
 }
-
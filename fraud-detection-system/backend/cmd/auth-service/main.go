@@ -15,8 +15,12 @@ import (
 	"github.com/fraud-detection-system/backend/internal/cache"
 	"github.com/fraud-detection-system/backend/internal/config"
 	"github.com/fraud-detection-system/backend/internal/database"
+	"github.com/fraud-detection-system/backend/internal/encryption"
+	"github.com/fraud-detection-system/backend/internal/health"
+	"github.com/fraud-detection-system/backend/internal/metrics"
 	"github.com/fraud-detection-system/backend/internal/repository"
 	"github.com/fraud-detection-system/backend/internal/service"
+	"github.com/fraud-detection-system/backend/internal/tracing"
 	"github.com/fraud-detection-system/backend/internal/utils"
 )
 
@@ -30,9 +34,17 @@ func main() {
 
 	// Initialize logger
 	utils.InitLogger(cfg.App.LogLevel)
+	utils.SetRedactor(utils.NewRedactor(cfg.Logging.RedactionMode, cfg.Logging.SafeFields))
 	logger := utils.GetLogger()
 	logger.Info("Starting Auth Service...")
 
+	// Initialize tracing
+	shutdownTracing, err := tracing.Init(context.Background(), cfg, "auth-service")
+	if err != nil {
+		logger.WithError(err).Fatal("Failed to initialize tracing")
+	}
+	defer shutdownTracing(context.Background())
+
 	// Initialize database
 	db, err := database.NewDatabase(cfg)
 	if err != nil {
@@ -41,6 +53,18 @@ func main() {
 	defer db.Close()
 	logger.Info("Database connected")
 
+	// "<binary> migrate ..." manages the schema and exits
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if err := database.RunMigrateCommand(context.Background(), db.DB, os.Args[2:], os.Stdout); err != nil {
+			logger.WithError(err).Fatal("Migration command failed")
+		}
+		return
+	}
+	if err := database.EnsureMigrated(context.Background(), db.DB, cfg.Database.AutoMigrate); err != nil {
+		logger.WithError(err).Fatal("Database schema is not up to date")
+	}
+	db.RegisterMetrics()
+
 	// Initialize cache
 	redisCache, err := cache.NewRedisCache(cfg)
 	if err != nil {
@@ -49,11 +73,20 @@ func main() {
 	defer redisCache.Close()
 	logger.Info("Redis connected")
 
+	// Initialize encryption
+	keyProvider, err := encryption.NewLocalKeyProvider(cfg.Encryption)
+	if err != nil {
+		logger.WithError(err).Fatal("Failed to load encryption keys")
+	}
+	fieldCipher := encryption.NewFieldCipher(keyProvider)
+
 	// Initialize repositories
-	userRepo := repository.NewUserRepository(db.DB)
+	userRepo := repository.NewUserRepository(db.DB, fieldCipher)
+	passwordResetRepo := repository.NewPasswordResetRepository(db.DB)
+	recoveryCodeRepo := repository.NewRecoveryCodeRepository(db.DB)
 
 	// Initialize services
-	authService := service.NewAuthService(userRepo, cfg)
+	authService := service.NewAuthService(userRepo, passwordResetRepo, recoveryCodeRepo, redisCache, cfg)
 
 	// Initialize handlers
 	authHandler := handlers.NewAuthHandler(authService)
@@ -61,13 +94,24 @@ func main() {
 	// Setup router
 	router := gin.New()
 	router.Use(gin.Recovery())
+	router.Use(middleware.RequestIDMiddleware())
+	router.Use(middleware.TracingMiddleware())
 	router.Use(middleware.LoggingMiddleware())
 	router.Use(middleware.CORSMiddleware(cfg))
+	if cfg.Metrics.Enabled {
+		router.Use(middleware.MetricsMiddleware())
+	}
 
-	// Health check
-	router.GET("/health", func(c *gin.Context) {
-		c.JSON(http.StatusOK, gin.H{"status": "healthy"})
-	})
+	// Health checks
+	checker := health.NewChecker("auth-service", cfg.Health.CheckTimeout, cfg.Health.CacheTTL, cfg.Health.Criticality)
+	checker.Register("database", true, db.HealthCheck)
+	checker.Register("redis", true, redisCache.HealthCheck)
+	healthHandler := handlers.NewHealthHandler(checker, redisCache)
+	router.GET("/health", healthHandler.HealthCheck)
+	router.GET("/health/live", healthHandler.HealthCheck)
+	probes := router.Group("", middleware.OptionalAuthMiddleware(cfg))
+	probes.GET("/health/ready", healthHandler.ReadinessCheck)
+	probes.GET("/health/startup", healthHandler.StartupCheck)
 
 	// Auth routes
 	v1 := router.Group("/api/v1/auth")
@@ -75,14 +119,24 @@ func main() {
 	{
 		v1.POST("/register", authHandler.Register)
 		v1.POST("/login", authHandler.Login)
+		v1.POST("/login/2fa", authHandler.VerifyMFALogin)
 		v1.POST("/refresh", authHandler.RefreshToken)
+		v1.POST("/forgot-password", authHandler.ForgotPassword)
+		v1.POST("/reset-password", authHandler.ResetPassword)
 	}
 
 	// Protected routes
 	protected := router.Group("/api/v1")
 	protected.Use(middleware.AuthMiddleware(cfg))
+	protected.Use(middleware.SessionRevocationMiddleware(redisCache))
+	protected.Use(middleware.MFAPolicyMiddleware(cfg))
 	{
 		protected.GET("/profile", authHandler.GetProfile)
+		protected.PUT("/profile/password", authHandler.ChangePassword)
+		protected.POST("/profile/2fa/enroll", authHandler.EnrollMFA)
+		protected.POST("/profile/2fa/confirm", authHandler.ConfirmMFA)
+		protected.POST("/profile/2fa/disable", authHandler.DisableMFA)
+		protected.POST("/profile/2fa/recovery-codes", authHandler.RegenerateRecoveryCodes)
 	}
 
 	// Create HTTP server
@@ -99,9 +153,22 @@ func main() {
 		WriteTimeout: 15 * time.Second,
 	}
 
+	// Serve metrics on their own port, away from the public API
+	var metricsServer *http.Server
+	if cfg.Metrics.Enabled {
+		metricsServer = metrics.NewServer(":" + cfg.Metrics.Port)
+		go func() {
+			logger.WithField("port", cfg.Metrics.Port).Info("Metrics server started")
+			if err := metricsServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+				logger.WithError(err).Error("Metrics server error")
+			}
+		}()
+	}
+
 	// Start server
 	go func() {
 		logger.WithField("address", addr).Info("Auth Service started")
+		checker.MarkStarted()
 		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
 			logger.WithError(err).Fatal("Failed to start server")
 		}
@@ -117,10 +184,12 @@ func main() {
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
 
+	if metricsServer != nil {
+		metricsServer.Shutdown(ctx)
+	}
 	if err := srv.Shutdown(ctx); err != nil {
 		logger.WithError(err).Fatal("Server forced to shutdown")
 	}
 
 	logger.Info("Auth Service stopped")
 }
-
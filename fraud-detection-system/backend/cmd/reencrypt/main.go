@@ -0,0 +1,79 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/fraud-detection-system/backend/internal/config"
+	"github.com/fraud-detection-system/backend/internal/database"
+	"github.com/fraud-detection-system/backend/internal/encryption"
+	"github.com/fraud-detection-system/backend/internal/repository"
+	"github.com/fraud-detection-system/backend/internal/service"
+	"github.com/fraud-detection-system/backend/internal/utils"
+)
+
+// reencrypt re-encrypts stored message and report fields with the active
+// key from ENCRYPTION_ACTIVE_KEY_ID. Run it after rotating keys, keeping the
+// old key configured until it completes. It also builds the search vectors
+// of messages stored before message search existed, which can only be done
+// from the decrypted content.
+func main() {
+	// Load configuration
+	cfg, err := config.Load()
+	if err != nil {
+		fmt.Printf("Failed to load configuration: %v\n", err)
+		os.Exit(1)
+	}
+
+	// Initialize logger
+	utils.InitLogger(cfg.App.LogLevel)
+	utils.SetRedactor(utils.NewRedactor(cfg.Logging.RedactionMode, cfg.Logging.SafeFields))
+	logger := utils.GetLogger()
+	logger.WithField("active_key_id", cfg.Encryption.ActiveKeyID).Info("Starting re-encryption...")
+
+	// Initialize encryption
+	keyProvider, err := encryption.NewLocalKeyProvider(cfg.Encryption)
+	if err != nil {
+		logger.WithError(err).Fatal("Failed to load encryption keys")
+	}
+	fieldCipher := encryption.NewFieldCipher(keyProvider)
+
+	// Initialize database
+	db, err := database.NewDatabase(cfg)
+	if err != nil {
+		logger.WithError(err).Fatal("Failed to connect to database")
+	}
+	defer db.Close()
+
+	// Initialize repositories and service
+	messageRepo := repository.NewMessageRepository(db.DB, fieldCipher)
+	reportRepo := repository.NewReportRepository(db.DB, fieldCipher)
+	userRepo := repository.NewUserRepository(db.DB, fieldCipher)
+	reencryptionService := service.NewReencryptionService(messageRepo, reportRepo, userRepo, 500)
+
+	// Stop between batches on interrupt; the job can be resumed by rerunning it
+	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer cancel()
+
+	messages, reports, err := reencryptionService.Run(ctx)
+	if err != nil {
+		logger.WithError(err).WithField("messages", messages).WithField("reports", reports).Fatal("Re-encryption failed")
+	}
+
+	logger.WithField("messages", messages).WithField("reports", reports).Info("Re-encryption completed")
+
+	secrets, err := reencryptionService.ReencryptTOTPSecrets(ctx)
+	if err != nil {
+		logger.WithError(err).WithField("totp_secrets", secrets).Fatal("TOTP secret re-encryption failed")
+	}
+	logger.WithField("totp_secrets", secrets).Info("TOTP secret re-encryption completed")
+
+	indexed, err := reencryptionService.IndexSearch(ctx)
+	if err != nil {
+		logger.WithError(err).WithField("indexed_messages", indexed).Fatal("Search indexing failed")
+	}
+	logger.WithField("indexed_messages", indexed).Info("Search indexing completed")
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/fraud-detection-system/backend/internal/classifier"
+	"github.com/fraud-detection-system/backend/internal/config"
+	"github.com/fraud-detection-system/backend/internal/database"
+	"github.com/fraud-detection-system/backend/internal/encryption"
+	"github.com/fraud-detection-system/backend/internal/repository"
+	"github.com/fraud-detection-system/backend/internal/service"
+	"github.com/fraud-detection-system/backend/internal/utils"
+)
+
+// train-classifier trains the embedded classifier from fraud and false
+// positive reports and writes its weights for ML_EMBEDDED_MODEL_PATH
+func main() {
+	opts := classifier.DefaultTrainOptions()
+	output := flag.String("output", "", "weights file to write (default ML_EMBEDDED_MODEL_PATH)")
+	holdout := flag.Float64("holdout", 0.2, "fraction of reports held out for evaluation")
+	flag.StringVar(&opts.Version, "version", opts.Version, "model version recorded on verifications")
+	flag.IntVar(&opts.Epochs, "epochs", opts.Epochs, "training epochs")
+	flag.Float64Var(&opts.LearningRate, "learning-rate", opts.LearningRate, "initial learning rate")
+	flag.Float64Var(&opts.L2, "l2", opts.L2, "L2 regularization strength")
+	flag.IntVar(&opts.MinTokenCount, "min-token-count", opts.MinTokenCount, "ignore tokens seen in fewer reports")
+	flag.Parse()
+	if *holdout < 0 || *holdout >= 1 {
+		fmt.Printf("-holdout must be at least 0 and less than 1, got %v\n", *holdout)
+		os.Exit(2)
+	}
+
+	// Load configuration
+	cfg, err := config.Load()
+	if err != nil {
+		fmt.Printf("Failed to load configuration: %v\n", err)
+		os.Exit(1)
+	}
+	if *output == "" {
+		*output = cfg.ML.EmbeddedModelPath
+	}
+
+	// Initialize logger
+	utils.InitLogger(cfg.App.LogLevel)
+	utils.SetRedactor(utils.NewRedactor(cfg.Logging.RedactionMode, cfg.Logging.SafeFields))
+	logger := utils.GetLogger()
+	logger.WithField("version", opts.Version).Info("Starting classifier training...")
+
+	// Initialize encryption
+	keyProvider, err := encryption.NewLocalKeyProvider(cfg.Encryption)
+	if err != nil {
+		logger.WithError(err).Fatal("Failed to load encryption keys")
+	}
+	fieldCipher := encryption.NewFieldCipher(keyProvider)
+
+	// Initialize database
+	db, err := database.NewDatabase(cfg)
+	if err != nil {
+		logger.WithError(err).Fatal("Failed to connect to database")
+	}
+	defer db.Close()
+
+	// Initialize repository and service
+	reportRepo := repository.NewReportRepository(db.DB, fieldCipher)
+	trainingService := service.NewClassifierTrainingService(reportRepo, 1000)
+
+	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer cancel()
+
+	model, metrics, err := trainingService.Train(ctx, opts, *holdout)
+	if err != nil {
+		logger.WithError(err).Fatal("Training failed")
+	}
+
+	if err := model.Save(*output); err != nil {
+		logger.WithError(err).Fatal("Failed to save model")
+	}
+
+	logger.WithField("output", *output).
+		WithField("examples", model.Examples).
+		WithField("accuracy", metrics.Accuracy).
+		WithField("precision", metrics.Precision).
+		WithField("recall", metrics.Recall).
+		Info("Classifier trained")
+}
@@ -15,8 +15,12 @@ import (
 	"github.com/fraud-detection-system/backend/internal/cache"
 	"github.com/fraud-detection-system/backend/internal/config"
 	"github.com/fraud-detection-system/backend/internal/database"
+	"github.com/fraud-detection-system/backend/internal/health"
+	"github.com/fraud-detection-system/backend/internal/metrics"
+	"github.com/fraud-detection-system/backend/internal/encryption"
 	"github.com/fraud-detection-system/backend/internal/repository"
 	"github.com/fraud-detection-system/backend/internal/service"
+	"github.com/fraud-detection-system/backend/internal/tracing"
 	"github.com/fraud-detection-system/backend/internal/utils"
 )
 
@@ -30,9 +34,17 @@ func main() {
 
 	// Initialize logger
 	utils.InitLogger(cfg.App.LogLevel)
+	utils.SetRedactor(utils.NewRedactor(cfg.Logging.RedactionMode, cfg.Logging.SafeFields))
 	logger := utils.GetLogger()
 	logger.Info("Starting Verification Service...")
 
+	// Initialize tracing
+	shutdownTracing, err := tracing.Init(context.Background(), cfg, "verification-service")
+	if err != nil {
+		logger.WithError(err).Fatal("Failed to initialize tracing")
+	}
+	defer shutdownTracing(context.Background())
+
 	// Initialize database
 	db, err := database.NewDatabase(cfg)
 	if err != nil {
@@ -41,6 +53,18 @@ func main() {
 	defer db.Close()
 	logger.Info("Database connected")
 
+	// "<binary> migrate ..." manages the schema and exits
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if err := database.RunMigrateCommand(context.Background(), db.DB, os.Args[2:], os.Stdout); err != nil {
+			logger.WithError(err).Fatal("Migration command failed")
+		}
+		return
+	}
+	if err := database.EnsureMigrated(context.Background(), db.DB, cfg.Database.AutoMigrate); err != nil {
+		logger.WithError(err).Fatal("Database schema is not up to date")
+	}
+	db.RegisterMetrics()
+
 	// Initialize cache
 	redisCache, err := cache.NewRedisCache(cfg)
 	if err != nil {
@@ -49,22 +73,41 @@ func main() {
 	defer redisCache.Close()
 	logger.Info("Redis connected")
 
+	// Initialize encryption
+	keyProvider, err := encryption.NewLocalKeyProvider(cfg.Encryption)
+	if err != nil {
+		logger.WithError(err).Fatal("Failed to load encryption keys")
+	}
+	fieldCipher := encryption.NewFieldCipher(keyProvider)
+
 	// Initialize repositories
-	messageRepo := repository.NewMessageRepository(db.DB)
-	verificationRepo := repository.NewVerificationRepository(db.DB)
+	userRepo := repository.NewUserRepository(db.DB, fieldCipher)
+	messageRepo := repository.NewMessageRepository(db.DB, fieldCipher)
+	verificationRepo := repository.NewVerificationRepository(db.DB).WithReader(db.Reader())
+	outboxRepo := repository.NewOutboxRepository(db.DB)
+	reportRepo := repository.NewReportRepository(db.DB, fieldCipher).WithReader(db.Reader())
 	rbiRepo := repository.NewRBIRepository(db.DB)
+	analyticsRepo := repository.NewAnalyticsRepository(db.DB).WithReader(db.Reader())
+	uow := repository.NewUnitOfWork(db.DB, userRepo, messageRepo, verificationRepo, reportRepo, rbiRepo, outboxRepo, analyticsRepo)
 
 	// Initialize services
-	mlClient := service.NewMLClient(cfg)
-	rbiService := service.NewRBIComplianceService(rbiRepo)
-	headerService := service.NewHeaderVerificationService(rbiRepo)
+	mlClient, err := service.NewMLClient(cfg)
+	if err != nil {
+		logger.WithError(err).Fatal("Failed to initialize ML client")
+	}
+	defer mlClient.Close()
+	verdictCache := service.NewVerdictCache(redisCache, cfg)
+	rbiService := service.NewRBIComplianceService(rbiRepo, uow, verdictCache, cfg)
+	headerService := service.NewHeaderVerificationService(rbiRepo, uow, verdictCache, cfg)
 	verificationService := service.NewVerificationService(
-		messageRepo,
 		verificationRepo,
+		uow,
 		mlClient,
 		rbiService,
 		headerService,
+		verdictCache,
 		redisCache,
+		cfg,
 	)
 
 	// Initialize handlers
@@ -73,17 +116,33 @@ func main() {
 	// Setup router
 	router := gin.New()
 	router.Use(gin.Recovery())
+	router.Use(middleware.RequestIDMiddleware())
+	router.Use(middleware.TracingMiddleware())
 	router.Use(middleware.LoggingMiddleware())
 	router.Use(middleware.CORSMiddleware(cfg))
+	if cfg.Metrics.Enabled {
+		router.Use(middleware.MetricsMiddleware())
+	}
 
-	// Health check
-	router.GET("/health", func(c *gin.Context) {
-		c.JSON(http.StatusOK, gin.H{"status": "healthy"})
-	})
+	// Health checks
+	checker := health.NewChecker("verification-service", cfg.Health.CheckTimeout, cfg.Health.CacheTTL, cfg.Health.Criticality)
+	checker.Register("database", true, db.HealthCheck)
+	checker.Register("redis", true, redisCache.HealthCheck)
+	// With the fallback scorer verification keeps working without the ML
+	// service, so an outage only degrades the service
+	checker.Register("ml", !cfg.ML.FallbackEnabled, mlClient.HealthCheck)
+	checker.AddInfo("ml_circuit_breakers", func() interface{} { return mlClient.CircuitStates() })
+	healthHandler := handlers.NewHealthHandler(checker, redisCache)
+	router.GET("/health", healthHandler.HealthCheck)
+	router.GET("/health/live", healthHandler.HealthCheck)
+	probes := router.Group("", middleware.OptionalAuthMiddleware(cfg))
+	probes.GET("/health/ready", healthHandler.ReadinessCheck)
+	probes.GET("/health/startup", healthHandler.StartupCheck)
 
 	// Verification routes
 	v1 := router.Group("/api/v1/verify")
 	v1.Use(middleware.OptionalAuthMiddleware(cfg))
+	v1.Use(middleware.TenantMiddleware())
 	v1.Use(middleware.RateLimitMiddleware(redisCache, 100))
 	{
 		v1.POST("", verificationHandler.VerifyMessage)
@@ -94,6 +153,7 @@ func main() {
 	// Protected routes
 	protected := router.Group("/api/v1/verify")
 	protected.Use(middleware.AuthMiddleware(cfg))
+	protected.Use(middleware.TenantMiddleware())
 	{
 		protected.GET("/history", verificationHandler.GetVerificationHistory)
 	}
@@ -112,9 +172,22 @@ func main() {
 		WriteTimeout: 15 * time.Second,
 	}
 
+	// Serve metrics on their own port, away from the public API
+	var metricsServer *http.Server
+	if cfg.Metrics.Enabled {
+		metricsServer = metrics.NewServer(":" + cfg.Metrics.Port)
+		go func() {
+			logger.WithField("port", cfg.Metrics.Port).Info("Metrics server started")
+			if err := metricsServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+				logger.WithError(err).Error("Metrics server error")
+			}
+		}()
+	}
+
 	// Start server
 	go func() {
 		logger.WithField("address", addr).Info("Verification Service started")
+		checker.MarkStarted()
 		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
 			logger.WithError(err).Fatal("Failed to start server")
 		}
@@ -130,10 +203,12 @@ func main() {
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
 
+	if metricsServer != nil {
+		metricsServer.Shutdown(ctx)
+	}
 	if err := srv.Shutdown(ctx); err != nil {
 		logger.WithError(err).Fatal("Server forced to shutdown")
 	}
 
 	logger.Info("Verification Service stopped")
 }
-
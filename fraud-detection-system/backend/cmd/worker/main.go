@@ -3,18 +3,25 @@ package main
 import (
 	"context"
 	"fmt"
+	"net/http"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/fraud-detection-system/backend/internal/cache"
 	"github.com/fraud-detection-system/backend/internal/config"
 	"github.com/fraud-detection-system/backend/internal/database"
+	"github.com/fraud-detection-system/backend/internal/health"
+	"github.com/fraud-detection-system/backend/internal/encryption"
+	"github.com/fraud-detection-system/backend/internal/metrics"
 	"github.com/fraud-detection-system/backend/internal/models"
 	"github.com/fraud-detection-system/backend/internal/queue"
 	"github.com/fraud-detection-system/backend/internal/repository"
 	"github.com/fraud-detection-system/backend/internal/service"
+	"github.com/fraud-detection-system/backend/internal/tracing"
 	"github.com/fraud-detection-system/backend/internal/utils"
 )
 
@@ -28,9 +35,17 @@ func main() {
 
 	// Initialize logger
 	utils.InitLogger(cfg.App.LogLevel)
+	utils.SetRedactor(utils.NewRedactor(cfg.Logging.RedactionMode, cfg.Logging.SafeFields))
 	logger := utils.GetLogger()
 	logger.Info("Starting Worker Service...")
 
+	// Initialize tracing
+	shutdownTracing, err := tracing.Init(context.Background(), cfg, "worker")
+	if err != nil {
+		logger.WithError(err).Fatal("Failed to initialize tracing")
+	}
+	defer shutdownTracing(context.Background())
+
 	// Initialize database
 	db, err := database.NewDatabase(cfg)
 	if err != nil {
@@ -39,6 +54,18 @@ func main() {
 	defer db.Close()
 	logger.Info("Database connected")
 
+	// "<binary> migrate ..." manages the schema and exits
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if err := database.RunMigrateCommand(context.Background(), db.DB, os.Args[2:], os.Stdout); err != nil {
+			logger.WithError(err).Fatal("Migration command failed")
+		}
+		return
+	}
+	if err := database.EnsureMigrated(context.Background(), db.DB, cfg.Database.AutoMigrate); err != nil {
+		logger.WithError(err).Fatal("Database schema is not up to date")
+	}
+	db.RegisterMetrics()
+
 	// Initialize cache
 	redisCache, err := cache.NewRedisCache(cfg)
 	if err != nil {
@@ -47,27 +74,69 @@ func main() {
 	defer redisCache.Close()
 	logger.Info("Redis connected")
 
+	// Initialize Kafka producer for the outbox relay
+	producer := queue.NewProducer(cfg)
+	defer producer.Close()
+	logger.Info("Kafka producer initialized")
+
+	// Initialize encryption
+	keyProvider, err := encryption.NewLocalKeyProvider(cfg.Encryption)
+	if err != nil {
+		logger.WithError(err).Fatal("Failed to load encryption keys")
+	}
+	fieldCipher := encryption.NewFieldCipher(keyProvider)
+
 	// Initialize repositories
-	messageRepo := repository.NewMessageRepository(db.DB)
+	userRepo := repository.NewUserRepository(db.DB, fieldCipher)
+	messageRepo := repository.NewMessageRepository(db.DB, fieldCipher)
 	verificationRepo := repository.NewVerificationRepository(db.DB)
+	outboxRepo := repository.NewOutboxRepository(db.DB)
+	reportRepo := repository.NewReportRepository(db.DB, fieldCipher)
 	rbiRepo := repository.NewRBIRepository(db.DB)
+	analyticsRepo := repository.NewAnalyticsRepository(db.DB)
+	resetRepo := repository.NewPasswordResetRepository(db.DB)
+	retentionRepo := repository.NewRetentionRepository(db.DB)
+	uow := repository.NewUnitOfWork(db.DB, userRepo, messageRepo, verificationRepo, reportRepo, rbiRepo, outboxRepo, analyticsRepo)
 
 	// Initialize services
-	mlClient := service.NewMLClient(cfg)
-	rbiService := service.NewRBIComplianceService(rbiRepo)
-	headerService := service.NewHeaderVerificationService(rbiRepo)
+	mlClient, err := service.NewMLClient(cfg)
+	if err != nil {
+		logger.WithError(err).Fatal("Failed to initialize ML client")
+	}
+	defer mlClient.Close()
+	verdictCache := service.NewVerdictCache(redisCache, cfg)
+	rbiService := service.NewRBIComplianceService(rbiRepo, uow, verdictCache, cfg)
+	headerService := service.NewHeaderVerificationService(rbiRepo, uow, verdictCache, cfg)
 	verificationService := service.NewVerificationService(
-		messageRepo,
 		verificationRepo,
+		uow,
 		mlClient,
 		rbiService,
 		headerService,
+		verdictCache,
+		redisCache,
+		cfg,
+	)
+	retentionService := service.NewRetentionService(
+		messageRepo,
+		verificationRepo,
+		resetRepo,
+		retentionRepo,
 		redisCache,
+		cfg,
 	)
+	analyticsService := service.NewAnalyticsService(uow, analyticsRepo)
+	// Kafka messages are retried until they are published, so none are lost;
+	// only entries that can never be published are abandoned
+	kafkaRelay := service.NewKafkaRelay(producer)
+	outboxProcessor := service.NewOutboxProcessor(uow, outboxRepo, cfg)
+	outboxProcessor.Handle(models.OutboxKindSenderStats, headerService.ApplySenderStats, cfg.Outbox.MaxAttempts)
+	outboxProcessor.Handle(models.OutboxKindAnalytics, analyticsService.ApplyVerification, cfg.Outbox.MaxAttempts)
+	outboxProcessor.Handle(models.OutboxKindKafka, kafkaRelay.Publish, 0)
 
 	// Create message handler
-	messageHandler := func(ctx context.Context, msg *queue.QueueMessage) error {
-		logger.WithField("message_id", msg.ID).Info("Processing verification request")
+	messageHandler := func(ctx context.Context, msg *models.QueueMessage) error {
+		logger.WithContext(ctx).WithField("message_id", msg.ID).Info("Processing verification request")
 
 		// Extract verification request from payload
 		content, ok := msg.Payload["content"].(string)
@@ -85,6 +154,9 @@ func main() {
 			Content:      content,
 			SenderHeader: senderHeader,
 		}
+		if tenantID, ok := msg.Payload["tenant_id"].(string); ok {
+			req.TenantID = tenantID
+		}
 
 		// Get user ID if present
 		var userID *uuid.UUID
@@ -97,11 +169,11 @@ func main() {
 		// Perform verification
 		_, err := verificationService.VerifyMessage(ctx, req, userID)
 		if err != nil {
-			logger.WithError(err).Error("Failed to verify message")
+			logger.WithContext(ctx).WithError(err).Error("Failed to verify message")
 			return err
 		}
 
-		logger.WithField("message_id", msg.ID).Info("Verification completed")
+		logger.WithContext(ctx).WithField("message_id", msg.ID).Info("Verification completed")
 		return nil
 	}
 
@@ -121,6 +193,65 @@ func main() {
 		}
 	}()
 
+	// Start the retention scheduler
+	if cfg.Retention.Enabled {
+		go func() {
+			logger.WithField("interval", cfg.Retention.Interval).Info("Retention scheduler started")
+			retentionService.Start(ctx)
+		}()
+	}
+
+	// Start the outbox processor
+	if cfg.Outbox.Enabled {
+		go func() {
+			logger.WithField("poll_interval", cfg.Outbox.PollInterval).Info("Outbox processor started")
+			outboxProcessor.Start(ctx)
+		}()
+	}
+
+	// Serve health probes and metrics; the worker has no API
+	checker := health.NewChecker("worker", cfg.Health.CheckTimeout, cfg.Health.CacheTTL, cfg.Health.Criticality)
+	checker.Register("database", true, db.HealthCheck)
+	checker.Register("redis", true, redisCache.HealthCheck)
+	// With the fallback scorer verification keeps working without the ML
+	// service, so an outage only degrades the service
+	checker.Register("ml", !cfg.ML.FallbackEnabled, mlClient.HealthCheck)
+	checker.AddInfo("ml_circuit_breakers", func() interface{} { return mlClient.CircuitStates() })
+	checker.Register("kafka", true, health.Kafka(cfg.Kafka.Brokers))
+
+	mux := http.NewServeMux()
+	// Probe reports carry error details only for admins whose session is
+	// still valid
+	checker.RegisterHTTP(mux, func(r *http.Request) bool {
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok {
+			return false
+		}
+		claims, err := utils.ValidateToken(token, cfg.JWT.Secret)
+		if err != nil || claims.Role != models.RoleAdmin {
+			return false
+		}
+		var issuedAt time.Time
+		if claims.IssuedAt != nil {
+			issuedAt = claims.IssuedAt.Time
+		}
+		return !utils.SessionRevoked(r.Context(), redisCache, claims.UserID, issuedAt)
+	})
+	if cfg.Metrics.Enabled {
+		mux.Handle("/metrics", metrics.Handler())
+	}
+	httpServer := &http.Server{
+		Addr:    ":" + cfg.Server.WorkerPort,
+		Handler: mux,
+	}
+	go func() {
+		logger.WithField("port", cfg.Server.WorkerPort).Info("Health and metrics server started")
+		checker.MarkStarted()
+		if err := httpServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			logger.WithError(err).Error("Health and metrics server error")
+		}
+	}()
+
 	// Wait for interrupt signal
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
@@ -128,7 +259,9 @@ func main() {
 
 	logger.Info("Shutting down Worker Service...")
 	cancel()
+	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer shutdownCancel()
+	httpServer.Shutdown(shutdownCtx)
 
 	logger.Info("Worker Service stopped")
 }
-
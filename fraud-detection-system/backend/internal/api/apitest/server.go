@@ -0,0 +1,228 @@
+// Package apitest builds the full API router over in-memory repositories, an
+// in-memory cache and a stub ML server, so tests can drive the HTTP API end
+// to end without Postgres, Redis, Kafka or the ML service.
+package apitest
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/fraud-detection-system/backend/internal/api/handlers"
+	"github.com/fraud-detection-system/backend/internal/api/routes"
+	"github.com/fraud-detection-system/backend/internal/cache"
+	"github.com/fraud-detection-system/backend/internal/config"
+	"github.com/fraud-detection-system/backend/internal/events"
+	"github.com/fraud-detection-system/backend/internal/health"
+	"github.com/fraud-detection-system/backend/internal/mlstub"
+	"github.com/fraud-detection-system/backend/internal/models"
+	"github.com/fraud-detection-system/backend/internal/repository/memory"
+	"github.com/fraud-detection-system/backend/internal/service"
+	"github.com/fraud-detection-system/backend/internal/utils"
+)
+
+// Server is a router wired like the api-gateway, with its backing stores
+// exposed so tests can seed and inspect them
+type Server struct {
+	Router *gin.Engine
+	Config *config.Config
+	DB     *memory.DB
+	Cache  *cache.MemoryCache
+	ML     *mlstub.Server
+	Health *health.Checker
+}
+
+// New builds a Server. The stub ML server scores with predict, or
+// mlstub.DefaultPredict if nil. configure, if given, adjusts the config
+// before anything is built from it. Everything is torn down when t ends.
+func New(t *testing.T, predict mlstub.PredictFunc, configure func(cfg *config.Config)) *Server {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	ml := mlstub.NewServer(predict)
+	addr, err := ml.Start()
+	if err != nil {
+		t.Fatalf("failed to start stub ML server: %v", err)
+	}
+	t.Cleanup(ml.Stop)
+
+	cfg, err := config.Load()
+	if err != nil {
+		t.Fatalf("failed to load config: %v", err)
+	}
+	cfg.App.Env = "test"
+	cfg.JWT.Secret = "apitest-secret"
+	cfg.Metrics.Enabled = false
+	cfg.ML.Transport = config.MLTransportGRPC
+	cfg.ML.Models = []config.MLModelEndpoint{{Name: "default", URL: addr}}
+	cfg.ML.Champion = "default"
+	cfg.ML.Challenger = ""
+	cfg.ML.ShadowEnabled = false
+	cfg.ML.TenantModels = nil
+	if configure != nil {
+		configure(cfg)
+	}
+
+	db := memory.New()
+	memCache := cache.NewMemoryCache(cfg.Redis.CacheTTL)
+
+	userRepo := db.Users()
+	messageRepo := db.Messages()
+	verificationRepo := db.Verifications()
+	reportRepo := db.Reports()
+	rbiRepo := db.RBI()
+	outboxRepo := db.Outbox()
+	analyticsRepo := db.Analytics()
+	uow := db.UnitOfWork()
+
+	mlClient, err := service.NewMLClient(cfg)
+	if err != nil {
+		t.Fatalf("failed to create ML client: %v", err)
+	}
+	t.Cleanup(func() { mlClient.Close() })
+
+	authService := service.NewAuthService(userRepo, db.PasswordResets(), db.RecoveryCodes(), memCache, cfg)
+	verdictCache := service.NewVerdictCache(memCache, cfg)
+	rbiService := service.NewRBIComplianceService(rbiRepo, uow, verdictCache, cfg)
+	headerService := service.NewHeaderVerificationService(rbiRepo, uow, verdictCache, cfg)
+	accountService := service.NewAccountService(uow, userRepo, authService, messageRepo, verificationRepo, reportRepo, memCache, cfg)
+	verificationService := service.NewVerificationService(
+		verificationRepo,
+		uow,
+		mlClient,
+		rbiService,
+		headerService,
+		verdictCache,
+		memCache,
+		cfg,
+	)
+	reportService := service.NewReportService(uow, cfg)
+
+	checker := health.NewChecker("api-gateway", cfg.Health.CheckTimeout, cfg.Health.CacheTTL, cfg.Health.Criticality)
+	checker.Register("cache", true, memCache.HealthCheck)
+	checker.Register("ml", !cfg.ML.FallbackEnabled, mlClient.HealthCheck)
+
+	router := routes.SetupRouter(&routes.RouterConfig{
+		Config:              cfg,
+		Cache:               memCache,
+		HealthHandler:       handlers.NewHealthHandler(checker, memCache),
+		AuthHandler:         handlers.NewAuthHandler(authService),
+		VerificationHandler: handlers.NewVerificationHandler(verificationService),
+		ReportHandler:       handlers.NewReportHandler(reportService, reportRepo),
+		AccountHandler:      handlers.NewAccountHandler(accountService),
+		OutboxHandler:       handlers.NewOutboxHandler(outboxRepo),
+		EventsHandler:       handlers.NewEventsHandler(events.DefaultRegistry()),
+		SearchHandler:       handlers.NewSearchHandler(service.NewSearchService(messageRepo)),
+		AnalyticsHandler:    handlers.NewAnalyticsHandler(service.NewAnalyticsService(uow, analyticsRepo)),
+		RegistryHandler:     handlers.NewRegistryHandler(rbiService, headerService),
+	})
+
+	return &Server{Router: router, Config: cfg, DB: db, Cache: memCache, ML: ml, Health: checker}
+}
+
+// Do sends a request with body marshalled to JSON, authenticated with token
+// if it is not empty
+func (s *Server) Do(t *testing.T, method, path string, body interface{}, token string) *httptest.ResponseRecorder {
+	t.Helper()
+	return s.DoWithHeaders(t, method, path, body, token, nil)
+}
+
+// DoWithHeaders is Do with extra request headers
+func (s *Server) DoWithHeaders(t *testing.T, method, path string, body interface{}, token string, header http.Header) *httptest.ResponseRecorder {
+	t.Helper()
+
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			t.Fatalf("failed to marshal request body: %v", err)
+		}
+		reader = bytes.NewReader(data)
+	}
+	req := httptest.NewRequest(method, path, reader)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	for key, values := range header {
+		for _, value := range values {
+			req.Header.Add(key, value)
+		}
+	}
+
+	w := httptest.NewRecorder()
+	s.Router.ServeHTTP(w, req)
+	return w
+}
+
+// Decode unmarshals the data field of a success response into dest
+func Decode(t *testing.T, w *httptest.ResponseRecorder, dest interface{}) {
+	t.Helper()
+
+	var envelope struct {
+		Data json.RawMessage `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &envelope); err != nil {
+		t.Fatalf("failed to decode response %q: %v", w.Body.String(), err)
+	}
+	if err := json.Unmarshal(envelope.Data, dest); err != nil {
+		t.Fatalf("failed to decode response data %q: %v", envelope.Data, err)
+	}
+}
+
+// Register creates a user through the API and returns an access token for it
+func (s *Server) Register(t *testing.T, email, password string) string {
+	t.Helper()
+
+	w := s.Do(t, http.MethodPost, "/api/v1/auth/register", models.UserRegistration{
+		Email:       email,
+		Password:    password,
+		FullName:    "Test User",
+		PhoneNumber: "+919876543210",
+	}, "")
+	if w.Code != http.StatusCreated {
+		t.Fatalf("register: expected 201, got %d: %s", w.Code, w.Body.String())
+	}
+	return s.Login(t, email, password)
+}
+
+// Login logs a user in through the API and returns an access token
+func (s *Server) Login(t *testing.T, email, password string) string {
+	t.Helper()
+
+	w := s.Do(t, http.MethodPost, "/api/v1/auth/login", models.UserLogin{Email: email, Password: password}, "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("login: expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var resp models.LoginResponse
+	Decode(t, w, &resp)
+	if resp.TokenPair == nil {
+		t.Fatalf("login: no tokens in %s", w.Body.String())
+	}
+	return resp.AccessToken
+}
+
+// Token returns an access token for a user with the given role that has
+// completed two-factor login, for calling role-restricted endpoints without
+// enrolling the user in two-factor authentication
+func (s *Server) Token(t *testing.T, userID uuid.UUID, email, role string) string {
+	t.Helper()
+	return s.TenantToken(t, userID, email, role, models.DefaultTenantID)
+}
+
+// TenantToken is Token for a user of the given tenant
+func (s *Server) TenantToken(t *testing.T, userID uuid.UUID, email, role, tenantID string) string {
+	t.Helper()
+	token, err := utils.GenerateToken(userID, email, role, tenantID, true, s.Config.JWT.Secret, s.Config.JWT.Expiry)
+	if err != nil {
+		t.Fatalf("failed to generate token: %v", err)
+	}
+	return token
+}
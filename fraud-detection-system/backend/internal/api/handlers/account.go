@@ -0,0 +1,72 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/fraud-detection-system/backend/internal/models"
+	"github.com/fraud-detection-system/backend/internal/service"
+	"github.com/fraud-detection-system/backend/internal/utils"
+)
+
+type AccountHandler struct {
+	accountService *service.AccountService
+}
+
+func NewAccountHandler(accountService *service.AccountService) *AccountHandler {
+	return &AccountHandler{
+		accountService: accountService,
+	}
+}
+
+// ExportData handles downloading all data stored about the user
+func (h *AccountHandler) ExportData(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		utils.RespondWithError(c, http.StatusUnauthorized, utils.ErrUnauthorized, "User not authenticated")
+		return
+	}
+
+	export, err := h.accountService.ExportUserData(c.Request.Context(), userID.(uuid.UUID))
+	if err != nil {
+		utils.HandleError(c, err)
+		return
+	}
+
+	filename := fmt.Sprintf("fraud-detection-export-%s.zip", export.ExportedAt.Format("20060102-150405"))
+	c.Header("Content-Type", "application/zip")
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	c.Status(http.StatusOK)
+
+	if err := h.accountService.WriteExportArchive(c.Writer, export); err != nil {
+		// Headers are already sent, so the error can only be logged
+		utils.GetLoggerWithContext(c.Request.Context()).WithError(err).Error("Failed to write data export")
+		_ = c.Error(err)
+	}
+}
+
+// DeleteAccount handles erasing the user's account and data
+func (h *AccountHandler) DeleteAccount(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		utils.RespondWithError(c, http.StatusUnauthorized, utils.ErrUnauthorized, "User not authenticated")
+		return
+	}
+
+	var req models.DeleteAccountRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.RespondWithError(c, http.StatusBadRequest, utils.ErrBadRequest, err.Error())
+		return
+	}
+
+	if err := h.accountService.DeleteAccount(c.Request.Context(), userID.(uuid.UUID), req.Password, req.Code); err != nil {
+		utils.HandleError(c, err)
+		return
+	}
+
+	utils.RespondWithSuccess(c, http.StatusOK, gin.H{
+		"message": "Account and associated personal data deleted",
+	})
+}
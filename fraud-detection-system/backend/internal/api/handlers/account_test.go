@@ -0,0 +1,156 @@
+package handlers_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/fraud-detection-system/backend/internal/api/apitest"
+	"github.com/fraud-detection-system/backend/internal/models"
+	"github.com/fraud-detection-system/backend/internal/utils"
+)
+
+func TestExportData(t *testing.T) {
+	server := apitest.New(t, nil, nil)
+	token := server.Register(t, "export@example.com", "SecurePass123!")
+	other := server.Register(t, "other@example.com", "SecurePass123!")
+
+	for _, tok := range []string{token, other} {
+		w := server.Do(t, http.MethodPost, "/api/v1/verify", models.VerificationRequest{
+			Content:      "Your KYC is pending, update at http://bit.ly/kyc",
+			SenderHeader: "VM-ALERTS",
+		}, tok)
+		if w.Code != http.StatusOK {
+			t.Fatalf("verify: expected 200, got %d: %s", w.Code, w.Body.String())
+		}
+	}
+
+	w := server.Do(t, http.MethodGet, "/api/v1/profile/export", nil, token)
+	if w.Code != http.StatusOK {
+		t.Fatalf("export: expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if ct := w.Header().Get("Content-Type"); ct != "application/zip" {
+		t.Errorf("expected a zip archive, got %q", ct)
+	}
+	archive, err := zip.NewReader(bytes.NewReader(w.Body.Bytes()), int64(w.Body.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	files := map[string]*zip.File{}
+	for _, f := range archive.File {
+		files[f.Name] = f
+	}
+	for _, name := range []string{"profile.json", "messages.json", "verifications.json", "reports.json", "export.json"} {
+		if files[name] == nil {
+			t.Errorf("expected %s in the archive", name)
+		}
+	}
+
+	read := func(name string, dest interface{}) {
+		t.Helper()
+		f, err := files[name].Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer f.Close()
+		if err := json.NewDecoder(f).Decode(dest); err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+	}
+	var profile models.UserResponse
+	read("profile.json", &profile)
+	if profile.Email != "export@example.com" {
+		t.Errorf("expected the user's own profile, got %s", profile.Email)
+	}
+	var summary struct {
+		MessageCount      int `json:"message_count"`
+		VerificationCount int `json:"verification_count"`
+	}
+	read("export.json", &summary)
+	if summary.MessageCount != 1 || summary.VerificationCount != 1 {
+		t.Errorf("expected only the user's own message and verification, got %+v", summary)
+	}
+}
+
+func TestDeleteAccount(t *testing.T) {
+	server := apitest.New(t, nil, nil)
+	ctx := context.Background()
+	token := server.Register(t, "delete@example.com", "SecurePass123!")
+
+	w := server.Do(t, http.MethodPost, "/api/v1/verify", models.VerificationRequest{
+		Content:      "Your KYC is pending, update at http://bit.ly/kyc",
+		SenderHeader: "VM-ALERTS",
+	}, token)
+	if w.Code != http.StatusOK {
+		t.Fatalf("verify: expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var verification models.VerificationResponse
+	apitest.Decode(t, w, &verification)
+
+	// Enable two-factor
+	w = server.Do(t, http.MethodPost, "/api/v1/profile/2fa/enroll", nil, token)
+	var enrollment models.MFAEnrollmentResponse
+	apitest.Decode(t, w, &enrollment)
+	now := time.Now()
+	code, err := utils.GenerateTOTPCode(enrollment.Secret, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w = server.Do(t, http.MethodPost, "/api/v1/profile/2fa/confirm", models.MFACodeRequest{Code: code}, token)
+	if w.Code != http.StatusOK {
+		t.Fatalf("confirm: expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var confirmation models.MFAConfirmationResponse
+	apitest.Decode(t, w, &confirmation)
+	token = confirmation.Tokens.AccessToken
+
+	for name, tt := range map[string]struct {
+		req  models.DeleteAccountRequest
+		want int
+	}{
+		"wrong password": {models.DeleteAccountRequest{Password: "WrongPass123!", Code: confirmation.RecoveryCodes[0]}, http.StatusBadRequest},
+		"no code":        {models.DeleteAccountRequest{Password: "SecurePass123!"}, http.StatusUnauthorized},
+		"wrong code":     {models.DeleteAccountRequest{Password: "SecurePass123!", Code: "000000"}, http.StatusUnauthorized},
+	} {
+		if w := server.Do(t, http.MethodDelete, "/api/v1/profile", tt.req, token); w.Code != tt.want {
+			t.Errorf("%s: expected %d, got %d: %s", name, tt.want, w.Code, w.Body.String())
+		}
+	}
+	if _, err := server.DB.Users().GetByEmail(ctx, "delete@example.com"); err != nil {
+		t.Fatalf("expected the user to survive failed deletions: %v", err)
+	}
+
+	code, err = utils.GenerateTOTPCode(enrollment.Secret, now.Add(30*time.Second))
+	if err != nil {
+		t.Fatal(err)
+	}
+	w = server.Do(t, http.MethodDelete, "/api/v1/profile", models.DeleteAccountRequest{Password: "SecurePass123!", Code: code}, token)
+	if w.Code != http.StatusOK {
+		t.Fatalf("delete: expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	if _, err := server.DB.Users().GetByEmail(ctx, "delete@example.com"); err == nil {
+		t.Error("expected the user to be deleted")
+	}
+	message, err := server.DB.Messages().GetByID(ctx, verification.MessageID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if message.UserID != nil || message.Content != "[deleted]" {
+		t.Errorf("expected the message to be anonymized, got %+v", message)
+	}
+	stored, err := server.DB.Verifications().GetByID(ctx, verification.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stored.UserID != nil {
+		t.Error("expected the verification to be detached from the user")
+	}
+	if w := server.Do(t, http.MethodGet, "/api/v1/profile", nil, token); w.Code == http.StatusOK {
+		t.Error("expected the deleted user's session to be revoked")
+	}
+}
@@ -0,0 +1,48 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/fraud-detection-system/backend/internal/models"
+	"github.com/fraud-detection-system/backend/internal/service"
+	"github.com/fraud-detection-system/backend/internal/utils"
+)
+
+type AnalyticsHandler struct {
+	analyticsService *service.AnalyticsService
+}
+
+func NewAnalyticsHandler(analyticsService *service.AnalyticsService) *AnalyticsHandler {
+	return &AnalyticsHandler{
+		analyticsService: analyticsService,
+	}
+}
+
+// GetTrends handles the fraud trends time series of the caller's tenant, or
+// of every tenant for admins
+func (h *AnalyticsHandler) GetTrends(c *gin.Context) {
+	var query models.TrendsQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		utils.RespondWithError(c, http.StatusBadRequest, utils.ErrBadRequest, err.Error())
+		return
+	}
+	if query.AllTenants && c.GetString("user_role") != models.RoleAdmin {
+		utils.RespondWithError(c, http.StatusForbidden, utils.ErrForbidden, "Only admins can view every tenant")
+		return
+	}
+	filter, err := query.Filter(c.GetString("tenant_id"), time.Now())
+	if err != nil {
+		utils.RespondWithError(c, http.StatusBadRequest, utils.ErrBadRequest, err.Error())
+		return
+	}
+
+	trends, err := h.analyticsService.GetTrends(c.Request.Context(), filter, query.Top)
+	if err != nil {
+		utils.HandleError(c, err)
+		return
+	}
+
+	utils.RespondWithSuccess(c, http.StatusOK, trends)
+}
@@ -0,0 +1,157 @@
+package handlers_test
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/fraud-detection-system/backend/internal/api/apitest"
+	"github.com/fraud-detection-system/backend/internal/api/middleware"
+	"github.com/fraud-detection-system/backend/internal/models"
+	"github.com/fraud-detection-system/backend/internal/service"
+)
+
+func TestFraudTrends(t *testing.T) {
+	server := apitest.New(t, nil, nil)
+	ctx := context.Background()
+	userToken := server.Register(t, "user@example.com", "SecurePass123!")
+
+	started := time.Now().UTC()
+	for _, req := range []models.VerificationRequest{
+		{
+			Content:      "URGENT: your HDFC KYC is pending, update now at http://bit.ly/kyc-update or your account will be blocked",
+			SenderHeader: "VM-ALERTS",
+		},
+		{
+			Content:      "Your account statement for March is ready",
+			SenderHeader: "AD-ICICIB",
+			MessageType:  "Email",
+		},
+	} {
+		if w := server.Do(t, http.MethodPost, "/api/v1/verify", req, userToken); w.Code != http.StatusOK {
+			t.Fatalf("verify: expected 200, got %d: %s", w.Code, w.Body.String())
+		}
+	}
+
+	// Apply the analytics entries as the worker would
+	analytics := service.NewAnalyticsService(server.DB.UnitOfWork(), server.DB.Analytics())
+	entries, err := server.DB.Outbox().GetPending(ctx, models.OutboxKindAnalytics, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("expected an analytics entry per verification, got %d", len(entries))
+	}
+	for _, entry := range entries {
+		if err := analytics.ApplyVerification(ctx, nil, entry); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := server.DB.Analytics().RecordVerification(ctx, models.VerificationRollupUpdate{
+		TenantID:     "other-bank",
+		MessageType:  "SMS",
+		SenderHeader: "VM-OTHER",
+		IsFraud:      true,
+		RiskLevel:    models.RiskLevelHigh,
+		VerifiedAt:   time.Now(),
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	user, err := server.DB.Users().GetByEmail(ctx, "user@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	analystToken := server.Token(t, user.ID, user.Email, models.RoleAnalyst)
+	adminToken := server.Token(t, user.ID, user.Email, models.RoleAdmin)
+
+	if w := server.Do(t, http.MethodGet, "/api/v1/analytics/trends", nil, userToken); w.Code != http.StatusForbidden {
+		t.Errorf("user: expected 403, got %d", w.Code)
+	}
+
+	trends := func(query url.Values, token string) models.FraudTrends {
+		t.Helper()
+		w := server.Do(t, http.MethodGet, "/api/v1/analytics/trends?"+query.Encode(), nil, token)
+		if w.Code != http.StatusOK {
+			t.Fatalf("trends %s: expected 200, got %d: %s", query.Encode(), w.Code, w.Body.String())
+		}
+		var result models.FraudTrends
+		apitest.Decode(t, w, &result)
+		return result
+	}
+
+	got := trends(url.Values{"granularity": {"hour"}}, analystToken)
+	if len(got.Buckets) != 48 {
+		t.Errorf("expected 48 hourly buckets, got %d", len(got.Buckets))
+	}
+
+	// A fixed range around the verifications, which may straddle an hour
+	from := models.BucketStart(started, models.GranularityHour)
+	to := time.Now().UTC().Add(time.Hour)
+	got = trends(url.Values{
+		"granularity": {"hour"},
+		"from":        {from.Format(time.RFC3339)},
+		"to":          {to.Format(time.RFC3339)},
+	}, analystToken)
+	if !got.From.Equal(from) {
+		t.Errorf("expected the range to start at %s, got %s", from, got.From)
+	}
+	if got.Verifications != 2 || got.Fraud != 1 || got.FraudRate != 0.5 {
+		t.Errorf("expected the tenant's 2 verifications, 1 fraud, got %+v", got)
+	}
+	var bucketed, risked int64
+	for _, bucket := range got.Buckets {
+		bucketed += bucket.Verifications
+		for _, n := range bucket.RiskLevels {
+			risked += n
+		}
+	}
+	if bucketed != 2 || risked != 2 {
+		t.Errorf("expected both verifications and their risk levels in the buckets, got %+v", got.Buckets)
+	}
+	if len(got.TopBanks) != 1 || got.TopBanks[0] != (models.TopEntry{Value: "HDFC", Count: 1}) {
+		t.Errorf("expected HDFC as the only impersonated bank, got %+v", got.TopBanks)
+	}
+	if len(got.TopSenders) != 1 || got.TopSenders[0].Value != "VM-ALERTS" {
+		t.Errorf("expected only the fraudulent sender, got %+v", got.TopSenders)
+	}
+
+	got = trends(url.Values{"message_type": {"Email"}}, analystToken)
+	if len(got.Buckets) != 30 || got.Verifications != 1 || got.Fraud != 0 {
+		t.Errorf("message type: expected 30 days with the one email, got %+v", got)
+	}
+
+	otherTenant := http.Header{middleware.TenantHeader: {"other-bank"}}
+	if w := server.DoWithHeaders(t, http.MethodGet, "/api/v1/analytics/trends", nil, analystToken, otherTenant); w.Code != http.StatusForbidden {
+		t.Errorf("analyst in another tenant: expected 403, got %d", w.Code)
+	}
+	w := server.DoWithHeaders(t, http.MethodGet, "/api/v1/analytics/trends", nil, adminToken, otherTenant)
+	if w.Code != http.StatusOK {
+		t.Fatalf("admin in another tenant: expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	apitest.Decode(t, w, &got)
+	if got.Verifications != 1 || got.TopSenders[0].Value != "VM-OTHER" {
+		t.Errorf("admin in another tenant: expected only its verification, got %+v", got)
+	}
+
+	if w := server.Do(t, http.MethodGet, "/api/v1/analytics/trends?all_tenants=true", nil, analystToken); w.Code != http.StatusForbidden {
+		t.Errorf("analyst across tenants: expected 403, got %d", w.Code)
+	}
+	got = trends(url.Values{"all_tenants": {"true"}, "granularity": {"week"}}, adminToken)
+	if got.Verifications != 3 || got.Fraud != 2 || len(got.TopSenders) != 2 {
+		t.Errorf("all tenants: expected every tenant's verifications, got %+v", got)
+	}
+
+	for name, query := range map[string]url.Values{
+		"granularity": {"granularity": {"minute"}},
+		"range":       {"from": {"2026-01-02T00:00:00Z"}, "to": {"2026-01-01T00:00:00Z"}},
+		"too long":    {"granularity": {"hour"}, "from": {"2020-01-01T00:00:00Z"}},
+	} {
+		w := server.Do(t, http.MethodGet, "/api/v1/analytics/trends?"+query.Encode(), nil, analystToken)
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("%s: expected 400, got %d: %s", name, w.Code, w.Body.String())
+		}
+	}
+}
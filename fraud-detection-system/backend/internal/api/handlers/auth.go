@@ -88,3 +88,167 @@ func (h *AuthHandler) GetProfile(c *gin.Context) {
 	utils.RespondWithSuccess(c, http.StatusOK, user)
 }
 
+
+// ForgotPassword handles password reset requests
+func (h *AuthHandler) ForgotPassword(c *gin.Context) {
+	var req models.ForgotPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.RespondWithError(c, http.StatusBadRequest, utils.ErrBadRequest, err.Error())
+		return
+	}
+
+	if err := h.authService.ForgotPassword(c.Request.Context(), req.Email); err != nil {
+		utils.HandleError(c, err)
+		return
+	}
+
+	// Same response whether or not the email is registered
+	utils.RespondWithSuccess(c, http.StatusAccepted, gin.H{
+		"message": "If the email is registered, a password reset link has been sent",
+	})
+}
+
+// ResetPassword handles setting a new password with a reset token
+func (h *AuthHandler) ResetPassword(c *gin.Context) {
+	var req models.ResetPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.RespondWithError(c, http.StatusBadRequest, utils.ErrBadRequest, err.Error())
+		return
+	}
+
+	if err := h.authService.ResetPassword(c.Request.Context(), &req); err != nil {
+		utils.HandleError(c, err)
+		return
+	}
+
+	utils.RespondWithSuccess(c, http.StatusOK, gin.H{
+		"message": "Password has been reset, please log in again",
+	})
+}
+
+// ChangePassword handles changing the password of the authenticated user
+func (h *AuthHandler) ChangePassword(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		utils.RespondWithError(c, http.StatusUnauthorized, utils.ErrUnauthorized, "User not authenticated")
+		return
+	}
+
+	var req models.ChangePasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.RespondWithError(c, http.StatusBadRequest, utils.ErrBadRequest, err.Error())
+		return
+	}
+
+	tokens, err := h.authService.ChangePassword(c.Request.Context(), userID.(uuid.UUID), c.GetBool("mfa_verified"), &req)
+	if err != nil {
+		utils.HandleError(c, err)
+		return
+	}
+
+	utils.RespondWithSuccess(c, http.StatusOK, tokens)
+}
+
+// VerifyMFALogin handles the second step of login for two-factor accounts
+func (h *AuthHandler) VerifyMFALogin(c *gin.Context) {
+	var req models.MFALoginRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.RespondWithError(c, http.StatusBadRequest, utils.ErrBadRequest, err.Error())
+		return
+	}
+
+	tokens, err := h.authService.VerifyMFALogin(c.Request.Context(), &req)
+	if err != nil {
+		utils.HandleError(c, err)
+		return
+	}
+
+	utils.RespondWithSuccess(c, http.StatusOK, tokens)
+}
+
+// EnrollMFA handles starting two-factor enrollment
+func (h *AuthHandler) EnrollMFA(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		utils.RespondWithError(c, http.StatusUnauthorized, utils.ErrUnauthorized, "User not authenticated")
+		return
+	}
+
+	enrollment, err := h.authService.EnrollMFA(c.Request.Context(), userID.(uuid.UUID))
+	if err != nil {
+		utils.HandleError(c, err)
+		return
+	}
+
+	utils.RespondWithSuccess(c, http.StatusOK, enrollment)
+}
+
+// ConfirmMFA handles confirming two-factor enrollment with a first code
+func (h *AuthHandler) ConfirmMFA(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		utils.RespondWithError(c, http.StatusUnauthorized, utils.ErrUnauthorized, "User not authenticated")
+		return
+	}
+
+	var req models.MFACodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.RespondWithError(c, http.StatusBadRequest, utils.ErrBadRequest, err.Error())
+		return
+	}
+
+	result, err := h.authService.ConfirmMFA(c.Request.Context(), userID.(uuid.UUID), req.Code)
+	if err != nil {
+		utils.HandleError(c, err)
+		return
+	}
+
+	utils.RespondWithSuccess(c, http.StatusOK, result)
+}
+
+// DisableMFA handles turning off two-factor authentication
+func (h *AuthHandler) DisableMFA(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		utils.RespondWithError(c, http.StatusUnauthorized, utils.ErrUnauthorized, "User not authenticated")
+		return
+	}
+
+	var req models.MFADisableRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.RespondWithError(c, http.StatusBadRequest, utils.ErrBadRequest, err.Error())
+		return
+	}
+
+	if err := h.authService.DisableMFA(c.Request.Context(), userID.(uuid.UUID), &req); err != nil {
+		utils.HandleError(c, err)
+		return
+	}
+
+	utils.RespondWithSuccess(c, http.StatusOK, gin.H{
+		"message": "Two-factor authentication disabled",
+	})
+}
+
+// RegenerateRecoveryCodes handles replacing two-factor recovery codes
+func (h *AuthHandler) RegenerateRecoveryCodes(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		utils.RespondWithError(c, http.StatusUnauthorized, utils.ErrUnauthorized, "User not authenticated")
+		return
+	}
+
+	var req models.MFACodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.RespondWithError(c, http.StatusBadRequest, utils.ErrBadRequest, err.Error())
+		return
+	}
+
+	result, err := h.authService.RegenerateRecoveryCodes(c.Request.Context(), userID.(uuid.UUID), req.Code)
+	if err != nil {
+		utils.HandleError(c, err)
+		return
+	}
+
+	utils.RespondWithSuccess(c, http.StatusOK, result)
+}
@@ -1,107 +1,266 @@
-package handlers
+package handlers_test
 
 import (
-	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"net/http"
-	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
-	"github.com/gin-gonic/gin"
-	"github.com/stretchr/testify/assert"
-	"github.com/stretchr/testify/mock"
+	"github.com/google/uuid"
+	"github.com/fraud-detection-system/backend/internal/api/apitest"
+	"github.com/fraud-detection-system/backend/internal/models"
+	"github.com/fraud-detection-system/backend/internal/utils"
 )
 
-// MockAuthService is a mock implementation of AuthService
-type MockAuthService struct {
-	mock.Mock
-}
-
 func TestRegisterHandler(t *testing.T) {
-	// TODO: Implement test
-	// This is a stub showing the test structure
-	
-	gin.SetMode(gin.TestMode)
-	
-	t.Run("successful registration", func(t *testing.T) {
-		// Setup
-		w := httptest.NewRecorder()
-		c, _ := gin.CreateTestContext(w)
-		
-		// Test data
-		reqBody := map[string]interface{}{
-			"email":        "test@example.com",
-			"password":     "SecurePass123!",
-			"full_name":    "Test User",
-			"phone_number": "+919876543210",
-		}
-		
-		jsonData, _ := json.Marshal(reqBody)
-		c.Request, _ = http.NewRequest("POST", "/api/v1/auth/register", bytes.NewBuffer(jsonData))
-		c.Request.Header.Set("Content-Type", "application/json")
-		
-		// TODO: Mock service and test handler
-		// handler := NewAuthHandler(mockService)
-		// handler.Register(c)
-		
-		// Assertions
-		// assert.Equal(t, http.StatusCreated, w.Code)
-		
-		t.Skip("Test not implemented - stub only")
-	})
-	
-	t.Run("invalid email", func(t *testing.T) {
-		t.Skip("Test not implemented - stub only")
-	})
-	
-	t.Run("weak password", func(t *testing.T) {
-		t.Skip("Test not implemented - stub only")
-	})
-	
-	t.Run("duplicate email", func(t *testing.T) {
-		t.Skip("Test not implemented - stub only")
-	})
+	server := apitest.New(t, nil, nil)
+	register := func(email, password string) int {
+		return server.Do(t, http.MethodPost, "/api/v1/auth/register", models.UserRegistration{
+			Email:       email,
+			Password:    password,
+			FullName:    "Test User",
+			PhoneNumber: "+919876543210",
+		}, "").Code
+	}
+
+	if code := register("test@example.com", "SecurePass123!"); code != http.StatusCreated {
+		t.Fatalf("successful registration: expected 201, got %d", code)
+	}
+	if code := register("not-an-email", "SecurePass123!"); code != http.StatusBadRequest {
+		t.Errorf("invalid email: expected 400, got %d", code)
+	}
+	if code := register("weak@example.com", "short"); code != http.StatusBadRequest {
+		t.Errorf("weak password: expected 400, got %d", code)
+	}
+	if code := register("test@example.com", "SecurePass123!"); code != http.StatusConflict {
+		t.Errorf("duplicate email: expected 409, got %d", code)
+	}
 }
 
 func TestLoginHandler(t *testing.T) {
-	t.Run("successful login", func(t *testing.T) {
-		t.Skip("Test not implemented - stub only")
-	})
-	
-	t.Run("invalid credentials", func(t *testing.T) {
-		t.Skip("Test not implemented - stub only")
-	})
-	
-	t.Run("inactive user", func(t *testing.T) {
-		t.Skip("Test not implemented - stub only")
-	})
+	server := apitest.New(t, nil, nil)
+	token := server.Register(t, "login@example.com", "SecurePass123!")
+
+	w := server.Do(t, http.MethodGet, "/api/v1/profile", nil, token)
+	if w.Code != http.StatusOK {
+		t.Fatalf("profile: expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var profile models.UserResponse
+	apitest.Decode(t, w, &profile)
+	if profile.Email != "login@example.com" {
+		t.Errorf("expected profile of login@example.com, got %s", profile.Email)
+	}
+
+	w = server.Do(t, http.MethodPost, "/api/v1/auth/login",
+		models.UserLogin{Email: "login@example.com", Password: "WrongPass123!"}, "")
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("invalid credentials: expected 400, got %d", w.Code)
+	}
+
+	ctx := context.Background()
+	user, err := server.DB.Users().GetByEmail(ctx, "login@example.com")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	user.IsActive = false
+	if err := server.DB.Users().Update(ctx, user); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	w = server.Do(t, http.MethodPost, "/api/v1/auth/login",
+		models.UserLogin{Email: "login@example.com", Password: "SecurePass123!"}, "")
+	if w.Code != http.StatusForbidden {
+		t.Errorf("inactive user: expected 403, got %d: %s", w.Code, w.Body.String())
+	}
+	var resp utils.ErrorResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil || resp.Error != utils.ErrAccountInactive.Error() {
+		t.Errorf("inactive user: expected %q, got %+v, %v", utils.ErrAccountInactive, resp, err)
+	}
+
+	// Without the right password the account's state is not revealed
+	w = server.Do(t, http.MethodPost, "/api/v1/auth/login",
+		models.UserLogin{Email: "login@example.com", Password: "WrongPass123!"}, "")
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("inactive user with a wrong password: expected 400, got %d", w.Code)
+	}
 }
 
 func TestRefreshTokenHandler(t *testing.T) {
+	server := apitest.New(t, nil, nil)
+	server.Register(t, "refresh@example.com", "SecurePass123!")
+	ctx := context.Background()
+	user, err := server.DB.Users().GetByEmail(ctx, "refresh@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	w := server.Do(t, http.MethodPost, "/api/v1/auth/login",
+		models.UserLogin{Email: "refresh@example.com", Password: "SecurePass123!"}, "")
+	var login models.LoginResponse
+	apitest.Decode(t, w, &login)
+
 	t.Run("valid refresh token", func(t *testing.T) {
-		t.Skip("Test not implemented - stub only")
+		w := server.Do(t, http.MethodPost, "/api/v1/auth/refresh",
+			models.RefreshTokenRequest{RefreshToken: login.RefreshToken}, "")
+		if w.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+		}
+		var tokens models.TokenPair
+		apitest.Decode(t, w, &tokens)
+		if tokens.AccessToken == "" {
+			t.Error("expected a new access token")
+		}
 	})
-	
+
 	t.Run("expired refresh token", func(t *testing.T) {
-		t.Skip("Test not implemented - stub only")
+		expired, err := utils.GenerateRefreshToken(user.ID, user.Email, user.Role, user.TenantID, false, server.Config.JWT.Secret, -time.Minute)
+		if err != nil {
+			t.Fatal(err)
+		}
+		w := server.Do(t, http.MethodPost, "/api/v1/auth/refresh",
+			models.RefreshTokenRequest{RefreshToken: expired}, "")
+		if w.Code != http.StatusUnauthorized {
+			t.Errorf("expected 401, got %d", w.Code)
+		}
 	})
-	
+
 	t.Run("invalid refresh token", func(t *testing.T) {
-		t.Skip("Test not implemented - stub only")
+		w := server.Do(t, http.MethodPost, "/api/v1/auth/refresh",
+			models.RefreshTokenRequest{RefreshToken: "not-a-token"}, "")
+		if w.Code != http.StatusUnauthorized {
+			t.Errorf("expected 401, got %d", w.Code)
+		}
+	})
+
+	t.Run("inactive user", func(t *testing.T) {
+		user.IsActive = false
+		if err := server.DB.Users().Update(ctx, user); err != nil {
+			t.Fatal(err)
+		}
+		w := server.Do(t, http.MethodPost, "/api/v1/auth/refresh",
+			models.RefreshTokenRequest{RefreshToken: login.RefreshToken}, "")
+		if w.Code != http.StatusForbidden {
+			t.Errorf("expected 403, got %d", w.Code)
+		}
 	})
 }
 
-/*
-To run tests:
-1. Install testify: go get github.com/stretchr/testify
-2. Run: go test ./internal/api/handlers/... -v
-3. Run with coverage: go test ./internal/api/handlers/... -cover
+func TestPasswordResetHandlers(t *testing.T) {
+	server := apitest.New(t, nil, nil)
+	server.Register(t, "reset@example.com", "SecurePass123!")
+	ctx := context.Background()
+
+	// The response does not reveal whether the email is registered
+	for _, email := range []string{"reset@example.com", "nobody@example.com"} {
+		w := server.Do(t, http.MethodPost, "/api/v1/auth/forgot-password", models.ForgotPasswordRequest{Email: email}, "")
+		if w.Code != http.StatusAccepted {
+			t.Errorf("forgot password for %s: expected 202, got %d", email, w.Code)
+		}
+	}
+	if w := server.Do(t, http.MethodPost, "/api/v1/auth/forgot-password", map[string]string{"email": "not-an-email"}, ""); w.Code != http.StatusBadRequest {
+		t.Errorf("forgot password with an invalid email: expected 400, got %d", w.Code)
+	}
+
+	// Reset tokens are not returned or logged, so store a known one
+	user, err := server.DB.Users().GetByEmail(ctx, "reset@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resetToken := "known-reset-token"
+	sum := sha256.Sum256([]byte(resetToken))
+	if err := server.DB.PasswordResets().Create(ctx, &models.PasswordResetToken{
+		ID:        uuid.New(),
+		UserID:    user.ID,
+		TokenHash: hex.EncodeToString(sum[:]),
+		ExpiresAt: time.Now().Add(time.Hour),
+		CreatedAt: time.Now(),
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		req  models.ResetPasswordRequest
+		want int
+	}{
+		{"unknown token", models.ResetPasswordRequest{Token: "not-a-token", NewPassword: "NewSecurePass456!"}, http.StatusBadRequest},
+		{"weak password", models.ResetPasswordRequest{Token: resetToken, NewPassword: "weakpassword"}, http.StatusBadRequest},
+		{"valid", models.ResetPasswordRequest{Token: resetToken, NewPassword: "NewSecurePass456!"}, http.StatusOK},
+		{"reused token", models.ResetPasswordRequest{Token: resetToken, NewPassword: "OtherSecurePass789!"}, http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		if w := server.Do(t, http.MethodPost, "/api/v1/auth/reset-password", tt.req, ""); w.Code != tt.want {
+			t.Errorf("%s: expected %d, got %d: %s", tt.name, tt.want, w.Code, w.Body.String())
+		}
+	}
+
+	if w := server.Do(t, http.MethodPost, "/api/v1/auth/login", models.UserLogin{Email: "reset@example.com", Password: "SecurePass123!"}, ""); w.Code != http.StatusBadRequest {
+		t.Errorf("login with the old password: expected 400, got %d", w.Code)
+	}
+	server.Login(t, "reset@example.com", "NewSecurePass456!")
+}
+
+func TestMFAHandlers(t *testing.T) {
+	server := apitest.New(t, nil, nil)
+	token := server.Register(t, "mfa@example.com", "SecurePass123!")
+
+	w := server.Do(t, http.MethodPost, "/api/v1/profile/2fa/enroll", nil, token)
+	if w.Code != http.StatusOK {
+		t.Fatalf("enroll: expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var enrollment models.MFAEnrollmentResponse
+	apitest.Decode(t, w, &enrollment)
+	if enrollment.Secret == "" || !strings.HasPrefix(enrollment.OTPAuthURI, "otpauth://totp/") {
+		t.Fatalf("expected a secret and otpauth URI, got %+v", enrollment)
+	}
 
-To implement tests:
-1. Create mock services using testify/mock
-2. Set up test fixtures and data
-3. Call handler functions
-4. Assert responses and status codes
-5. Test error cases and edge cases
-*/
+	if w := server.Do(t, http.MethodPost, "/api/v1/profile/2fa/confirm", models.MFACodeRequest{Code: "000000"}, token); w.Code != http.StatusUnauthorized {
+		t.Errorf("confirm with a wrong code: expected 401, got %d", w.Code)
+	}
+	code, err := utils.GenerateTOTPCode(enrollment.Secret, time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	w = server.Do(t, http.MethodPost, "/api/v1/profile/2fa/confirm", models.MFACodeRequest{Code: code}, token)
+	if w.Code != http.StatusOK {
+		t.Fatalf("confirm: expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var confirmation models.MFAConfirmationResponse
+	apitest.Decode(t, w, &confirmation)
+	if len(confirmation.RecoveryCodes) == 0 || confirmation.Tokens == nil {
+		t.Fatalf("expected recovery codes and tokens, got %+v", confirmation)
+	}
 
+	w = server.Do(t, http.MethodPost, "/api/v1/auth/login",
+		models.UserLogin{Email: "mfa@example.com", Password: "SecurePass123!"}, "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("login: expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var login models.LoginResponse
+	apitest.Decode(t, w, &login)
+	if !login.MFARequired || login.ChallengeToken == "" || login.TokenPair != nil {
+		t.Fatalf("login: expected a challenge instead of tokens, got %s", w.Body.String())
+	}
+
+	// The challenge token is not an access token
+	if w := server.Do(t, http.MethodGet, "/api/v1/profile", nil, login.ChallengeToken); w.Code != http.StatusUnauthorized {
+		t.Errorf("challenge as access token: expected 401, got %d", w.Code)
+	}
+	if w := server.Do(t, http.MethodPost, "/api/v1/auth/login/2fa",
+		models.MFALoginRequest{ChallengeToken: login.ChallengeToken, Code: "000000"}, ""); w.Code != http.StatusUnauthorized {
+		t.Errorf("wrong second factor: expected 401, got %d", w.Code)
+	}
+	w = server.Do(t, http.MethodPost, "/api/v1/auth/login/2fa",
+		models.MFALoginRequest{ChallengeToken: login.ChallengeToken, Code: confirmation.RecoveryCodes[0]}, "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("recovery code: expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var tokens models.TokenPair
+	apitest.Decode(t, w, &tokens)
+	if w := server.Do(t, http.MethodGet, "/api/v1/profile", nil, tokens.AccessToken); w.Code != http.StatusOK {
+		t.Errorf("profile after two-factor login: expected 200, got %d", w.Code)
+	}
+}
@@ -0,0 +1,26 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/fraud-detection-system/backend/internal/events"
+	"github.com/fraud-detection-system/backend/internal/utils"
+)
+
+type EventsHandler struct {
+	registry *events.Registry
+}
+
+func NewEventsHandler(registry *events.Registry) *EventsHandler {
+	return &EventsHandler{
+		registry: registry,
+	}
+}
+
+// GetSchemas handles listing every version of every domain event schema
+func (h *EventsHandler) GetSchemas(c *gin.Context) {
+	utils.RespondWithSuccess(c, http.StatusOK, gin.H{
+		"schemas": h.registry.Schemas(),
+	})
+}
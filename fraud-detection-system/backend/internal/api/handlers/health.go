@@ -1,73 +1,58 @@
 package handlers
 
 import (
-	"net/http"
+	"context"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 	"github.com/fraud-detection-system/backend/internal/cache"
-	"github.com/fraud-detection-system/backend/internal/database"
-	"github.com/fraud-detection-system/backend/internal/service"
+	"github.com/fraud-detection-system/backend/internal/health"
+	"github.com/fraud-detection-system/backend/internal/models"
+	"github.com/fraud-detection-system/backend/internal/utils"
 )
 
 type HealthHandler struct {
-	db       *database.Database
-	cache    *cache.RedisCache
-	mlClient *service.MLClient
+	checker *health.Checker
+	cache   cache.Cache
 }
 
-func NewHealthHandler(db *database.Database, cache *cache.RedisCache, mlClient *service.MLClient) *HealthHandler {
+func NewHealthHandler(checker *health.Checker, cache cache.Cache) *HealthHandler {
 	return &HealthHandler{
-		db:       db,
-		cache:    cache,
-		mlClient: mlClient,
+		checker: checker,
+		cache:   cache,
 	}
 }
 
-// HealthCheck handles health check requests
+// HealthCheck handles liveness probes
 func (h *HealthHandler) HealthCheck(c *gin.Context) {
-	c.JSON(http.StatusOK, gin.H{
-		"status":  "healthy",
-		"service": "fraud-detection-api",
-	})
+	c.JSON(h.checker.Live(c.Request.Context()))
 }
 
-// ReadinessCheck handles readiness check requests
+// ReadinessCheck handles readiness probes, reporting the latency of each
+// dependency. Errors are only included for admins.
 func (h *HealthHandler) ReadinessCheck(c *gin.Context) {
-	checks := map[string]string{
-		"database": "healthy",
-		"cache":    "healthy",
-		"ml":       "healthy",
-	}
-
-	// Check database
-	if err := h.db.HealthCheck(); err != nil {
-		checks["database"] = "unhealthy"
-	}
+	h.respond(c, h.checker.Ready)
+}
 
-	// Check cache
-	if err := h.cache.HealthCheck(); err != nil {
-		checks["cache"] = "unhealthy"
-	}
+// StartupCheck handles startup probes
+func (h *HealthHandler) StartupCheck(c *gin.Context) {
+	h.respond(c, h.checker.Startup)
+}
 
-	// Check ML service
-	if err := h.mlClient.HealthCheck(c.Request.Context()); err != nil {
-		checks["ml"] = "unhealthy"
+// respond runs probe and redacts the report unless the caller is an admin
+// whose session is still valid; the probe routes use OptionalAuthMiddleware
+func (h *HealthHandler) respond(c *gin.Context, probe func(context.Context) (int, health.Report)) {
+	code, report := probe(c.Request.Context())
+	if !h.detailed(c) {
+		report = report.Redacted()
 	}
+	c.JSON(code, report)
+}
 
-	// Determine overall status
-	status := "ready"
-	statusCode := http.StatusOK
-	for _, health := range checks {
-		if health == "unhealthy" {
-			status = "not ready"
-			statusCode = http.StatusServiceUnavailable
-			break
-		}
+func (h *HealthHandler) detailed(c *gin.Context) bool {
+	userID, ok := c.Get("user_id")
+	if !ok || c.GetString("user_role") != models.RoleAdmin {
+		return false
 	}
-
-	c.JSON(statusCode, gin.H{
-		"status": status,
-		"checks": checks,
-	})
+	return !utils.SessionRevoked(c.Request.Context(), h.cache, userID.(uuid.UUID), c.GetTime("token_issued_at"))
 }
-
@@ -0,0 +1,95 @@
+package handlers_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/fraud-detection-system/backend/internal/api/apitest"
+	"github.com/fraud-detection-system/backend/internal/health"
+	"github.com/fraud-detection-system/backend/internal/models"
+	"github.com/fraud-detection-system/backend/internal/utils"
+)
+
+func TestReadinessDetails(t *testing.T) {
+	server := apitest.New(t, nil, nil)
+	server.Health.Register("queue", false, func(ctx context.Context) error {
+		return errors.New("dial tcp 10.0.3.7:9092: connection refused")
+	})
+	server.Health.AddInfo("ml_circuit_breakers", func() interface{} {
+		return map[string]string{"default": "closed"}
+	})
+	userToken := server.Token(t, uuid.New(), "user@example.com", models.RoleUser)
+	adminID := uuid.New()
+	adminToken := server.Token(t, adminID, "admin@example.com", models.RoleAdmin)
+
+	for _, path := range []string{"/ready", "/health/ready"} {
+		w := server.Do(t, http.MethodGet, path, nil, "")
+		if w.Code != http.StatusOK {
+			t.Fatalf("%s: expected 200, got %d", path, w.Code)
+		}
+		report := decodeReport(t, w)
+		if report.Status != health.StatusDegraded {
+			t.Errorf("%s: expected degraded, got %s", path, report.Status)
+		}
+		assertRedacted(t, path+" anonymous", report)
+
+		w = server.Do(t, http.MethodGet, path, nil, userToken)
+		assertRedacted(t, path+" user", decodeReport(t, w))
+
+		w = server.Do(t, http.MethodGet, path, nil, adminToken)
+		report = decodeReport(t, w)
+		var found bool
+		for _, check := range report.Checks {
+			if check.Name == "queue" && check.LastError != "" {
+				found = true
+			}
+		}
+		if !found || report.Info == nil {
+			t.Errorf("%s: expected details for an admin, got %+v", path, report)
+		}
+	}
+
+	// A revoked admin session no longer sees details
+	if err := utils.RevokeSessions(context.Background(), server.Cache, adminID, time.Hour); err != nil {
+		t.Fatalf("failed to revoke sessions: %v", err)
+	}
+	w := server.Do(t, http.MethodGet, "/health/ready", nil, adminToken)
+	if w.Code != http.StatusOK {
+		t.Errorf("expected 200 for a revoked session, got %d", w.Code)
+	}
+	assertRedacted(t, "revoked admin", decodeReport(t, w))
+
+	// An invalid token is treated as anonymous, not rejected
+	w = server.Do(t, http.MethodGet, "/health/ready", nil, "not-a-token")
+	if report := decodeReport(t, w); w.Code != http.StatusOK || report.Info != nil {
+		t.Errorf("expected a redacted 200 for an invalid token, got %d %+v", w.Code, report)
+	}
+}
+
+func assertRedacted(t *testing.T, caller string, report health.Report) {
+	t.Helper()
+	for _, check := range report.Checks {
+		if check.LastError != "" {
+			t.Errorf("%s saw error %q for %s", caller, check.LastError, check.Name)
+		}
+	}
+	if report.Info != nil {
+		t.Errorf("%s saw info %v", caller, report.Info)
+	}
+}
+
+// decodeReport unmarshals a probe response, which has no envelope
+func decodeReport(t *testing.T, w *httptest.ResponseRecorder) health.Report {
+	t.Helper()
+	var report health.Report
+	if err := json.Unmarshal(w.Body.Bytes(), &report); err != nil {
+		t.Fatalf("failed to decode report %q: %v", w.Body.String(), err)
+	}
+	return report
+}
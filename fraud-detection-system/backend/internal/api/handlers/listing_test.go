@@ -0,0 +1,187 @@
+package handlers_test
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/fraud-detection-system/backend/internal/api/apitest"
+	"github.com/fraud-detection-system/backend/internal/models"
+)
+
+type historyPage struct {
+	Verifications []models.VerificationResponse `json:"verifications"`
+	Count         int                           `json:"count"`
+	Total         int                           `json:"total"`
+	HasMore       bool                          `json:"has_more"`
+	NextCursor    string                        `json:"next_cursor"`
+}
+
+// seedHistory stores n verifications for the user, one minute apart, with
+// rising fraud scores. Every other one is fraud and came by WhatsApp.
+func seedHistory(t *testing.T, server *apitest.Server, userID uuid.UUID, n int) {
+	t.Helper()
+	ctx := context.Background()
+	base := time.Now().Add(-time.Hour).UTC()
+	for i := 0; i < n; i++ {
+		createdAt := base.Add(time.Duration(i) * time.Minute)
+		messageType := "SMS"
+		if i%2 == 1 {
+			messageType = "WhatsApp"
+		}
+		message := &models.Message{ID: uuid.New(), UserID: &userID, Content: "seeded", SenderHeader: "VM-BANK", MessageType: messageType, CreatedAt: createdAt}
+		if err := server.DB.Messages().Create(ctx, message); err != nil {
+			t.Fatal(err)
+		}
+		verification := &models.Verification{
+			ID:         uuid.New(),
+			MessageID:  message.ID,
+			UserID:     &userID,
+			IsFraud:    i%2 == 1,
+			FraudScore: float64(i) / float64(n),
+			CreatedAt:  createdAt,
+		}
+		if err := server.DB.Verifications().Create(ctx, verification); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestVerificationHistoryPagination(t *testing.T) {
+	server := apitest.New(t, nil, nil)
+	token := server.Register(t, "history@example.com", "SecurePass123!")
+	user, err := server.DB.Users().GetByEmail(context.Background(), "history@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	seedHistory(t, server, user.ID, 10)
+
+	get := func(query url.Values) historyPage {
+		t.Helper()
+		w := server.Do(t, http.MethodGet, "/api/v1/verify/history?"+query.Encode(), nil, token)
+		if w.Code != http.StatusOK {
+			t.Fatalf("history %s: expected 200, got %d: %s", query.Encode(), w.Code, w.Body.String())
+		}
+		var page historyPage
+		apitest.Decode(t, w, &page)
+		return page
+	}
+
+	// Walking the cursors visits every verification once, newest first
+	seen := make(map[uuid.UUID]bool)
+	var last time.Time
+	query := url.Values{"limit": {"3"}}
+	for pages := 0; ; pages++ {
+		if pages > 4 {
+			t.Fatal("cursor walk did not terminate")
+		}
+		page := get(query)
+		if page.Total != 10 {
+			t.Errorf("expected total 10, got %d", page.Total)
+		}
+		for _, v := range page.Verifications {
+			if seen[v.ID] {
+				t.Errorf("verification %s returned twice", v.ID)
+			}
+			seen[v.ID] = true
+			if !last.IsZero() && v.VerifiedAt.After(last) {
+				t.Errorf("expected newest first, got %s after %s", v.VerifiedAt, last)
+			}
+			last = v.VerifiedAt
+		}
+		if !page.HasMore {
+			break
+		}
+		query.Set("cursor", page.NextCursor)
+	}
+	if len(seen) != 10 {
+		t.Errorf("expected to see 10 verifications, saw %d", len(seen))
+	}
+
+	page := get(url.Values{"is_fraud": {"true"}, "message_type": {"WhatsApp"}})
+	if page.Total != 5 || page.HasMore {
+		t.Errorf("expected 5 WhatsApp frauds on one page, got total %d has_more %v", page.Total, page.HasMore)
+	}
+	for _, v := range page.Verifications {
+		if !v.IsFraud {
+			t.Errorf("expected only fraud, got %+v", v)
+		}
+	}
+
+	page = get(url.Values{"risk_level": {"HIGH"}, "sort": {"fraud_score"}, "order": {"asc"}})
+	if page.Total != 2 || len(page.Verifications) != 2 {
+		t.Fatalf("expected scores 0.6 and 0.7 to be HIGH, got %+v", page)
+	}
+	if page.Verifications[0].FraudScore > page.Verifications[1].FraudScore {
+		t.Errorf("expected ascending scores, got %v then %v", page.Verifications[0].FraudScore, page.Verifications[1].FraudScore)
+	}
+
+	first := get(url.Values{"limit": {"2"}})
+	for name, query := range map[string]url.Values{
+		"garbage cursor":     {"cursor": {"not-a-cursor"}},
+		"cursor of sort":     {"cursor": {first.NextCursor}, "sort": {"fraud_score"}},
+		"cursor of order":    {"cursor": {first.NextCursor}, "order": {"asc"}},
+		"unknown sort":       {"sort": {"content"}},
+		"unknown risk level": {"risk_level": {"SEVERE"}},
+		"bad from":           {"from": {"yesterday"}},
+		"offset":             {"offset": {"2"}},
+		"offset with cursor": {"offset": {"0"}, "cursor": {first.NextCursor}},
+	} {
+		w := server.Do(t, http.MethodGet, "/api/v1/verify/history?"+query.Encode(), nil, token)
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("%s: expected 400, got %d: %s", name, w.Code, w.Body.String())
+		}
+	}
+}
+
+func TestUserReportsFilter(t *testing.T) {
+	server := apitest.New(t, nil, nil)
+	token := server.Register(t, "reports@example.com", "SecurePass123!")
+	user, err := server.DB.Users().GetByEmail(context.Background(), "reports@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now().UTC()
+	for i, status := range []string{models.ReportStatusPending, models.ReportStatusResolved, models.ReportStatusPending} {
+		report := &models.Report{
+			ID:         uuid.New(),
+			UserID:     &user.ID,
+			ReportType: "FRAUD",
+			Content:    "seeded",
+			Status:     status,
+			Priority:   "MEDIUM",
+			CreatedAt:  now.Add(time.Duration(i-3) * time.Hour),
+			UpdatedAt:  now,
+		}
+		if err := server.DB.Reports().Create(context.Background(), report); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	from := now.Add(-150 * time.Minute).Format(time.RFC3339)
+	w := server.Do(t, http.MethodGet, "/api/v1/reports?status=PENDING&from="+url.QueryEscape(from), nil, token)
+	if w.Code != http.StatusOK {
+		t.Fatalf("reports: expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var page struct {
+		Reports []models.ReportResponse `json:"reports"`
+		Total   int                     `json:"total"`
+	}
+	apitest.Decode(t, w, &page)
+	if page.Total != 1 || len(page.Reports) != 1 || page.Reports[0].Status != models.ReportStatusPending {
+		t.Errorf("expected the one recent pending report, got %+v", page)
+	}
+
+	w = server.Do(t, http.MethodGet, "/api/v1/reports?status=OPEN", nil, token)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("unknown status: expected 400, got %d", w.Code)
+	}
+
+	w = server.Do(t, http.MethodGet, "/api/v1/reports?offset=1", nil, token)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("offset: expected 400, got %d", w.Code)
+	}
+}
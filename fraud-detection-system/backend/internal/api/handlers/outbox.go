@@ -0,0 +1,32 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/fraud-detection-system/backend/internal/repository"
+	"github.com/fraud-detection-system/backend/internal/utils"
+)
+
+type OutboxHandler struct {
+	outboxRepo repository.OutboxStore
+}
+
+func NewOutboxHandler(outboxRepo repository.OutboxStore) *OutboxHandler {
+	return &OutboxHandler{
+		outboxRepo: outboxRepo,
+	}
+}
+
+// GetStats handles getting how far the outbox processor is behind
+func (h *OutboxHandler) GetStats(c *gin.Context) {
+	stats, err := h.outboxRepo.GetStats(c.Request.Context())
+	if err != nil {
+		utils.HandleError(c, err)
+		return
+	}
+
+	utils.RespondWithSuccess(c, http.StatusOK, gin.H{
+		"streams": stats,
+	})
+}
@@ -0,0 +1,95 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/fraud-detection-system/backend/internal/models"
+	"github.com/fraud-detection-system/backend/internal/service"
+	"github.com/fraud-detection-system/backend/internal/utils"
+)
+
+// RegistryHandler lets admins maintain the RBI circulars and the sender
+// registry that verifications are checked against
+type RegistryHandler struct {
+	rbiService    *service.RBIComplianceService
+	headerService *service.HeaderVerificationService
+}
+
+func NewRegistryHandler(rbiService *service.RBIComplianceService, headerService *service.HeaderVerificationService) *RegistryHandler {
+	return &RegistryHandler{
+		rbiService:    rbiService,
+		headerService: headerService,
+	}
+}
+
+// AddCircular handles recording a new RBI circular
+func (h *RegistryHandler) AddCircular(c *gin.Context) {
+	var req models.CircularInput
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.RespondWithError(c, http.StatusBadRequest, utils.ErrBadRequest, err.Error())
+		return
+	}
+
+	now := time.Now()
+	keywords := make([]string, len(req.Keywords))
+	for i, keyword := range req.Keywords {
+		keywords[i] = strings.ToLower(strings.TrimSpace(keyword))
+	}
+	circular := &models.RBICircular{
+		ID:             uuid.New(),
+		CircularNumber: strings.TrimSpace(req.CircularNumber),
+		Title:          req.Title,
+		Content:        req.Content,
+		IssuedDate:     req.IssuedDate,
+		EffectiveDate:  req.EffectiveDate,
+		ExpiryDate:     req.ExpiryDate,
+		Category:       req.Category,
+		Keywords:       keywords,
+		IsActive:       true,
+		SourceURL:      req.SourceURL,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+	if err := h.rbiService.AddCircular(c.Request.Context(), circular); err != nil {
+		utils.HandleError(c, err)
+		return
+	}
+
+	utils.RespondWithSuccess(c, http.StatusCreated, circular)
+}
+
+// RegisterSender handles adding a verified sender to the registry
+func (h *RegistryHandler) RegisterSender(c *gin.Context) {
+	var req models.SenderInput
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.RespondWithError(c, http.StatusBadRequest, utils.ErrBadRequest, err.Error())
+		return
+	}
+
+	now := time.Now()
+	sender := &models.SenderRegistry{
+		ID:               uuid.New(),
+		SenderID:         strings.TrimSpace(req.SenderID),
+		BankName:         req.BankName,
+		BankCode:         req.BankCode,
+		IsVerified:       true,
+		IsActive:         true,
+		VerifiedBy:       req.VerifiedBy,
+		TelecomOperator:  req.TelecomOperator,
+		RegistrationDate: now,
+		LastVerifiedAt:   &now,
+		ReputationScore:  1.0,
+		CreatedAt:        now,
+		UpdatedAt:        now,
+	}
+	if err := h.headerService.RegisterSender(c.Request.Context(), sender); err != nil {
+		utils.HandleError(c, err)
+		return
+	}
+
+	utils.RespondWithSuccess(c, http.StatusCreated, sender)
+}
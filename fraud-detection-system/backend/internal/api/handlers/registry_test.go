@@ -0,0 +1,104 @@
+package handlers_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/fraud-detection-system/backend/internal/api/apitest"
+	"github.com/fraud-detection-system/backend/internal/events"
+	"github.com/fraud-detection-system/backend/internal/models"
+)
+
+func TestRegistry(t *testing.T) {
+	server := apitest.New(t, nil, nil)
+	ctx := context.Background()
+	userToken := server.Register(t, "user@example.com", "SecurePass123!")
+	user, err := server.DB.Users().GetByEmail(ctx, "user@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	adminToken := server.Token(t, user.ID, user.Email, models.RoleAdmin)
+
+	circular := map[string]interface{}{
+		"circular_number": "RBI/2026-27/42",
+		"title":           "Reporting of fraudulent loan apps",
+		"content":         "Regulated entities shall not lend through unregistered digital lending apps",
+		"issued_date":     "2026-10-01T00:00:00Z",
+		"category":        "Digital Lending",
+		"keywords":        []string{"Loan App", "digital lending"},
+	}
+	sender := map[string]interface{}{
+		"sender_id":   "VM-TESTBK",
+		"bank_name":   "Test Bank",
+		"bank_code":   "TESTBK",
+		"verified_by": "BANK",
+	}
+
+	for _, path := range []string{"/api/v1/admin/circulars", "/api/v1/admin/senders"} {
+		if w := server.Do(t, http.MethodPost, path, circular, userToken); w.Code != http.StatusForbidden {
+			t.Errorf("%s as user: expected 403, got %d", path, w.Code)
+		}
+		if w := server.Do(t, http.MethodPost, path, map[string]string{}, adminToken); w.Code != http.StatusBadRequest {
+			t.Errorf("%s without fields: expected 400, got %d", path, w.Code)
+		}
+	}
+
+	for path, body := range map[string]interface{}{
+		"/api/v1/admin/circulars": circular,
+		"/api/v1/admin/senders":   sender,
+	} {
+		if w := server.Do(t, http.MethodPost, path, body, adminToken); w.Code != http.StatusCreated {
+			t.Fatalf("%s: expected 201, got %d: %s", path, w.Code, w.Body.String())
+		}
+		if w := server.Do(t, http.MethodPost, path, body, adminToken); w.Code != http.StatusConflict {
+			t.Errorf("%s again: expected 409, got %d: %s", path, w.Code, w.Body.String())
+		}
+	}
+
+	circulars, err := server.DB.RBI().SearchCircularsByKeywords(ctx, []string{"loan app"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(circulars) != 1 || !circulars[0].IsActive {
+		t.Errorf("expected the active circular to match its keyword, got %+v", circulars)
+	}
+	registered, err := server.DB.RBI().GetSenderBySenderID(ctx, "VM-TESTBK")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !registered.IsVerified || registered.BankCode != "TESTBK" {
+		t.Errorf("expected a verified sender, got %+v", registered)
+	}
+
+	// Each registration queues exactly one domain event, not the duplicates
+	entries, err := server.DB.Outbox().GetPending(ctx, models.OutboxKindKafka, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := map[models.MessageType]int{}
+	for _, entry := range entries {
+		if entry.Topic == nil || *entry.Topic != server.Config.Kafka.TopicEvents {
+			t.Errorf("expected the events topic, got %v", entry.Topic)
+		}
+		var message models.QueueMessage
+		if err := json.Unmarshal([]byte(entry.Payload), &message); err != nil {
+			t.Fatal(err)
+		}
+		got[message.Type]++
+		switch message.Type {
+		case events.TypeSenderReputationChanged:
+			if message.Payload["sender_id"] != "VM-TESTBK" || message.Payload["reason"] != events.ReputationReasonRegistered {
+				t.Errorf("expected the registered sender, got %v", message.Payload)
+			}
+		case events.TypeCircularAdded:
+			if message.Payload["circular_number"] != "RBI/2026-27/42" || message.Payload["is_active"] != true {
+				t.Errorf("expected the active circular, got %v", message.Payload)
+			}
+		}
+	}
+	if got[events.TypeCircularAdded] != 1 || got[events.TypeSenderReputationChanged] != 1 {
+		t.Errorf("expected one circular.added and one sender.reputation_changed event, got %v", got)
+	}
+}
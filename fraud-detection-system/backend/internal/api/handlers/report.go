@@ -2,23 +2,25 @@ package handlers
 
 import (
 	"net/http"
-	"strconv"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"github.com/fraud-detection-system/backend/internal/models"
 	"github.com/fraud-detection-system/backend/internal/repository"
+	"github.com/fraud-detection-system/backend/internal/service"
 	"github.com/fraud-detection-system/backend/internal/utils"
 )
 
 type ReportHandler struct {
-	reportRepo *repository.ReportRepository
+	reportService *service.ReportService
+	reportRepo    repository.ReportStore
 }
 
-func NewReportHandler(reportRepo *repository.ReportRepository) *ReportHandler {
+func NewReportHandler(reportService *service.ReportService, reportRepo repository.ReportStore) *ReportHandler {
 	return &ReportHandler{
-		reportRepo: reportRepo,
+		reportService: reportService,
+		reportRepo:    reportRepo,
 	}
 }
 
@@ -58,7 +60,7 @@ func (h *ReportHandler) CreateReport(c *gin.Context) {
 		UpdatedAt:      time.Now(),
 	}
 
-	if err := h.reportRepo.Create(c.Request.Context(), report); err != nil {
+	if err := h.reportService.CreateReport(c.Request.Context(), report); err != nil {
 		utils.HandleError(c, err)
 		return
 	}
@@ -77,6 +79,48 @@ func (h *ReportHandler) CreateReport(c *gin.Context) {
 	utils.RespondWithSuccess(c, http.StatusCreated, response)
 }
 
+// ReviewReport handles recording a reviewer's decision on a report
+func (h *ReportHandler) ReviewReport(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		utils.RespondWithError(c, http.StatusUnauthorized, utils.ErrUnauthorized, "User not authenticated")
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.RespondWithError(c, http.StatusBadRequest, utils.ErrBadRequest, "Invalid report ID")
+		return
+	}
+
+	var req models.ReportReviewInput
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.RespondWithError(c, http.StatusBadRequest, utils.ErrBadRequest, err.Error())
+		return
+	}
+
+	report, err := h.reportService.ReviewReport(c.Request.Context(), id, userID.(uuid.UUID), &req)
+	if err != nil {
+		utils.HandleError(c, err)
+		return
+	}
+
+	response := &models.ReportResponse{
+		ID:           report.ID,
+		ReportType:   report.ReportType,
+		Content:      report.Content,
+		SenderHeader: report.SenderHeader,
+		Description:  report.Description,
+		Status:       report.Status,
+		Priority:     report.Priority,
+		ReviewedAt:   report.ReviewedAt,
+		ReviewNotes:  report.ReviewNotes,
+		CreatedAt:    report.CreatedAt,
+	}
+
+	utils.RespondWithSuccess(c, http.StatusOK, response)
+}
+
 // GetReport handles getting a report by ID
 func (h *ReportHandler) GetReport(c *gin.Context) {
 	idStr := c.Param("id")
@@ -116,15 +160,17 @@ func (h *ReportHandler) GetUserReports(c *gin.Context) {
 		return
 	}
 
-	// Parse pagination parameters
-	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
-	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
-
-	if limit > 100 {
-		limit = 100
+	var query models.ReportListQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		utils.RespondWithError(c, http.StatusBadRequest, utils.ErrBadRequest, err.Error())
+		return
+	}
+	if query.Offset != nil {
+		utils.RespondWithError(c, http.StatusBadRequest, utils.ErrBadRequest, "offset is not supported, use the next_cursor of the previous page")
+		return
 	}
 
-	reports, err := h.reportRepo.GetByUserID(c.Request.Context(), userID.(uuid.UUID), limit, offset)
+	reports, page, err := h.reportRepo.ListByUserID(c.Request.Context(), userID.(uuid.UUID), query.Filter(), query.Options())
 	if err != nil {
 		utils.HandleError(c, err)
 		return
@@ -147,10 +193,12 @@ func (h *ReportHandler) GetUserReports(c *gin.Context) {
 	}
 
 	utils.RespondWithSuccess(c, http.StatusOK, gin.H{
-		"reports": responses,
-		"limit":   limit,
-		"offset":  offset,
-		"count":   len(responses),
+		"reports":     responses,
+		"limit":       page.Limit,
+		"count":       page.Count,
+		"total":       page.Total,
+		"has_more":    page.HasMore,
+		"next_cursor": page.NextCursor,
 	})
 }
 
@@ -164,4 +212,3 @@ func (h *ReportHandler) GetReportStats(c *gin.Context) {
 
 	utils.RespondWithSuccess(c, http.StatusOK, stats)
 }
-
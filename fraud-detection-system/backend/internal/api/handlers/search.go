@@ -0,0 +1,53 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/fraud-detection-system/backend/internal/models"
+	"github.com/fraud-detection-system/backend/internal/service"
+	"github.com/fraud-detection-system/backend/internal/utils"
+)
+
+type SearchHandler struct {
+	searchService *service.SearchService
+}
+
+func NewSearchHandler(searchService *service.SearchService) *SearchHandler {
+	return &SearchHandler{
+		searchService: searchService,
+	}
+}
+
+// SearchMessages handles searching the tenant's messages, or every
+// tenant's for admins
+func (h *SearchHandler) SearchMessages(c *gin.Context) {
+	var query models.MessageSearchQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		utils.RespondWithError(c, http.StatusBadRequest, utils.ErrBadRequest, err.Error())
+		return
+	}
+	if query.AllTenants && c.GetString("user_role") != models.RoleAdmin {
+		utils.RespondWithError(c, http.StatusForbidden, utils.ErrForbidden, "Only admins can search every tenant")
+		return
+	}
+	search, err := query.Search(c.GetString("tenant_id"))
+	if err != nil {
+		utils.RespondWithError(c, http.StatusBadRequest, utils.ErrBadRequest, err.Error())
+		return
+	}
+
+	hits, total, err := h.searchService.SearchMessages(c.Request.Context(), search)
+	if err != nil {
+		utils.HandleError(c, err)
+		return
+	}
+
+	utils.RespondWithSuccess(c, http.StatusOK, gin.H{
+		"results": hits,
+		"limit":   search.Limit,
+		"offset":  search.Offset,
+		"count":   len(hits),
+		"total":   total,
+	})
+}
@@ -0,0 +1,181 @@
+package handlers_test
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/fraud-detection-system/backend/internal/api/apitest"
+	"github.com/fraud-detection-system/backend/internal/api/middleware"
+	"github.com/fraud-detection-system/backend/internal/models"
+)
+
+func TestSearchMessages(t *testing.T) {
+	server := apitest.New(t, nil, nil)
+	ctx := context.Background()
+	phone := "9876543210"
+	seed := []struct {
+		message models.Message
+		isFraud bool
+	}{
+		{models.Message{
+			Content:       "Dear customer, your account will be blocked today. Update KYC at http://bit.ly/kyc-now or call 9876543210",
+			SenderHeader:  "VM-HDFCBK",
+			MessageType:   "SMS",
+			ExtractedURLs: []string{"http://bit.ly/kyc-now"},
+			PhoneNumber:   &phone,
+		}, true},
+		{models.Message{
+			Content:      "Your account statement for March is ready. Queries: +91 8765432109",
+			SenderHeader: "AD-ICICIB",
+			MessageType:  "Email",
+		}, false},
+		{models.Message{
+			Content:      "Your account will be blocked, pay now",
+			SenderHeader: "VM-HDFCBK",
+			MessageType:  "SMS",
+			TenantID:     "other-bank",
+		}, true},
+	}
+	for i, s := range seed {
+		message := s.message
+		message.ID = uuid.New()
+		message.CreatedAt = time.Now().Add(time.Duration(i) * time.Minute)
+		if err := server.DB.Messages().Create(ctx, &message); err != nil {
+			t.Fatal(err)
+		}
+		verification := &models.Verification{ID: uuid.New(), MessageID: message.ID, IsFraud: s.isFraud, FraudScore: 0.9, TenantID: message.TenantID}
+		if !s.isFraud {
+			verification.FraudScore = 0.1
+		}
+		if err := server.DB.Verifications().Create(ctx, verification); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	userToken := server.Register(t, "user@example.com", "SecurePass123!")
+	analyst, err := server.DB.Users().GetByEmail(ctx, "user@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	analystToken := server.Token(t, analyst.ID, analyst.Email, models.RoleAnalyst)
+
+	w := server.Do(t, http.MethodGet, "/api/v1/search/messages?q=account", nil, userToken)
+	if w.Code != http.StatusForbidden {
+		t.Errorf("user: expected 403, got %d", w.Code)
+	}
+
+	type searchPage struct {
+		Results []models.MessageSearchHit `json:"results"`
+		Total   int                       `json:"total"`
+	}
+	search := func(query url.Values) searchPage {
+		t.Helper()
+		w := server.Do(t, http.MethodGet, "/api/v1/search/messages?"+query.Encode(), nil, analystToken)
+		if w.Code != http.StatusOK {
+			t.Fatalf("search %s: expected 200, got %d: %s", query.Encode(), w.Code, w.Body.String())
+		}
+		var page searchPage
+		apitest.Decode(t, w, &page)
+		return page
+	}
+	highlight := func(hit models.MessageSearchHit, field string) string {
+		for _, h := range hit.Highlights {
+			if h.Field == field {
+				return strings.Join(h.Fragments, " ")
+			}
+		}
+		return ""
+	}
+
+	page := search(url.Values{"q": {`"account will be blocked"`}})
+	if page.Total != 1 || len(page.Results) != 1 {
+		t.Fatalf("phrase: expected the one blocked-account message of this tenant, got %+v", page)
+	}
+	hit := page.Results[0]
+	if got := highlight(hit, models.SearchFieldContent); !strings.Contains(got, "<mark>account will be blocked</mark>") {
+		t.Errorf("expected the phrase to be highlighted, got %q", got)
+	}
+	if hit.IsFraud == nil || !*hit.IsFraud || hit.RiskLevel != models.RiskLevelCritical {
+		t.Errorf("expected the fraud verdict with the hit, got %+v", hit)
+	}
+
+	page = search(url.Values{"q": {`refund OR "account will be blocked"`}})
+	if page.Total != 1 {
+		t.Errorf("or: expected the blocked-account message, got %+v", page)
+	}
+	if w := server.Do(t, http.MethodGet, "/api/v1/search/messages?"+url.Values{"q": {"kyc or -otp"}}.Encode(), nil, analystToken); w.Code != http.StatusBadRequest {
+		t.Errorf("or with an exclusion: expected 400, got %d", w.Code)
+	}
+
+	page = search(url.Values{"q": {"account"}, "is_fraud": {"false"}})
+	if page.Total != 1 || page.Results[0].SenderHeader != "AD-ICICIB" {
+		t.Errorf("is_fraud filter: expected only the statement, got %+v", page)
+	}
+
+	page = search(url.Values{"q": {"bit.ly/kyc"}, "fields": {"url"}})
+	if page.Total != 1 || !strings.Contains(highlight(page.Results[0], models.SearchFieldURL), "<mark>bit.ly/kyc</mark>") {
+		t.Errorf("url: expected a highlighted URL match, got %+v", page)
+	}
+
+	page = search(url.Values{"q": {"hdfc"}, "fields": {"sender"}})
+	if page.Total != 1 || highlight(page.Results[0], models.SearchFieldSender) != "VM-<mark>HDFC</mark>BK" {
+		t.Errorf("sender: expected a highlighted sender match, got %+v", page)
+	}
+
+	page = search(url.Values{"q": {phone}, "fields": {"phone"}})
+	if page.Total != 1 || highlight(page.Results[0], models.SearchFieldPhone) != "<mark>"+phone+"</mark>" {
+		t.Errorf("phone: expected a highlighted phone match, got %+v", page)
+	}
+	page = search(url.Values{"q": {"+91 98765 43210"}, "fields": {"phone"}})
+	if page.Total != 1 || page.Results[0].SenderHeader != "VM-HDFCBK" {
+		t.Errorf("phone written differently: expected one match, got %+v", page)
+	}
+	// Phone numbers are redacted from the indexed content, but found by
+	// their hashes
+	page = search(url.Values{"q": {"8765432109"}, "fields": {"content"}})
+	if page.Total != 0 {
+		t.Errorf("expected phone numbers not to be in the content index, got %+v", page)
+	}
+	page = search(url.Values{"q": {"08765432109"}, "fields": {"phone"}})
+	if page.Total != 1 || page.Results[0].SenderHeader != "AD-ICICIB" {
+		t.Errorf("phone in content: expected the statement, got %+v", page)
+	}
+
+	// Other tenants' messages are only for admins, who must ask for them
+	phrase := url.Values{"q": {`"account will be blocked"`}}
+	otherTenant := http.Header{middleware.TenantHeader: {"other-bank"}}
+	if w := server.DoWithHeaders(t, http.MethodGet, "/api/v1/search/messages?"+phrase.Encode(), nil, analystToken, otherTenant); w.Code != http.StatusForbidden {
+		t.Errorf("analyst in another tenant: expected 403, got %d", w.Code)
+	}
+	allTenants := url.Values{"q": {`"account will be blocked"`}, "all_tenants": {"true"}}
+	if w := server.Do(t, http.MethodGet, "/api/v1/search/messages?"+allTenants.Encode(), nil, analystToken); w.Code != http.StatusForbidden {
+		t.Errorf("analyst across tenants: expected 403, got %d", w.Code)
+	}
+	adminToken := server.Token(t, analyst.ID, analyst.Email, models.RoleAdmin)
+	w = server.Do(t, http.MethodGet, "/api/v1/search/messages?"+allTenants.Encode(), nil, adminToken)
+	apitest.Decode(t, w, &page)
+	if page.Total != 2 {
+		t.Errorf("admin across tenants: expected both tenants' matches, got %+v", page)
+	}
+	w = server.DoWithHeaders(t, http.MethodGet, "/api/v1/search/messages?"+phrase.Encode(), nil, adminToken, otherTenant)
+	apitest.Decode(t, w, &page)
+	if page.Total != 1 || page.Results[0].TenantID != "other-bank" {
+		t.Errorf("admin in another tenant: expected its one match, got %+v", page)
+	}
+
+	for name, query := range map[string]url.Values{
+		"missing query": {"fields": {"content"}},
+		"unknown field": {"q": {"kyc"}, "fields": {"body"}},
+		"no words":      {"q": {`""`}},
+	} {
+		w := server.Do(t, http.MethodGet, "/api/v1/search/messages?"+query.Encode(), nil, analystToken)
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("%s: expected 400, got %d: %s", name, w.Code, w.Body.String())
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package handlers_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/fraud-detection-system/backend/internal/api/apitest"
+	"github.com/fraud-detection-system/backend/internal/api/middleware"
+	"github.com/fraud-detection-system/backend/internal/models"
+)
+
+func TestTenantFromPrincipal(t *testing.T) {
+	server := apitest.New(t, nil, nil)
+	ctx := context.Background()
+	server.Register(t, "user@example.com", "SecurePass123!")
+	user, err := server.DB.Users().GetByEmail(ctx, "user@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	acmeToken := server.TenantToken(t, user.ID, user.Email, models.RoleAnalyst, "acme")
+	adminToken := server.Token(t, user.ID, user.Email, models.RoleAdmin)
+	request := models.VerificationRequest{Content: "Your parcel is on its way", SenderHeader: "AD-PARCEL"}
+
+	verify := func(token, tenantHeader string) (int, string) {
+		t.Helper()
+		header := http.Header{}
+		if tenantHeader != "" {
+			header.Set(middleware.TenantHeader, tenantHeader)
+		}
+		w := server.DoWithHeaders(t, http.MethodPost, "/api/v1/verify", request, token, header)
+		if w.Code != http.StatusOK {
+			return w.Code, ""
+		}
+		var result models.VerificationResponse
+		apitest.Decode(t, w, &result)
+		verification, err := server.DB.Verifications().GetByID(ctx, result.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return w.Code, verification.TenantID
+	}
+
+	tests := []struct {
+		name       string
+		token      string
+		header     string
+		wantCode   int
+		wantTenant string
+	}{
+		{"user tenant", acmeToken, "", http.StatusOK, "acme"},
+		{"matching header", acmeToken, "acme", http.StatusOK, "acme"},
+		{"other tenant", acmeToken, "globex", http.StatusForbidden, ""},
+		{"anonymous", "", "", http.StatusOK, models.DefaultTenantID},
+		{"anonymous with tenant", "", "acme", http.StatusForbidden, ""},
+		{"admin acting in tenant", adminToken, "globex", http.StatusOK, "globex"},
+		{"invalid header", adminToken, "not a tenant", http.StatusBadRequest, ""},
+	}
+	for _, tt := range tests {
+		code, tenant := verify(tt.token, tt.header)
+		if code != tt.wantCode || tenant != tt.wantTenant {
+			t.Errorf("%s: got %d in tenant %q, want %d in %q", tt.name, code, tenant, tt.wantCode, tt.wantTenant)
+		}
+	}
+}
@@ -2,7 +2,7 @@ package handlers
 
 import (
 	"net/http"
-	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -35,6 +35,7 @@ func (h *VerificationHandler) VerifyMessage(c *gin.Context) {
 		id := uid.(uuid.UUID)
 		userID = &id
 	}
+	req.TenantID = c.GetString("tenant_id")
 
 	result, err := h.verificationService.VerifyMessage(c.Request.Context(), &req, userID)
 	if err != nil {
@@ -71,15 +72,17 @@ func (h *VerificationHandler) GetVerificationHistory(c *gin.Context) {
 		return
 	}
 
-	// Parse pagination parameters
-	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
-	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
-
-	if limit > 100 {
-		limit = 100
+	var query models.VerificationHistoryQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		utils.RespondWithError(c, http.StatusBadRequest, utils.ErrBadRequest, err.Error())
+		return
+	}
+	if query.Offset != nil {
+		utils.RespondWithError(c, http.StatusBadRequest, utils.ErrBadRequest, "offset is not supported, use the next_cursor of the previous page")
+		return
 	}
 
-	results, err := h.verificationService.GetVerificationHistory(c.Request.Context(), userID.(uuid.UUID), limit, offset)
+	results, page, err := h.verificationService.GetVerificationHistory(c.Request.Context(), userID.(uuid.UUID), query.Filter(), query.Options())
 	if err != nil {
 		utils.HandleError(c, err)
 		return
@@ -87,9 +90,11 @@ func (h *VerificationHandler) GetVerificationHistory(c *gin.Context) {
 
 	utils.RespondWithSuccess(c, http.StatusOK, gin.H{
 		"verifications": results,
-		"limit":         limit,
-		"offset":        offset,
-		"count":         len(results),
+		"limit":         page.Limit,
+		"count":         page.Count,
+		"total":         page.Total,
+		"has_more":      page.HasMore,
+		"next_cursor":   page.NextCursor,
 	})
 }
 
@@ -110,3 +115,26 @@ func (h *VerificationHandler) GetStats(c *gin.Context) {
 	utils.RespondWithSuccess(c, http.StatusOK, stats)
 }
 
+// GetModelComparison handles comparing champion and challenger predictions
+func (h *VerificationHandler) GetModelComparison(c *gin.Context) {
+	since := time.Now().Add(-24 * time.Hour)
+	if sinceStr := c.Query("since"); sinceStr != "" {
+		parsed, err := time.Parse(time.RFC3339, sinceStr)
+		if err != nil {
+			utils.RespondWithError(c, http.StatusBadRequest, utils.ErrBadRequest, "Invalid since, expected RFC3339 timestamp")
+			return
+		}
+		since = parsed
+	}
+
+	comparisons, err := h.verificationService.GetModelComparison(c.Request.Context(), since)
+	if err != nil {
+		utils.HandleError(c, err)
+		return
+	}
+
+	utils.RespondWithSuccess(c, http.StatusOK, gin.H{
+		"since":       since,
+		"comparisons": comparisons,
+	})
+}
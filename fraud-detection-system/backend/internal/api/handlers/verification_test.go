@@ -0,0 +1,63 @@
+package handlers_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/fraud-detection-system/backend/internal/api/apitest"
+	"github.com/fraud-detection-system/backend/internal/models"
+)
+
+func TestVerifyMessageFlow(t *testing.T) {
+	server := apitest.New(t, nil, nil)
+	token := server.Register(t, "verify@example.com", "SecurePass123!")
+
+	w := server.Do(t, http.MethodPost, "/api/v1/verify", models.VerificationRequest{
+		Content:      "URGENT: your KYC is pending, update now at http://bit.ly/kyc-update or your account will be blocked",
+		SenderHeader: "VM-ALERTS",
+	}, token)
+	if w.Code != http.StatusOK {
+		t.Fatalf("verify: expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var result models.VerificationResponse
+	apitest.Decode(t, w, &result)
+	if !result.IsFraud {
+		t.Errorf("expected KYC phishing message to be flagged, got score %.2f", result.FraudScore)
+	}
+	if server.ML.Calls() != 1 {
+		t.Errorf("expected one call to the ML server, got %d", server.ML.Calls())
+	}
+
+	w = server.Do(t, http.MethodGet, "/api/v1/verify/"+result.ID.String(), nil, token)
+	if w.Code != http.StatusOK {
+		t.Errorf("get verification: expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	w = server.Do(t, http.MethodGet, "/api/v1/verify/history", nil, token)
+	if w.Code != http.StatusOK {
+		t.Fatalf("history: expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var history struct {
+		Count int `json:"count"`
+	}
+	apitest.Decode(t, w, &history)
+	if history.Count != 1 {
+		t.Errorf("expected one verification in history, got %d", history.Count)
+	}
+
+	w = server.Do(t, http.MethodDelete, "/api/v1/profile", models.DeleteAccountRequest{Password: "SecurePass123!"}, token)
+	if w.Code != http.StatusOK {
+		t.Fatalf("delete account: expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if _, err := server.DB.Users().GetByEmail(context.Background(), "verify@example.com"); err == nil {
+		t.Error("expected user to be deleted")
+	}
+	message, err := server.DB.Messages().GetByID(context.Background(), result.MessageID)
+	if err != nil {
+		t.Fatalf("expected anonymized message to be kept: %v", err)
+	}
+	if message.Content != "[deleted]" || message.UserID != nil {
+		t.Errorf("expected message to be anonymized, got %+v", message)
+	}
+}
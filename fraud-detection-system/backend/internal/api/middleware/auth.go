@@ -5,7 +5,10 @@ import (
 	"strings"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/fraud-detection-system/backend/internal/cache"
 	"github.com/fraud-detection-system/backend/internal/config"
+	"github.com/fraud-detection-system/backend/internal/models"
 	"github.com/fraud-detection-system/backend/internal/utils"
 )
 
@@ -38,8 +41,7 @@ func AuthMiddleware(cfg *config.Config) gin.HandlerFunc {
 		}
 
 		// Set user info in context
-		c.Set("user_id", claims.UserID)
-		c.Set("user_email", claims.Email)
+		setPrincipal(c, claims)
 
 		c.Next()
 	}
@@ -55,8 +57,7 @@ func OptionalAuthMiddleware(cfg *config.Config) gin.HandlerFunc {
 				token := parts[1]
 				claims, err := utils.ValidateToken(token, cfg.JWT.Secret)
 				if err == nil {
-					c.Set("user_id", claims.UserID)
-					c.Set("user_email", claims.Email)
+					setPrincipal(c, claims)
 				}
 			}
 		}
@@ -64,3 +65,78 @@ func OptionalAuthMiddleware(cfg *config.Config) gin.HandlerFunc {
 	}
 }
 
+// setPrincipal stores the authenticated user in the context. Tokens issued
+// before users had tenants belong to the default tenant.
+func setPrincipal(c *gin.Context, claims *utils.Claims) {
+	tenantID := claims.Tenant
+	if tenantID == "" {
+		tenantID = models.DefaultTenantID
+	}
+	c.Set("user_id", claims.UserID)
+	c.Set("user_email", claims.Email)
+	c.Set("user_role", claims.Role)
+	c.Set("user_tenant_id", tenantID)
+	c.Set("mfa_verified", claims.MFA)
+	if claims.IssuedAt != nil {
+		c.Set("token_issued_at", claims.IssuedAt.Time)
+	}
+}
+
+// SessionRevocationMiddleware rejects access tokens issued before the user's
+// sessions were revoked (e.g. by a password reset). Must run after AuthMiddleware.
+func SessionRevocationMiddleware(cache cache.Cache) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, exists := c.Get("user_id")
+		if !exists {
+			c.Next()
+			return
+		}
+
+		issuedAt := c.GetTime("token_issued_at")
+		if utils.SessionRevoked(c.Request.Context(), cache, userID.(uuid.UUID), issuedAt) {
+			utils.RespondWithError(c, http.StatusUnauthorized, utils.ErrInvalidToken, "Session has been revoked, please log in again")
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// MFAPolicyMiddleware blocks sessions of roles that must use two-factor
+// authentication unless the session passed it. Enrollment routes stay open
+// so affected users can set it up. Must run after AuthMiddleware.
+func MFAPolicyMiddleware(cfg *config.Config) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		role := c.GetString("user_role")
+		if !utils.MFARequiredForRole(cfg.MFA.RequiredRoles, role) || c.GetBool("mfa_verified") {
+			c.Next()
+			return
+		}
+
+		if strings.HasPrefix(c.FullPath(), "/api/v1/profile/2fa") {
+			c.Next()
+			return
+		}
+
+		utils.RespondWithError(c, http.StatusForbidden, utils.ErrForbidden, "Two-factor authentication is required for this account")
+		c.Abort()
+	}
+}
+
+// RequireRole rejects users whose role is not one of roles. Must run after
+// AuthMiddleware.
+func RequireRole(roles ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		role := c.GetString("user_role")
+		for _, allowed := range roles {
+			if role == allowed {
+				c.Next()
+				return
+			}
+		}
+
+		utils.RespondWithError(c, http.StatusForbidden, utils.ErrForbidden, "Insufficient permissions")
+		c.Abort()
+	}
+}
@@ -17,7 +17,7 @@ func LoggingMiddleware() gin.HandlerFunc {
 
 		// Log request
 		duration := time.Since(startTime)
-		logger := utils.GetLogger().WithFields(map[string]interface{}{
+		logger := utils.GetLoggerWithContext(c.Request.Context()).WithFields(map[string]interface{}{
 			"method":     c.Request.Method,
 			"path":       c.Request.URL.Path,
 			"status":     c.Writer.Status(),
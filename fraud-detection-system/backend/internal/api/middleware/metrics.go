@@ -0,0 +1,32 @@
+package middleware
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/fraud-detection-system/backend/internal/metrics"
+)
+
+// MetricsMiddleware records the count and latency of HTTP requests
+func MetricsMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		startTime := time.Now()
+
+		c.Next()
+
+		status := strconv.Itoa(c.Writer.Status())
+		route := routeLabel(c)
+		metrics.HTTPRequests.WithLabelValues(c.Request.Method, route, status).Inc()
+		metrics.HTTPRequestDuration.WithLabelValues(c.Request.Method, route, status).Observe(time.Since(startTime).Seconds())
+	}
+}
+
+// routeLabel returns the matched route template, or "unmatched" for 404s,
+// so arbitrary paths do not create new series
+func routeLabel(c *gin.Context) string {
+	if route := c.FullPath(); route != "" {
+		return route
+	}
+	return "unmatched"
+}
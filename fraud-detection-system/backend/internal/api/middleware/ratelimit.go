@@ -7,11 +7,12 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/fraud-detection-system/backend/internal/cache"
+	"github.com/fraud-detection-system/backend/internal/metrics"
 	"github.com/fraud-detection-system/backend/internal/utils"
 )
 
 // RateLimitMiddleware implements rate limiting using Redis
-func RateLimitMiddleware(cache *cache.RedisCache, requestsPerMinute int) gin.HandlerFunc {
+func RateLimitMiddleware(cache cache.Cache, requestsPerMinute int) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		// Get client identifier (IP or user ID)
 		identifier := c.ClientIP()
@@ -25,7 +26,7 @@ func RateLimitMiddleware(cache *cache.RedisCache, requestsPerMinute int) gin.Han
 		// Increment counter
 		count, err := cache.Increment(ctx, key)
 		if err != nil {
-			utils.GetLogger().WithError(err).Error("Failed to increment rate limit counter")
+			utils.GetLoggerWithContext(c.Request.Context()).WithError(err).Error("Failed to increment rate limit counter")
 			// Don't block on error, just log it
 			c.Next()
 			return
@@ -34,12 +35,13 @@ func RateLimitMiddleware(cache *cache.RedisCache, requestsPerMinute int) gin.Han
 		// Set expiry on first request
 		if count == 1 {
 			if err := cache.SetExpire(ctx, key, time.Minute); err != nil {
-				utils.GetLogger().WithError(err).Error("Failed to set rate limit expiry")
+				utils.GetLoggerWithContext(c.Request.Context()).WithError(err).Error("Failed to set rate limit expiry")
 			}
 		}
 
 		// Check if limit exceeded
 		if count > int64(requestsPerMinute) {
+			metrics.RateLimitRejections.WithLabelValues(routeLabel(c)).Inc()
 			utils.RespondWithError(c, http.StatusTooManyRequests, 
 				fmt.Errorf("rate limit exceeded"), 
 				fmt.Sprintf("Rate limit exceeded. Maximum %d requests per minute allowed", requestsPerMinute))
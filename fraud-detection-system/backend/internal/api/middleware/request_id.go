@@ -0,0 +1,45 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/fraud-detection-system/backend/internal/utils"
+)
+
+// RequestIDHeader is the header a request ID is read from and echoed in
+const RequestIDHeader = "X-Request-ID"
+
+// maxRequestIDLength bounds caller-supplied IDs, which end up in every log line
+const maxRequestIDLength = 128
+
+// RequestIDMiddleware takes the request ID from the X-Request-ID header, or
+// generates one, and stores it in the request context for loggers, error
+// responses and queued messages. It is echoed in the response header.
+func RequestIDMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID := c.GetHeader(RequestIDHeader)
+		if !validRequestID(requestID) {
+			requestID = uuid.New().String()
+		}
+
+		c.Set("request_id", requestID)
+		c.Request = c.Request.WithContext(utils.ContextWithRequestID(c.Request.Context(), requestID))
+		c.Header(RequestIDHeader, requestID)
+
+		c.Next()
+	}
+}
+
+// validRequestID accepts IDs of printable ASCII without spaces, so callers
+// cannot inject text into logs
+func validRequestID(requestID string) bool {
+	if requestID == "" || len(requestID) > maxRequestIDLength {
+		return false
+	}
+	for _, r := range requestID {
+		if r <= ' ' || r > '~' {
+			return false
+		}
+	}
+	return true
+}
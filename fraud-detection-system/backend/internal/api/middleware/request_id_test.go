@@ -0,0 +1,27 @@
+package middleware
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidRequestID(t *testing.T) {
+	tests := []struct {
+		requestID string
+		want      bool
+	}{
+		{"3f1c2a9e-7b1d-4c1e-9a55-0d6f4f1b2c3d", true},
+		{"req_abc.123", true},
+		{"", false},
+		{"has space", false},
+		{"line\nbreak", false},
+		{"naïve", false},
+		{strings.Repeat("a", maxRequestIDLength), true},
+		{strings.Repeat("a", maxRequestIDLength+1), false},
+	}
+	for _, tt := range tests {
+		if got := validRequestID(tt.requestID); got != tt.want {
+			t.Errorf("validRequestID(%q) = %v, want %v", tt.requestID, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,45 @@
+package middleware
+
+import (
+	"net/http"
+	"regexp"
+
+	"github.com/gin-gonic/gin"
+	"github.com/fraud-detection-system/backend/internal/models"
+	"github.com/fraud-detection-system/backend/internal/utils"
+)
+
+// TenantHeader names the tenant a request belongs to
+const TenantHeader = "X-Tenant-ID"
+
+var tenantIDPattern = regexp.MustCompile(`^[a-zA-Z0-9_-]{1,64}$`)
+
+// TenantMiddleware resolves the tenant of a request from the authenticated
+// user, or the default tenant for anonymous requests. The X-Tenant-ID header
+// may repeat that tenant; only admins may use it to act in another one.
+// Must run after AuthMiddleware or OptionalAuthMiddleware.
+func TenantMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		tenantID := c.GetString("user_tenant_id")
+		if tenantID == "" {
+			tenantID = models.DefaultTenantID
+		}
+
+		if requested := c.GetHeader(TenantHeader); requested != "" && requested != tenantID {
+			if !tenantIDPattern.MatchString(requested) {
+				utils.RespondWithError(c, http.StatusBadRequest, utils.ErrBadRequest, "Invalid tenant ID")
+				c.Abort()
+				return
+			}
+			if c.GetString("user_role") != models.RoleAdmin {
+				utils.RespondWithError(c, http.StatusForbidden, utils.ErrForbidden, "Tenant does not match your account")
+				c.Abort()
+				return
+			}
+			tenantID = requested
+		}
+
+		c.Set("tenant_id", tenantID)
+		c.Next()
+	}
+}
@@ -6,15 +6,22 @@ import (
 	"github.com/fraud-detection-system/backend/internal/api/middleware"
 	"github.com/fraud-detection-system/backend/internal/cache"
 	"github.com/fraud-detection-system/backend/internal/config"
+	"github.com/fraud-detection-system/backend/internal/models"
 )
 
 type RouterConfig struct {
 	Config              *config.Config
-	Cache               *cache.RedisCache
+	Cache               cache.Cache
 	HealthHandler       *handlers.HealthHandler
 	AuthHandler         *handlers.AuthHandler
 	VerificationHandler *handlers.VerificationHandler
 	ReportHandler       *handlers.ReportHandler
+	AccountHandler      *handlers.AccountHandler
+	OutboxHandler       *handlers.OutboxHandler
+	EventsHandler       *handlers.EventsHandler
+	SearchHandler       *handlers.SearchHandler
+	AnalyticsHandler    *handlers.AnalyticsHandler
+	RegistryHandler     *handlers.RegistryHandler
 }
 
 // SetupRouter sets up the Gin router with all routes
@@ -28,12 +35,22 @@ func SetupRouter(cfg *RouterConfig) *gin.Engine {
 
 	// Global middleware
 	router.Use(gin.Recovery())
+	router.Use(middleware.RequestIDMiddleware())
+	router.Use(middleware.TracingMiddleware())
 	router.Use(middleware.LoggingMiddleware())
 	router.Use(middleware.CORSMiddleware(cfg.Config))
+	if cfg.Config.Metrics.Enabled {
+		router.Use(middleware.MetricsMiddleware())
+	}
 
 	// Health check endpoints (no auth required)
 	router.GET("/health", cfg.HealthHandler.HealthCheck)
-	router.GET("/ready", cfg.HealthHandler.ReadinessCheck)
+	router.GET("/health/live", cfg.HealthHandler.HealthCheck)
+	// A token is optional; it only adds error details to the reports
+	probes := router.Group("", middleware.OptionalAuthMiddleware(cfg.Config))
+	probes.GET("/ready", cfg.HealthHandler.ReadinessCheck)
+	probes.GET("/health/ready", cfg.HealthHandler.ReadinessCheck)
+	probes.GET("/health/startup", cfg.HealthHandler.StartupCheck)
 
 	// API v1 routes
 	v1 := router.Group("/api/v1")
@@ -43,12 +60,16 @@ func SetupRouter(cfg *RouterConfig) *gin.Engine {
 		{
 			auth.POST("/register", cfg.AuthHandler.Register)
 			auth.POST("/login", cfg.AuthHandler.Login)
+			auth.POST("/login/2fa", cfg.AuthHandler.VerifyMFALogin)
 			auth.POST("/refresh", cfg.AuthHandler.RefreshToken)
+			auth.POST("/forgot-password", cfg.AuthHandler.ForgotPassword)
+			auth.POST("/reset-password", cfg.AuthHandler.ResetPassword)
 		}
 
 		// Verification routes (optional auth)
 		verify := v1.Group("/verify")
 		verify.Use(middleware.OptionalAuthMiddleware(cfg.Config))
+		verify.Use(middleware.TenantMiddleware())
 		verify.Use(middleware.RateLimitMiddleware(cfg.Cache, 100))
 		{
 			verify.POST("", cfg.VerificationHandler.VerifyMessage)
@@ -59,10 +80,25 @@ func SetupRouter(cfg *RouterConfig) *gin.Engine {
 		// Protected routes (auth required)
 		protected := v1.Group("")
 		protected.Use(middleware.AuthMiddleware(cfg.Config))
+		protected.Use(middleware.SessionRevocationMiddleware(cfg.Cache))
+		protected.Use(middleware.MFAPolicyMiddleware(cfg.Config))
+		protected.Use(middleware.TenantMiddleware())
 		protected.Use(middleware.RateLimitMiddleware(cfg.Cache, 100))
 		{
 			// User profile
 			protected.GET("/profile", cfg.AuthHandler.GetProfile)
+			protected.PUT("/profile/password", cfg.AuthHandler.ChangePassword)
+			protected.GET("/profile/export", cfg.AccountHandler.ExportData)
+			protected.DELETE("/profile", cfg.AccountHandler.DeleteAccount)
+
+			// Two-factor authentication
+			mfa := protected.Group("/profile/2fa")
+			{
+				mfa.POST("/enroll", cfg.AuthHandler.EnrollMFA)
+				mfa.POST("/confirm", cfg.AuthHandler.ConfirmMFA)
+				mfa.POST("/disable", cfg.AuthHandler.DisableMFA)
+				mfa.POST("/recovery-codes", cfg.AuthHandler.RegenerateRecoveryCodes)
+			}
 
 			// Verification history
 			protected.GET("/verify/history", cfg.VerificationHandler.GetVerificationHistory)
@@ -74,10 +110,29 @@ func SetupRouter(cfg *RouterConfig) *gin.Engine {
 				reports.GET("/:id", cfg.ReportHandler.GetReport)
 				reports.GET("", cfg.ReportHandler.GetUserReports)
 				reports.GET("/stats", cfg.ReportHandler.GetReportStats)
+				reports.PUT("/:id/review", middleware.RequireRole(models.RoleAdmin, models.RoleAnalyst), cfg.ReportHandler.ReviewReport)
 			}
+
+			// Champion/challenger model comparison
+			protected.GET("/ml/comparison", middleware.RequireRole(models.RoleAdmin, models.RoleAnalyst), cfg.VerificationHandler.GetModelComparison)
+
+			// Message search for analysts
+			protected.GET("/search/messages", middleware.RequireRole(models.RoleAdmin, models.RoleAnalyst), cfg.SearchHandler.SearchMessages)
+
+			// Fraud trends for dashboards
+			protected.GET("/analytics/trends", middleware.RequireRole(models.RoleAdmin, models.RoleAnalyst), cfg.AnalyticsHandler.GetTrends)
+
+			// RBI circulars and the verified sender registry
+			protected.POST("/admin/circulars", middleware.RequireRole(models.RoleAdmin), cfg.RegistryHandler.AddCircular)
+			protected.POST("/admin/senders", middleware.RequireRole(models.RoleAdmin), cfg.RegistryHandler.RegisterSender)
+
+			// Outbox backlog, per stream
+			protected.GET("/outbox/stats", middleware.RequireRole(models.RoleAdmin), cfg.OutboxHandler.GetStats)
+
+			// Domain event schemas
+			protected.GET("/events/schemas", cfg.EventsHandler.GetSchemas)
 		}
 	}
 
 	return router
 }
-
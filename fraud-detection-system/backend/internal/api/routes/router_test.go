@@ -0,0 +1,32 @@
+package routes_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	"github.com/fraud-detection-system/backend/internal/api/apitest"
+	"github.com/fraud-detection-system/backend/internal/config"
+	"github.com/fraud-detection-system/backend/internal/metrics"
+)
+
+func TestMetricsNotOnAPIPort(t *testing.T) {
+	server := apitest.New(t, nil, func(cfg *config.Config) {
+		cfg.Metrics.Enabled = true
+	})
+
+	if w := server.Do(t, http.MethodGet, "/metrics", nil, ""); w.Code != http.StatusNotFound {
+		t.Errorf("expected /metrics to be absent from the API router, got %d", w.Code)
+	}
+
+	// Requests are still counted, by route template
+	counter := metrics.HTTPRequests.WithLabelValues(http.MethodGet, "/health", "200")
+	before := testutil.ToFloat64(counter)
+	if w := server.Do(t, http.MethodGet, "/health", nil, ""); w.Code != http.StatusOK {
+		t.Fatalf("expected 200 from /health, got %d", w.Code)
+	}
+	if got := testutil.ToFloat64(counter) - before; got != 1 {
+		t.Errorf("expected the request to be counted once, got %v", got)
+	}
+}
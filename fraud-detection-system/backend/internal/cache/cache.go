@@ -0,0 +1,30 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrNotFound is returned by Get when the key does not exist
+var ErrNotFound = errors.New("key not found")
+
+// Cache is the key-value store used for sessions, rate limits and cached
+// verdicts. Values passed to Set are stored as JSON. RedisCache is the
+// production implementation; MemoryCache keeps everything in process.
+type Cache interface {
+	Set(ctx context.Context, key string, value interface{}, ttl time.Duration) error
+	Get(ctx context.Context, key string, dest interface{}) error
+	Delete(ctx context.Context, key string) error
+	Exists(ctx context.Context, key string) (bool, error)
+	Increment(ctx context.Context, key string) (int64, error)
+	SetExpire(ctx context.Context, key string, ttl time.Duration) error
+	SetNX(ctx context.Context, key string, value interface{}, ttl time.Duration) (bool, error)
+	CompareAndDelete(ctx context.Context, key, value string) (bool, error)
+	HealthCheck(ctx context.Context) error
+}
+
+var (
+	_ Cache = (*RedisCache)(nil)
+	_ Cache = (*MemoryCache)(nil)
+)
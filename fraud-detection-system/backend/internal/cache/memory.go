@@ -0,0 +1,167 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+)
+
+type memoryEntry struct {
+	data      []byte
+	expiresAt time.Time
+}
+
+func (e memoryEntry) expired(now time.Time) bool {
+	return !e.expiresAt.IsZero() && !now.Before(e.expiresAt)
+}
+
+// MemoryCache is an in-process Cache with the same semantics as RedisCache,
+// for tests and single-instance development. It is safe for concurrent use.
+type MemoryCache struct {
+	mu      sync.Mutex
+	entries map[string]memoryEntry
+	ttl     time.Duration
+	now     func() time.Time
+}
+
+// NewMemoryCache creates an empty cache. A ttl of 0 passed to Set uses
+// defaultTTL, as with REDIS_CACHE_TTL.
+func NewMemoryCache(defaultTTL time.Duration) *MemoryCache {
+	return &MemoryCache{
+		entries: make(map[string]memoryEntry),
+		ttl:     defaultTTL,
+		now:     time.Now,
+	}
+}
+
+// get returns a live entry, dropping it if it has expired. The caller holds mu.
+func (m *MemoryCache) get(key string) (memoryEntry, bool) {
+	entry, ok := m.entries[key]
+	if ok && entry.expired(m.now()) {
+		delete(m.entries, key)
+		return memoryEntry{}, false
+	}
+	return entry, ok
+}
+
+func (m *MemoryCache) expiry(ttl time.Duration) time.Time {
+	if ttl <= 0 {
+		return time.Time{}
+	}
+	return m.now().Add(ttl)
+}
+
+// Set stores a value in cache
+func (m *MemoryCache) Set(ctx context.Context, key string, value interface{}, ttl time.Duration) error {
+	if ttl == 0 {
+		ttl = m.ttl
+	}
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("failed to marshal value: %w", err)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.entries[key] = memoryEntry{data: data, expiresAt: m.expiry(ttl)}
+	return nil
+}
+
+// Get retrieves a value from cache
+func (m *MemoryCache) Get(ctx context.Context, key string, dest interface{}) error {
+	m.mu.Lock()
+	entry, ok := m.get(key)
+	m.mu.Unlock()
+	if !ok {
+		return ErrNotFound
+	}
+
+	if err := json.Unmarshal(entry.data, dest); err != nil {
+		return fmt.Errorf("failed to unmarshal value: %w", err)
+	}
+	return nil
+}
+
+// Delete removes a value from cache
+func (m *MemoryCache) Delete(ctx context.Context, key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.entries, key)
+	return nil
+}
+
+// Exists checks if a key exists
+func (m *MemoryCache) Exists(ctx context.Context, key string) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	_, ok := m.get(key)
+	return ok, nil
+}
+
+// Increment increments a counter, creating it without expiry if it does not exist
+func (m *MemoryCache) Increment(ctx context.Context, key string) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	entry, ok := m.get(key)
+	var value int64
+	if ok {
+		parsed, err := strconv.ParseInt(string(entry.data), 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("value is not an integer")
+		}
+		value = parsed
+	}
+	value++
+	entry.data = []byte(strconv.FormatInt(value, 10))
+	m.entries[key] = entry
+	return value, nil
+}
+
+// SetExpire sets expiration on a key
+func (m *MemoryCache) SetExpire(ctx context.Context, key string, ttl time.Duration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if entry, ok := m.get(key); ok {
+		entry.expiresAt = m.expiry(ttl)
+		m.entries[key] = entry
+	}
+	return nil
+}
+
+// SetNX sets a value only if the key does not exist, reporting whether it
+// was set. Like Redis, the value is stored in its string form, not as JSON.
+func (m *MemoryCache) SetNX(ctx context.Context, key string, value interface{}, ttl time.Duration) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.get(key); ok {
+		return false, nil
+	}
+	m.entries[key] = memoryEntry{data: []byte(fmt.Sprint(value)), expiresAt: m.expiry(ttl)}
+	return true, nil
+}
+
+// CompareAndDelete deletes a key only if it holds value, as set by SetNX,
+// reporting whether it was deleted
+func (m *MemoryCache) CompareAndDelete(ctx context.Context, key, value string) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	entry, ok := m.get(key)
+	if !ok || string(entry.data) != value {
+		return false, nil
+	}
+	delete(m.entries, key)
+	return true, nil
+}
+
+// HealthCheck always succeeds
+func (m *MemoryCache) HealthCheck(ctx context.Context) error {
+	return nil
+}
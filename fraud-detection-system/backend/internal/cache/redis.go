@@ -56,7 +56,7 @@ func (r *RedisCache) Get(ctx context.Context, key string, dest interface{}) erro
 	data, err := r.client.Get(ctx, key).Bytes()
 	if err != nil {
 		if err == redis.Nil {
-			return fmt.Errorf("key not found")
+			return ErrNotFound
 		}
 		return fmt.Errorf("failed to get value: %w", err)
 	}
@@ -92,16 +92,37 @@ func (r *RedisCache) SetExpire(ctx context.Context, key string, ttl time.Duratio
 	return r.client.Expire(ctx, key, ttl).Err()
 }
 
+// SetNX sets a value only if the key does not exist, reporting whether it was set
+func (r *RedisCache) SetNX(ctx context.Context, key string, value interface{}, ttl time.Duration) (bool, error) {
+	return r.client.SetNX(ctx, key, value, ttl).Result()
+}
+
+// compareAndDelete deletes KEYS[1] only if it holds ARGV[1], atomically
+var compareAndDelete = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0
+`)
+
+// CompareAndDelete deletes a key only if it holds value, as set by SetNX,
+// reporting whether it was deleted. Locks are released with it so an owner
+// whose lock expired cannot release the next owner's.
+func (r *RedisCache) CompareAndDelete(ctx context.Context, key, value string) (bool, error) {
+	deleted, err := compareAndDelete.Run(ctx, r.client, []string{key}, value).Int()
+	if err != nil {
+		return false, err
+	}
+	return deleted == 1, nil
+}
+
 // Close closes the Redis connection
 func (r *RedisCache) Close() error {
 	return r.client.Close()
 }
 
 // HealthCheck checks if Redis is healthy
-func (r *RedisCache) HealthCheck() error {
-	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
-	defer cancel()
-	
+func (r *RedisCache) HealthCheck(ctx context.Context) error {
 	return r.client.Ping(ctx).Err()
 }
 
@@ -0,0 +1,152 @@
+// Package classifier is a pure-Go logistic regression fraud classifier over
+// message tokens and extracted message features. It runs in-process, so
+// verification keeps working without the ML service.
+package classifier
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/fraud-detection-system/backend/internal/models"
+)
+
+// Model holds the weights of a trained classifier. It is saved and loaded as JSON.
+type Model struct {
+	Version        string             `json:"version"`
+	Bias           float64            `json:"bias"`
+	Threshold      float64            `json:"threshold"`
+	TokenWeights   map[string]float64 `json:"token_weights"`
+	FeatureWeights map[string]float64 `json:"feature_weights"`
+	TrainedAt      time.Time          `json:"trained_at"`
+	Examples       int                `json:"examples"`
+}
+
+// Prediction is the output of the classifier for one message
+type Prediction struct {
+	Score     float64
+	IsFraud   bool
+	TopTokens []string // tokens that pushed the score up the most
+}
+
+// Load reads model weights from a JSON file
+func Load(path string) (*Model, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read model: %w", err)
+	}
+
+	var m Model
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("failed to parse model: %w", err)
+	}
+	if m.Version == "" {
+		return nil, fmt.Errorf("model has no version")
+	}
+	if m.Threshold <= 0 || m.Threshold >= 1 {
+		m.Threshold = 0.5
+	}
+	return &m, nil
+}
+
+// Save writes model weights to a JSON file
+func (m *Model) Save(path string) error {
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal model: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write model: %w", err)
+	}
+	return nil
+}
+
+// Predict scores a message
+func (m *Model) Predict(content string, features models.MessageFeatures) Prediction {
+	z := m.Bias
+	for name, value := range featureVector(features) {
+		z += m.FeatureWeights[name] * value
+	}
+
+	type contribution struct {
+		token  string
+		weight float64
+	}
+	var positive []contribution
+	for _, token := range Tokenize(content) {
+		w, ok := m.TokenWeights[token]
+		if !ok {
+			continue
+		}
+		z += w
+		if w > 0 {
+			positive = append(positive, contribution{token, w})
+		}
+	}
+
+	sort.Slice(positive, func(i, j int) bool { return positive[i].weight > positive[j].weight })
+	var top []string
+	for i := 0; i < len(positive) && i < 5; i++ {
+		top = append(top, positive[i].token)
+	}
+
+	score := sigmoid(z)
+	return Prediction{
+		Score:     score,
+		IsFraud:   score >= m.Threshold,
+		TopTokens: top,
+	}
+}
+
+// Tokenize lower-cases text and splits it into the distinct words and
+// numbers it contains. Single characters are dropped.
+func Tokenize(text string) []string {
+	fields := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	seen := make(map[string]bool, len(fields))
+	tokens := make([]string, 0, len(fields))
+	for _, field := range fields {
+		if len([]rune(field)) < 2 || seen[field] {
+			continue
+		}
+		seen[field] = true
+		tokens = append(tokens, field)
+	}
+	return tokens
+}
+
+// featureVector scales the extracted message features to comparable ranges
+func featureVector(f models.MessageFeatures) map[string]float64 {
+	return map[string]float64{
+		"message_length":     math.Log1p(float64(f.MessageLength)) / 6,
+		"has_links":          boolValue(f.HasLinks),
+		"link_count":         math.Log1p(float64(f.LinkCount)),
+		"has_phone_number":   boolValue(f.HasPhoneNumber),
+		"phone_number_count": math.Log1p(float64(f.PhoneNumberCount)),
+		"has_urgent_words":   boolValue(f.HasUrgentWords),
+		"urgent_word_count":  math.Log1p(float64(f.UrgentWordCount)),
+		"special_char_ratio": f.SpecialCharRatio,
+		"capital_ratio":      f.CapitalRatio,
+		"number_ratio":       f.NumberRatio,
+		"has_kyc_keywords":   boolValue(f.HasKYCKeywords),
+		"has_bank_names":     boolValue(f.HasBankNames),
+	}
+}
+
+func boolValue(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+func sigmoid(z float64) float64 {
+	return 1 / (1 + math.Exp(-z))
+}
@@ -0,0 +1,101 @@
+package classifier
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/fraud-detection-system/backend/internal/models"
+)
+
+func trainingExamples() []Example {
+	fraud := []string{
+		"Your KYC is pending, update now at http://kyc-update.in or account will be blocked",
+		"URGENT: account suspended, verify KYC immediately http://bit.ly/x",
+		"Dear customer your account will be blocked today, update KYC http://secure-bank.co",
+		"Verify your account now to avoid suspension http://verify-now.in",
+		"You won a lottery prize, claim now by sharing OTP",
+		"Share OTP to claim your cashback reward immediately",
+	}
+	legit := []string{
+		"Rs 500 debited from your account for grocery purchase",
+		"Your salary has been credited to your account",
+		"Meeting moved to 4pm tomorrow",
+		"Your order has been shipped and will arrive tomorrow",
+		"Rs 1200 credited to your account via UPI",
+		"Thanks for paying your electricity bill",
+	}
+
+	var examples []Example
+	for _, content := range fraud {
+		examples = append(examples, Example{Content: content, Features: features(content), IsFraud: true})
+	}
+	for _, content := range legit {
+		examples = append(examples, Example{Content: content, Features: features(content)})
+	}
+	return examples
+}
+
+func features(content string) models.MessageFeatures {
+	return models.MessageFeatures{Content: content, MessageLength: len(content)}
+}
+
+func TestTokenize(t *testing.T) {
+	got := Tokenize("Update KYC now! Visit http://kyc.in, UPDATE now - a 5000")
+	want := []string{"update", "kyc", "now", "visit", "http", "in", "5000"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestTrainAndPredict(t *testing.T) {
+	opts := DefaultTrainOptions()
+	opts.MinTokenCount = 1
+	examples := trainingExamples()
+
+	m, err := Train(examples, opts)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if metrics := m.Evaluate(examples); metrics.Accuracy < 1 {
+		t.Errorf("expected training set to be separable, got %+v", metrics)
+	}
+
+	fraud := "Update your KYC now or your account will be blocked http://kyc.in"
+	if p := m.Predict(fraud, features(fraud)); !p.IsFraud || len(p.TopTokens) == 0 {
+		t.Errorf("expected fraud with contributing tokens, got %+v", p)
+	}
+	legit := "Rs 300 debited from your account for purchase"
+	if p := m.Predict(legit, features(legit)); p.IsFraud {
+		t.Errorf("expected legitimate, got %+v", p)
+	}
+}
+
+func TestTrainNeedsBothClasses(t *testing.T) {
+	examples := trainingExamples()[:3]
+	if _, err := Train(examples, DefaultTrainOptions()); err == nil {
+		t.Error("expected error for single-class training data")
+	}
+}
+
+func TestSaveLoad(t *testing.T) {
+	m, err := Train(trainingExamples(), DefaultTrainOptions())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	path := filepath.Join(t.TempDir(), "model.json")
+	if err := m.Save(path); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+
+	content := "verify KYC now http://kyc.in"
+	if got, want := loaded.Predict(content, features(content)).Score, m.Predict(content, features(content)).Score; got != want {
+		t.Errorf("loaded model scores %f, want %f", got, want)
+	}
+}
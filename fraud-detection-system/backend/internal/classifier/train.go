@@ -0,0 +1,155 @@
+package classifier
+
+import (
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/fraud-detection-system/backend/internal/models"
+)
+
+// Example is a labeled message used for training
+type Example struct {
+	Content  string
+	Features models.MessageFeatures
+	IsFraud  bool
+}
+
+// TrainOptions controls training
+type TrainOptions struct {
+	Version       string
+	Epochs        int
+	LearningRate  float64
+	L2            float64
+	MinTokenCount int   // tokens seen in fewer messages are ignored
+	Seed          int64 // shuffling seed, so runs are reproducible
+}
+
+// DefaultTrainOptions returns options that work for a few thousand messages
+func DefaultTrainOptions() TrainOptions {
+	return TrainOptions{
+		Version:       "embedded-" + time.Now().UTC().Format("20060102150405"),
+		Epochs:        20,
+		LearningRate:  0.1,
+		L2:            1e-4,
+		MinTokenCount: 2,
+		Seed:          1,
+	}
+}
+
+// Metrics summarizes how a model does on a set of examples
+type Metrics struct {
+	Examples  int     `json:"examples"`
+	Accuracy  float64 `json:"accuracy"`
+	Precision float64 `json:"precision"`
+	Recall    float64 `json:"recall"`
+}
+
+// Train fits a logistic regression with stochastic gradient descent. Classes
+// are weighted by their inverse frequency, since fraud is usually rare.
+func Train(examples []Example, opts TrainOptions) (*Model, error) {
+	var fraud int
+	for _, ex := range examples {
+		if ex.IsFraud {
+			fraud++
+		}
+	}
+	if fraud == 0 || fraud == len(examples) {
+		return nil, fmt.Errorf("training needs both fraud and legitimate examples, got %d of %d fraud", fraud, len(examples))
+	}
+
+	// Build the vocabulary from tokens that appear often enough
+	tokenized := make([][]string, len(examples))
+	counts := make(map[string]int)
+	for i, ex := range examples {
+		tokenized[i] = Tokenize(ex.Content)
+		for _, token := range tokenized[i] {
+			counts[token]++
+		}
+	}
+
+	m := &Model{
+		Version:        opts.Version,
+		Threshold:      0.5,
+		TokenWeights:   make(map[string]float64),
+		FeatureWeights: make(map[string]float64),
+		TrainedAt:      time.Now().UTC(),
+		Examples:       len(examples),
+	}
+	for token, n := range counts {
+		if n >= opts.MinTokenCount {
+			m.TokenWeights[token] = 0
+		}
+	}
+
+	classWeight := map[bool]float64{
+		true:  float64(len(examples)) / (2 * float64(fraud)),
+		false: float64(len(examples)) / (2 * float64(len(examples)-fraud)),
+	}
+
+	rng := rand.New(rand.NewSource(opts.Seed))
+	order := rng.Perm(len(examples))
+	for epoch := 0; epoch < opts.Epochs; epoch++ {
+		rng.Shuffle(len(order), func(i, j int) { order[i], order[j] = order[j], order[i] })
+		// Decay the step size so late epochs fine-tune
+		rate := opts.LearningRate / (1 + float64(epoch)*0.1)
+
+		for _, i := range order {
+			ex := examples[i]
+			features := featureVector(ex.Features)
+
+			z := m.Bias
+			for name, value := range features {
+				z += m.FeatureWeights[name] * value
+			}
+			for _, token := range tokenized[i] {
+				z += m.TokenWeights[token]
+			}
+
+			label := boolValue(ex.IsFraud)
+			grad := (sigmoid(z) - label) * classWeight[ex.IsFraud]
+
+			m.Bias -= rate * grad
+			for name, value := range features {
+				m.FeatureWeights[name] -= rate * (grad*value + opts.L2*m.FeatureWeights[name])
+			}
+			for _, token := range tokenized[i] {
+				if w, ok := m.TokenWeights[token]; ok {
+					m.TokenWeights[token] = w - rate*(grad+opts.L2*w)
+				}
+			}
+		}
+	}
+
+	return m, nil
+}
+
+// Evaluate measures the model on labeled examples
+func (m *Model) Evaluate(examples []Example) Metrics {
+	var tp, fp, tn, fn int
+	for _, ex := range examples {
+		predicted := m.Predict(ex.Content, ex.Features).IsFraud
+		switch {
+		case predicted && ex.IsFraud:
+			tp++
+		case predicted && !ex.IsFraud:
+			fp++
+		case !predicted && ex.IsFraud:
+			fn++
+		default:
+			tn++
+		}
+	}
+
+	metrics := Metrics{Examples: len(examples)}
+	if len(examples) > 0 {
+		metrics.Accuracy = float64(tp+tn) / float64(len(examples))
+	}
+	if tp+fp > 0 {
+		metrics.Precision = float64(tp) / float64(tp+fp)
+	}
+	if tp+fn > 0 {
+		metrics.Recall = float64(tp) / float64(tp+fn)
+	}
+	return metrics
+}
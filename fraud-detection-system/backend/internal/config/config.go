@@ -2,27 +2,49 @@ package config
 
 import (
 	"fmt"
+	"net"
 	"os"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/joho/godotenv"
 )
 
+// Development-only encryption keys, rejected in production
+const (
+	devEncryptionKeys    = "dev:ZGV2ZWxvcG1lbnQtb25seS1rZXktZG8tbm90LXVzZSE="
+	devEncryptionHashKey = "ZGV2ZWxvcG1lbnQtb25seS1oYXNoLWtleS1jaGFuZ2U="
+)
+
 type Config struct {
-	App      AppConfig
-	Database DatabaseConfig
-	Redis    RedisConfig
-	Kafka    KafkaConfig
-	JWT      JWTConfig
-	ML       MLConfig
-	Server   ServerConfig
+	App          AppConfig
+	Database     DatabaseConfig
+	Redis        RedisConfig
+	Kafka        KafkaConfig
+	JWT          JWTConfig
+	MFA          MFAConfig
+	ML           MLConfig
+	VerdictCache VerdictCacheConfig
+	Verification VerificationConfig
+	Retention    RetentionConfig
+	Outbox       OutboxConfig
+	Encryption   EncryptionConfig
+	Server       ServerConfig
+	Metrics      MetricsConfig
+	Tracing      TracingConfig
+	Logging      LoggingConfig
+	Health       HealthConfig
 }
 
+// AppConfig holds application settings. DevLogResetTokens logs password
+// reset tokens, for local use while no mail transport exists; it is
+// rejected outside development.
 type AppConfig struct {
-	Env      string
-	Name     string
-	LogLevel string
+	Env               string
+	Name              string
+	LogLevel          string
+	DevLogResetTokens bool
 }
 
 type DatabaseConfig struct {
@@ -35,6 +57,17 @@ type DatabaseConfig struct {
 	MaxConnections  int
 	MaxIdleConns    int
 	ConnMaxLifetime time.Duration
+	ConnMaxIdleTime time.Duration
+	// ReplicaHosts are read replicas as host or host:port. They share the
+	// primary's credentials and pool settings. Listing and aggregate queries
+	// go to a replica that is reachable and within ReplicaMaxLag of the
+	// primary, and to the primary when none is.
+	ReplicaHosts         []string
+	ReplicaMaxLag        time.Duration
+	ReplicaCheckInterval time.Duration
+	// AutoMigrate applies pending embedded migrations on startup. When off,
+	// services refuse to start until "migrate up" has been run.
+	AutoMigrate bool
 }
 
 type RedisConfig struct {
@@ -46,33 +79,165 @@ type RedisConfig struct {
 }
 
 type KafkaConfig struct {
-	Brokers              []string
-	TopicVerification    string
-	TopicReports         string
-	TopicAlerts          string
-	ConsumerGroup        string
-	AutoOffsetReset      string
-	SessionTimeout       time.Duration
-	HeartbeatInterval    time.Duration
+	Brokers           []string
+	TopicVerification string
+	TopicReports      string
+	TopicAlerts       string
+	TopicEvents       string
+	ConsumerGroup     string
+	AutoOffsetReset   string
+	SessionTimeout    time.Duration
+	HeartbeatInterval time.Duration
 }
 
 type JWTConfig struct {
-	Secret               string
-	Expiry               time.Duration
-	RefreshTokenExpiry   time.Duration
+	Secret              string
+	Expiry              time.Duration
+	RefreshTokenExpiry  time.Duration
+	PasswordResetExpiry time.Duration
+}
+
+type MFAConfig struct {
+	Issuer            string
+	RequiredRoles     []string
+	ChallengeExpiry   time.Duration
+	RecoveryCodeCount int
+	MaxAttempts       int
 }
 
 type MLConfig struct {
-	ServiceURL       string
-	InferenceTimeout time.Duration
+	ServiceURL              string
+	InferenceTimeout        time.Duration
+	MaxRetries              int
+	RetryBaseDelay          time.Duration
+	RetryMaxDelay           time.Duration
+	BreakerFailureThreshold int
+	BreakerOpenTimeout      time.Duration
+	FallbackEnabled         bool
+	Models                  []MLModelEndpoint
+	Champion                string
+	Challenger              string
+	ChallengerPercent       int
+	ShadowEnabled           bool
+	ShadowTimeout           time.Duration
+	TenantModels            map[string]string
+	Transport               string
+	GRPCAddress             string
+	GRPCPoolSize            int
+	EmbeddedModelPath       string
+}
+
+// ML service transports
+const (
+	MLTransportHTTP     = "http"
+	MLTransportGRPC     = "grpc"
+	MLTransportEmbedded = "embedded"
+)
+
+// MLEmbeddedScheme prefixes model URLs that name a weights file for the
+// in-process classifier, e.g. "embedded:/models/classifier.json"
+const MLEmbeddedScheme = "embedded:"
+
+// MLModelEndpoint is a named ML inference service
+type MLModelEndpoint struct {
+	Name string
+	URL  string
+}
+
+// VerificationConfig bounds the checks VerifyMessage runs in parallel. Each
+// check has its own timeout and all of them share Deadline.
+type VerificationConfig struct {
+	Deadline      time.Duration
+	MLTimeout     time.Duration
+	HeaderTimeout time.Duration
+	RBITimeout    time.Duration
+}
+
+type VerdictCacheConfig struct {
+	Enabled bool
+	TTL     time.Duration
 }
 
+type RetentionConfig struct {
+	Enabled         bool
+	Interval        time.Duration
+	BatchSize       int
+	MaxBatches      int
+	Default         RetentionPolicy
+	TenantOverrides map[string]RetentionPolicy
+}
+
+// RetentionPolicy holds how long each entity is kept; zero keeps it forever
+type RetentionPolicy struct {
+	Messages      time.Duration
+	Verifications time.Duration
+}
+
+// OutboxConfig controls the worker that applies outbox entries. An entry
+// that fails MaxAttempts times is abandoned so later ones can proceed;
+// processed entries are deleted after Retention.
+type OutboxConfig struct {
+	Enabled      bool
+	PollInterval time.Duration
+	BatchSize    int
+	MaxAttempts  int
+	Retention    time.Duration
+}
+
+type EncryptionConfig struct {
+	Keys        string // comma-separated "id:base64key" entries
+	KeysFile    string
+	ActiveKeyID string
+	HashKey     string
+}
+
+// ServerConfig configures the API gateway's HTTP server. WorkerPort is
+// where the worker, which has no API, serves health probes and metrics.
 type ServerConfig struct {
 	Port            string
 	Host            string
 	ReadTimeout     time.Duration
 	WriteTimeout    time.Duration
 	ShutdownTimeout time.Duration
+	WorkerPort      string
+}
+
+// MetricsConfig controls the Prometheus /metrics endpoint. The API
+// gateway, auth and verification services serve it on Port, apart from
+// their public API; the worker serves it on Server.WorkerPort.
+type MetricsConfig struct {
+	Enabled bool
+	Port    string
+}
+
+// HealthConfig controls dependency checks. Criticality overrides whether a
+// dependency being down makes a service not ready (true) or only degraded
+// (false), by check name: database, redis, kafka or ml.
+type HealthConfig struct {
+	CheckTimeout time.Duration
+	CacheTTL     time.Duration
+	Criticality  map[string]bool
+}
+
+// TracingConfig controls OpenTelemetry tracing. Exporter is none, stdout
+// (spans printed as JSON, for local use) or otlp (sent over HTTP to
+// OTLPEndpoint). SampleRatio applies to traces started by this service;
+// traces continued from a caller follow the caller's decision.
+type TracingConfig struct {
+	Exporter     string
+	OTLPEndpoint string
+	OTLPInsecure bool
+	SampleRatio  float64
+}
+
+// LoggingConfig controls how PII is hidden in logs and queue payloads.
+// RedactionMode is off, mask (keep the last four characters) or remove; it
+// defaults to remove in production and mask elsewhere. SafeFields are log
+// fields and payload keys that are never redacted, on top of the built-in
+// ID fields.
+type LoggingConfig struct {
+	RedactionMode string
+	SafeFields    []string
 }
 
 // Load loads configuration from environment variables
@@ -82,20 +247,26 @@ func Load() (*Config, error) {
 
 	config := &Config{
 		App: AppConfig{
-			Env:      getEnv("APP_ENV", "development"),
-			Name:     getEnv("APP_NAME", "fraud-detection-system"),
-			LogLevel: getEnv("LOG_LEVEL", "info"),
+			Env:               getEnv("APP_ENV", "development"),
+			Name:              getEnv("APP_NAME", "fraud-detection-system"),
+			LogLevel:          getEnv("LOG_LEVEL", "info"),
+			DevLogResetTokens: getEnvAsBool("DEV_LOG_RESET_TOKENS", false),
 		},
 		Database: DatabaseConfig{
-			Host:            getEnv("DATABASE_HOST", "localhost"),
-			Port:            getEnvAsInt("DATABASE_PORT", 5432),
-			User:            getEnv("DATABASE_USER", "frauddetection"),
-			Password:        getEnv("DATABASE_PASSWORD", "frauddetection_password"),
-			DBName:          getEnv("DATABASE_NAME", "frauddetection_db"),
-			SSLMode:         getEnv("DATABASE_SSL_MODE", "disable"),
-			MaxConnections:  getEnvAsInt("DATABASE_MAX_CONNECTIONS", 100),
-			MaxIdleConns:    getEnvAsInt("DATABASE_MAX_IDLE_CONNECTIONS", 10),
-			ConnMaxLifetime: time.Hour,
+			Host:                 getEnv("DATABASE_HOST", "localhost"),
+			Port:                 getEnvAsInt("DATABASE_PORT", 5432),
+			User:                 getEnv("DATABASE_USER", "frauddetection"),
+			Password:             getEnv("DATABASE_PASSWORD", "frauddetection_password"),
+			DBName:               getEnv("DATABASE_NAME", "frauddetection_db"),
+			SSLMode:              getEnv("DATABASE_SSL_MODE", "disable"),
+			MaxConnections:       getEnvAsInt("DATABASE_MAX_CONNECTIONS", 100),
+			MaxIdleConns:         getEnvAsInt("DATABASE_MAX_IDLE_CONNECTIONS", 10),
+			ConnMaxLifetime:      getEnvAsDuration("DATABASE_CONN_MAX_LIFETIME", time.Hour),
+			ConnMaxIdleTime:      getEnvAsDuration("DATABASE_CONN_MAX_IDLE_TIME", 10*time.Minute),
+			ReplicaHosts:         getEnvAsSlice("DATABASE_REPLICA_HOSTS", nil),
+			ReplicaMaxLag:        getEnvAsDuration("DATABASE_REPLICA_MAX_LAG", 5*time.Second),
+			ReplicaCheckInterval: getEnvAsDuration("DATABASE_REPLICA_CHECK_INTERVAL", 5*time.Second),
+			AutoMigrate:          getEnvAsBool("DATABASE_AUTO_MIGRATE", false),
 		},
 		Redis: RedisConfig{
 			Host:     getEnv("REDIS_HOST", "localhost"),
@@ -105,23 +276,80 @@ func Load() (*Config, error) {
 			CacheTTL: getEnvAsDuration("REDIS_CACHE_TTL", 3600*time.Second),
 		},
 		Kafka: KafkaConfig{
-			Brokers:              []string{getEnv("KAFKA_BROKERS", "localhost:9092")},
-			TopicVerification:    getEnv("KAFKA_TOPIC_VERIFICATION", "verification-requests"),
-			TopicReports:         getEnv("KAFKA_TOPIC_REPORTS", "fraud-reports"),
-			TopicAlerts:          getEnv("KAFKA_TOPIC_ALERTS", "fraud-alerts"),
-			ConsumerGroup:        getEnv("KAFKA_CONSUMER_GROUP", "fraud-detection-workers"),
-			AutoOffsetReset:      getEnv("KAFKA_AUTO_OFFSET_RESET", "earliest"),
-			SessionTimeout:       30 * time.Second,
-			HeartbeatInterval:    3 * time.Second,
+			Brokers:           []string{getEnv("KAFKA_BROKERS", "localhost:9092")},
+			TopicVerification: getEnv("KAFKA_TOPIC_VERIFICATION", "verification-requests"),
+			TopicReports:      getEnv("KAFKA_TOPIC_REPORTS", "fraud-reports"),
+			TopicAlerts:       getEnv("KAFKA_TOPIC_ALERTS", "fraud-alerts"),
+			TopicEvents:       getEnv("KAFKA_TOPIC_EVENTS", "platform-events"),
+			ConsumerGroup:     getEnv("KAFKA_CONSUMER_GROUP", "fraud-detection-workers"),
+			AutoOffsetReset:   getEnv("KAFKA_AUTO_OFFSET_RESET", "earliest"),
+			SessionTimeout:    30 * time.Second,
+			HeartbeatInterval: 3 * time.Second,
 		},
 		JWT: JWTConfig{
-			Secret:             getEnv("JWT_SECRET", "your-secret-key"),
-			Expiry:             getEnvAsDuration("JWT_EXPIRY", 24*time.Hour),
-			RefreshTokenExpiry: getEnvAsDuration("REFRESH_TOKEN_EXPIRY", 168*time.Hour),
+			Secret:              getEnv("JWT_SECRET", "your-secret-key"),
+			Expiry:              getEnvAsDuration("JWT_EXPIRY", 24*time.Hour),
+			RefreshTokenExpiry:  getEnvAsDuration("REFRESH_TOKEN_EXPIRY", 168*time.Hour),
+			PasswordResetExpiry: getEnvAsDuration("PASSWORD_RESET_EXPIRY", 30*time.Minute),
+		},
+		MFA: MFAConfig{
+			Issuer:            getEnv("MFA_ISSUER", "FraudShield"),
+			RequiredRoles:     getEnvAsSlice("MFA_REQUIRED_ROLES", []string{"ANALYST", "ADMIN"}),
+			ChallengeExpiry:   getEnvAsDuration("MFA_CHALLENGE_EXPIRY", 5*time.Minute),
+			RecoveryCodeCount: getEnvAsInt("MFA_RECOVERY_CODE_COUNT", 10),
+			MaxAttempts:       getEnvAsInt("MFA_MAX_ATTEMPTS", 5),
 		},
 		ML: MLConfig{
-			ServiceURL:       getEnv("ML_SERVICE_URL", "http://localhost:8000"),
-			InferenceTimeout: getEnvAsDuration("INFERENCE_TIMEOUT", 5*time.Second),
+			ServiceURL:              getEnv("ML_SERVICE_URL", "http://localhost:8000"),
+			InferenceTimeout:        getEnvAsDuration("INFERENCE_TIMEOUT", 5*time.Second),
+			MaxRetries:              getEnvAsInt("ML_MAX_RETRIES", 2),
+			RetryBaseDelay:          getEnvAsDuration("ML_RETRY_BASE_DELAY", 200*time.Millisecond),
+			RetryMaxDelay:           getEnvAsDuration("ML_RETRY_MAX_DELAY", 2*time.Second),
+			BreakerFailureThreshold: getEnvAsInt("ML_BREAKER_FAILURE_THRESHOLD", 5),
+			BreakerOpenTimeout:      getEnvAsDuration("ML_BREAKER_OPEN_TIMEOUT", 30*time.Second),
+			FallbackEnabled:         getEnvAsBool("ML_FALLBACK_ENABLED", true),
+			Champion:                getEnv("ML_CHAMPION", ""),
+			Challenger:              getEnv("ML_CHALLENGER", ""),
+			ChallengerPercent:       getEnvAsInt("ML_CHALLENGER_PERCENT", 0),
+			ShadowEnabled:           getEnvAsBool("ML_SHADOW_ENABLED", true),
+			ShadowTimeout:           getEnvAsDuration("ML_SHADOW_TIMEOUT", 5*time.Second),
+			Transport:               getEnv("ML_TRANSPORT", MLTransportHTTP),
+			GRPCAddress:             getEnv("ML_GRPC_ADDRESS", "localhost:50051"),
+			GRPCPoolSize:            getEnvAsInt("ML_GRPC_POOL_SIZE", 4),
+			EmbeddedModelPath:       getEnv("ML_EMBEDDED_MODEL_PATH", "models/classifier.json"),
+		},
+		Verification: VerificationConfig{
+			Deadline:      getEnvAsDuration("VERIFICATION_DEADLINE", 10*time.Second),
+			MLTimeout:     getEnvAsDuration("VERIFICATION_ML_TIMEOUT", 8*time.Second),
+			HeaderTimeout: getEnvAsDuration("VERIFICATION_HEADER_TIMEOUT", time.Second),
+			RBITimeout:    getEnvAsDuration("VERIFICATION_RBI_TIMEOUT", 2*time.Second),
+		},
+		VerdictCache: VerdictCacheConfig{
+			Enabled: getEnvAsBool("VERDICT_CACHE_ENABLED", true),
+			TTL:     getEnvAsDuration("VERDICT_CACHE_TTL", time.Hour),
+		},
+		Retention: RetentionConfig{
+			Enabled:    getEnvAsBool("RETENTION_ENABLED", true),
+			Interval:   getEnvAsDuration("RETENTION_INTERVAL", 24*time.Hour),
+			BatchSize:  getEnvAsInt("RETENTION_BATCH_SIZE", 1000),
+			MaxBatches: getEnvAsInt("RETENTION_MAX_BATCHES", 100),
+			Default: RetentionPolicy{
+				Messages:      getEnvAsDuration("RETENTION_MESSAGES", 90*24*time.Hour),
+				Verifications: getEnvAsDuration("RETENTION_VERIFICATIONS", 365*24*time.Hour),
+			},
+		},
+		Outbox: OutboxConfig{
+			Enabled:      getEnvAsBool("OUTBOX_ENABLED", true),
+			PollInterval: getEnvAsDuration("OUTBOX_POLL_INTERVAL", time.Second),
+			BatchSize:    getEnvAsInt("OUTBOX_BATCH_SIZE", 100),
+			MaxAttempts:  getEnvAsInt("OUTBOX_MAX_ATTEMPTS", 10),
+			Retention:    getEnvAsDuration("OUTBOX_RETENTION", 7*24*time.Hour),
+		},
+		Encryption: EncryptionConfig{
+			Keys:        getEnv("ENCRYPTION_KEYS", ""),
+			KeysFile:    getEnv("ENCRYPTION_KEYS_FILE", ""),
+			ActiveKeyID: getEnv("ENCRYPTION_ACTIVE_KEY_ID", "dev"),
+			HashKey:     getEnv("ENCRYPTION_HASH_KEY", devEncryptionHashKey),
 		},
 		Server: ServerConfig{
 			Port:            getEnv("API_GATEWAY_PORT", "8080"),
@@ -129,17 +357,81 @@ func Load() (*Config, error) {
 			ReadTimeout:     15 * time.Second,
 			WriteTimeout:    15 * time.Second,
 			ShutdownTimeout: 30 * time.Second,
+			WorkerPort:      getEnv("WORKER_HTTP_PORT", getEnv("WORKER_METRICS_PORT", "9091")),
+		},
+		Metrics: MetricsConfig{
+			Enabled: getEnvAsBool("METRICS_ENABLED", true),
+			Port:    getEnv("METRICS_PORT", "9090"),
+		},
+		Tracing: TracingConfig{
+			Exporter:     getEnv("TRACING_EXPORTER", "none"),
+			OTLPEndpoint: getEnv("TRACING_OTLP_ENDPOINT", "localhost:4318"),
+			OTLPInsecure: getEnvAsBool("TRACING_OTLP_INSECURE", true),
+			SampleRatio:  getEnvAsFloat("TRACING_SAMPLE_RATIO", 1.0),
+		},
+		Logging: LoggingConfig{
+			RedactionMode: getEnv("LOG_REDACTION_MODE", defaultRedactionMode(getEnv("APP_ENV", "development"))),
+			SafeFields:    getEnvAsSlice("LOG_SAFE_FIELDS", nil),
+		},
+		Health: HealthConfig{
+			CheckTimeout: getEnvAsDuration("HEALTH_CHECK_TIMEOUT", 2*time.Second),
+			CacheTTL:     getEnvAsDuration("HEALTH_CACHE_TTL", 2*time.Second),
 		},
 	}
 
+	overrides, err := parseRetentionOverrides(getEnv("RETENTION_TENANT_OVERRIDES", ""), config.Retention.Default)
+	if err != nil {
+		return nil, err
+	}
+	config.Retention.TenantOverrides = overrides
+
+	criticality, err := parseHealthCriticality(getEnv("HEALTH_CRITICALITY", ""))
+	if err != nil {
+		return nil, err
+	}
+	config.Health.Criticality = criticality
+
+	if err := loadMLModels(&config.ML); err != nil {
+		return nil, err
+	}
+
+	if config.Encryption.Keys == "" && config.Encryption.KeysFile == "" {
+		config.Encryption.Keys = devEncryptionKeys
+	}
+
+	switch config.Logging.RedactionMode {
+	case "off", "mask", "remove":
+	default:
+		return nil, fmt.Errorf("invalid LOG_REDACTION_MODE: %s", config.Logging.RedactionMode)
+	}
+
 	// Validate required fields
 	if config.JWT.Secret == "your-secret-key" && config.App.Env == "production" {
 		return nil, fmt.Errorf("JWT_SECRET must be set in production")
 	}
+	if config.App.Env == "production" &&
+		(config.Encryption.Keys == devEncryptionKeys || config.Encryption.HashKey == devEncryptionHashKey) {
+		return nil, fmt.Errorf("ENCRYPTION_KEYS and ENCRYPTION_HASH_KEY must be set in production")
+	}
+	if config.App.Env == "production" && config.Logging.RedactionMode == "off" {
+		return nil, fmt.Errorf("LOG_REDACTION_MODE cannot be off in production")
+	}
+	if config.App.DevLogResetTokens && config.App.Env != "development" {
+		return nil, fmt.Errorf("DEV_LOG_RESET_TOKENS is only allowed in development")
+	}
 
 	return config, nil
 }
 
+// defaultRedactionMode removes PII from production logs outright, and masks
+// it elsewhere so developers can still tell values apart
+func defaultRedactionMode(env string) string {
+	if env == "production" {
+		return "remove"
+	}
+	return "mask"
+}
+
 // Helper functions
 func getEnv(key, defaultValue string) string {
 	if value := os.Getenv(key); value != "" {
@@ -164,12 +456,202 @@ func getEnvAsDuration(key string, defaultValue time.Duration) time.Duration {
 	return defaultValue
 }
 
+func getEnvAsBool(key string, defaultValue bool) bool {
+	valueStr := os.Getenv(key)
+	if value, err := strconv.ParseBool(valueStr); err == nil {
+		return value
+	}
+	return defaultValue
+}
+
+func getEnvAsFloat(key string, defaultValue float64) float64 {
+	valueStr := os.Getenv(key)
+	if value, err := strconv.ParseFloat(valueStr, 64); err == nil {
+		return value
+	}
+	return defaultValue
+}
+
+func getEnvAsSlice(key string, defaultValue []string) []string {
+	valueStr := os.Getenv(key)
+	if valueStr == "" {
+		return defaultValue
+	}
+	var values []string
+	for _, v := range strings.Split(valueStr, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			values = append(values, v)
+		}
+	}
+	return values
+}
+
+// parseRetentionOverrides parses per-tenant retention overrides in the form
+// "acme:messages=720h,verifications=2160h;globex:messages=168h". Entities
+// not named for a tenant fall back to the defaults.
+func parseRetentionOverrides(value string, defaults RetentionPolicy) (map[string]RetentionPolicy, error) {
+	overrides := make(map[string]RetentionPolicy)
+	for _, entry := range strings.Split(value, ";") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		tenant, settings, ok := strings.Cut(entry, ":")
+		tenant = strings.TrimSpace(tenant)
+		if !ok || tenant == "" {
+			return nil, fmt.Errorf("invalid RETENTION_TENANT_OVERRIDES entry %q", entry)
+		}
+
+		policy := defaults
+		for _, setting := range strings.Split(settings, ",") {
+			name, durationStr, ok := strings.Cut(strings.TrimSpace(setting), "=")
+			if !ok {
+				return nil, fmt.Errorf("invalid retention setting %q for tenant %s", setting, tenant)
+			}
+			duration, err := time.ParseDuration(strings.TrimSpace(durationStr))
+			if err != nil {
+				return nil, fmt.Errorf("invalid retention duration for tenant %s: %w", tenant, err)
+			}
+			switch strings.TrimSpace(name) {
+			case "messages":
+				policy.Messages = duration
+			case "verifications":
+				policy.Verifications = duration
+			default:
+				return nil, fmt.Errorf("unknown retention entity %q for tenant %s", name, tenant)
+			}
+		}
+		overrides[tenant] = policy
+	}
+	return overrides, nil
+}
+
+// parseHealthCriticality parses HEALTH_CRITICALITY, e.g.
+// "ml=degraded,kafka=critical"
+func parseHealthCriticality(value string) (map[string]bool, error) {
+	criticality := make(map[string]bool)
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		name, level, ok := strings.Cut(entry, "=")
+		name = strings.TrimSpace(name)
+		if !ok || name == "" {
+			return nil, fmt.Errorf("invalid HEALTH_CRITICALITY entry %q", entry)
+		}
+		switch strings.TrimSpace(level) {
+		case "critical":
+			criticality[name] = true
+		case "degraded":
+			criticality[name] = false
+		default:
+			return nil, fmt.Errorf("invalid criticality %q for %s, want critical or degraded", level, name)
+		}
+	}
+	return criticality, nil
+}
+
+// loadMLModels parses the named model endpoints from ML_MODELS
+// ("champion=http://ml-a:8000,challenger=http://ml-b:8000") and the tenant
+// routes from ML_TENANT_MODELS ("acme=challenger;globex=champion"). Without
+// ML_MODELS the single ML_SERVICE_URL (ML_GRPC_ADDRESS with the gRPC
+// transport, ML_EMBEDDED_MODEL_PATH with the embedded one) is used as the
+// "default" model.
+func loadMLModels(ml *MLConfig) error {
+	switch ml.Transport {
+	case MLTransportHTTP, MLTransportGRPC, MLTransportEmbedded:
+	default:
+		return fmt.Errorf("ML_TRANSPORT must be %q, %q or %q", MLTransportHTTP, MLTransportGRPC, MLTransportEmbedded)
+	}
+	if ml.Transport == MLTransportGRPC && ml.GRPCPoolSize < 1 {
+		return fmt.Errorf("ML_GRPC_POOL_SIZE must be at least 1")
+	}
+
+	known := make(map[string]bool)
+	for _, entry := range getEnvAsSlice("ML_MODELS", nil) {
+		name, url, ok := strings.Cut(entry, "=")
+		name, url = strings.TrimSpace(name), strings.TrimSpace(url)
+		if !ok || name == "" || url == "" {
+			return fmt.Errorf("invalid ML_MODELS entry %q", entry)
+		}
+		if known[name] {
+			return fmt.Errorf("duplicate model %q in ML_MODELS", name)
+		}
+		known[name] = true
+		ml.Models = append(ml.Models, MLModelEndpoint{Name: name, URL: url})
+	}
+	if len(ml.Models) == 0 {
+		url := ml.ServiceURL
+		switch ml.Transport {
+		case MLTransportGRPC:
+			url = ml.GRPCAddress
+		case MLTransportEmbedded:
+			url = MLEmbeddedScheme + ml.EmbeddedModelPath
+		}
+		ml.Models = []MLModelEndpoint{{Name: "default", URL: url}}
+		known["default"] = true
+	}
+
+	if ml.Champion == "" {
+		ml.Champion = ml.Models[0].Name
+	}
+	if !known[ml.Champion] {
+		return fmt.Errorf("ML_CHAMPION %q is not defined in ML_MODELS", ml.Champion)
+	}
+	if ml.Challenger != "" && (!known[ml.Challenger] || ml.Challenger == ml.Champion) {
+		return fmt.Errorf("ML_CHALLENGER %q must be a model in ML_MODELS other than the champion", ml.Challenger)
+	}
+	if ml.ChallengerPercent < 0 || ml.ChallengerPercent > 100 {
+		return fmt.Errorf("ML_CHALLENGER_PERCENT must be between 0 and 100")
+	}
+
+	ml.TenantModels = make(map[string]string)
+	for _, entry := range strings.Split(getEnv("ML_TENANT_MODELS", ""), ";") {
+		if entry = strings.TrimSpace(entry); entry == "" {
+			continue
+		}
+		tenant, model, ok := strings.Cut(entry, "=")
+		tenant, model = strings.TrimSpace(tenant), strings.TrimSpace(model)
+		if !ok || tenant == "" || !known[model] {
+			return fmt.Errorf("invalid ML_TENANT_MODELS entry %q", entry)
+		}
+		ml.TenantModels[tenant] = model
+	}
+
+	return nil
+}
+
+// PolicyFor returns the retention policy that applies to a tenant
+func (c RetentionConfig) PolicyFor(tenantID string) RetentionPolicy {
+	if policy, ok := c.TenantOverrides[tenantID]; ok {
+		return policy
+	}
+	return c.Default
+}
+
 // GetDatabaseURL returns the database connection string
 func (c *Config) GetDatabaseURL() string {
+	return c.databaseURL(c.Database.Host, c.Database.Port)
+}
+
+// GetReplicaURL returns the connection string for a read replica given as
+// host or host:port, defaulting to the primary's port
+func (c *Config) GetReplicaURL(replica string) string {
+	host, port := replica, c.Database.Port
+	if h, p, err := net.SplitHostPort(replica); err == nil {
+		if n, err := strconv.Atoi(p); err == nil {
+			host, port = h, n
+		}
+	}
+	return c.databaseURL(host, port)
+}
+
+func (c *Config) databaseURL(host string, port int) string {
 	return fmt.Sprintf(
 		"host=%s port=%d user=%s password=%s dbname=%s sslmode=%s",
-		c.Database.Host,
-		c.Database.Port,
+		host,
+		port,
 		c.Database.User,
 		c.Database.Password,
 		c.Database.DBName,
@@ -181,4 +663,3 @@ func (c *Config) GetDatabaseURL() string {
 func (c *Config) GetRedisAddr() string {
 	return fmt.Sprintf("%s:%d", c.Redis.Host, c.Redis.Port)
 }
-
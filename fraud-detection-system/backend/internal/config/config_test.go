@@ -0,0 +1,155 @@
+package config
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseRetentionOverrides(t *testing.T) {
+	defaults := RetentionPolicy{Messages: 90 * 24 * time.Hour, Verifications: 365 * 24 * time.Hour}
+
+	overrides, err := parseRetentionOverrides("acme:messages=720h,verifications=2160h; globex:messages=168h", defaults)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got := overrides["acme"]; got.Messages != 720*time.Hour || got.Verifications != 2160*time.Hour {
+		t.Errorf("acme: got %+v", got)
+	}
+	if got := overrides["globex"]; got.Messages != 168*time.Hour || got.Verifications != defaults.Verifications {
+		t.Errorf("globex: got %+v", got)
+	}
+
+	cfg := RetentionConfig{Default: defaults, TenantOverrides: overrides}
+	if got := cfg.PolicyFor("unknown"); got != defaults {
+		t.Errorf("unknown tenant: got %+v, want defaults", got)
+	}
+}
+
+func TestParseRetentionOverridesInvalid(t *testing.T) {
+	for _, value := range []string{
+		"acme",
+		":messages=1h",
+		"acme:messages",
+		"acme:messages=soon",
+		"acme:reports=1h",
+	} {
+		if _, err := parseRetentionOverrides(value, RetentionPolicy{}); err == nil {
+			t.Errorf("%q: expected error", value)
+		}
+	}
+}
+
+func TestLoadMLModels(t *testing.T) {
+	t.Setenv("ML_MODELS", "champion=http://ml-a:8000, challenger=http://ml-b:8000")
+	t.Setenv("ML_TENANT_MODELS", "acme=challenger; globex=champion")
+
+	ml := MLConfig{Transport: MLTransportHTTP, Challenger: "challenger", ChallengerPercent: 10}
+	if err := loadMLModels(&ml); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(ml.Models) != 2 || ml.Models[1].URL != "http://ml-b:8000" {
+		t.Errorf("models: got %+v", ml.Models)
+	}
+	if ml.Champion != "champion" {
+		t.Errorf("champion: got %q, want first model", ml.Champion)
+	}
+	if ml.TenantModels["acme"] != "challenger" || ml.TenantModels["globex"] != "champion" {
+		t.Errorf("tenant models: got %+v", ml.TenantModels)
+	}
+}
+
+func TestLoadMLModelsDefault(t *testing.T) {
+	ml := MLConfig{Transport: MLTransportHTTP, ServiceURL: "http://ml-service:8000"}
+	if err := loadMLModels(&ml); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(ml.Models) != 1 || ml.Models[0].Name != "default" || ml.Champion != "default" {
+		t.Errorf("got %+v", ml)
+	}
+
+	ml = MLConfig{Transport: MLTransportGRPC, GRPCAddress: "ml-service:50051", GRPCPoolSize: 4}
+	if err := loadMLModels(&ml); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if ml.Models[0].URL != "ml-service:50051" {
+		t.Errorf("grpc default model: got %+v", ml.Models)
+	}
+
+	ml = MLConfig{Transport: MLTransportEmbedded, EmbeddedModelPath: "/models/classifier.json"}
+	if err := loadMLModels(&ml); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if ml.Models[0].URL != "embedded:/models/classifier.json" {
+		t.Errorf("embedded default model: got %+v", ml.Models)
+	}
+}
+
+func TestLoadMLModelsInvalid(t *testing.T) {
+	t.Setenv("ML_MODELS", "a=http://ml-a:8000,b=http://ml-b:8000")
+	for _, ml := range []MLConfig{
+		{Transport: MLTransportHTTP, Champion: "c"},
+		{Transport: MLTransportHTTP, Challenger: "a"},
+		{Transport: MLTransportHTTP, Challenger: "c"},
+		{Transport: MLTransportHTTP, Challenger: "b", ChallengerPercent: 101},
+		{Transport: "thrift"},
+		{Transport: MLTransportGRPC},
+	} {
+		if err := loadMLModels(&ml); err == nil {
+			t.Errorf("%+v: expected error", ml)
+		}
+	}
+}
+
+func TestParseHealthCriticality(t *testing.T) {
+	criticality, err := parseHealthCriticality("ml=degraded, kafka=critical")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if critical, ok := criticality["ml"]; !ok || critical {
+		t.Errorf("ml: got %v, %v, want non-critical", critical, ok)
+	}
+	if !criticality["kafka"] {
+		t.Error("kafka: want critical")
+	}
+
+	for _, value := range []string{"ml", "ml=optional", "=critical"} {
+		if _, err := parseHealthCriticality(value); err == nil {
+			t.Errorf("expected error for %q", value)
+		}
+	}
+}
+
+func TestGetReplicaURL(t *testing.T) {
+	cfg := &Config{Database: DatabaseConfig{
+		Port: 5432, User: "fd", Password: "secret", DBName: "fd_db", SSLMode: "disable",
+	}}
+
+	tests := map[string]string{
+		"replica-1":      "host=replica-1 port=5432 user=fd password=secret dbname=fd_db sslmode=disable",
+		"replica-2:6432": "host=replica-2 port=6432 user=fd password=secret dbname=fd_db sslmode=disable",
+	}
+	for replica, want := range tests {
+		if got := cfg.GetReplicaURL(replica); got != want {
+			t.Errorf("GetReplicaURL(%q) = %q, want %q", replica, got, want)
+		}
+	}
+}
+
+func TestDevLogResetTokens(t *testing.T) {
+	t.Setenv("DEV_LOG_RESET_TOKENS", "true")
+
+	t.Setenv("APP_ENV", "development")
+	cfg, err := Load()
+	if err != nil || !cfg.App.DevLogResetTokens {
+		t.Errorf("development: expected the flag to load, got %v", err)
+	}
+
+	for _, env := range []string{"staging", "test"} {
+		t.Setenv("APP_ENV", env)
+		if _, err := Load(); err == nil {
+			t.Errorf("%s: expected DEV_LOG_RESET_TOKENS to be rejected", env)
+		}
+	}
+}
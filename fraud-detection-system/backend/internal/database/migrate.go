@@ -0,0 +1,526 @@
+package database
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/fraud-detection-system/backend/internal/database/migrations"
+	"github.com/fraud-detection-system/backend/internal/utils"
+)
+
+// migrationLockKey is the advisory lock held while migrations run, so
+// replicas starting together apply each migration exactly once
+const migrationLockKey int64 = 7_240_315_001
+
+const createSchemaMigrations = `
+	CREATE TABLE IF NOT EXISTS schema_migrations (
+		version INTEGER PRIMARY KEY,
+		name VARCHAR(255) NOT NULL,
+		checksum VARCHAR(64) NOT NULL,
+		applied_at TIMESTAMP NOT NULL DEFAULT NOW()
+	)
+`
+
+// ErrMigrationDrift is returned when the migrations recorded in the database
+// no longer match the ones embedded in the binary
+var ErrMigrationDrift = errors.New("database schema has drifted from embedded migrations")
+
+// ErrPendingMigrations is returned at startup when migrations have not been
+// applied and automatic migration is disabled
+var ErrPendingMigrations = errors.New("database has pending migrations")
+
+// Migration is a single versioned schema change
+type Migration struct {
+	Version  int
+	Name     string
+	Up       string
+	Down     string
+	Checksum string
+}
+
+// MigrationStatus describes a migration and whether it has been applied.
+// Modified is set when the applied checksum differs from the embedded file,
+// and Unknown when the database records a version this binary does not have.
+type MigrationStatus struct {
+	Version   int
+	Name      string
+	Applied   bool
+	AppliedAt *time.Time
+	Modified  bool
+	Unknown   bool
+}
+
+type appliedMigration struct {
+	name      string
+	checksum  string
+	appliedAt time.Time
+}
+
+// LoadMigrations reads NNN_name.sql and NNN_name.down.sql files from fsys
+// and returns them ordered by version
+func LoadMigrations(fsys fs.FS) ([]Migration, error) {
+	files, err := fs.Glob(fsys, "*.sql")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list migrations: %w", err)
+	}
+
+	byVersion := make(map[int]*Migration)
+	downs := make(map[int]string)
+	for _, file := range files {
+		base := path.Base(file)
+		isDown := strings.HasSuffix(base, ".down.sql")
+		stem := strings.TrimSuffix(strings.TrimSuffix(base, ".sql"), ".down")
+
+		prefix, name, ok := strings.Cut(stem, "_")
+		version, err := strconv.Atoi(prefix)
+		if !ok || err != nil || version <= 0 || name == "" {
+			return nil, fmt.Errorf("invalid migration file name %q: expected NNN_name.sql", base)
+		}
+
+		content, err := fs.ReadFile(fsys, file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read migration %s: %w", base, err)
+		}
+
+		if isDown {
+			if _, exists := downs[version]; exists {
+				return nil, fmt.Errorf("duplicate down migration for version %d", version)
+			}
+			downs[version] = string(content)
+			continue
+		}
+
+		if existing, exists := byVersion[version]; exists {
+			return nil, fmt.Errorf("duplicate migration version %d: %s and %s", version, existing.Name, name)
+		}
+		sum := sha256.Sum256(content)
+		byVersion[version] = &Migration{
+			Version:  version,
+			Name:     name,
+			Up:       string(content),
+			Checksum: hex.EncodeToString(sum[:]),
+		}
+	}
+
+	for version, down := range downs {
+		m, ok := byVersion[version]
+		if !ok {
+			return nil, fmt.Errorf("down migration for version %d has no up migration", version)
+		}
+		m.Down = down
+	}
+
+	result := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		result = append(result, *m)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Version < result[j].Version })
+
+	return result, nil
+}
+
+// Migrator applies and reverts migrations, recording them in schema_migrations
+type Migrator struct {
+	db         *sql.DB
+	migrations []Migration
+}
+
+// NewMigrator creates a migrator for the migrations in fsys
+func NewMigrator(db *sql.DB, fsys fs.FS) (*Migrator, error) {
+	loaded, err := LoadMigrations(fsys)
+	if err != nil {
+		return nil, err
+	}
+	return &Migrator{db: db, migrations: loaded}, nil
+}
+
+// NewEmbeddedMigrator creates a migrator for the migrations built into the binary
+func NewEmbeddedMigrator(db *sql.DB) (*Migrator, error) {
+	return NewMigrator(db, migrations.FS)
+}
+
+// Up applies all pending migrations in order, each in its own transaction,
+// and returns how many were applied
+func (m *Migrator) Up(ctx context.Context) (int, error) {
+	count := 0
+	err := m.withLock(ctx, func(conn *sql.Conn) error {
+		applied, err := loadApplied(ctx, conn)
+		if err != nil {
+			return err
+		}
+		if err := m.checkDrift(applied); err != nil {
+			return err
+		}
+
+		for _, mig := range m.migrations {
+			if _, ok := applied[mig.Version]; ok {
+				continue
+			}
+			if err := m.apply(ctx, conn, mig); err != nil {
+				return err
+			}
+			count++
+		}
+		return nil
+	})
+	return count, err
+}
+
+// Down reverts the most recently applied migrations, up to steps of them
+func (m *Migrator) Down(ctx context.Context, steps int) (int, error) {
+	if steps <= 0 {
+		return 0, fmt.Errorf("steps must be positive, got %d", steps)
+	}
+
+	count := 0
+	err := m.withLock(ctx, func(conn *sql.Conn) error {
+		applied, err := loadApplied(ctx, conn)
+		if err != nil {
+			return err
+		}
+		if err := m.checkDrift(applied); err != nil {
+			return err
+		}
+
+		for i := len(m.migrations) - 1; i >= 0 && count < steps; i-- {
+			mig := m.migrations[i]
+			if _, ok := applied[mig.Version]; !ok {
+				continue
+			}
+			if mig.Down == "" {
+				return fmt.Errorf("migration %03d_%s has no down migration", mig.Version, mig.Name)
+			}
+			if err := m.revert(ctx, conn, mig); err != nil {
+				return err
+			}
+			count++
+		}
+		return nil
+	})
+	return count, err
+}
+
+// Baseline records migrations up to version as applied without running them,
+// for databases created before migrations were tracked. A version of 0
+// baselines every embedded migration.
+func (m *Migrator) Baseline(ctx context.Context, version int) (int, error) {
+	count := 0
+	err := m.withLock(ctx, func(conn *sql.Conn) error {
+		applied, err := loadApplied(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		for _, mig := range m.migrations {
+			if version > 0 && mig.Version > version {
+				break
+			}
+			if _, ok := applied[mig.Version]; ok {
+				continue
+			}
+			if err := recordApplied(ctx, conn, mig); err != nil {
+				return err
+			}
+			count++
+		}
+		return nil
+	})
+	return count, err
+}
+
+// Status reports every embedded migration along with any applied versions
+// this binary does not know about
+func (m *Migrator) Status(ctx context.Context) ([]MigrationStatus, error) {
+	if _, err := m.db.ExecContext(ctx, createSchemaMigrations); err != nil {
+		return nil, fmt.Errorf("failed to create schema_migrations: %w", err)
+	}
+	applied, err := loadApplied(ctx, m.db)
+	if err != nil {
+		return nil, err
+	}
+
+	known := make(map[int]bool, len(m.migrations))
+	statuses := make([]MigrationStatus, 0, len(m.migrations))
+	for _, mig := range m.migrations {
+		known[mig.Version] = true
+		status := MigrationStatus{Version: mig.Version, Name: mig.Name}
+		if rec, ok := applied[mig.Version]; ok {
+			appliedAt := rec.appliedAt
+			status.Applied = true
+			status.AppliedAt = &appliedAt
+			status.Modified = rec.checksum != mig.Checksum
+		}
+		statuses = append(statuses, status)
+	}
+
+	for version, rec := range applied {
+		if known[version] {
+			continue
+		}
+		appliedAt := rec.appliedAt
+		statuses = append(statuses, MigrationStatus{
+			Version:   version,
+			Name:      rec.name,
+			Applied:   true,
+			AppliedAt: &appliedAt,
+			Unknown:   true,
+		})
+	}
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].Version < statuses[j].Version })
+
+	return statuses, nil
+}
+
+// EnsureMigrated is called on service startup. With auto set it applies
+// pending migrations; otherwise it refuses to start against a schema that is
+// behind or has drifted from the embedded migrations.
+func EnsureMigrated(ctx context.Context, db *sql.DB, auto bool) error {
+	migrator, err := NewEmbeddedMigrator(db)
+	if err != nil {
+		return err
+	}
+
+	if auto {
+		applied, err := migrator.Up(ctx)
+		if err != nil {
+			return err
+		}
+		if applied > 0 {
+			utils.GetLoggerWithContext(ctx).WithField("applied", applied).Info("Database migrations applied")
+		}
+		return nil
+	}
+
+	statuses, err := migrator.Status(ctx)
+	if err != nil {
+		return err
+	}
+	pending := 0
+	for _, status := range statuses {
+		if status.Modified || status.Unknown {
+			return fmt.Errorf("%w: migration %03d_%s", ErrMigrationDrift, status.Version, status.Name)
+		}
+		if !status.Applied {
+			pending++
+		}
+	}
+	if pending > 0 {
+		return fmt.Errorf("%w: %d not applied, run \"migrate up\"", ErrPendingMigrations, pending)
+	}
+	return nil
+}
+
+// RunMigrateCommand implements the "migrate" subcommand shared by the
+// service binaries:
+//
+//	migrate up              apply pending migrations
+//	migrate down [n]        revert the last n migrations (default 1)
+//	migrate status          list migrations and whether they are applied
+//	migrate baseline [ver]  mark migrations up to ver as applied without running them
+func RunMigrateCommand(ctx context.Context, db *sql.DB, args []string, out io.Writer) error {
+	if len(args) == 0 {
+		return errors.New("usage: migrate up|down [n]|status|baseline [version]")
+	}
+
+	migrator, err := NewEmbeddedMigrator(db)
+	if err != nil {
+		return err
+	}
+
+	optionalInt := func(def int) (int, error) {
+		if len(args) < 2 {
+			return def, nil
+		}
+		n, err := strconv.Atoi(args[1])
+		if err != nil {
+			return 0, fmt.Errorf("invalid %s argument %q", args[0], args[1])
+		}
+		return n, nil
+	}
+
+	switch args[0] {
+	case "up":
+		applied, err := migrator.Up(ctx)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "Applied %d migration(s)\n", applied)
+	case "down":
+		steps, err := optionalInt(1)
+		if err != nil {
+			return err
+		}
+		reverted, err := migrator.Down(ctx, steps)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "Reverted %d migration(s)\n", reverted)
+	case "baseline":
+		version, err := optionalInt(0)
+		if err != nil {
+			return err
+		}
+		recorded, err := migrator.Baseline(ctx, version)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "Baselined %d migration(s)\n", recorded)
+	case "status":
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			return err
+		}
+		for _, status := range statuses {
+			state := "pending"
+			switch {
+			case status.Unknown:
+				state = "applied, not in this binary"
+			case status.Modified:
+				state = "applied, MODIFIED since"
+			case status.Applied:
+				state = "applied " + status.AppliedAt.Format(time.RFC3339)
+			}
+			fmt.Fprintf(out, "%03d_%s\t%s\n", status.Version, status.Name, state)
+		}
+	default:
+		return fmt.Errorf("unknown migrate command %q", args[0])
+	}
+	return nil
+}
+
+// withLock runs fn on a dedicated connection holding the migration advisory
+// lock. Other runners block until it is released.
+func (m *Migrator) withLock(ctx context.Context, fn func(conn *sql.Conn) error) error {
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to acquire connection: %w", err)
+	}
+	defer conn.Close()
+
+	if _, err := conn.ExecContext(ctx, "SELECT pg_advisory_lock($1)", migrationLockKey); err != nil {
+		return fmt.Errorf("failed to acquire migration lock: %w", err)
+	}
+	defer conn.ExecContext(context.Background(), "SELECT pg_advisory_unlock($1)", migrationLockKey)
+
+	if _, err := conn.ExecContext(ctx, createSchemaMigrations); err != nil {
+		return fmt.Errorf("failed to create schema_migrations: %w", err)
+	}
+
+	return fn(conn)
+}
+
+// checkDrift fails when an applied migration was edited after it ran or is
+// missing from this binary
+func (m *Migrator) checkDrift(applied map[int]appliedMigration) error {
+	embedded := make(map[int]Migration, len(m.migrations))
+	for _, mig := range m.migrations {
+		embedded[mig.Version] = mig
+	}
+
+	for version, rec := range applied {
+		mig, ok := embedded[version]
+		if !ok {
+			return fmt.Errorf("%w: version %03d_%s is applied but not embedded in this binary", ErrMigrationDrift, version, rec.name)
+		}
+		if rec.checksum != mig.Checksum {
+			return fmt.Errorf("%w: %03d_%s was modified after it was applied", ErrMigrationDrift, version, mig.Name)
+		}
+	}
+	return nil
+}
+
+func (m *Migrator) apply(ctx context.Context, conn *sql.Conn, mig Migration) error {
+	start := time.Now()
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, mig.Up); err != nil {
+		return fmt.Errorf("failed to apply migration %03d_%s: %w", mig.Version, mig.Name, err)
+	}
+	if err := recordApplied(ctx, tx, mig); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit migration %03d_%s: %w", mig.Version, mig.Name, err)
+	}
+
+	utils.GetLoggerWithContext(ctx).WithField("version", mig.Version).WithField("name", mig.Name).
+		WithField("duration_ms", time.Since(start).Milliseconds()).Info("Applied migration")
+	return nil
+}
+
+func (m *Migrator) revert(ctx context.Context, conn *sql.Conn, mig Migration) error {
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, mig.Down); err != nil {
+		return fmt.Errorf("failed to revert migration %03d_%s: %w", mig.Version, mig.Name, err)
+	}
+	if _, err := tx.ExecContext(ctx, "DELETE FROM schema_migrations WHERE version = $1", mig.Version); err != nil {
+		return fmt.Errorf("failed to unrecord migration %03d_%s: %w", mig.Version, mig.Name, err)
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit revert of %03d_%s: %w", mig.Version, mig.Name, err)
+	}
+
+	utils.GetLoggerWithContext(ctx).WithField("version", mig.Version).WithField("name", mig.Name).Info("Reverted migration")
+	return nil
+}
+
+type execer interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+}
+
+type querier interface {
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+}
+
+func recordApplied(ctx context.Context, db execer, mig Migration) error {
+	_, err := db.ExecContext(ctx,
+		"INSERT INTO schema_migrations (version, name, checksum) VALUES ($1, $2, $3)",
+		mig.Version, mig.Name, mig.Checksum,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to record migration %03d_%s: %w", mig.Version, mig.Name, err)
+	}
+	return nil
+}
+
+func loadApplied(ctx context.Context, db querier) (map[int]appliedMigration, error) {
+	rows, err := db.QueryContext(ctx, "SELECT version, name, checksum, applied_at FROM schema_migrations")
+	if err != nil {
+		return nil, fmt.Errorf("failed to load applied migrations: %w", err)
+	}
+	defer rows.Close()
+
+	applied := make(map[int]appliedMigration)
+	for rows.Next() {
+		var version int
+		var rec appliedMigration
+		if err := rows.Scan(&version, &rec.name, &rec.checksum, &rec.appliedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan applied migration: %w", err)
+		}
+		applied[version] = rec
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to load applied migrations: %w", err)
+	}
+	return applied, nil
+}
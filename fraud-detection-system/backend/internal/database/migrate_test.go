@@ -0,0 +1,96 @@
+package database
+
+import (
+	"errors"
+	"testing"
+	"testing/fstest"
+
+	"github.com/fraud-detection-system/backend/internal/database/migrations"
+)
+
+func TestLoadMigrationsOrdersAndPairsDowns(t *testing.T) {
+	fsys := fstest.MapFS{
+		"002_add_b.sql":         {Data: []byte("ALTER TABLE a ADD COLUMN b INT;")},
+		"001_create_a.sql":      {Data: []byte("CREATE TABLE a (id INT);")},
+		"001_create_a.down.sql": {Data: []byte("DROP TABLE a;")},
+		"embed.go":              {Data: []byte("package migrations")},
+	}
+
+	loaded, err := LoadMigrations(fsys)
+	if err != nil {
+		t.Fatalf("LoadMigrations() error = %v", err)
+	}
+	if len(loaded) != 2 {
+		t.Fatalf("got %d migrations, want 2", len(loaded))
+	}
+	if loaded[0].Version != 1 || loaded[0].Name != "create_a" || loaded[0].Down != "DROP TABLE a;" {
+		t.Errorf("first migration = %+v", loaded[0])
+	}
+	if loaded[1].Version != 2 || loaded[1].Down != "" {
+		t.Errorf("second migration = %+v", loaded[1])
+	}
+	if len(loaded[0].Checksum) != 64 || loaded[0].Checksum == loaded[1].Checksum {
+		t.Errorf("unexpected checksums %q and %q", loaded[0].Checksum, loaded[1].Checksum)
+	}
+}
+
+func TestLoadMigrationsRejectsInvalidSets(t *testing.T) {
+	tests := map[string]fstest.MapFS{
+		"bad name": {
+			"create_a.sql": {Data: []byte("")},
+		},
+		"duplicate version": {
+			"001_create_a.sql": {Data: []byte("")},
+			"001_create_b.sql": {Data: []byte("")},
+		},
+		"orphan down": {
+			"001_create_a.sql":   {Data: []byte("")},
+			"002_add_b.down.sql": {Data: []byte("")},
+		},
+	}
+
+	for name, fsys := range tests {
+		if _, err := LoadMigrations(fsys); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
+
+func TestCheckDrift(t *testing.T) {
+	m := &Migrator{migrations: []Migration{
+		{Version: 1, Name: "create_a", Checksum: "aaa"},
+		{Version: 2, Name: "add_b", Checksum: "bbb"},
+	}}
+
+	if err := m.checkDrift(map[int]appliedMigration{1: {name: "create_a", checksum: "aaa"}}); err != nil {
+		t.Errorf("unexpected drift: %v", err)
+	}
+
+	modified := map[int]appliedMigration{1: {name: "create_a", checksum: "edited"}}
+	if err := m.checkDrift(modified); !errors.Is(err, ErrMigrationDrift) {
+		t.Errorf("modified migration: got %v, want ErrMigrationDrift", err)
+	}
+
+	unknown := map[int]appliedMigration{3: {name: "add_c", checksum: "ccc"}}
+	if err := m.checkDrift(unknown); !errors.Is(err, ErrMigrationDrift) {
+		t.Errorf("unknown migration: got %v, want ErrMigrationDrift", err)
+	}
+}
+
+func TestEmbeddedMigrationsAreReversible(t *testing.T) {
+	loaded, err := LoadMigrations(migrations.FS)
+	if err != nil {
+		t.Fatalf("LoadMigrations() error = %v", err)
+	}
+	if len(loaded) == 0 {
+		t.Fatal("no embedded migrations")
+	}
+	for i, mig := range loaded {
+		if mig.Version != i+1 {
+			t.Errorf("migration %03d_%s: expected version %d, versions must be contiguous", mig.Version, mig.Name, i+1)
+		}
+		if mig.Down == "" {
+			t.Errorf("migration %03d_%s has no down migration", mig.Version, mig.Name)
+		}
+	}
+}
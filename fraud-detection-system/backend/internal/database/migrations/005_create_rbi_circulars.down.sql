@@ -0,0 +1 @@
+DROP TABLE IF EXISTS rbi_circulars;
@@ -0,0 +1,20 @@
+-- Add roles and TOTP two-factor authentication to users
+ALTER TABLE users ADD COLUMN IF NOT EXISTS role VARCHAR(20) NOT NULL DEFAULT 'USER' CHECK (role IN ('USER', 'ANALYST', 'ADMIN'));
+ALTER TABLE users ADD COLUMN IF NOT EXISTS totp_secret VARCHAR(64);
+ALTER TABLE users ADD COLUMN IF NOT EXISTS totp_enabled BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS totp_enabled_at TIMESTAMP;
+
+CREATE INDEX idx_users_role ON users(role);
+
+-- Create recovery codes table
+CREATE TABLE IF NOT EXISTS user_recovery_codes (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    code_hash VARCHAR(64) NOT NULL,
+    used_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Create indexes
+CREATE INDEX idx_user_recovery_codes_user_id ON user_recovery_codes(user_id);
+CREATE UNIQUE INDEX idx_user_recovery_codes_user_code ON user_recovery_codes(user_id, code_hash);
@@ -0,0 +1,9 @@
+DROP TABLE IF EXISTS retention_runs;
+DROP INDEX IF EXISTS idx_verifications_tenant_created_at;
+DROP INDEX IF EXISTS idx_messages_content_hash;
+DROP INDEX IF EXISTS idx_messages_tenant_created_at;
+ALTER TABLE verifications DROP COLUMN IF EXISTS tenant_id;
+ALTER TABLE messages DROP COLUMN IF EXISTS anonymized_at;
+ALTER TABLE messages DROP COLUMN IF EXISTS features;
+ALTER TABLE messages DROP COLUMN IF EXISTS content_hash;
+ALTER TABLE messages DROP COLUMN IF EXISTS tenant_id;
@@ -0,0 +1,30 @@
+-- Tenant ownership and retention metadata for messages
+ALTER TABLE messages ADD COLUMN IF NOT EXISTS tenant_id VARCHAR(64) NOT NULL DEFAULT 'default';
+ALTER TABLE messages ADD COLUMN IF NOT EXISTS content_hash VARCHAR(64);
+ALTER TABLE messages ADD COLUMN IF NOT EXISTS features JSONB;
+ALTER TABLE messages ADD COLUMN IF NOT EXISTS anonymized_at TIMESTAMP;
+
+-- Tenant ownership for verifications
+ALTER TABLE verifications ADD COLUMN IF NOT EXISTS tenant_id VARCHAR(64) NOT NULL DEFAULT 'default';
+
+-- Create indexes
+CREATE INDEX idx_messages_tenant_created_at ON messages(tenant_id, created_at);
+CREATE INDEX idx_messages_content_hash ON messages(content_hash);
+CREATE INDEX idx_verifications_tenant_created_at ON verifications(tenant_id, created_at);
+
+-- Create retention runs table
+CREATE TABLE IF NOT EXISTS retention_runs (
+    id UUID PRIMARY KEY,
+    started_at TIMESTAMP NOT NULL,
+    finished_at TIMESTAMP,
+    status VARCHAR(20) NOT NULL CHECK (status IN ('RUNNING', 'COMPLETED', 'FAILED')),
+    messages_deleted BIGINT NOT NULL DEFAULT 0,
+    messages_anonymized BIGINT NOT NULL DEFAULT 0,
+    verifications_deleted BIGINT NOT NULL DEFAULT 0,
+    reset_tokens_deleted BIGINT NOT NULL DEFAULT 0,
+    details JSONB,
+    error TEXT
+);
+
+-- Create indexes
+CREATE INDEX idx_retention_runs_started_at ON retention_runs(started_at);
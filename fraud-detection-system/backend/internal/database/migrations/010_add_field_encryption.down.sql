@@ -0,0 +1,10 @@
+-- phone_number stays TEXT: encrypted values do not fit the original VARCHAR(20)
+-- and must be decrypted by reencrypt before the column could be narrowed
+DROP INDEX IF EXISTS idx_reports_encryption_key_id;
+DROP INDEX IF EXISTS idx_reports_content_hash;
+DROP INDEX IF EXISTS idx_messages_encryption_key_id;
+DROP INDEX IF EXISTS idx_messages_phone_number_hash;
+ALTER TABLE reports DROP COLUMN IF EXISTS encryption_key_id;
+ALTER TABLE reports DROP COLUMN IF EXISTS content_hash;
+ALTER TABLE messages DROP COLUMN IF EXISTS encryption_key_id;
+ALTER TABLE messages DROP COLUMN IF EXISTS phone_number_hash;
@@ -0,0 +1,16 @@
+-- Encrypted values are longer than the plaintext they replace
+ALTER TABLE messages ALTER COLUMN phone_number TYPE TEXT;
+
+-- Keyed hashes and key IDs for encrypted message columns
+ALTER TABLE messages ADD COLUMN IF NOT EXISTS phone_number_hash VARCHAR(64);
+ALTER TABLE messages ADD COLUMN IF NOT EXISTS encryption_key_id VARCHAR(64);
+
+-- Keyed hashes and key IDs for encrypted report columns
+ALTER TABLE reports ADD COLUMN IF NOT EXISTS content_hash VARCHAR(64);
+ALTER TABLE reports ADD COLUMN IF NOT EXISTS encryption_key_id VARCHAR(64);
+
+-- Create indexes
+CREATE INDEX idx_messages_phone_number_hash ON messages(phone_number_hash);
+CREATE INDEX idx_messages_encryption_key_id ON messages(encryption_key_id);
+CREATE INDEX idx_reports_content_hash ON reports(content_hash);
+CREATE INDEX idx_reports_encryption_key_id ON reports(encryption_key_id);
@@ -0,0 +1,2 @@
+-- Flag verifications scored by the fallback scorer while the ML service was unavailable
+ALTER TABLE verifications ADD COLUMN IF NOT EXISTS degraded BOOLEAN NOT NULL DEFAULT FALSE;
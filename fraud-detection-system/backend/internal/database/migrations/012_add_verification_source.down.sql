@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_verifications_source_verification_id;
+ALTER TABLE verifications DROP COLUMN IF EXISTS source_verification_id;
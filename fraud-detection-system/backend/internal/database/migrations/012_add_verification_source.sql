@@ -0,0 +1,5 @@
+-- Link verifications served from the verdict cache to the verification they reuse
+ALTER TABLE verifications ADD COLUMN IF NOT EXISTS source_verification_id UUID REFERENCES verifications(id) ON DELETE SET NULL;
+
+-- Create indexes
+CREATE INDEX idx_verifications_source_verification_id ON verifications(source_verification_id);
@@ -0,0 +1,2 @@
+-- Per-stage durations and outcomes of each verification
+ALTER TABLE verifications ADD COLUMN IF NOT EXISTS stage_timings JSONB;
@@ -0,0 +1,15 @@
+-- Create outbox table for side effects committed with the records that
+-- caused them and applied later by the worker
+CREATE TABLE IF NOT EXISTS outbox (
+    id BIGSERIAL PRIMARY KEY,
+    kind VARCHAR(64) NOT NULL,
+    payload JSONB NOT NULL,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    last_error TEXT,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    processed_at TIMESTAMP
+);
+
+-- Create indexes
+CREATE INDEX IF NOT EXISTS idx_outbox_pending ON outbox(id) WHERE processed_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_outbox_processed_at ON outbox(processed_at);
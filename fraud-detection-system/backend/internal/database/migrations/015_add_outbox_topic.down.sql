@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_outbox_kind_pending;
+CREATE INDEX IF NOT EXISTS idx_outbox_pending ON outbox(id) WHERE processed_at IS NULL;
+ALTER TABLE outbox DROP COLUMN IF EXISTS message_key;
+ALTER TABLE outbox DROP COLUMN IF EXISTS topic;
@@ -0,0 +1,7 @@
+-- Kafka messages are outbox entries with a topic and partition key
+ALTER TABLE outbox ADD COLUMN IF NOT EXISTS topic VARCHAR(255);
+ALTER TABLE outbox ADD COLUMN IF NOT EXISTS message_key VARCHAR(255);
+
+-- Each kind is processed as its own ordered stream
+DROP INDEX IF EXISTS idx_outbox_pending;
+CREATE INDEX IF NOT EXISTS idx_outbox_kind_pending ON outbox(kind, id) WHERE processed_at IS NULL;
@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_messages_message_type;
+DROP INDEX IF EXISTS idx_reports_user_updated_id;
+DROP INDEX IF EXISTS idx_reports_user_created_id;
+DROP INDEX IF EXISTS idx_verifications_user_fraud_created;
+DROP INDEX IF EXISTS idx_verifications_user_score_id;
+DROP INDEX IF EXISTS idx_verifications_user_created_id;
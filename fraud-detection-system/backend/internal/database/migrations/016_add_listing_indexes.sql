@@ -0,0 +1,10 @@
+-- Keyset pagination walks (user_id, sort key, id), so each listing sort gets
+-- an index that serves both the range condition and the ORDER BY
+CREATE INDEX IF NOT EXISTS idx_verifications_user_created_id ON verifications(user_id, created_at, id);
+CREATE INDEX IF NOT EXISTS idx_verifications_user_score_id ON verifications(user_id, fraud_score, id);
+CREATE INDEX IF NOT EXISTS idx_verifications_user_fraud_created ON verifications(user_id, is_fraud, created_at);
+CREATE INDEX IF NOT EXISTS idx_reports_user_created_id ON reports(user_id, created_at, id);
+CREATE INDEX IF NOT EXISTS idx_reports_user_updated_id ON reports(user_id, updated_at, id);
+
+-- History can be filtered by message type
+CREATE INDEX IF NOT EXISTS idx_messages_message_type ON messages(message_type);
@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_messages_sender_header_trgm;
+DROP INDEX IF EXISTS idx_messages_search_urls_trgm;
+DROP INDEX IF EXISTS idx_messages_search_vector;
+ALTER TABLE messages DROP COLUMN IF EXISTS search_urls;
+ALTER TABLE messages DROP COLUMN IF EXISTS search_vector;
+DROP EXTENSION IF EXISTS pg_trgm;
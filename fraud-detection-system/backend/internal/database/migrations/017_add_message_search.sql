@@ -0,0 +1,16 @@
+-- Trigram indexes serve substring searches of URLs and sender headers
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+-- Content is encrypted, so its search vector is built by the application
+-- from the plaintext with PII removed. Messages stored before this are
+-- indexed by the reencrypt command.
+ALTER TABLE messages ADD COLUMN IF NOT EXISTS search_vector TSVECTOR;
+
+-- Extracted URLs as one string; array_to_string cannot be used in an index
+ALTER TABLE messages ADD COLUMN IF NOT EXISTS search_urls TEXT;
+UPDATE messages SET search_urls = array_to_string(extracted_urls, ' ')
+WHERE anonymized_at IS NULL AND cardinality(extracted_urls) > 0;
+
+CREATE INDEX IF NOT EXISTS idx_messages_search_vector ON messages USING GIN (search_vector);
+CREATE INDEX IF NOT EXISTS idx_messages_search_urls_trgm ON messages USING GIN (search_urls gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS idx_messages_sender_header_trgm ON messages USING GIN (sender_header gin_trgm_ops);
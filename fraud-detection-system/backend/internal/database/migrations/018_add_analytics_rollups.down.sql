@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS verification_dimension_rollups;
+DROP TABLE IF EXISTS verification_rollups;
@@ -0,0 +1,60 @@
+-- Hourly verification counts per tenant and message type. The worker adds
+-- each verification as its analytics outbox entry is processed; day and week
+-- series are summed from the hours.
+CREATE TABLE IF NOT EXISTS verification_rollups (
+    tenant_id VARCHAR(64) NOT NULL,
+    message_type VARCHAR(20) NOT NULL,
+    bucket_start TIMESTAMP NOT NULL,
+    verifications BIGINT NOT NULL DEFAULT 0,
+    fraud BIGINT NOT NULL DEFAULT 0,
+    risk_low BIGINT NOT NULL DEFAULT 0,
+    risk_medium BIGINT NOT NULL DEFAULT 0,
+    risk_high BIGINT NOT NULL DEFAULT 0,
+    risk_critical BIGINT NOT NULL DEFAULT 0,
+    PRIMARY KEY (tenant_id, message_type, bucket_start)
+);
+
+-- Cross-tenant series scan by time alone
+CREATE INDEX IF NOT EXISTS idx_verification_rollups_bucket ON verification_rollups(bucket_start);
+
+-- Hourly fraud counts per fraud type, mentioned bank and sender, for the top
+-- lists. They are hourly like the series so both cover the same range.
+CREATE TABLE IF NOT EXISTS verification_dimension_rollups (
+    tenant_id VARCHAR(64) NOT NULL,
+    message_type VARCHAR(20) NOT NULL,
+    dimension VARCHAR(20) NOT NULL,
+    value VARCHAR(100) NOT NULL,
+    bucket_start TIMESTAMP NOT NULL,
+    fraud BIGINT NOT NULL DEFAULT 0,
+    PRIMARY KEY (tenant_id, dimension, bucket_start, message_type, value)
+);
+
+CREATE INDEX IF NOT EXISTS idx_verification_dimension_rollups_bucket ON verification_dimension_rollups(dimension, bucket_start);
+
+-- Backfill from the verifications already stored. Risk levels come from the
+-- fraud score alone and banks cannot be recovered from encrypted content.
+INSERT INTO verification_rollups (tenant_id, message_type, bucket_start, verifications, fraud, risk_low, risk_medium, risk_high, risk_critical)
+SELECT v.tenant_id, COALESCE(NULLIF(m.message_type, ''), 'SMS'), date_trunc('hour', v.created_at),
+       COUNT(*),
+       COUNT(*) FILTER (WHERE v.is_fraud),
+       COUNT(*) FILTER (WHERE v.fraud_score < 0.4),
+       COUNT(*) FILTER (WHERE v.fraud_score >= 0.4 AND v.fraud_score < 0.6),
+       COUNT(*) FILTER (WHERE v.fraud_score >= 0.6 AND v.fraud_score < 0.8),
+       COUNT(*) FILTER (WHERE v.fraud_score >= 0.8)
+FROM verifications v
+LEFT JOIN messages m ON m.id = v.message_id
+GROUP BY 1, 2, 3;
+
+INSERT INTO verification_dimension_rollups (tenant_id, message_type, dimension, value, bucket_start, fraud)
+SELECT v.tenant_id, COALESCE(NULLIF(m.message_type, ''), 'SMS'), 'fraud_type', v.fraud_type, date_trunc('hour', v.created_at), COUNT(*)
+FROM verifications v
+LEFT JOIN messages m ON m.id = v.message_id
+WHERE v.is_fraud AND COALESCE(v.fraud_type, '') <> ''
+GROUP BY 1, 2, 4, 5;
+
+INSERT INTO verification_dimension_rollups (tenant_id, message_type, dimension, value, bucket_start, fraud)
+SELECT v.tenant_id, COALESCE(NULLIF(m.message_type, ''), 'SMS'), 'sender', m.sender_header, date_trunc('hour', v.created_at), COUNT(*)
+FROM verifications v
+JOIN messages m ON m.id = v.message_id
+WHERE v.is_fraud AND m.sender_header <> ''
+GROUP BY 1, 2, 4, 5;
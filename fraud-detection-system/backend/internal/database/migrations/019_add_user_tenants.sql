@@ -0,0 +1,3 @@
+-- Users belong to a tenant, which scopes the data their requests can reach
+ALTER TABLE users ADD COLUMN IF NOT EXISTS tenant_id VARCHAR(64) NOT NULL DEFAULT 'default';
+CREATE INDEX IF NOT EXISTS idx_users_tenant_id ON users(tenant_id);
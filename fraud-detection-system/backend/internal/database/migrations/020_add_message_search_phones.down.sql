@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_messages_search_phone_hashes;
+ALTER TABLE messages DROP COLUMN IF EXISTS search_phone_hashes;
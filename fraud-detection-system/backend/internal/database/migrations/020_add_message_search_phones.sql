@@ -0,0 +1,7 @@
+-- Phone numbers are redacted from the search vector, so searches match the
+-- keyed hashes of the normalized numbers a message mentions instead. NULL
+-- until the message is indexed; existing messages are indexed by the
+-- reencrypt command.
+ALTER TABLE messages ADD COLUMN IF NOT EXISTS search_phone_hashes TEXT[];
+
+CREATE INDEX IF NOT EXISTS idx_messages_search_phone_hashes ON messages USING GIN (search_phone_hashes);
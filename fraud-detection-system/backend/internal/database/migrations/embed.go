@@ -0,0 +1,14 @@
+// Package migrations embeds the versioned schema migrations so every binary
+// carries the schema it was built against.
+//
+// Migrations are named NNN_description.sql, with an optional
+// NNN_description.down.sql that reverts them. Applied migrations must not be
+// edited; add a new one instead.
+package migrations
+
+import "embed"
+
+// FS holds the up and down migration files.
+//
+//go:embed *.sql
+var FS embed.FS
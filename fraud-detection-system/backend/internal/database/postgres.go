@@ -8,16 +8,61 @@ import (
 
 	_ "github.com/lib/pq"
 	"github.com/fraud-detection-system/backend/internal/config"
+	"github.com/fraud-detection-system/backend/internal/metrics"
 )
 
+// defaultReplicaCheckInterval is used when the configured interval is not positive
+const defaultReplicaCheckInterval = 5 * time.Second
+
 type Database struct {
-	DB *sql.DB
+	DB          *sql.DB
+	reader      *ReadRouter
+	stopMonitor context.CancelFunc
 }
 
-// NewDatabase creates a new database connection
+// NewDatabase creates a new database connection, plus a pool per configured
+// read replica. Replicas that are down at startup do not prevent it; reads
+// go to the primary until they recover.
 func NewDatabase(cfg *config.Config) (*Database, error) {
-	connStr := cfg.GetDatabaseURL()
-	
+	db, err := openPool(cfg, cfg.GetDatabaseURL())
+	if err != nil {
+		return nil, err
+	}
+
+	// Test connection
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to ping database: %w", err)
+	}
+
+	d := &Database{DB: db, reader: newReadRouter(db, cfg.Database.ReplicaMaxLag)}
+	if len(cfg.Database.ReplicaHosts) == 0 {
+		return d, nil
+	}
+
+	for i, host := range cfg.Database.ReplicaHosts {
+		replicaDB, err := openPool(cfg, cfg.GetReplicaURL(host))
+		if err != nil {
+			d.Close()
+			return nil, fmt.Errorf("replica %s: %w", host, err)
+		}
+		d.reader.addReplica(fmt.Sprintf("replica-%d", i+1), replicaDB)
+	}
+
+	interval := cfg.Database.ReplicaCheckInterval
+	if interval <= 0 {
+		interval = defaultReplicaCheckInterval
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	d.stopMonitor = cancel
+	d.reader.check(ctx, interval)
+	go d.reader.monitor(ctx, interval)
+
+	return d, nil
+}
+
+// openPool opens a connection pool with the configured pool settings
+func openPool(cfg *config.Config, connStr string) (*sql.DB, error) {
 	db, err := sql.Open("postgres", connStr)
 	if err != nil {
 		return nil, fmt.Errorf("failed to open database: %w", err)
@@ -27,25 +72,38 @@ func NewDatabase(cfg *config.Config) (*Database, error) {
 	db.SetMaxOpenConns(cfg.Database.MaxConnections)
 	db.SetMaxIdleConns(cfg.Database.MaxIdleConns)
 	db.SetConnMaxLifetime(cfg.Database.ConnMaxLifetime)
+	db.SetConnMaxIdleTime(cfg.Database.ConnMaxIdleTime)
 
-	// Test connection
-	if err := db.Ping(); err != nil {
-		return nil, fmt.Errorf("failed to ping database: %w", err)
-	}
+	return db, nil
+}
+
+// Reader returns the router for read-only queries that tolerate replica lag.
+// Without replicas it reads from the primary.
+func (d *Database) Reader() *ReadRouter {
+	return d.reader
+}
 
-	return &Database{DB: db}, nil
+// RegisterMetrics exports the connection pool stats of the primary and
+// every replica, labelled by pool
+func (d *Database) RegisterMetrics() {
+	metrics.RegisterDB(PrimaryPool, d.DB)
+	for _, rep := range d.reader.replicas {
+		metrics.RegisterDB(rep.name, rep.db)
+	}
 }
 
-// Close closes the database connection
+// Close closes the database connection and replica pools
 func (d *Database) Close() error {
+	if d.stopMonitor != nil {
+		d.stopMonitor()
+	}
+	for _, rep := range d.reader.replicas {
+		rep.db.Close()
+	}
 	return d.DB.Close()
 }
 
 // HealthCheck checks if the database is healthy
-func (d *Database) HealthCheck() error {
-	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
-	defer cancel()
-	
+func (d *Database) HealthCheck(ctx context.Context) error {
 	return d.DB.PingContext(ctx)
 }
-
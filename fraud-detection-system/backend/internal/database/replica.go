@@ -0,0 +1,170 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/fraud-detection-system/backend/internal/metrics"
+	"github.com/fraud-detection-system/backend/internal/utils"
+)
+
+// PrimaryPool is the pool name of the primary in metrics
+const PrimaryPool = "primary"
+
+// replicaLagQuery returns whether the replica is streaming from the primary
+// and how far its replay is behind. A streaming replica that has replayed
+// everything it received is current even if no transaction has committed
+// recently; one that has lost its connection is not, since it cannot know
+// what it is missing.
+const replicaLagQuery = `
+	WITH state AS (
+		SELECT
+			pg_is_in_recovery() AS in_recovery,
+			EXISTS (SELECT 1 FROM pg_stat_wal_receiver WHERE status = 'streaming') AS streaming
+	)
+	SELECT
+		NOT in_recovery OR streaming,
+		COALESCE(
+			CASE
+				WHEN NOT in_recovery THEN 0
+				WHEN streaming AND pg_last_wal_receive_lsn() = pg_last_wal_replay_lsn() THEN 0
+				ELSE EXTRACT(EPOCH FROM NOW() - pg_last_xact_replay_timestamp())
+			END, 0)
+	FROM state
+`
+
+type replica struct {
+	name    string
+	db      *sql.DB
+	healthy atomic.Bool
+	checked bool
+}
+
+// ReadRouter sends read-only queries to a healthy read replica, round robin,
+// and falls back to the primary when no replica is reachable and within the
+// lag limit. Writes always go to the primary.
+type ReadRouter struct {
+	primary  *sql.DB
+	replicas []*replica
+	maxLag   time.Duration
+	next     atomic.Uint64
+}
+
+func newReadRouter(primary *sql.DB, maxLag time.Duration) *ReadRouter {
+	return &ReadRouter{primary: primary, maxLag: maxLag}
+}
+
+// addReplica registers a replica. It receives no reads until a check has
+// found it healthy.
+func (r *ReadRouter) addReplica(name string, db *sql.DB) {
+	r.replicas = append(r.replicas, &replica{name: name, db: db})
+}
+
+// pick returns the pool the next read should use
+func (r *ReadRouter) pick() (string, *sql.DB) {
+	n := len(r.replicas)
+	if n == 0 {
+		return PrimaryPool, r.primary
+	}
+
+	start := int(r.next.Add(1) % uint64(n))
+	for i := 0; i < n; i++ {
+		rep := r.replicas[(start+i)%n]
+		if rep.healthy.Load() {
+			return rep.name, rep.db
+		}
+	}
+	return PrimaryPool, r.primary
+}
+
+func (r *ReadRouter) reader() *sql.DB {
+	pool, db := r.pick()
+	metrics.DBReads.WithLabelValues(pool).Inc()
+	return db
+}
+
+// Pin returns the pool for a call that runs several queries, so they all
+// read from the same replica rather than ones at different positions
+func (r *ReadRouter) Pin() *sql.DB {
+	return r.reader()
+}
+
+// QueryContext runs a read-only query on the selected pool
+func (r *ReadRouter) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	return r.reader().QueryContext(ctx, query, args...)
+}
+
+// QueryRowContext runs a read-only single-row query on the selected pool
+func (r *ReadRouter) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	return r.reader().QueryRowContext(ctx, query, args...)
+}
+
+// ExecContext runs on the primary, so a write routed here by mistake still succeeds
+func (r *ReadRouter) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	return r.primary.ExecContext(ctx, query, args...)
+}
+
+// check refreshes the health and lag of every replica. It is only called
+// from one goroutine at a time.
+func (r *ReadRouter) check(ctx context.Context, timeout time.Duration) {
+	for _, rep := range r.replicas {
+		checkCtx, cancel := context.WithTimeout(ctx, timeout)
+		lag, err := replicaLag(checkCtx, rep.db)
+		cancel()
+
+		healthy := err == nil && lag <= r.maxLag
+		if !rep.checked || healthy != rep.healthy.Load() {
+			entry := utils.GetLogger().WithField("replica", rep.name).WithField("lag", lag.String())
+			if err != nil {
+				entry = entry.WithError(err)
+			}
+			if healthy {
+				entry.Info("Read replica is healthy, routing reads to it")
+			} else {
+				entry.Warn("Read replica is unavailable or lagging, routing its reads elsewhere")
+			}
+		}
+
+		rep.healthy.Store(healthy)
+		rep.checked = true
+		metrics.DBReplicaLag.WithLabelValues(rep.name).Set(lag.Seconds())
+		if healthy {
+			metrics.DBReplicaHealthy.WithLabelValues(rep.name).Set(1)
+		} else {
+			metrics.DBReplicaHealthy.WithLabelValues(rep.name).Set(0)
+		}
+	}
+}
+
+// monitor checks the replicas every interval until ctx is done
+func (r *ReadRouter) monitor(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			r.check(ctx, interval)
+		}
+	}
+}
+
+// replicaLag returns how far db is behind the primary, and an error if it
+// cannot tell because the replica is not streaming
+func replicaLag(ctx context.Context, db *sql.DB) (time.Duration, error) {
+	var streaming bool
+	var seconds float64
+	if err := db.QueryRowContext(ctx, replicaLagQuery).Scan(&streaming, &seconds); err != nil {
+		return 0, fmt.Errorf("failed to check replica lag: %w", err)
+	}
+	lag := time.Duration(seconds * float64(time.Second))
+	if !streaming {
+		return lag, fmt.Errorf("replica is not streaming from the primary")
+	}
+	return lag, nil
+}
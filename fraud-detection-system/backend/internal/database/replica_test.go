@@ -0,0 +1,59 @@
+package database
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+)
+
+func TestReadRouterPick(t *testing.T) {
+	primary, first, second := &sql.DB{}, &sql.DB{}, &sql.DB{}
+
+	router := newReadRouter(primary, time.Second)
+	if pool, db := router.pick(); pool != PrimaryPool || db != primary {
+		t.Fatalf("no replicas: got %s, want primary", pool)
+	}
+
+	router.addReplica("replica-1", first)
+	router.addReplica("replica-2", second)
+	if pool, _ := router.pick(); pool != PrimaryPool {
+		t.Errorf("unchecked replicas: got %s, want primary", pool)
+	}
+
+	router.replicas[0].healthy.Store(true)
+	router.replicas[1].healthy.Store(true)
+	seen := map[string]int{}
+	for i := 0; i < 4; i++ {
+		pool, _ := router.pick()
+		seen[pool]++
+	}
+	if seen["replica-1"] != 2 || seen["replica-2"] != 2 {
+		t.Errorf("expected round robin across replicas, got %v", seen)
+	}
+
+	router.replicas[0].healthy.Store(false)
+	for i := 0; i < 3; i++ {
+		if pool, db := router.pick(); pool != "replica-2" || db != second {
+			t.Errorf("one healthy replica: got %s, want replica-2", pool)
+		}
+	}
+
+	router.replicas[1].healthy.Store(false)
+	if pool, db := router.pick(); pool != PrimaryPool || db != primary {
+		t.Errorf("no healthy replicas: got %s, want primary", pool)
+	}
+}
+
+func TestReadRouterPin(t *testing.T) {
+	primary, replicaDB := &sql.DB{}, &sql.DB{}
+	router := newReadRouter(primary, time.Second)
+	if db := router.Pin(); db != primary {
+		t.Error("no replicas: expected the primary")
+	}
+
+	router.addReplica("replica-1", replicaDB)
+	router.replicas[0].healthy.Store(true)
+	if db := router.Pin(); db != replicaDB {
+		t.Error("healthy replica: expected the replica")
+	}
+}
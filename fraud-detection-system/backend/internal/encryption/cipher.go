@@ -0,0 +1,170 @@
+package encryption
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+// Encrypted values are stored as "enc:v1:<key id>:<wrapped data key>:<ciphertext>"
+const (
+	envelopePrefix    = "enc:v1:"
+	envelopeSeparator = ":"
+)
+
+// FieldCipher encrypts individual column values with envelope encryption:
+// each value gets a fresh AES-256-GCM data key, which is itself encrypted
+// with the provider's active key-encryption key
+type FieldCipher struct {
+	provider KeyProvider
+}
+
+func NewFieldCipher(provider KeyProvider) *FieldCipher {
+	return &FieldCipher{provider: provider}
+}
+
+// ActiveKeyID returns the ID of the key new values are encrypted with
+func (c *FieldCipher) ActiveKeyID() string {
+	return c.provider.ActiveKeyID()
+}
+
+// Encrypt encrypts plaintext with the active key and returns the envelope
+// along with the ID of the key used
+func (c *FieldCipher) Encrypt(plaintext string) (string, string, error) {
+	keyID := c.provider.ActiveKeyID()
+	kek, err := c.provider.Key(keyID)
+	if err != nil {
+		return "", "", err
+	}
+
+	dataKey := make([]byte, 32)
+	if _, err := rand.Read(dataKey); err != nil {
+		return "", "", fmt.Errorf("failed to generate data key: %w", err)
+	}
+
+	// Bind the wrapped key to its key ID so envelopes cannot be re-labelled
+	wrappedKey, err := seal(kek, dataKey, []byte(keyID))
+	if err != nil {
+		return "", "", err
+	}
+	ciphertext, err := seal(dataKey, []byte(plaintext), wrappedKey)
+	if err != nil {
+		return "", "", err
+	}
+
+	envelope := envelopePrefix + strings.Join([]string{
+		keyID,
+		base64.RawStdEncoding.EncodeToString(wrappedKey),
+		base64.RawStdEncoding.EncodeToString(ciphertext),
+	}, envelopeSeparator)
+
+	return envelope, keyID, nil
+}
+
+// Decrypt decrypts an envelope produced by Encrypt. Values without the
+// envelope prefix are legacy plaintext and are returned unchanged.
+func (c *FieldCipher) Decrypt(value string) (string, error) {
+	if !IsEncrypted(value) {
+		return value, nil
+	}
+
+	parts := strings.Split(strings.TrimPrefix(value, envelopePrefix), envelopeSeparator)
+	if len(parts) != 3 {
+		return "", fmt.Errorf("malformed encrypted value")
+	}
+
+	kek, err := c.provider.Key(parts[0])
+	if err != nil {
+		return "", err
+	}
+	wrappedKey, err := base64.RawStdEncoding.DecodeString(parts[1])
+	if err != nil {
+		return "", fmt.Errorf("malformed encrypted value: %w", err)
+	}
+	ciphertext, err := base64.RawStdEncoding.DecodeString(parts[2])
+	if err != nil {
+		return "", fmt.Errorf("malformed encrypted value: %w", err)
+	}
+
+	dataKey, err := open(kek, wrappedKey, []byte(parts[0]))
+	if err != nil {
+		return "", err
+	}
+	plaintext, err := open(dataKey, ciphertext, wrappedKey)
+	if err != nil {
+		return "", err
+	}
+
+	return string(plaintext), nil
+}
+
+// Hash returns a keyed hash of value, used to look up encrypted columns
+// without decrypting them
+func (c *FieldCipher) Hash(value string) string {
+	mac := hmac.New(sha256.New, c.provider.HashKey())
+	mac.Write([]byte(value))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// KeyPrefix returns the prefix of envelopes encrypted with keyID, used to
+// find values that are not yet on a key
+func KeyPrefix(keyID string) string {
+	return envelopePrefix + keyID + envelopeSeparator
+}
+
+// IsEncrypted reports whether value is an encryption envelope
+func IsEncrypted(value string) bool {
+	return strings.HasPrefix(value, envelopePrefix)
+}
+
+// seal encrypts plaintext with AES-GCM and prepends the nonce
+func seal(key, plaintext, additionalData []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("failed to generate nonce: %w", err)
+	}
+
+	return gcm.Seal(nonce, nonce, plaintext, additionalData), nil
+}
+
+// open reverses seal
+func open(key, sealed, additionalData []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(sealed) < gcm.NonceSize() {
+		return nil, fmt.Errorf("malformed encrypted value")
+	}
+	nonce, ciphertext := sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():]
+
+	plaintext, err := gcm.Open(nil, nonce, ciphertext, additionalData)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt value: %w", err)
+	}
+	return plaintext, nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create GCM: %w", err)
+	}
+	return gcm, nil
+}
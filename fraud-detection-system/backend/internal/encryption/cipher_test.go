@@ -0,0 +1,77 @@
+package encryption
+
+import (
+	"encoding/base64"
+	"strings"
+	"testing"
+
+	"github.com/fraud-detection-system/backend/internal/config"
+)
+
+func testKey(b byte) string {
+	return base64.StdEncoding.EncodeToString([]byte(strings.Repeat(string(b), 32)))
+}
+
+func newTestCipher(t *testing.T, keys, active string) *FieldCipher {
+	t.Helper()
+	provider, err := NewLocalKeyProvider(config.EncryptionConfig{
+		Keys:        keys,
+		ActiveKeyID: active,
+		HashKey:     testKey('h'),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return NewFieldCipher(provider)
+}
+
+func TestEncryptDecrypt(t *testing.T) {
+	c := newTestCipher(t, "k1:"+testKey('a'), "k1")
+
+	envelope, keyID, err := c.Encrypt("Your KYC expires today, call 9876543210")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if keyID != "k1" || !IsEncrypted(envelope) || strings.Contains(envelope, "KYC") {
+		t.Fatalf("unexpected envelope %q with key %q", envelope, keyID)
+	}
+
+	plaintext, err := c.Decrypt(envelope)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if plaintext != "Your KYC expires today, call 9876543210" {
+		t.Errorf("got %q", plaintext)
+	}
+
+	// Legacy plaintext passes through
+	if got, _ := c.Decrypt("[redacted]"); got != "[redacted]" {
+		t.Errorf("got %q", got)
+	}
+
+	// Tampering is detected
+	if _, err := c.Decrypt(envelope[:len(envelope)-2] + "AA"); err == nil {
+		t.Error("expected error for tampered envelope")
+	}
+}
+
+func TestKeyRotation(t *testing.T) {
+	old := newTestCipher(t, "k1:"+testKey('a'), "k1")
+	envelope, _, err := old.Encrypt("secret")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	rotated := newTestCipher(t, "k1:"+testKey('a')+",k2:"+testKey('b'), "k2")
+	if got, err := rotated.Decrypt(envelope); err != nil || got != "secret" {
+		t.Fatalf("got %q, %v", got, err)
+	}
+	if _, keyID, _ := rotated.Encrypt("secret"); keyID != "k2" {
+		t.Errorf("got key %q, want k2", keyID)
+	}
+
+	// Hashes do not depend on the encryption key, so lookups survive rotation
+	if old.Hash("secret") != rotated.Hash("secret") {
+		t.Error("hash changed across key rotation")
+	}
+}
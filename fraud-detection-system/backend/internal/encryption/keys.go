@@ -0,0 +1,122 @@
+package encryption
+
+import (
+	"bufio"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/fraud-detection-system/backend/internal/config"
+)
+
+// KeyProvider supplies the key-encryption keys used to wrap per-value data
+// keys, and the key used for the searchable keyed hashes
+type KeyProvider interface {
+	// ActiveKeyID returns the ID of the key new values are encrypted with
+	ActiveKeyID() string
+	// Key returns the key-encryption key with the given ID
+	Key(keyID string) ([]byte, error)
+	// HashKey returns the HMAC key for keyed hashes
+	HashKey() []byte
+}
+
+// LocalKeyProvider holds keys loaded from the environment or a key file
+type LocalKeyProvider struct {
+	keys        map[string][]byte
+	activeKeyID string
+	hashKey     []byte
+}
+
+// NewLocalKeyProvider loads keys from ENCRYPTION_KEYS and ENCRYPTION_KEYS_FILE.
+// Both use "id:base64key" entries, comma separated in the variable and one
+// per line in the file. Keys must be 32 bytes (AES-256).
+func NewLocalKeyProvider(cfg config.EncryptionConfig) (*LocalKeyProvider, error) {
+	p := &LocalKeyProvider{
+		keys:        make(map[string][]byte),
+		activeKeyID: cfg.ActiveKeyID,
+	}
+
+	for _, entry := range strings.Split(cfg.Keys, ",") {
+		if err := p.addKey(entry); err != nil {
+			return nil, err
+		}
+	}
+
+	if cfg.KeysFile != "" {
+		f, err := os.Open(cfg.KeysFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open key file: %w", err)
+		}
+		defer f.Close()
+
+		scanner := bufio.NewScanner(f)
+		for scanner.Scan() {
+			line := strings.TrimSpace(scanner.Text())
+			if strings.HasPrefix(line, "#") {
+				continue
+			}
+			if err := p.addKey(line); err != nil {
+				return nil, err
+			}
+		}
+		if err := scanner.Err(); err != nil {
+			return nil, fmt.Errorf("failed to read key file: %w", err)
+		}
+	}
+
+	if len(p.keys) == 0 {
+		return nil, fmt.Errorf("no encryption keys configured")
+	}
+	if _, ok := p.keys[p.activeKeyID]; !ok {
+		return nil, fmt.Errorf("active encryption key %q is not configured", p.activeKeyID)
+	}
+
+	hashKey, err := base64.StdEncoding.DecodeString(cfg.HashKey)
+	if err != nil || len(hashKey) < 32 {
+		return nil, fmt.Errorf("ENCRYPTION_HASH_KEY must be at least 32 base64-encoded bytes")
+	}
+	p.hashKey = hashKey
+
+	return p, nil
+}
+
+func (p *LocalKeyProvider) addKey(entry string) error {
+	entry = strings.TrimSpace(entry)
+	if entry == "" {
+		return nil
+	}
+
+	id, encoded, ok := strings.Cut(entry, ":")
+	id = strings.TrimSpace(id)
+	if !ok || id == "" || strings.Contains(id, envelopeSeparator) {
+		return fmt.Errorf("invalid encryption key entry for key %q", id)
+	}
+
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+	if err != nil || len(key) != 32 {
+		return fmt.Errorf("encryption key %q must be 32 base64-encoded bytes", id)
+	}
+
+	p.keys[id] = key
+	return nil
+}
+
+// ActiveKeyID returns the ID of the key new values are encrypted with
+func (p *LocalKeyProvider) ActiveKeyID() string {
+	return p.activeKeyID
+}
+
+// Key returns the key-encryption key with the given ID
+func (p *LocalKeyProvider) Key(keyID string) ([]byte, error) {
+	key, ok := p.keys[keyID]
+	if !ok {
+		return nil, fmt.Errorf("unknown encryption key %q", keyID)
+	}
+	return key, nil
+}
+
+// HashKey returns the HMAC key for keyed hashes
+func (p *LocalKeyProvider) HashKey() []byte {
+	return p.hashKey
+}
@@ -0,0 +1,113 @@
+// Package events defines the domain events published to the events topic.
+// Each event is a models.QueueMessage whose Type names the event and whose
+// Version selects the schema its payload follows.
+package events
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/fraud-detection-system/backend/internal/models"
+)
+
+// Event types
+const (
+	TypeVerificationCompleted   models.MessageType = "verification.completed"
+	TypeReportFiled             models.MessageType = "report.filed"
+	TypeReportResolved          models.MessageType = "report.resolved"
+	TypeSenderReputationChanged models.MessageType = "sender.reputation_changed"
+	TypeCircularAdded           models.MessageType = "circular.added"
+)
+
+// Reasons a sender's reputation changed
+const (
+	ReputationReasonRegistered   = "registered"
+	ReputationReasonVerification = "verification"
+)
+
+// Event is a domain event payload
+type Event interface {
+	// EventType names the schema the payload follows
+	EventType() models.MessageType
+	// Key is the partition key; events with the same key keep their order
+	Key() string
+}
+
+// VerificationCompleted is published when a message has been verified
+type VerificationCompleted struct {
+	VerificationID       uuid.UUID  `json:"verification_id"`
+	MessageID            uuid.UUID  `json:"message_id"`
+	TenantID             string     `json:"tenant_id"`
+	SenderHeader         string     `json:"sender_header"`
+	IsFraud              bool       `json:"is_fraud"`
+	FraudScore           float64    `json:"fraud_score"`
+	FraudType            *string    `json:"fraud_type,omitempty"`
+	Confidence           float64    `json:"confidence"`
+	ModelVersion         string     `json:"model_version"`
+	Degraded             bool       `json:"degraded"`
+	SourceVerificationID *uuid.UUID `json:"source_verification_id,omitempty"`
+	CompletedAt          time.Time  `json:"completed_at"`
+}
+
+func (e *VerificationCompleted) EventType() models.MessageType { return TypeVerificationCompleted }
+func (e *VerificationCompleted) Key() string                   { return e.VerificationID.String() }
+
+// ReportFiled is published when a user reports a message. The reported
+// content is left out.
+type ReportFiled struct {
+	ReportID       uuid.UUID  `json:"report_id"`
+	ReportType     string     `json:"report_type"`
+	SenderHeader   string     `json:"sender_header"`
+	MessageID      *uuid.UUID `json:"message_id,omitempty"`
+	VerificationID *uuid.UUID `json:"verification_id,omitempty"`
+	Priority       string     `json:"priority"`
+	FiledAt        time.Time  `json:"filed_at"`
+}
+
+func (e *ReportFiled) EventType() models.MessageType { return TypeReportFiled }
+func (e *ReportFiled) Key() string                   { return e.ReportID.String() }
+
+// ReportResolved is published when a reviewer resolves or dismisses a report
+type ReportResolved struct {
+	ReportID     uuid.UUID `json:"report_id"`
+	ReportType   string    `json:"report_type"`
+	SenderHeader string    `json:"sender_header"`
+	Status       string    `json:"status"`
+	ReviewedBy   uuid.UUID `json:"reviewed_by"`
+	ResolvedAt   time.Time `json:"resolved_at"`
+}
+
+func (e *ReportResolved) EventType() models.MessageType { return TypeReportResolved }
+func (e *ReportResolved) Key() string                   { return e.ReportID.String() }
+
+// SenderReputationChanged is published when an admin registers a sender or a
+// verification moves its reputation score
+type SenderReputationChanged struct {
+	SenderID         string    `json:"sender_id"`
+	PreviousScore    *float64  `json:"previous_score,omitempty"` // unset for new senders
+	ReputationScore  float64   `json:"reputation_score"`
+	MessageCount     int       `json:"message_count"`
+	FraudReportCount int       `json:"fraud_report_count"`
+	Reason           string    `json:"reason"`
+	ChangedAt        time.Time `json:"changed_at"`
+}
+
+func (e *SenderReputationChanged) EventType() models.MessageType { return TypeSenderReputationChanged }
+func (e *SenderReputationChanged) Key() string                   { return e.SenderID }
+
+// CircularAdded is published when an admin records an RBI circular
+type CircularAdded struct {
+	CircularID     uuid.UUID  `json:"circular_id"`
+	CircularNumber string     `json:"circular_number"`
+	Title          string     `json:"title"`
+	Category       string     `json:"category"`
+	Keywords       []string   `json:"keywords"`
+	IssuedDate     time.Time  `json:"issued_date"`
+	EffectiveDate  *time.Time `json:"effective_date,omitempty"`
+	IsActive       bool       `json:"is_active"`
+	SourceURL      string     `json:"source_url,omitempty"`
+	AddedAt        time.Time  `json:"added_at"`
+}
+
+func (e *CircularAdded) EventType() models.MessageType { return TypeCircularAdded }
+func (e *CircularAdded) Key() string                   { return e.CircularID.String() }
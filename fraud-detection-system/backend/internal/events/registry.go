@@ -0,0 +1,247 @@
+package events
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/fraud-detection-system/backend/internal/models"
+)
+
+// FieldType is the JSON type of a payload field
+type FieldType string
+
+const (
+	FieldString    FieldType = "string"
+	FieldUUID      FieldType = "uuid"
+	FieldTimestamp FieldType = "timestamp" // RFC3339
+	FieldNumber    FieldType = "number"
+	FieldInteger   FieldType = "integer"
+	FieldBoolean   FieldType = "boolean"
+	FieldStrings   FieldType = "string[]"
+)
+
+// Field describes one payload field
+type Field struct {
+	Name        string    `json:"name"`
+	Type        FieldType `json:"type"`
+	Required    bool      `json:"required"`
+	Description string    `json:"description,omitempty"`
+}
+
+// Schema describes the payload of one version of an event
+type Schema struct {
+	Type        models.MessageType `json:"type"`
+	Version     int                `json:"version"`
+	Description string             `json:"description"`
+	Fields      []Field            `json:"fields"`
+}
+
+// IsText reports whether the field holds free text that may carry PII, as
+// opposed to an ID, timestamp or other typed value
+func (f Field) IsText() bool {
+	return f.Type == FieldString || f.Type == FieldStrings
+}
+
+func (s *Schema) field(name string) (Field, bool) {
+	for _, f := range s.Fields {
+		if f.Name == name {
+			return f, true
+		}
+	}
+	return Field{}, false
+}
+
+// Registry holds every version of every event schema. Versions of an event
+// must stay compatible, so a consumer written against any version can read
+// events of the later ones.
+type Registry struct {
+	schemas map[models.MessageType][]*Schema
+}
+
+func NewRegistry() *Registry {
+	return &Registry{schemas: make(map[models.MessageType][]*Schema)}
+}
+
+// Register adds the next version of an event's schema. Versions start at 1
+// and go up by one. A new version may add optional fields but must keep
+// every field of the previous version with the same type, and keep
+// required fields required.
+func (r *Registry) Register(schema *Schema) error {
+	versions := r.schemas[schema.Type]
+	if schema.Version != len(versions)+1 {
+		return fmt.Errorf("schema %s: expected version %d, got %d", schema.Type, len(versions)+1, schema.Version)
+	}
+
+	seen := make(map[string]bool)
+	for _, f := range schema.Fields {
+		if seen[f.Name] {
+			return fmt.Errorf("schema %s v%d: duplicate field %q", schema.Type, schema.Version, f.Name)
+		}
+		seen[f.Name] = true
+	}
+
+	if len(versions) > 0 {
+		if err := checkCompatible(versions[len(versions)-1], schema); err != nil {
+			return err
+		}
+	}
+
+	r.schemas[schema.Type] = append(versions, schema)
+	return nil
+}
+
+// checkCompatible reports how next breaks consumers of prev, if it does
+func checkCompatible(prev, next *Schema) error {
+	for _, old := range prev.Fields {
+		f, ok := next.field(old.Name)
+		if !ok {
+			return fmt.Errorf("schema %s v%d: removes field %q", next.Type, next.Version, old.Name)
+		}
+		if f.Type != old.Type {
+			return fmt.Errorf("schema %s v%d: changes type of field %q from %s to %s", next.Type, next.Version, old.Name, old.Type, f.Type)
+		}
+		if old.Required && !f.Required {
+			return fmt.Errorf("schema %s v%d: makes required field %q optional", next.Type, next.Version, old.Name)
+		}
+	}
+	for _, f := range next.Fields {
+		if _, ok := prev.field(f.Name); !ok && f.Required {
+			return fmt.Errorf("schema %s v%d: adds required field %q", next.Type, next.Version, f.Name)
+		}
+	}
+	return nil
+}
+
+// Lookup returns a version of an event's schema
+func (r *Registry) Lookup(eventType models.MessageType, version int) (*Schema, bool) {
+	versions := r.schemas[eventType]
+	if version < 1 || version > len(versions) {
+		return nil, false
+	}
+	return versions[version-1], true
+}
+
+// Latest returns the newest version of an event's schema
+func (r *Registry) Latest(eventType models.MessageType) (*Schema, bool) {
+	return r.Lookup(eventType, len(r.schemas[eventType]))
+}
+
+// Schemas returns every registered schema, ordered by type and version
+func (r *Registry) Schemas() []*Schema {
+	var schemas []*Schema
+	for _, versions := range r.schemas {
+		schemas = append(schemas, versions...)
+	}
+	sort.Slice(schemas, func(i, j int) bool {
+		if schemas[i].Type != schemas[j].Type {
+			return schemas[i].Type < schemas[j].Type
+		}
+		return schemas[i].Version < schemas[j].Version
+	})
+	return schemas
+}
+
+// NewMessage wraps an event in a queue message stamped with the latest
+// version of its schema, and checks the payload against it
+func (r *Registry) NewMessage(event Event) (*models.QueueMessage, error) {
+	schema, ok := r.Latest(event.EventType())
+	if !ok {
+		return nil, fmt.Errorf("no schema registered for event %s", event.EventType())
+	}
+
+	data, err := json.Marshal(event)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal event %s: %w", event.EventType(), err)
+	}
+	var payload map[string]interface{}
+	if err := json.Unmarshal(data, &payload); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal event %s: %w", event.EventType(), err)
+	}
+
+	message := models.NewQueueMessage(event.EventType(), payload)
+	message.Version = schema.Version
+	if err := r.Validate(message); err != nil {
+		return nil, err
+	}
+	return message, nil
+}
+
+// Validate checks a message's payload against the schema version it names.
+// Payloads may not carry fields their schema does not declare.
+func (r *Registry) Validate(message *models.QueueMessage) error {
+	schema, ok := r.Lookup(message.Type, message.Version)
+	if !ok {
+		return fmt.Errorf("unknown event %s v%d", message.Type, message.Version)
+	}
+
+	for _, f := range schema.Fields {
+		value, ok := message.Payload[f.Name]
+		if !ok || value == nil {
+			if f.Required {
+				return fmt.Errorf("event %s v%d: missing required field %q", schema.Type, schema.Version, f.Name)
+			}
+			continue
+		}
+		if err := checkType(f.Type, value); err != nil {
+			return fmt.Errorf("event %s v%d: field %q: %w", schema.Type, schema.Version, f.Name, err)
+		}
+	}
+	for name := range message.Payload {
+		if _, ok := schema.field(name); !ok {
+			return fmt.Errorf("event %s v%d: undeclared field %q", schema.Type, schema.Version, name)
+		}
+	}
+	return nil
+}
+
+// checkType checks a value decoded from JSON against a field type
+func checkType(fieldType FieldType, value interface{}) error {
+	switch fieldType {
+	case FieldString:
+		if _, ok := value.(string); ok {
+			return nil
+		}
+	case FieldUUID:
+		if s, ok := value.(string); ok {
+			if _, err := uuid.Parse(s); err != nil {
+				return fmt.Errorf("invalid UUID %q", s)
+			}
+			return nil
+		}
+	case FieldTimestamp:
+		if s, ok := value.(string); ok {
+			if _, err := time.Parse(time.RFC3339Nano, s); err != nil {
+				return fmt.Errorf("invalid timestamp %q", s)
+			}
+			return nil
+		}
+	case FieldNumber:
+		if _, ok := value.(float64); ok {
+			return nil
+		}
+	case FieldInteger:
+		if n, ok := value.(float64); ok && n == math.Trunc(n) {
+			return nil
+		}
+	case FieldBoolean:
+		if _, ok := value.(bool); ok {
+			return nil
+		}
+	case FieldStrings:
+		if items, ok := value.([]interface{}); ok {
+			for _, item := range items {
+				if _, ok := item.(string); !ok {
+					return fmt.Errorf("expected %s, got an element of %T", fieldType, item)
+				}
+			}
+			return nil
+		}
+	default:
+		return fmt.Errorf("unknown field type %s", fieldType)
+	}
+	return fmt.Errorf("expected %s, got %T", fieldType, value)
+}
@@ -0,0 +1,117 @@
+package events
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/fraud-detection-system/backend/internal/models"
+)
+
+func TestDefaultRegistryEvents(t *testing.T) {
+	r := DefaultRegistry()
+	fraudType := "PHISHING"
+	previous := 0.8
+	messageID := uuid.New()
+	now := time.Now()
+
+	events := []Event{
+		&VerificationCompleted{VerificationID: uuid.New(), MessageID: uuid.New(), TenantID: "default", SenderHeader: "AX-HDFC", FraudType: &fraudType, ModelVersion: "v1", CompletedAt: now},
+		&ReportFiled{ReportID: uuid.New(), ReportType: "FRAUD", SenderHeader: "AX-HDFC", MessageID: &messageID, Priority: "HIGH", FiledAt: now},
+		&ReportResolved{ReportID: uuid.New(), ReportType: "FRAUD", SenderHeader: "AX-HDFC", Status: "RESOLVED", ReviewedBy: uuid.New(), ResolvedAt: now},
+		&SenderReputationChanged{SenderID: "AX-HDFC", PreviousScore: &previous, ReputationScore: 0.7, MessageCount: 3, Reason: ReputationReasonVerification, ChangedAt: now},
+		&SenderReputationChanged{SenderID: "AX-HDFC", ReputationScore: 1, Reason: ReputationReasonRegistered, ChangedAt: now},
+		&CircularAdded{CircularID: uuid.New(), CircularNumber: "RBI/2024/1", Title: "KYC", Category: "KYC", Keywords: []string{"kyc"}, IssuedDate: now, IsActive: true, AddedAt: now},
+		&CircularAdded{CircularID: uuid.New(), CircularNumber: "RBI/2024/2", Title: "OTP", Category: "SECURITY", IssuedDate: now, AddedAt: now},
+	}
+	for _, event := range events {
+		message, err := r.NewMessage(event)
+		if err != nil {
+			t.Errorf("%s: %v", event.EventType(), err)
+			continue
+		}
+		if message.Type != event.EventType() || message.Version != 1 {
+			t.Errorf("%s: got type %s v%d", event.EventType(), message.Type, message.Version)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	r := DefaultRegistry()
+	valid := func() *models.QueueMessage {
+		m, err := r.NewMessage(&ReportResolved{ReportID: uuid.New(), ReportType: "FRAUD", SenderHeader: "AX-HDFC", Status: "DISMISSED", ReviewedBy: uuid.New(), ResolvedAt: time.Now()})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return m
+	}
+
+	tests := []struct {
+		name   string
+		modify func(m *models.QueueMessage)
+		want   string
+	}{
+		{"missing required", func(m *models.QueueMessage) { delete(m.Payload, "status") }, "missing required field"},
+		{"wrong type", func(m *models.QueueMessage) { m.Payload["status"] = 1.0 }, "expected string"},
+		{"bad uuid", func(m *models.QueueMessage) { m.Payload["report_id"] = "nope" }, "invalid UUID"},
+		{"bad timestamp", func(m *models.QueueMessage) { m.Payload["resolved_at"] = "yesterday" }, "invalid timestamp"},
+		{"undeclared field", func(m *models.QueueMessage) { m.Payload["content"] = "secret" }, "undeclared field"},
+		{"unknown version", func(m *models.QueueMessage) { m.Version = 2 }, "unknown event"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := valid()
+			tt.modify(m)
+			err := r.Validate(m)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("got %v, want error containing %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestRegisterCompatibility(t *testing.T) {
+	v1 := &Schema{Type: "test.event", Version: 1, Fields: []Field{
+		{Name: "id", Type: FieldUUID, Required: true},
+		{Name: "note", Type: FieldString},
+	}}
+
+	tests := []struct {
+		name   string
+		fields []Field
+		want   string
+	}{
+		{"adds optional field", []Field{{Name: "id", Type: FieldUUID, Required: true}, {Name: "note", Type: FieldString}, {Name: "extra", Type: FieldInteger}}, ""},
+		{"requires optional field", []Field{{Name: "id", Type: FieldUUID, Required: true}, {Name: "note", Type: FieldString, Required: true}}, ""},
+		{"removes field", []Field{{Name: "id", Type: FieldUUID, Required: true}}, "removes field"},
+		{"changes type", []Field{{Name: "id", Type: FieldString, Required: true}, {Name: "note", Type: FieldString}}, "changes type"},
+		{"relaxes required", []Field{{Name: "id", Type: FieldUUID}, {Name: "note", Type: FieldString}}, "makes required field"},
+		{"adds required field", []Field{{Name: "id", Type: FieldUUID, Required: true}, {Name: "note", Type: FieldString}, {Name: "extra", Type: FieldInteger, Required: true}}, "adds required field"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := NewRegistry()
+			if err := r.Register(v1); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			err := r.Register(&Schema{Type: "test.event", Version: 2, Fields: tt.fields})
+			if tt.want == "" {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("got %v, want error containing %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestRegisterVersionOrder(t *testing.T) {
+	r := NewRegistry()
+	if err := r.Register(&Schema{Type: "test.event", Version: 2}); err == nil {
+		t.Error("expected error registering version 2 first")
+	}
+}
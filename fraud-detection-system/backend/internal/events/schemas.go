@@ -0,0 +1,95 @@
+package events
+
+// schemas lists every published version of every event. Add a new version
+// rather than editing a published one; Register rejects changes that would
+// break existing consumers.
+var schemas = []*Schema{
+	{
+		Type:        TypeVerificationCompleted,
+		Version:     1,
+		Description: "A message was verified, either scored or answered from a cached verdict",
+		Fields: []Field{
+			{Name: "verification_id", Type: FieldUUID, Required: true},
+			{Name: "message_id", Type: FieldUUID, Required: true},
+			{Name: "tenant_id", Type: FieldString, Required: true},
+			{Name: "sender_header", Type: FieldString, Required: true},
+			{Name: "is_fraud", Type: FieldBoolean, Required: true},
+			{Name: "fraud_score", Type: FieldNumber, Required: true, Description: "0 to 1"},
+			{Name: "fraud_type", Type: FieldString},
+			{Name: "confidence", Type: FieldNumber, Required: true},
+			{Name: "model_version", Type: FieldString, Required: true},
+			{Name: "degraded", Type: FieldBoolean, Required: true, Description: "scored by the fallback scorer"},
+			{Name: "source_verification_id", Type: FieldUUID, Description: "set when the verdict came from the cache"},
+			{Name: "completed_at", Type: FieldTimestamp, Required: true},
+		},
+	},
+	{
+		Type:        TypeReportFiled,
+		Version:     1,
+		Description: "A user reported a message; the reported content is not included",
+		Fields: []Field{
+			{Name: "report_id", Type: FieldUUID, Required: true},
+			{Name: "report_type", Type: FieldString, Required: true, Description: "FRAUD, FALSE_POSITIVE or FEEDBACK"},
+			{Name: "sender_header", Type: FieldString, Required: true},
+			{Name: "message_id", Type: FieldUUID},
+			{Name: "verification_id", Type: FieldUUID},
+			{Name: "priority", Type: FieldString, Required: true},
+			{Name: "filed_at", Type: FieldTimestamp, Required: true},
+		},
+	},
+	{
+		Type:        TypeReportResolved,
+		Version:     1,
+		Description: "A reviewer resolved or dismissed a report",
+		Fields: []Field{
+			{Name: "report_id", Type: FieldUUID, Required: true},
+			{Name: "report_type", Type: FieldString, Required: true},
+			{Name: "sender_header", Type: FieldString, Required: true},
+			{Name: "status", Type: FieldString, Required: true, Description: "RESOLVED or DISMISSED"},
+			{Name: "reviewed_by", Type: FieldUUID, Required: true},
+			{Name: "resolved_at", Type: FieldTimestamp, Required: true},
+		},
+	},
+	{
+		Type:        TypeSenderReputationChanged,
+		Version:     1,
+		Description: "A sender was registered or its reputation score changed",
+		Fields: []Field{
+			{Name: "sender_id", Type: FieldString, Required: true},
+			{Name: "previous_score", Type: FieldNumber, Description: "unset for newly registered senders"},
+			{Name: "reputation_score", Type: FieldNumber, Required: true},
+			{Name: "message_count", Type: FieldInteger, Required: true},
+			{Name: "fraud_report_count", Type: FieldInteger, Required: true},
+			{Name: "reason", Type: FieldString, Required: true, Description: "registered or verification"},
+			{Name: "changed_at", Type: FieldTimestamp, Required: true},
+		},
+	},
+	{
+		Type:        TypeCircularAdded,
+		Version:     1,
+		Description: "An RBI circular was recorded",
+		Fields: []Field{
+			{Name: "circular_id", Type: FieldUUID, Required: true},
+			{Name: "circular_number", Type: FieldString, Required: true},
+			{Name: "title", Type: FieldString, Required: true},
+			{Name: "category", Type: FieldString, Required: true},
+			{Name: "keywords", Type: FieldStrings},
+			{Name: "issued_date", Type: FieldTimestamp, Required: true},
+			{Name: "effective_date", Type: FieldTimestamp},
+			{Name: "is_active", Type: FieldBoolean, Required: true},
+			{Name: "source_url", Type: FieldString},
+			{Name: "added_at", Type: FieldTimestamp, Required: true},
+		},
+	},
+}
+
+// DefaultRegistry returns a registry of the events this system publishes
+func DefaultRegistry() *Registry {
+	r := NewRegistry()
+	for _, schema := range schemas {
+		if err := r.Register(schema); err != nil {
+			panic(err)
+		}
+	}
+	return r
+}
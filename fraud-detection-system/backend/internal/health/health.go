@@ -0,0 +1,297 @@
+// Package health runs dependency checks for the liveness, readiness and
+// startup probes of every service.
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Probe and check statuses
+const (
+	StatusUp       = "up"
+	StatusDown     = "down"
+	StatusReady    = "ready"
+	StatusDegraded = "degraded" // a non-critical dependency is down
+	StatusNotReady = "not_ready"
+	StatusStarting = "starting"
+	StatusAlive    = "alive"
+)
+
+// CheckFunc reports whether a dependency is reachable
+type CheckFunc func(ctx context.Context) error
+
+// InfoFunc returns extra state to include in readiness reports
+type InfoFunc func() interface{}
+
+// CheckResult is the latest outcome of a check. LastError is kept after the
+// dependency recovers, so flapping shows up.
+type CheckResult struct {
+	Name        string     `json:"name"`
+	Status      string     `json:"status"`
+	Critical    bool       `json:"critical"`
+	LatencyMs   int64      `json:"latency_ms"`
+	CheckedAt   time.Time  `json:"checked_at"`
+	LastError   string     `json:"last_error,omitempty"`
+	LastErrorAt *time.Time `json:"last_error_at,omitempty"`
+}
+
+// Report is the body of a probe response
+type Report struct {
+	Status        string                 `json:"status"`
+	Service       string                 `json:"service"`
+	UptimeSeconds int64                  `json:"uptime_seconds"`
+	Checks        []CheckResult          `json:"checks,omitempty"`
+	Info          map[string]interface{} `json:"info,omitempty"`
+}
+
+type check struct {
+	name     string
+	critical bool
+	fn       CheckFunc
+}
+
+// Checker runs the registered dependency checks concurrently, each bounded
+// by a timeout. A failing critical dependency makes the service not ready;
+// a failing non-critical one only degrades it. Results are reused for
+// cacheTTL, so frequent probes do not hammer the dependencies.
+type Checker struct {
+	service     string
+	timeout     time.Duration
+	cacheTTL    time.Duration
+	criticality map[string]bool
+	startedAt   time.Time
+
+	runMu   sync.Mutex // one run of the checks at a time
+	mu      sync.Mutex
+	checks  []check
+	info    map[string]InfoFunc
+	results map[string]CheckResult
+	latest  []CheckResult
+	ranAt   time.Time
+	started bool
+	warm    bool // all critical checks have passed since startup
+}
+
+// NewChecker creates a checker. criticality overrides the criticality a
+// check is registered with, by name. A cacheTTL of zero runs the checks on
+// every probe.
+func NewChecker(service string, timeout, cacheTTL time.Duration, criticality map[string]bool) *Checker {
+	return &Checker{
+		service:     service,
+		timeout:     timeout,
+		cacheTTL:    cacheTTL,
+		criticality: criticality,
+		startedAt:   time.Now(),
+		info:        make(map[string]InfoFunc),
+		results:     make(map[string]CheckResult),
+	}
+}
+
+// Register adds a dependency check. critical is the default criticality,
+// which configuration may override.
+func (c *Checker) Register(name string, critical bool, fn CheckFunc) {
+	if override, ok := c.criticality[name]; ok {
+		critical = override
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.checks = append(c.checks, check{name: name, critical: critical, fn: fn})
+	c.latest = nil
+}
+
+// AddInfo adds state, such as circuit breaker states, to readiness reports
+func (c *Checker) AddInfo(name string, fn InfoFunc) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.info[name] = fn
+}
+
+// MarkStarted records that the service finished initializing. The startup
+// probe passes once this is called and the critical checks have passed.
+func (c *Checker) MarkStarted() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.started = true
+}
+
+// Live reports that the process is running; it checks no dependencies, so
+// a dependency outage does not get the service restarted
+func (c *Checker) Live(ctx context.Context) (int, Report) {
+	return http.StatusOK, c.report(StatusAlive, nil, nil)
+}
+
+// Ready runs every check and reports whether the service can take traffic
+func (c *Checker) Ready(ctx context.Context) (int, Report) {
+	results := c.run(ctx)
+
+	status, code := StatusReady, http.StatusOK
+	for _, result := range results {
+		if result.Status == StatusUp {
+			continue
+		}
+		if result.Critical {
+			status, code = StatusNotReady, http.StatusServiceUnavailable
+			break
+		}
+		status = StatusDegraded
+	}
+
+	c.mu.Lock()
+	if c.started && code == http.StatusOK {
+		c.warm = true
+	}
+	info := make(map[string]interface{}, len(c.info))
+	for name, fn := range c.info {
+		info[name] = fn()
+	}
+	c.mu.Unlock()
+
+	return code, c.report(status, results, info)
+}
+
+// Startup reports whether the service has finished starting. Once it has,
+// the dependency checks are not run again by this probe.
+func (c *Checker) Startup(ctx context.Context) (int, Report) {
+	c.mu.Lock()
+	started, warm := c.started, c.warm
+	c.mu.Unlock()
+
+	if !started {
+		return http.StatusServiceUnavailable, c.report(StatusStarting, nil, nil)
+	}
+	if warm {
+		return http.StatusOK, c.report(StatusReady, nil, nil)
+	}
+	code, report := c.Ready(ctx)
+	if code != http.StatusOK {
+		report.Status = StatusStarting
+	}
+	return code, report
+}
+
+// run runs all checks concurrently and records their results, or returns
+// the results of the last run if it was within cacheTTL. Concurrent probes
+// wait for a single run rather than starting their own.
+func (c *Checker) run(ctx context.Context) []CheckResult {
+	c.runMu.Lock()
+	defer c.runMu.Unlock()
+
+	c.mu.Lock()
+	if c.latest != nil && time.Since(c.ranAt) < c.cacheTTL {
+		results := append([]CheckResult(nil), c.latest...)
+		c.mu.Unlock()
+		return results
+	}
+	checks := append([]check(nil), c.checks...)
+	c.mu.Unlock()
+
+	// The results are shared with other probes, so a caller going away must
+	// not fail the checks; each is still bounded by the check timeout
+	ctx = context.WithoutCancel(ctx)
+
+	results := make([]CheckResult, len(checks))
+	var wg sync.WaitGroup
+	for i, chk := range checks {
+		wg.Add(1)
+		go func(i int, chk check) {
+			defer wg.Done()
+			results[i] = c.runCheck(ctx, chk)
+		}(i, chk)
+	}
+	wg.Wait()
+
+	sort.Slice(results, func(i, j int) bool { return results[i].Name < results[j].Name })
+
+	c.mu.Lock()
+	c.latest, c.ranAt = results, time.Now()
+	c.mu.Unlock()
+	return append([]CheckResult(nil), results...)
+}
+
+func (c *Checker) runCheck(ctx context.Context, chk check) CheckResult {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	start := time.Now()
+	err := runWithContext(ctx, chk.fn)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	result := c.results[chk.name]
+	result.Name = chk.name
+	result.Critical = chk.critical
+	result.LatencyMs = time.Since(start).Milliseconds()
+	result.CheckedAt = start
+	result.Status = StatusUp
+	if err != nil {
+		result.Status = StatusDown
+		result.LastError = err.Error()
+		result.LastErrorAt = &start
+	}
+	c.results[chk.name] = result
+	return result
+}
+
+// runWithContext returns when fn does or ctx is done, for checks that
+// ignore their context
+func runWithContext(ctx context.Context, fn CheckFunc) error {
+	done := make(chan error, 1)
+	go func() { done <- fn(ctx) }()
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Redacted returns the report without check errors and extra state, which
+// can name internal hosts and models, for callers that are not authenticated
+func (r Report) Redacted() Report {
+	if r.Checks != nil {
+		checks := make([]CheckResult, len(r.Checks))
+		for i, result := range r.Checks {
+			result.LastError = ""
+			checks[i] = result
+		}
+		r.Checks = checks
+	}
+	r.Info = nil
+	return r
+}
+
+func (c *Checker) report(status string, results []CheckResult, info map[string]interface{}) Report {
+	return Report{
+		Status:        status,
+		Service:       c.service,
+		UptimeSeconds: int64(time.Since(c.startedAt).Seconds()),
+		Checks:        results,
+		Info:          info,
+	}
+}
+
+// RegisterHTTP adds the probe endpoints to mux, for services without a
+// router. Reports are redacted unless authenticated accepts the request.
+func (c *Checker) RegisterHTTP(mux *http.ServeMux, authenticated func(*http.Request) bool) {
+	mux.Handle("/health", c.httpHandler(c.Live, authenticated))
+	mux.Handle("/health/live", c.httpHandler(c.Live, authenticated))
+	mux.Handle("/health/ready", c.httpHandler(c.Ready, authenticated))
+	mux.Handle("/health/startup", c.httpHandler(c.Startup, authenticated))
+}
+
+func (c *Checker) httpHandler(probe func(context.Context) (int, Report), authenticated func(*http.Request) bool) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		code, report := probe(r.Context())
+		if !authenticated(r) {
+			report = report.Redacted()
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(code)
+		json.NewEncoder(w).Encode(report)
+	})
+}
@@ -0,0 +1,135 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func up(ctx context.Context) error   { return nil }
+func down(ctx context.Context) error { return errors.New("connection refused") }
+
+func TestReadyCriticality(t *testing.T) {
+	c := NewChecker("test", time.Second, 0, nil)
+	c.Register("database", true, up)
+	c.Register("ml", false, down)
+
+	code, report := c.Ready(context.Background())
+	if code != http.StatusOK || report.Status != StatusDegraded {
+		t.Errorf("got %d %s, want degraded", code, report.Status)
+	}
+
+	c = NewChecker("test", time.Second, 0, map[string]bool{"ml": true})
+	c.Register("database", true, up)
+	c.Register("ml", false, down)
+
+	code, report = c.Ready(context.Background())
+	if code != http.StatusServiceUnavailable || report.Status != StatusNotReady {
+		t.Errorf("got %d %s, want not ready when ml is made critical", code, report.Status)
+	}
+}
+
+func TestLastErrorKept(t *testing.T) {
+	failing := true
+	c := NewChecker("test", time.Second, 0, nil)
+	c.Register("redis", true, func(ctx context.Context) error {
+		if failing {
+			return errors.New("timeout")
+		}
+		return nil
+	})
+
+	c.Ready(context.Background())
+	failing = false
+	code, report := c.Ready(context.Background())
+	if code != http.StatusOK {
+		t.Fatalf("got %d, want ready after recovery", code)
+	}
+	if result := report.Checks[0]; result.Status != StatusUp || result.LastError != "timeout" || result.LastErrorAt == nil {
+		t.Errorf("expected the last error to be kept, got %+v", result)
+	}
+}
+
+func TestCheckTimeout(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+
+	c := NewChecker("test", 20*time.Millisecond, 0, nil)
+	c.Register("kafka", true, func(ctx context.Context) error {
+		<-release
+		return nil
+	})
+
+	start := time.Now()
+	code, report := c.Ready(context.Background())
+	if code != http.StatusServiceUnavailable || report.Checks[0].LastError != context.DeadlineExceeded.Error() {
+		t.Errorf("expected a timed out check, got %d %+v", code, report.Checks)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("check took %s, expected the timeout to apply", elapsed)
+	}
+}
+
+func TestStartup(t *testing.T) {
+	c := NewChecker("test", time.Second, 0, nil)
+	c.Register("database", true, up)
+
+	if code, _ := c.Startup(context.Background()); code != http.StatusServiceUnavailable {
+		t.Errorf("got %d before MarkStarted, want 503", code)
+	}
+	c.MarkStarted()
+	if code, report := c.Startup(context.Background()); code != http.StatusOK || report.Status != StatusReady {
+		t.Errorf("got %d %s after MarkStarted, want ready", code, report.Status)
+	}
+}
+
+func TestResultsCached(t *testing.T) {
+	var calls int
+	c := NewChecker("test", time.Second, 50*time.Millisecond, nil)
+	c.Register("database", true, func(ctx context.Context) error {
+		calls++
+		return nil
+	})
+
+	c.Ready(context.Background())
+	c.Ready(context.Background())
+	c.Startup(context.Background())
+	if calls != 1 {
+		t.Errorf("checks ran %d times within the cache TTL, want 1", calls)
+	}
+
+	time.Sleep(60 * time.Millisecond)
+	c.Ready(context.Background())
+	if calls != 2 {
+		t.Errorf("checks ran %d times after the cache TTL, want 2", calls)
+	}
+}
+
+func TestCanceledCallerNotCached(t *testing.T) {
+	c := NewChecker("test", time.Second, time.Minute, nil)
+	c.Register("database", true, up)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	c.Ready(ctx)
+	if code, report := c.Ready(context.Background()); code != http.StatusOK {
+		t.Errorf("a canceled caller failed the cached checks: %d %+v", code, report.Checks)
+	}
+}
+
+func TestRedacted(t *testing.T) {
+	c := NewChecker("test", time.Second, 0, nil)
+	c.Register("ml", false, down)
+	c.AddInfo("breakers", func() interface{} { return "open" })
+
+	_, report := c.Ready(context.Background())
+	redacted := report.Redacted()
+	if redacted.Checks[0].LastError != "" || redacted.Checks[0].Status != StatusDown || redacted.Info != nil {
+		t.Errorf("expected status without error details, got %+v", redacted)
+	}
+	if report.Checks[0].LastError == "" || report.Info == nil {
+		t.Error("Redacted modified the original report")
+	}
+}
@@ -0,0 +1,36 @@
+package health
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/segmentio/kafka-go"
+)
+
+// Kafka checks that at least one of brokers accepts a connection and
+// answers a metadata request
+func Kafka(brokers []string) CheckFunc {
+	return func(ctx context.Context) error {
+		var lastErr error
+		for _, broker := range brokers {
+			if lastErr = pingBroker(ctx, broker); lastErr == nil {
+				return nil
+			}
+		}
+		return fmt.Errorf("no Kafka broker reachable: %w", lastErr)
+	}
+}
+
+func pingBroker(ctx context.Context, broker string) error {
+	conn, err := kafka.DialContext(ctx, "tcp", broker)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+	_, err = conn.Brokers()
+	return err
+}
@@ -0,0 +1,158 @@
+// Package metrics defines the Prometheus metrics exported by every service
+// on /metrics, served on an internal port separate from the API.
+package metrics
+
+import (
+	"database/sql"
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "fraud_detection"
+
+var (
+	// HTTPRequests counts requests by route template, so path parameters do
+	// not create new series
+	HTTPRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "http_requests_total",
+		Help:      "HTTP requests by method, route and status.",
+	}, []string{"method", "route", "status"})
+
+	HTTPRequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "HTTP request latency by method, route and status.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+
+	RateLimitRejections = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "rate_limit_rejections_total",
+		Help:      "Requests rejected by the rate limiter, by route.",
+	}, []string{"route"})
+
+	// Verifications counts verdicts. source is scored, fallback or cached.
+	Verifications = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "verifications_total",
+		Help:      "Verifications by verdict, risk level, fraud type and source.",
+	}, []string{"verdict", "risk_level", "fraud_type", "source"})
+
+	VerificationStageDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "verification_stage_duration_seconds",
+		Help:      "Duration of each verification stage, by stage and status.",
+		Buckets:   []float64{.001, .005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10},
+	}, []string{"stage", "status"})
+
+	HeaderChecks = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "header_checks_total",
+		Help:      "Sender header checks by outcome.",
+	}, []string{"outcome"})
+
+	RBIChecks = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "rbi_checks_total",
+		Help:      "RBI compliance checks by outcome.",
+	}, []string{"outcome"})
+
+	// MLRequests counts each call to a model, including retries. outcome is
+	// ok, error or circuit_open.
+	MLRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "ml_requests_total",
+		Help:      "ML inference calls by model and outcome.",
+	}, []string{"model", "outcome"})
+
+	MLRequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "ml_request_duration_seconds",
+		Help:      "ML inference call latency by model and outcome.",
+		Buckets:   []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10},
+	}, []string{"model", "outcome"})
+
+	KafkaProduced = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "kafka_messages_produced_total",
+		Help:      "Messages written to Kafka by topic and outcome.",
+	}, []string{"topic", "outcome"})
+
+	KafkaConsumed = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "kafka_messages_consumed_total",
+		Help:      "Messages read from Kafka by topic and outcome.",
+	}, []string{"topic", "outcome"})
+
+	KafkaConsumerLag = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "kafka_consumer_lag",
+		Help:      "Messages behind the end of the partition as of the last message consumed.",
+	}, []string{"topic", "partition"})
+
+	OutboxProcessed = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "outbox_entries_processed_total",
+		Help:      "Outbox entries handled by kind and outcome.",
+	}, []string{"kind", "outcome"})
+
+	OutboxPending = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "outbox_pending_entries",
+		Help:      "Unprocessed outbox entries by kind.",
+	}, []string{"kind"})
+
+	OutboxLag = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "outbox_lag_seconds",
+		Help:      "Age of the oldest unprocessed outbox entry by kind.",
+	}, []string{"kind"})
+
+	// DBReads counts read-only queries by the pool that served them. Reads
+	// land on primary when no replica is configured or healthy.
+	DBReads = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "db_reads_total",
+		Help:      "Read-only queries by serving pool.",
+	}, []string{"pool"})
+
+	DBReplicaLag = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "db_replica_lag_seconds",
+		Help:      "Replication lag of each read replica as of the last check.",
+	}, []string{"pool"})
+
+	DBReplicaHealthy = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "db_replica_healthy",
+		Help:      "Whether each read replica is reachable and within the lag limit.",
+	}, []string{"pool"})
+)
+
+// RegisterDB exports the connection pool stats of db, labelled with pool
+func RegisterDB(pool string, db *sql.DB) {
+	prometheus.MustRegister(collectors.NewDBStatsCollector(db, pool))
+}
+
+// Handler serves the metrics
+func Handler() http.Handler {
+	return promhttp.Handler()
+}
+
+// NewServer returns a server for /metrics on addr. It is kept off the API
+// port so the metrics are only reachable from inside the deployment.
+func NewServer(addr string) *http.Server {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", Handler())
+	return &http.Server{
+		Addr:              addr,
+		Handler:           mux,
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+}
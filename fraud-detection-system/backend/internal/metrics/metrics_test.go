@@ -0,0 +1,29 @@
+package metrics
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestNewServer(t *testing.T) {
+	srv := NewServer(":0")
+	HTTPRequests.WithLabelValues("GET", "/test", "200").Inc()
+
+	w := httptest.NewRecorder()
+	srv.Handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", w.Code)
+	}
+	if !strings.Contains(w.Body.String(), "fraud_detection_http_requests_total") {
+		t.Error("expected the registered metrics in the response")
+	}
+
+	// Nothing but the metrics is served
+	w = httptest.NewRecorder()
+	srv.Handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/health", nil))
+	if w.Code != http.StatusNotFound {
+		t.Errorf("expected 404 for /health, got %d", w.Code)
+	}
+}
@@ -0,0 +1,5 @@
+// Package mlpb contains the protobuf and gRPC definitions of the ML inference
+// service.
+package mlpb
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative inference.proto
@@ -0,0 +1,563 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.31.0
+// 	protoc        v4.25.1
+// source: inference.proto
+
+package mlpb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	structpb "google.golang.org/protobuf/types/known/structpb"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type MessageFeatures struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Content          string   `protobuf:"bytes,1,opt,name=content,proto3" json:"content,omitempty"`
+	SenderHeader     string   `protobuf:"bytes,2,opt,name=sender_header,json=senderHeader,proto3" json:"sender_header,omitempty"`
+	MessageLength    int32    `protobuf:"varint,3,opt,name=message_length,json=messageLength,proto3" json:"message_length,omitempty"`
+	HasLinks         bool     `protobuf:"varint,4,opt,name=has_links,json=hasLinks,proto3" json:"has_links,omitempty"`
+	LinkCount        int32    `protobuf:"varint,5,opt,name=link_count,json=linkCount,proto3" json:"link_count,omitempty"`
+	ExtractedUrls    []string `protobuf:"bytes,6,rep,name=extracted_urls,json=extractedUrls,proto3" json:"extracted_urls,omitempty"`
+	HasPhoneNumber   bool     `protobuf:"varint,7,opt,name=has_phone_number,json=hasPhoneNumber,proto3" json:"has_phone_number,omitempty"`
+	PhoneNumberCount int32    `protobuf:"varint,8,opt,name=phone_number_count,json=phoneNumberCount,proto3" json:"phone_number_count,omitempty"`
+	HasUrgentWords   bool     `protobuf:"varint,9,opt,name=has_urgent_words,json=hasUrgentWords,proto3" json:"has_urgent_words,omitempty"`
+	UrgentWordCount  int32    `protobuf:"varint,10,opt,name=urgent_word_count,json=urgentWordCount,proto3" json:"urgent_word_count,omitempty"`
+	SpecialCharRatio float64  `protobuf:"fixed64,11,opt,name=special_char_ratio,json=specialCharRatio,proto3" json:"special_char_ratio,omitempty"`
+	CapitalRatio     float64  `protobuf:"fixed64,12,opt,name=capital_ratio,json=capitalRatio,proto3" json:"capital_ratio,omitempty"`
+	NumberRatio      float64  `protobuf:"fixed64,13,opt,name=number_ratio,json=numberRatio,proto3" json:"number_ratio,omitempty"`
+	HasKycKeywords   bool     `protobuf:"varint,14,opt,name=has_kyc_keywords,json=hasKycKeywords,proto3" json:"has_kyc_keywords,omitempty"`
+	HasBankNames     bool     `protobuf:"varint,15,opt,name=has_bank_names,json=hasBankNames,proto3" json:"has_bank_names,omitempty"`
+}
+
+func (x *MessageFeatures) Reset() {
+	*x = MessageFeatures{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_inference_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *MessageFeatures) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MessageFeatures) ProtoMessage() {}
+
+func (x *MessageFeatures) ProtoReflect() protoreflect.Message {
+	mi := &file_inference_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MessageFeatures.ProtoReflect.Descriptor instead.
+func (*MessageFeatures) Descriptor() ([]byte, []int) {
+	return file_inference_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *MessageFeatures) GetContent() string {
+	if x != nil {
+		return x.Content
+	}
+	return ""
+}
+
+func (x *MessageFeatures) GetSenderHeader() string {
+	if x != nil {
+		return x.SenderHeader
+	}
+	return ""
+}
+
+func (x *MessageFeatures) GetMessageLength() int32 {
+	if x != nil {
+		return x.MessageLength
+	}
+	return 0
+}
+
+func (x *MessageFeatures) GetHasLinks() bool {
+	if x != nil {
+		return x.HasLinks
+	}
+	return false
+}
+
+func (x *MessageFeatures) GetLinkCount() int32 {
+	if x != nil {
+		return x.LinkCount
+	}
+	return 0
+}
+
+func (x *MessageFeatures) GetExtractedUrls() []string {
+	if x != nil {
+		return x.ExtractedUrls
+	}
+	return nil
+}
+
+func (x *MessageFeatures) GetHasPhoneNumber() bool {
+	if x != nil {
+		return x.HasPhoneNumber
+	}
+	return false
+}
+
+func (x *MessageFeatures) GetPhoneNumberCount() int32 {
+	if x != nil {
+		return x.PhoneNumberCount
+	}
+	return 0
+}
+
+func (x *MessageFeatures) GetHasUrgentWords() bool {
+	if x != nil {
+		return x.HasUrgentWords
+	}
+	return false
+}
+
+func (x *MessageFeatures) GetUrgentWordCount() int32 {
+	if x != nil {
+		return x.UrgentWordCount
+	}
+	return 0
+}
+
+func (x *MessageFeatures) GetSpecialCharRatio() float64 {
+	if x != nil {
+		return x.SpecialCharRatio
+	}
+	return 0
+}
+
+func (x *MessageFeatures) GetCapitalRatio() float64 {
+	if x != nil {
+		return x.CapitalRatio
+	}
+	return 0
+}
+
+func (x *MessageFeatures) GetNumberRatio() float64 {
+	if x != nil {
+		return x.NumberRatio
+	}
+	return 0
+}
+
+func (x *MessageFeatures) GetHasKycKeywords() bool {
+	if x != nil {
+		return x.HasKycKeywords
+	}
+	return false
+}
+
+func (x *MessageFeatures) GetHasBankNames() bool {
+	if x != nil {
+		return x.HasBankNames
+	}
+	return false
+}
+
+type PredictRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Correlates stream responses with requests; unused by Predict
+	Id           string           `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Content      string           `protobuf:"bytes,2,opt,name=content,proto3" json:"content,omitempty"`
+	SenderHeader string           `protobuf:"bytes,3,opt,name=sender_header,json=senderHeader,proto3" json:"sender_header,omitempty"`
+	Features     *MessageFeatures `protobuf:"bytes,4,opt,name=features,proto3" json:"features,omitempty"`
+}
+
+func (x *PredictRequest) Reset() {
+	*x = PredictRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_inference_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PredictRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PredictRequest) ProtoMessage() {}
+
+func (x *PredictRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_inference_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PredictRequest.ProtoReflect.Descriptor instead.
+func (*PredictRequest) Descriptor() ([]byte, []int) {
+	return file_inference_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *PredictRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *PredictRequest) GetContent() string {
+	if x != nil {
+		return x.Content
+	}
+	return ""
+}
+
+func (x *PredictRequest) GetSenderHeader() string {
+	if x != nil {
+		return x.SenderHeader
+	}
+	return ""
+}
+
+func (x *PredictRequest) GetFeatures() *MessageFeatures {
+	if x != nil {
+		return x.Features
+	}
+	return nil
+}
+
+type PredictResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id               string           `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	IsFraud          bool             `protobuf:"varint,2,opt,name=is_fraud,json=isFraud,proto3" json:"is_fraud,omitempty"`
+	FraudScore       float64          `protobuf:"fixed64,3,opt,name=fraud_score,json=fraudScore,proto3" json:"fraud_score,omitempty"`
+	FraudType        string           `protobuf:"bytes,4,opt,name=fraud_type,json=fraudType,proto3" json:"fraud_type,omitempty"`
+	Confidence       float64          `protobuf:"fixed64,5,opt,name=confidence,proto3" json:"confidence,omitempty"`
+	ModelPredictions *structpb.Struct `protobuf:"bytes,6,opt,name=model_predictions,json=modelPredictions,proto3" json:"model_predictions,omitempty"`
+	Explanation      string           `protobuf:"bytes,7,opt,name=explanation,proto3" json:"explanation,omitempty"`
+	InferenceTimeMs  int32            `protobuf:"varint,8,opt,name=inference_time_ms,json=inferenceTimeMs,proto3" json:"inference_time_ms,omitempty"`
+	ModelVersion     string           `protobuf:"bytes,9,opt,name=model_version,json=modelVersion,proto3" json:"model_version,omitempty"`
+	// Set instead of the prediction when a streamed request failed
+	Error string `protobuf:"bytes,10,opt,name=error,proto3" json:"error,omitempty"`
+}
+
+func (x *PredictResponse) Reset() {
+	*x = PredictResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_inference_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PredictResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PredictResponse) ProtoMessage() {}
+
+func (x *PredictResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_inference_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PredictResponse.ProtoReflect.Descriptor instead.
+func (*PredictResponse) Descriptor() ([]byte, []int) {
+	return file_inference_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *PredictResponse) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *PredictResponse) GetIsFraud() bool {
+	if x != nil {
+		return x.IsFraud
+	}
+	return false
+}
+
+func (x *PredictResponse) GetFraudScore() float64 {
+	if x != nil {
+		return x.FraudScore
+	}
+	return 0
+}
+
+func (x *PredictResponse) GetFraudType() string {
+	if x != nil {
+		return x.FraudType
+	}
+	return ""
+}
+
+func (x *PredictResponse) GetConfidence() float64 {
+	if x != nil {
+		return x.Confidence
+	}
+	return 0
+}
+
+func (x *PredictResponse) GetModelPredictions() *structpb.Struct {
+	if x != nil {
+		return x.ModelPredictions
+	}
+	return nil
+}
+
+func (x *PredictResponse) GetExplanation() string {
+	if x != nil {
+		return x.Explanation
+	}
+	return ""
+}
+
+func (x *PredictResponse) GetInferenceTimeMs() int32 {
+	if x != nil {
+		return x.InferenceTimeMs
+	}
+	return 0
+}
+
+func (x *PredictResponse) GetModelVersion() string {
+	if x != nil {
+		return x.ModelVersion
+	}
+	return ""
+}
+
+func (x *PredictResponse) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+var File_inference_proto protoreflect.FileDescriptor
+
+var file_inference_proto_rawDesc = []byte{
+	0x0a, 0x0f, 0x69, 0x6e, 0x66, 0x65, 0x72, 0x65, 0x6e, 0x63, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x12, 0x0b, 0x66, 0x72, 0x61, 0x75, 0x64, 0x2e, 0x6d, 0x6c, 0x2e, 0x76, 0x31, 0x1a, 0x1c,
+	0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f,
+	0x73, 0x74, 0x72, 0x75, 0x63, 0x74, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0xce, 0x04, 0x0a,
+	0x0f, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x46, 0x65, 0x61, 0x74, 0x75, 0x72, 0x65, 0x73,
+	0x12, 0x18, 0x0a, 0x07, 0x63, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x07, 0x63, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x12, 0x23, 0x0a, 0x0d, 0x73, 0x65,
+	0x6e, 0x64, 0x65, 0x72, 0x5f, 0x68, 0x65, 0x61, 0x64, 0x65, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x0c, 0x73, 0x65, 0x6e, 0x64, 0x65, 0x72, 0x48, 0x65, 0x61, 0x64, 0x65, 0x72, 0x12,
+	0x25, 0x0a, 0x0e, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x5f, 0x6c, 0x65, 0x6e, 0x67, 0x74,
+	0x68, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0d, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65,
+	0x4c, 0x65, 0x6e, 0x67, 0x74, 0x68, 0x12, 0x1b, 0x0a, 0x09, 0x68, 0x61, 0x73, 0x5f, 0x6c, 0x69,
+	0x6e, 0x6b, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x08, 0x52, 0x08, 0x68, 0x61, 0x73, 0x4c, 0x69,
+	0x6e, 0x6b, 0x73, 0x12, 0x1d, 0x0a, 0x0a, 0x6c, 0x69, 0x6e, 0x6b, 0x5f, 0x63, 0x6f, 0x75, 0x6e,
+	0x74, 0x18, 0x05, 0x20, 0x01, 0x28, 0x05, 0x52, 0x09, 0x6c, 0x69, 0x6e, 0x6b, 0x43, 0x6f, 0x75,
+	0x6e, 0x74, 0x12, 0x25, 0x0a, 0x0e, 0x65, 0x78, 0x74, 0x72, 0x61, 0x63, 0x74, 0x65, 0x64, 0x5f,
+	0x75, 0x72, 0x6c, 0x73, 0x18, 0x06, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0d, 0x65, 0x78, 0x74, 0x72,
+	0x61, 0x63, 0x74, 0x65, 0x64, 0x55, 0x72, 0x6c, 0x73, 0x12, 0x28, 0x0a, 0x10, 0x68, 0x61, 0x73,
+	0x5f, 0x70, 0x68, 0x6f, 0x6e, 0x65, 0x5f, 0x6e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x18, 0x07, 0x20,
+	0x01, 0x28, 0x08, 0x52, 0x0e, 0x68, 0x61, 0x73, 0x50, 0x68, 0x6f, 0x6e, 0x65, 0x4e, 0x75, 0x6d,
+	0x62, 0x65, 0x72, 0x12, 0x2c, 0x0a, 0x12, 0x70, 0x68, 0x6f, 0x6e, 0x65, 0x5f, 0x6e, 0x75, 0x6d,
+	0x62, 0x65, 0x72, 0x5f, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x08, 0x20, 0x01, 0x28, 0x05, 0x52,
+	0x10, 0x70, 0x68, 0x6f, 0x6e, 0x65, 0x4e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x43, 0x6f, 0x75, 0x6e,
+	0x74, 0x12, 0x28, 0x0a, 0x10, 0x68, 0x61, 0x73, 0x5f, 0x75, 0x72, 0x67, 0x65, 0x6e, 0x74, 0x5f,
+	0x77, 0x6f, 0x72, 0x64, 0x73, 0x18, 0x09, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0e, 0x68, 0x61, 0x73,
+	0x55, 0x72, 0x67, 0x65, 0x6e, 0x74, 0x57, 0x6f, 0x72, 0x64, 0x73, 0x12, 0x2a, 0x0a, 0x11, 0x75,
+	0x72, 0x67, 0x65, 0x6e, 0x74, 0x5f, 0x77, 0x6f, 0x72, 0x64, 0x5f, 0x63, 0x6f, 0x75, 0x6e, 0x74,
+	0x18, 0x0a, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0f, 0x75, 0x72, 0x67, 0x65, 0x6e, 0x74, 0x57, 0x6f,
+	0x72, 0x64, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x2c, 0x0a, 0x12, 0x73, 0x70, 0x65, 0x63, 0x69,
+	0x61, 0x6c, 0x5f, 0x63, 0x68, 0x61, 0x72, 0x5f, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x18, 0x0b, 0x20,
+	0x01, 0x28, 0x01, 0x52, 0x10, 0x73, 0x70, 0x65, 0x63, 0x69, 0x61, 0x6c, 0x43, 0x68, 0x61, 0x72,
+	0x52, 0x61, 0x74, 0x69, 0x6f, 0x12, 0x23, 0x0a, 0x0d, 0x63, 0x61, 0x70, 0x69, 0x74, 0x61, 0x6c,
+	0x5f, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x01, 0x52, 0x0c, 0x63, 0x61,
+	0x70, 0x69, 0x74, 0x61, 0x6c, 0x52, 0x61, 0x74, 0x69, 0x6f, 0x12, 0x21, 0x0a, 0x0c, 0x6e, 0x75,
+	0x6d, 0x62, 0x65, 0x72, 0x5f, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x18, 0x0d, 0x20, 0x01, 0x28, 0x01,
+	0x52, 0x0b, 0x6e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x52, 0x61, 0x74, 0x69, 0x6f, 0x12, 0x28, 0x0a,
+	0x10, 0x68, 0x61, 0x73, 0x5f, 0x6b, 0x79, 0x63, 0x5f, 0x6b, 0x65, 0x79, 0x77, 0x6f, 0x72, 0x64,
+	0x73, 0x18, 0x0e, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0e, 0x68, 0x61, 0x73, 0x4b, 0x79, 0x63, 0x4b,
+	0x65, 0x79, 0x77, 0x6f, 0x72, 0x64, 0x73, 0x12, 0x24, 0x0a, 0x0e, 0x68, 0x61, 0x73, 0x5f, 0x62,
+	0x61, 0x6e, 0x6b, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x73, 0x18, 0x0f, 0x20, 0x01, 0x28, 0x08, 0x52,
+	0x0c, 0x68, 0x61, 0x73, 0x42, 0x61, 0x6e, 0x6b, 0x4e, 0x61, 0x6d, 0x65, 0x73, 0x22, 0x99, 0x01,
+	0x0a, 0x0e, 0x50, 0x72, 0x65, 0x64, 0x69, 0x63, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64,
+	0x12, 0x18, 0x0a, 0x07, 0x63, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x07, 0x63, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x12, 0x23, 0x0a, 0x0d, 0x73, 0x65,
+	0x6e, 0x64, 0x65, 0x72, 0x5f, 0x68, 0x65, 0x61, 0x64, 0x65, 0x72, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x0c, 0x73, 0x65, 0x6e, 0x64, 0x65, 0x72, 0x48, 0x65, 0x61, 0x64, 0x65, 0x72, 0x12,
+	0x38, 0x0a, 0x08, 0x66, 0x65, 0x61, 0x74, 0x75, 0x72, 0x65, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x1c, 0x2e, 0x66, 0x72, 0x61, 0x75, 0x64, 0x2e, 0x6d, 0x6c, 0x2e, 0x76, 0x31, 0x2e,
+	0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x46, 0x65, 0x61, 0x74, 0x75, 0x72, 0x65, 0x73, 0x52,
+	0x08, 0x66, 0x65, 0x61, 0x74, 0x75, 0x72, 0x65, 0x73, 0x22, 0xeb, 0x02, 0x0a, 0x0f, 0x50, 0x72,
+	0x65, 0x64, 0x69, 0x63, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x0e, 0x0a,
+	0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x12, 0x19, 0x0a,
+	0x08, 0x69, 0x73, 0x5f, 0x66, 0x72, 0x61, 0x75, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x52,
+	0x07, 0x69, 0x73, 0x46, 0x72, 0x61, 0x75, 0x64, 0x12, 0x1f, 0x0a, 0x0b, 0x66, 0x72, 0x61, 0x75,
+	0x64, 0x5f, 0x73, 0x63, 0x6f, 0x72, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x01, 0x52, 0x0a, 0x66,
+	0x72, 0x61, 0x75, 0x64, 0x53, 0x63, 0x6f, 0x72, 0x65, 0x12, 0x1d, 0x0a, 0x0a, 0x66, 0x72, 0x61,
+	0x75, 0x64, 0x5f, 0x74, 0x79, 0x70, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x66,
+	0x72, 0x61, 0x75, 0x64, 0x54, 0x79, 0x70, 0x65, 0x12, 0x1e, 0x0a, 0x0a, 0x63, 0x6f, 0x6e, 0x66,
+	0x69, 0x64, 0x65, 0x6e, 0x63, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x01, 0x52, 0x0a, 0x63, 0x6f,
+	0x6e, 0x66, 0x69, 0x64, 0x65, 0x6e, 0x63, 0x65, 0x12, 0x44, 0x0a, 0x11, 0x6d, 0x6f, 0x64, 0x65,
+	0x6c, 0x5f, 0x70, 0x72, 0x65, 0x64, 0x69, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x06, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x53, 0x74, 0x72, 0x75, 0x63, 0x74, 0x52, 0x10, 0x6d, 0x6f,
+	0x64, 0x65, 0x6c, 0x50, 0x72, 0x65, 0x64, 0x69, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x20,
+	0x0a, 0x0b, 0x65, 0x78, 0x70, 0x6c, 0x61, 0x6e, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x07, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x0b, 0x65, 0x78, 0x70, 0x6c, 0x61, 0x6e, 0x61, 0x74, 0x69, 0x6f, 0x6e,
+	0x12, 0x2a, 0x0a, 0x11, 0x69, 0x6e, 0x66, 0x65, 0x72, 0x65, 0x6e, 0x63, 0x65, 0x5f, 0x74, 0x69,
+	0x6d, 0x65, 0x5f, 0x6d, 0x73, 0x18, 0x08, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0f, 0x69, 0x6e, 0x66,
+	0x65, 0x72, 0x65, 0x6e, 0x63, 0x65, 0x54, 0x69, 0x6d, 0x65, 0x4d, 0x73, 0x12, 0x23, 0x0a, 0x0d,
+	0x6d, 0x6f, 0x64, 0x65, 0x6c, 0x5f, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x09, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x0c, 0x6d, 0x6f, 0x64, 0x65, 0x6c, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f,
+	0x6e, 0x12, 0x14, 0x0a, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x32, 0xa8, 0x01, 0x0a, 0x10, 0x49, 0x6e, 0x66, 0x65,
+	0x72, 0x65, 0x6e, 0x63, 0x65, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x44, 0x0a, 0x07,
+	0x50, 0x72, 0x65, 0x64, 0x69, 0x63, 0x74, 0x12, 0x1b, 0x2e, 0x66, 0x72, 0x61, 0x75, 0x64, 0x2e,
+	0x6d, 0x6c, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x72, 0x65, 0x64, 0x69, 0x63, 0x74, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x1a, 0x1c, 0x2e, 0x66, 0x72, 0x61, 0x75, 0x64, 0x2e, 0x6d, 0x6c, 0x2e,
+	0x76, 0x31, 0x2e, 0x50, 0x72, 0x65, 0x64, 0x69, 0x63, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x12, 0x4e, 0x0a, 0x0d, 0x50, 0x72, 0x65, 0x64, 0x69, 0x63, 0x74, 0x53, 0x74, 0x72,
+	0x65, 0x61, 0x6d, 0x12, 0x1b, 0x2e, 0x66, 0x72, 0x61, 0x75, 0x64, 0x2e, 0x6d, 0x6c, 0x2e, 0x76,
+	0x31, 0x2e, 0x50, 0x72, 0x65, 0x64, 0x69, 0x63, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x1a, 0x1c, 0x2e, 0x66, 0x72, 0x61, 0x75, 0x64, 0x2e, 0x6d, 0x6c, 0x2e, 0x76, 0x31, 0x2e, 0x50,
+	0x72, 0x65, 0x64, 0x69, 0x63, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x28, 0x01,
+	0x30, 0x01, 0x42, 0x39, 0x5a, 0x37, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d,
+	0x2f, 0x66, 0x72, 0x61, 0x75, 0x64, 0x2d, 0x64, 0x65, 0x74, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e,
+	0x2d, 0x73, 0x79, 0x73, 0x74, 0x65, 0x6d, 0x2f, 0x62, 0x61, 0x63, 0x6b, 0x65, 0x6e, 0x64, 0x2f,
+	0x69, 0x6e, 0x74, 0x65, 0x72, 0x6e, 0x61, 0x6c, 0x2f, 0x6d, 0x6c, 0x70, 0x62, 0x62, 0x06, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_inference_proto_rawDescOnce sync.Once
+	file_inference_proto_rawDescData = file_inference_proto_rawDesc
+)
+
+func file_inference_proto_rawDescGZIP() []byte {
+	file_inference_proto_rawDescOnce.Do(func() {
+		file_inference_proto_rawDescData = protoimpl.X.CompressGZIP(file_inference_proto_rawDescData)
+	})
+	return file_inference_proto_rawDescData
+}
+
+var file_inference_proto_msgTypes = make([]protoimpl.MessageInfo, 3)
+var file_inference_proto_goTypes = []interface{}{
+	(*MessageFeatures)(nil), // 0: fraud.ml.v1.MessageFeatures
+	(*PredictRequest)(nil),  // 1: fraud.ml.v1.PredictRequest
+	(*PredictResponse)(nil), // 2: fraud.ml.v1.PredictResponse
+	(*structpb.Struct)(nil), // 3: google.protobuf.Struct
+}
+var file_inference_proto_depIdxs = []int32{
+	0, // 0: fraud.ml.v1.PredictRequest.features:type_name -> fraud.ml.v1.MessageFeatures
+	3, // 1: fraud.ml.v1.PredictResponse.model_predictions:type_name -> google.protobuf.Struct
+	1, // 2: fraud.ml.v1.InferenceService.Predict:input_type -> fraud.ml.v1.PredictRequest
+	1, // 3: fraud.ml.v1.InferenceService.PredictStream:input_type -> fraud.ml.v1.PredictRequest
+	2, // 4: fraud.ml.v1.InferenceService.Predict:output_type -> fraud.ml.v1.PredictResponse
+	2, // 5: fraud.ml.v1.InferenceService.PredictStream:output_type -> fraud.ml.v1.PredictResponse
+	4, // [4:6] is the sub-list for method output_type
+	2, // [2:4] is the sub-list for method input_type
+	2, // [2:2] is the sub-list for extension type_name
+	2, // [2:2] is the sub-list for extension extendee
+	0, // [0:2] is the sub-list for field type_name
+}
+
+func init() { file_inference_proto_init() }
+func file_inference_proto_init() {
+	if File_inference_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_inference_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*MessageFeatures); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_inference_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PredictRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_inference_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PredictResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_inference_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   3,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_inference_proto_goTypes,
+		DependencyIndexes: file_inference_proto_depIdxs,
+		MessageInfos:      file_inference_proto_msgTypes,
+	}.Build()
+	File_inference_proto = out.File
+	file_inference_proto_rawDesc = nil
+	file_inference_proto_goTypes = nil
+	file_inference_proto_depIdxs = nil
+}
@@ -0,0 +1,185 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.3.0
+// - protoc             v4.25.1
+// source: inference.proto
+
+package mlpb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+const (
+	InferenceService_Predict_FullMethodName       = "/fraud.ml.v1.InferenceService/Predict"
+	InferenceService_PredictStream_FullMethodName = "/fraud.ml.v1.InferenceService/PredictStream"
+)
+
+// InferenceServiceClient is the client API for InferenceService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type InferenceServiceClient interface {
+	// Predict scores a single message
+	Predict(ctx context.Context, in *PredictRequest, opts ...grpc.CallOption) (*PredictResponse, error)
+	// PredictStream scores a batch of messages over one stream. Responses
+	// carry the id of their request and may arrive in any order.
+	PredictStream(ctx context.Context, opts ...grpc.CallOption) (InferenceService_PredictStreamClient, error)
+}
+
+type inferenceServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewInferenceServiceClient(cc grpc.ClientConnInterface) InferenceServiceClient {
+	return &inferenceServiceClient{cc}
+}
+
+func (c *inferenceServiceClient) Predict(ctx context.Context, in *PredictRequest, opts ...grpc.CallOption) (*PredictResponse, error) {
+	out := new(PredictResponse)
+	err := c.cc.Invoke(ctx, InferenceService_Predict_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *inferenceServiceClient) PredictStream(ctx context.Context, opts ...grpc.CallOption) (InferenceService_PredictStreamClient, error) {
+	stream, err := c.cc.NewStream(ctx, &InferenceService_ServiceDesc.Streams[0], InferenceService_PredictStream_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &inferenceServicePredictStreamClient{stream}
+	return x, nil
+}
+
+type InferenceService_PredictStreamClient interface {
+	Send(*PredictRequest) error
+	Recv() (*PredictResponse, error)
+	grpc.ClientStream
+}
+
+type inferenceServicePredictStreamClient struct {
+	grpc.ClientStream
+}
+
+func (x *inferenceServicePredictStreamClient) Send(m *PredictRequest) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *inferenceServicePredictStreamClient) Recv() (*PredictResponse, error) {
+	m := new(PredictResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// InferenceServiceServer is the server API for InferenceService service.
+// All implementations must embed UnimplementedInferenceServiceServer
+// for forward compatibility
+type InferenceServiceServer interface {
+	// Predict scores a single message
+	Predict(context.Context, *PredictRequest) (*PredictResponse, error)
+	// PredictStream scores a batch of messages over one stream. Responses
+	// carry the id of their request and may arrive in any order.
+	PredictStream(InferenceService_PredictStreamServer) error
+	mustEmbedUnimplementedInferenceServiceServer()
+}
+
+// UnimplementedInferenceServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedInferenceServiceServer struct {
+}
+
+func (UnimplementedInferenceServiceServer) Predict(context.Context, *PredictRequest) (*PredictResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Predict not implemented")
+}
+func (UnimplementedInferenceServiceServer) PredictStream(InferenceService_PredictStreamServer) error {
+	return status.Errorf(codes.Unimplemented, "method PredictStream not implemented")
+}
+func (UnimplementedInferenceServiceServer) mustEmbedUnimplementedInferenceServiceServer() {}
+
+// UnsafeInferenceServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to InferenceServiceServer will
+// result in compilation errors.
+type UnsafeInferenceServiceServer interface {
+	mustEmbedUnimplementedInferenceServiceServer()
+}
+
+func RegisterInferenceServiceServer(s grpc.ServiceRegistrar, srv InferenceServiceServer) {
+	s.RegisterService(&InferenceService_ServiceDesc, srv)
+}
+
+func _InferenceService_Predict_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PredictRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InferenceServiceServer).Predict(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: InferenceService_Predict_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InferenceServiceServer).Predict(ctx, req.(*PredictRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _InferenceService_PredictStream_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(InferenceServiceServer).PredictStream(&inferenceServicePredictStreamServer{stream})
+}
+
+type InferenceService_PredictStreamServer interface {
+	Send(*PredictResponse) error
+	Recv() (*PredictRequest, error)
+	grpc.ServerStream
+}
+
+type inferenceServicePredictStreamServer struct {
+	grpc.ServerStream
+}
+
+func (x *inferenceServicePredictStreamServer) Send(m *PredictResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *inferenceServicePredictStreamServer) Recv() (*PredictRequest, error) {
+	m := new(PredictRequest)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// InferenceService_ServiceDesc is the grpc.ServiceDesc for InferenceService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var InferenceService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "fraud.ml.v1.InferenceService",
+	HandlerType: (*InferenceServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Predict",
+			Handler:    _InferenceService_Predict_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "PredictStream",
+			Handler:       _InferenceService_PredictStream_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+	},
+	Metadata: "inference.proto",
+}
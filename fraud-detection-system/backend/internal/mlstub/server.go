@@ -0,0 +1,143 @@
+// Package mlstub provides an in-process gRPC ML inference server for tests
+// and local development without the Python ML service.
+package mlstub
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"sync/atomic"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/protobuf/types/known/structpb"
+	"github.com/fraud-detection-system/backend/internal/mlpb"
+)
+
+// ModelVersion is reported by the default prediction function
+const ModelVersion = "stub-v1"
+
+// PredictFunc scores a single request
+type PredictFunc func(ctx context.Context, req *mlpb.PredictRequest) (*mlpb.PredictResponse, error)
+
+// Server implements mlpb.InferenceServiceServer with a pluggable prediction
+// function
+type Server struct {
+	mlpb.UnimplementedInferenceServiceServer
+
+	predict    PredictFunc
+	calls      atomic.Int64
+	grpcServer *grpc.Server
+	health     *health.Server
+	listener   net.Listener
+}
+
+// NewServer creates a stub server. A nil predict uses DefaultPredict.
+func NewServer(predict PredictFunc) *Server {
+	if predict == nil {
+		predict = DefaultPredict
+	}
+	return &Server{
+		predict: predict,
+		health:  health.NewServer(),
+	}
+}
+
+// Start listens on a random local port and serves in the background. It
+// returns the address to dial.
+func (s *Server) Start() (string, error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return "", fmt.Errorf("failed to listen: %w", err)
+	}
+	s.listener = listener
+	s.grpcServer = grpc.NewServer()
+	mlpb.RegisterInferenceServiceServer(s.grpcServer, s)
+	healthpb.RegisterHealthServer(s.grpcServer, s.health)
+
+	go s.grpcServer.Serve(listener)
+	return listener.Addr().String(), nil
+}
+
+// Stop stops the server immediately
+func (s *Server) Stop() {
+	if s.grpcServer != nil {
+		s.grpcServer.Stop()
+	}
+}
+
+// SetServing sets the status reported to health checks
+func (s *Server) SetServing(serving bool) {
+	status := healthpb.HealthCheckResponse_SERVING
+	if !serving {
+		status = healthpb.HealthCheckResponse_NOT_SERVING
+	}
+	s.health.SetServingStatus("", status)
+}
+
+// Calls returns the number of requests scored so far
+func (s *Server) Calls() int64 {
+	return s.calls.Load()
+}
+
+// Predict implements mlpb.InferenceServiceServer
+func (s *Server) Predict(ctx context.Context, req *mlpb.PredictRequest) (*mlpb.PredictResponse, error) {
+	s.calls.Add(1)
+	return s.predict(ctx, req)
+}
+
+// PredictStream implements mlpb.InferenceServiceServer. Failed predictions
+// are reported in the response's error field.
+func (s *Server) PredictStream(stream mlpb.InferenceService_PredictStreamServer) error {
+	for {
+		req, err := stream.Recv()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		s.calls.Add(1)
+		resp, err := s.predict(stream.Context(), req)
+		if err != nil {
+			resp = &mlpb.PredictResponse{Error: err.Error()}
+		}
+		resp.Id = req.GetId()
+		if err := stream.Send(resp); err != nil {
+			return err
+		}
+	}
+}
+
+// DefaultPredict scores a request from its extracted features
+func DefaultPredict(ctx context.Context, req *mlpb.PredictRequest) (*mlpb.PredictResponse, error) {
+	f := req.GetFeatures()
+	score := 0.1
+	if f.GetHasLinks() {
+		score += 0.3
+	}
+	if f.GetHasUrgentWords() {
+		score += 0.2
+	}
+	if f.GetHasKycKeywords() {
+		score += 0.3
+	}
+	if score > 1 {
+		score = 1
+	}
+
+	predictions, _ := structpb.NewStruct(map[string]interface{}{
+		"stub": map[string]interface{}{"fraud_score": score},
+	})
+	return &mlpb.PredictResponse{
+		IsFraud:          score >= 0.5,
+		FraudScore:       score,
+		Confidence:       0.8,
+		ModelPredictions: predictions,
+		Explanation:      "stub prediction",
+		ModelVersion:     ModelVersion,
+	}, nil
+}
@@ -0,0 +1,170 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Time series bucket sizes
+const (
+	GranularityHour = "hour"
+	GranularityDay  = "day"
+	GranularityWeek = "week"
+)
+
+// DefaultMessageType is the type of messages verified without one, as the
+// messages column defaults to
+const DefaultMessageType = "SMS"
+
+// MaxTrendBuckets caps how many buckets one trends request may span
+const MaxTrendBuckets = 2000
+
+// Dimensions of the top lists kept in the hourly rollups. Only fraudulent
+// verifications are counted.
+const (
+	AnalyticsDimensionFraudType = "fraud_type"
+	AnalyticsDimensionBank      = "bank"
+	AnalyticsDimensionSender    = "sender"
+)
+
+// Dimensions returns the top list values the update adds to. Only
+// fraudulent verifications count.
+func (u VerificationRollupUpdate) Dimensions() map[string][]string {
+	if !u.IsFraud {
+		return nil
+	}
+	dims := map[string][]string{AnalyticsDimensionBank: u.Banks}
+	if u.FraudType != nil && *u.FraudType != "" {
+		dims[AnalyticsDimensionFraudType] = []string{*u.FraudType}
+	}
+	if u.SenderHeader != "" {
+		dims[AnalyticsDimensionSender] = []string{u.SenderHeader}
+	}
+	return dims
+}
+
+// BucketStart returns the start of the bucket t falls in. Buckets are in
+// UTC and weeks start on Monday.
+func BucketStart(t time.Time, granularity string) time.Time {
+	t = t.UTC()
+	switch granularity {
+	case GranularityHour:
+		return t.Truncate(time.Hour)
+	case GranularityWeek:
+		day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+		return day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7))
+	}
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// NextBucket returns the start of the bucket after the one starting at start
+func NextBucket(start time.Time, granularity string) time.Time {
+	switch granularity {
+	case GranularityHour:
+		return start.Add(time.Hour)
+	case GranularityWeek:
+		return start.AddDate(0, 0, 7)
+	}
+	return start.AddDate(0, 0, 1)
+}
+
+// AnalyticsFilter selects the verifications a trends report covers, from
+// the bucket containing From up to but excluding To. An empty TenantID
+// covers every tenant.
+type AnalyticsFilter struct {
+	TenantID    string
+	MessageType string
+	Granularity string
+	From        time.Time
+	To          time.Time
+}
+
+// TrendBucket holds the verifications of one time bucket
+type TrendBucket struct {
+	Start         time.Time        `json:"start"`
+	Verifications int64            `json:"verifications"`
+	Fraud         int64            `json:"fraud"`
+	FraudRate     float64          `json:"fraud_rate"`
+	RiskLevels    map[string]int64 `json:"risk_levels"`
+}
+
+// TopEntry is one row of a top list
+type TopEntry struct {
+	Value string `json:"value"`
+	Count int64  `json:"count"`
+}
+
+// FraudTrends is a trends report: a time series plus the top fraud types,
+// impersonated banks and senders over the whole range
+type FraudTrends struct {
+	Granularity   string         `json:"granularity"`
+	From          time.Time      `json:"from"`
+	To            time.Time      `json:"to"`
+	Buckets       []*TrendBucket `json:"buckets"`
+	Verifications int64          `json:"verifications"`
+	Fraud         int64          `json:"fraud"`
+	FraudRate     float64        `json:"fraud_rate"`
+	TopFraudTypes []TopEntry     `json:"top_fraud_types"`
+	TopBanks      []TopEntry     `json:"top_banks"`
+	TopSenders    []TopEntry     `json:"top_senders"`
+}
+
+// TrendsQuery holds the query parameters of the trends endpoint
+type TrendsQuery struct {
+	Granularity string     `form:"granularity" binding:"omitempty,oneof=hour day week"`
+	From        *time.Time `form:"from" time_format:"2006-01-02T15:04:05Z07:00"`
+	To          *time.Time `form:"to" time_format:"2006-01-02T15:04:05Z07:00"`
+	MessageType string     `form:"message_type" binding:"omitempty,oneof=SMS WhatsApp Email"`
+	AllTenants  bool       `form:"all_tenants"`
+	Top         int        `form:"top" binding:"omitempty,min=1,max=50"`
+}
+
+// defaultTrendBuckets is how many buckets back a trends report goes when
+// the query has no start
+var defaultTrendBuckets = map[string]int{
+	GranularityHour: 48,
+	GranularityDay:  30,
+	GranularityWeek: 12,
+}
+
+// Filter returns the filter for the query within a tenant, or across every
+// tenant if it asks for all of them. It defaults to daily buckets over the
+// last 30 days up to now, and to 48 hourly or 12 weekly buckets.
+func (q TrendsQuery) Filter(tenantID string, now time.Time) (AnalyticsFilter, error) {
+	filter := AnalyticsFilter{
+		TenantID:    tenantID,
+		MessageType: q.MessageType,
+		Granularity: q.Granularity,
+		To:          now.UTC(),
+	}
+	if q.AllTenants {
+		filter.TenantID = ""
+	}
+	if filter.Granularity == "" {
+		filter.Granularity = GranularityDay
+	}
+	if q.To != nil {
+		filter.To = q.To.UTC()
+	}
+	if q.From != nil {
+		filter.From = q.From.UTC()
+	} else {
+		// Go back to the start of the default number of buckets up to To
+		filter.From = filter.To
+		for i := 0; i < defaultTrendBuckets[filter.Granularity]; i++ {
+			filter.From = BucketStart(filter.From.Add(-time.Nanosecond), filter.Granularity)
+		}
+	}
+
+	if !filter.From.Before(filter.To) {
+		return filter, errors.New("from must be before to")
+	}
+	buckets := 0
+	for start := BucketStart(filter.From, filter.Granularity); start.Before(filter.To); start = NextBucket(start, filter.Granularity) {
+		if buckets++; buckets > MaxTrendBuckets {
+			return filter, fmt.Errorf("range spans more than %d %s buckets", MaxTrendBuckets, filter.Granularity)
+		}
+	}
+	return filter, nil
+}
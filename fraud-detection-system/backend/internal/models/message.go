@@ -6,19 +6,28 @@ import (
 	"github.com/google/uuid"
 )
 
+// DefaultTenantID is used for requests that do not name a tenant
+const DefaultTenantID = "default"
+
 type Message struct {
-	ID            uuid.UUID  `json:"id" db:"id"`
-	UserID        *uuid.UUID `json:"user_id,omitempty" db:"user_id"`
-	Content       string     `json:"content" db:"content"`
-	SenderHeader  string     `json:"sender_header" db:"sender_header"`
-	ReceivedAt    *time.Time `json:"received_at,omitempty" db:"received_at"`
-	MessageType   string     `json:"message_type" db:"message_type"` // SMS, WhatsApp, Email
-	PhoneNumber   *string    `json:"phone_number,omitempty" db:"phone_number"`
-	HasLinks      bool       `json:"has_links" db:"has_links"`
-	LinkCount     int        `json:"link_count" db:"link_count"`
-	ExtractedURLs []string   `json:"extracted_urls,omitempty" db:"extracted_urls"`
-	CreatedAt     time.Time  `json:"created_at" db:"created_at"`
-	UpdatedAt     time.Time  `json:"updated_at" db:"updated_at"`
+	ID              uuid.UUID  `json:"id" db:"id"`
+	UserID          *uuid.UUID `json:"user_id,omitempty" db:"user_id"`
+	Content         string     `json:"content" db:"content"`
+	SenderHeader    string     `json:"sender_header" db:"sender_header"`
+	ReceivedAt      *time.Time `json:"received_at,omitempty" db:"received_at"`
+	MessageType     string     `json:"message_type" db:"message_type"` // SMS, WhatsApp, Email
+	PhoneNumber     *string    `json:"phone_number,omitempty" db:"phone_number"`
+	HasLinks        bool       `json:"has_links" db:"has_links"`
+	LinkCount       int        `json:"link_count" db:"link_count"`
+	ExtractedURLs   []string   `json:"extracted_urls,omitempty" db:"extracted_urls"`
+	TenantID        string     `json:"tenant_id" db:"tenant_id"`
+	ContentHash     *string    `json:"content_hash,omitempty" db:"content_hash"`
+	Features        *string    `json:"features,omitempty" db:"features"` // JSON, without content
+	AnonymizedAt    *time.Time `json:"anonymized_at,omitempty" db:"anonymized_at"`
+	PhoneNumberHash *string    `json:"-" db:"phone_number_hash"`
+	EncryptionKeyID *string    `json:"-" db:"encryption_key_id"`
+	CreatedAt       time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at" db:"updated_at"`
 }
 
 type MessageInput struct {
@@ -0,0 +1,53 @@
+package models
+
+import "time"
+
+// Outbox entry kinds
+const (
+	OutboxKindSenderStats = "sender_stats"
+	OutboxKindKafka       = "kafka"
+	OutboxKindAnalytics   = "analytics"
+)
+
+// OutboxEntry is a side effect recorded in the same transaction as the rows
+// that caused it, and applied later by the outbox processor
+type OutboxEntry struct {
+	ID          int64      `json:"id" db:"id"`
+	Kind        string     `json:"kind" db:"kind"`
+	Topic       *string    `json:"topic,omitempty" db:"topic"`             // Kafka entries only
+	MessageKey  *string    `json:"message_key,omitempty" db:"message_key"` // Kafka entries only
+	Payload     string     `json:"payload" db:"payload"`                   // JSON
+	Attempts    int        `json:"attempts" db:"attempts"`
+	LastError   *string    `json:"last_error,omitempty" db:"last_error"`
+	CreatedAt   time.Time  `json:"created_at" db:"created_at"`
+	ProcessedAt *time.Time `json:"processed_at,omitempty" db:"processed_at"`
+}
+
+// SenderStatsUpdate is the payload of a sender_stats outbox entry
+type SenderStatsUpdate struct {
+	SenderHeader string `json:"sender_header"`
+	IsFraud      bool   `json:"is_fraud"`
+}
+
+// VerificationRollupUpdate is the payload of an analytics outbox entry,
+// adding one verification to the analytics rollups
+type VerificationRollupUpdate struct {
+	TenantID     string    `json:"tenant_id"`
+	MessageType  string    `json:"message_type"`
+	SenderHeader string    `json:"sender_header"`
+	IsFraud      bool      `json:"is_fraud"`
+	RiskLevel    string    `json:"risk_level"`
+	FraudType    *string   `json:"fraud_type,omitempty"`
+	Banks        []string  `json:"banks,omitempty"` // banks the content mentions
+	VerifiedAt   time.Time `json:"verified_at"`
+}
+
+// OutboxStats describes how far the outbox processor is behind for one kind
+type OutboxStats struct {
+	Kind            string     `json:"kind"`
+	Pending         int64      `json:"pending"`
+	Abandoned       int64      `json:"abandoned"`
+	OldestPendingAt *time.Time `json:"oldest_pending_at,omitempty"`
+	LagSeconds      float64    `json:"lag_seconds"`
+	LastProcessedAt *time.Time `json:"last_processed_at,omitempty"`
+}
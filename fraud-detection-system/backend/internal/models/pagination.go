@@ -0,0 +1,73 @@
+package models
+
+import "time"
+
+// Page sizes for keyset-paginated listings
+const (
+	DefaultPageSize = 20
+	MaxPageSize     = 100
+)
+
+// ListOptions selects one page of a keyset-paginated listing. Cursor is the
+// NextCursor of the previous page, or empty for the first page; it is only
+// valid with the same Sort and Desc it was issued for.
+type ListOptions struct {
+	Limit  int
+	Cursor string
+	Sort   string // empty for the listing's default
+	Desc   bool
+}
+
+// PageInfo describes a page of a listing. Total counts every row matching
+// the filters, not just this page.
+type PageInfo struct {
+	Limit      int    `json:"limit"`
+	Count      int    `json:"count"`
+	Total      int    `json:"total"`
+	HasMore    bool   `json:"has_more"`
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+// TimeRange bounds created_at to [From, To). Nil bounds are open.
+type TimeRange struct {
+	From *time.Time
+	To   *time.Time
+}
+
+// Contains reports whether t is within the range
+func (r TimeRange) Contains(t time.Time) bool {
+	if r.From != nil && t.Before(*r.From) {
+		return false
+	}
+	if r.To != nil && !t.Before(*r.To) {
+		return false
+	}
+	return true
+}
+
+// ListQuery holds the paging query parameters shared by listing endpoints.
+// Offset is only bound so it can be rejected; listings page by cursor.
+type ListQuery struct {
+	Limit  int        `form:"limit" binding:"omitempty,min=1"`
+	Cursor string     `form:"cursor"`
+	Sort   string     `form:"sort"`
+	Order  string     `form:"order" binding:"omitempty,oneof=asc desc"`
+	From   *time.Time `form:"from" time_format:"2006-01-02T15:04:05Z07:00"`
+	To     *time.Time `form:"to" time_format:"2006-01-02T15:04:05Z07:00"`
+	Offset *string    `form:"offset"`
+}
+
+// Options returns the list options for the query, newest first by default
+func (q ListQuery) Options() ListOptions {
+	return ListOptions{
+		Limit:  q.Limit,
+		Cursor: q.Cursor,
+		Sort:   q.Sort,
+		Desc:   q.Order != "asc",
+	}
+}
+
+// Created returns the query's created_at range
+func (q ListQuery) Created() TimeRange {
+	return TimeRange{From: q.From, To: q.To}
+}
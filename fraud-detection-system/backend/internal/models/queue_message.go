@@ -1,4 +1,4 @@
-package queue
+package models
 
 import (
 	"encoding/json"
@@ -20,10 +20,17 @@ const (
 type QueueMessage struct {
 	ID        uuid.UUID              `json:"id"`
 	Type      MessageType            `json:"type"`
+	Version   int                    `json:"version,omitempty"` // payload schema version, for domain events
 	Payload   map[string]interface{} `json:"payload"`
 	Timestamp time.Time              `json:"timestamp"`
 	Retry     int                    `json:"retry"`
 	MaxRetry  int                    `json:"max_retry"`
+	// TraceContext is the trace of the code that queued the message through
+	// the outbox, so the relay publishes it as part of that trace
+	TraceContext map[string]string `json:"trace_context,omitempty"`
+	// RequestID is the API request that produced the message, so worker logs
+	// can be matched to it
+	RequestID string `json:"request_id,omitempty"`
 }
 
 // NewQueueMessage creates a new queue message
@@ -43,8 +50,8 @@ func (m *QueueMessage) ToJSON() ([]byte, error) {
 	return json.Marshal(m)
 }
 
-// FromJSON creates a message from JSON
-func FromJSON(data []byte) (*QueueMessage, error) {
+// QueueMessageFromJSON creates a message from JSON
+func QueueMessageFromJSON(data []byte) (*QueueMessage, error) {
 	var msg QueueMessage
 	err := json.Unmarshal(data, &msg)
 	return &msg, err
@@ -59,4 +66,3 @@ func (m *QueueMessage) CanRetry() bool {
 func (m *QueueMessage) IncrementRetry() {
 	m.Retry++
 }
-
@@ -40,6 +40,28 @@ type SenderRegistry struct {
 	UpdatedAt       time.Time  `json:"updated_at" db:"updated_at"`
 }
 
+// CircularInput is an RBI circular added by an admin
+type CircularInput struct {
+	CircularNumber string     `json:"circular_number" binding:"required,max=50"`
+	Title          string     `json:"title" binding:"required,max=500"`
+	Content        string     `json:"content" binding:"required"`
+	IssuedDate     time.Time  `json:"issued_date" binding:"required"`
+	EffectiveDate  *time.Time `json:"effective_date,omitempty"`
+	ExpiryDate     *time.Time `json:"expiry_date,omitempty"`
+	Category       string     `json:"category" binding:"required,max=50"`
+	Keywords       []string   `json:"keywords" binding:"required,min=1,dive,required"`
+	SourceURL      string     `json:"source_url" binding:"omitempty,url,max=500"`
+}
+
+// SenderInput is a verified sender added to the registry by an admin
+type SenderInput struct {
+	SenderID        string  `json:"sender_id" binding:"required,max=50"`
+	BankName        string  `json:"bank_name" binding:"required,max=255"`
+	BankCode        string  `json:"bank_code" binding:"required,max=20"`
+	VerifiedBy      string  `json:"verified_by" binding:"required,oneof=TELECOM BANK MANUAL"`
+	TelecomOperator *string `json:"telecom_operator,omitempty" binding:"omitempty,max=50"`
+}
+
 type RBIComplianceCheck struct {
 	IsCompliant     bool     `json:"is_compliant"`
 	MatchedCirculars []string `json:"matched_circulars"`
@@ -6,22 +6,32 @@ import (
 	"github.com/google/uuid"
 )
 
+// Report statuses
+const (
+	ReportStatusPending   = "PENDING"
+	ReportStatusReviewed  = "REVIEWED"
+	ReportStatusResolved  = "RESOLVED"
+	ReportStatusDismissed = "DISMISSED"
+)
+
 type Report struct {
-	ID             uuid.UUID  `json:"id" db:"id"`
-	UserID         *uuid.UUID `json:"user_id,omitempty" db:"user_id"`
-	MessageID      *uuid.UUID `json:"message_id,omitempty" db:"message_id"`
-	VerificationID *uuid.UUID `json:"verification_id,omitempty" db:"verification_id"`
-	ReportType     string     `json:"report_type" db:"report_type"` // FRAUD, FALSE_POSITIVE, FEEDBACK
-	Content        string     `json:"content" db:"content"`
-	SenderHeader   string     `json:"sender_header" db:"sender_header"`
-	Description    string     `json:"description" db:"description"`
-	Status         string     `json:"status" db:"status"` // PENDING, REVIEWED, RESOLVED, DISMISSED
-	Priority       string     `json:"priority" db:"priority"` // LOW, MEDIUM, HIGH, CRITICAL
-	ReviewedBy     *uuid.UUID `json:"reviewed_by,omitempty" db:"reviewed_by"`
-	ReviewedAt     *time.Time `json:"reviewed_at,omitempty" db:"reviewed_at"`
-	ReviewNotes    *string    `json:"review_notes,omitempty" db:"review_notes"`
-	CreatedAt      time.Time  `json:"created_at" db:"created_at"`
-	UpdatedAt      time.Time  `json:"updated_at" db:"updated_at"`
+	ID              uuid.UUID  `json:"id" db:"id"`
+	UserID          *uuid.UUID `json:"user_id,omitempty" db:"user_id"`
+	MessageID       *uuid.UUID `json:"message_id,omitempty" db:"message_id"`
+	VerificationID  *uuid.UUID `json:"verification_id,omitempty" db:"verification_id"`
+	ReportType      string     `json:"report_type" db:"report_type"` // FRAUD, FALSE_POSITIVE, FEEDBACK
+	Content         string     `json:"content" db:"content"`
+	SenderHeader    string     `json:"sender_header" db:"sender_header"`
+	Description     string     `json:"description" db:"description"`
+	Status          string     `json:"status" db:"status"` // PENDING, REVIEWED, RESOLVED, DISMISSED
+	Priority        string     `json:"priority" db:"priority"` // LOW, MEDIUM, HIGH, CRITICAL
+	ReviewedBy      *uuid.UUID `json:"reviewed_by,omitempty" db:"reviewed_by"`
+	ReviewedAt      *time.Time `json:"reviewed_at,omitempty" db:"reviewed_at"`
+	ReviewNotes     *string    `json:"review_notes,omitempty" db:"review_notes"`
+	ContentHash     *string    `json:"-" db:"content_hash"`
+	EncryptionKeyID *string    `json:"-" db:"encryption_key_id"`
+	CreatedAt       time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at" db:"updated_at"`
 }
 
 type ReportInput struct {
@@ -33,6 +43,11 @@ type ReportInput struct {
 	Description    string     `json:"description" binding:"required,max=500"`
 }
 
+type ReportReviewInput struct {
+	Status string  `json:"status" binding:"required,oneof=REVIEWED RESOLVED DISMISSED"`
+	Notes  *string `json:"notes,omitempty" binding:"omitempty,max=1000"`
+}
+
 type ReportResponse struct {
 	ID             uuid.UUID  `json:"id"`
 	ReportType     string     `json:"report_type"`
@@ -46,6 +61,41 @@ type ReportResponse struct {
 	CreatedAt      time.Time  `json:"created_at"`
 }
 
+// ReportFilter narrows a report listing. Zero values match everything.
+type ReportFilter struct {
+	ReportType   string
+	Status       string
+	Priority     string
+	SenderHeader string
+	Created      TimeRange
+}
+
+// Sort keys of the report listing
+const (
+	ReportSortCreatedAt = "created_at"
+	ReportSortUpdatedAt = "updated_at"
+)
+
+// ReportListQuery holds the query parameters of the report listing endpoint
+type ReportListQuery struct {
+	ListQuery
+	ReportType   string `form:"report_type" binding:"omitempty,oneof=FRAUD FALSE_POSITIVE FEEDBACK"`
+	Status       string `form:"status" binding:"omitempty,oneof=PENDING REVIEWED RESOLVED DISMISSED"`
+	Priority     string `form:"priority" binding:"omitempty,oneof=LOW MEDIUM HIGH CRITICAL"`
+	SenderHeader string `form:"sender_header"`
+}
+
+// Filter returns the report filter for the query
+func (q ReportListQuery) Filter() ReportFilter {
+	return ReportFilter{
+		ReportType:   q.ReportType,
+		Status:       q.Status,
+		Priority:     q.Priority,
+		SenderHeader: q.SenderHeader,
+		Created:      q.Created(),
+	}
+}
+
 type ReportStats struct {
 	TotalReports     int            `json:"total_reports"`
 	PendingReports   int            `json:"pending_reports"`
@@ -0,0 +1,26 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type RetentionRun struct {
+	ID                   uuid.UUID                        `json:"id" db:"id"`
+	StartedAt            time.Time                        `json:"started_at" db:"started_at"`
+	FinishedAt           *time.Time                       `json:"finished_at,omitempty" db:"finished_at"`
+	Status               string                           `json:"status" db:"status"` // RUNNING, COMPLETED, FAILED
+	MessagesDeleted      int64                            `json:"messages_deleted" db:"messages_deleted"`
+	MessagesAnonymized   int64                            `json:"messages_anonymized" db:"messages_anonymized"`
+	VerificationsDeleted int64                            `json:"verifications_deleted" db:"verifications_deleted"`
+	ResetTokensDeleted   int64                            `json:"reset_tokens_deleted" db:"reset_tokens_deleted"`
+	Details              map[string]*TenantRetentionStats `json:"details" db:"details"` // JSON, keyed by tenant
+	Error                *string                          `json:"error,omitempty" db:"error"`
+}
+
+type TenantRetentionStats struct {
+	MessagesDeleted      int64 `json:"messages_deleted"`
+	MessagesAnonymized   int64 `json:"messages_anonymized"`
+	VerificationsDeleted int64 `json:"verifications_deleted"`
+}
@@ -0,0 +1,138 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/fraud-detection-system/backend/internal/utils"
+)
+
+// Message fields a search can match
+const (
+	SearchFieldContent = "content"
+	SearchFieldURL     = "url"
+	SearchFieldSender  = "sender"
+	SearchFieldPhone   = "phone"
+)
+
+// SearchFields lists every searchable message field
+var SearchFields = []string{SearchFieldContent, SearchFieldURL, SearchFieldSender, SearchFieldPhone}
+
+// MessageSearch is a search over a tenant's messages and their verdicts, or
+// over every tenant's if TenantID is empty. Content is matched as full text,
+// URLs and sender headers as substrings and the phone number exactly.
+type MessageSearch struct {
+	TenantID     string
+	Query        string
+	Fields       []string // empty for every field
+	MessageType  string
+	SenderHeader string
+	IsFraud      *bool
+	FraudType    string
+	Created      TimeRange
+	Limit        int
+	Offset       int
+}
+
+// HasField reports whether the search matches field
+func (s MessageSearch) HasField(field string) bool {
+	if len(s.Fields) == 0 {
+		return true
+	}
+	for _, f := range s.Fields {
+		if f == field {
+			return true
+		}
+	}
+	return false
+}
+
+// MessageSearchResult is a message found by a search, with its verification
+// if it has one
+type MessageSearchResult struct {
+	Message      Message
+	Verification *Verification // only ID, IsFraud, FraudScore and FraudType are set
+	Rank         float64
+}
+
+// SearchHighlight holds fragments of one field with the matches marked
+type SearchHighlight struct {
+	Field     string   `json:"field"`
+	Fragments []string `json:"fragments"`
+}
+
+// MessageSearchHit is a message search result as returned to analysts
+type MessageSearchHit struct {
+	MessageID      uuid.UUID         `json:"message_id"`
+	Content        string            `json:"content"`
+	SenderHeader   string            `json:"sender_header"`
+	MessageType    string            `json:"message_type"`
+	TenantID       string            `json:"tenant_id"`
+	ExtractedURLs  []string          `json:"extracted_urls,omitempty"`
+	VerificationID *uuid.UUID        `json:"verification_id,omitempty"`
+	IsFraud        *bool             `json:"is_fraud,omitempty"`
+	FraudScore     *float64          `json:"fraud_score,omitempty"`
+	FraudType      *string           `json:"fraud_type,omitempty"`
+	RiskLevel      string            `json:"risk_level,omitempty"`
+	Rank           float64           `json:"rank"`
+	Highlights     []SearchHighlight `json:"highlights"`
+	CreatedAt      time.Time         `json:"created_at"`
+}
+
+// MessageSearchQuery holds the query parameters of the message search
+// endpoint
+type MessageSearchQuery struct {
+	Q            string     `form:"q" binding:"required,max=200"`
+	Fields       string     `form:"fields"` // comma separated
+	MessageType  string     `form:"message_type" binding:"omitempty,oneof=SMS WhatsApp Email"`
+	SenderHeader string     `form:"sender_header"`
+	IsFraud      *bool      `form:"is_fraud"`
+	FraudType    string     `form:"fraud_type"`
+	From         *time.Time `form:"from" time_format:"2006-01-02T15:04:05Z07:00"`
+	To           *time.Time `form:"to" time_format:"2006-01-02T15:04:05Z07:00"`
+	Limit        int        `form:"limit" binding:"omitempty,min=1"`
+	Offset       int        `form:"offset" binding:"omitempty,min=0"`
+	AllTenants   bool       `form:"all_tenants"`
+}
+
+// Search returns the search for the query within a tenant, or across every
+// tenant if it asks for all of them
+func (q MessageSearchQuery) Search(tenantID string) (MessageSearch, error) {
+	if q.AllTenants {
+		tenantID = ""
+	}
+	search := MessageSearch{
+		TenantID:     tenantID,
+		Query:        strings.TrimSpace(q.Q),
+		MessageType:  q.MessageType,
+		SenderHeader: q.SenderHeader,
+		IsFraud:      q.IsFraud,
+		FraudType:    q.FraudType,
+		Created:      TimeRange{From: q.From, To: q.To},
+		Limit:        q.Limit,
+		Offset:       q.Offset,
+	}
+	if _, err := utils.ParseSearchQuery(search.Query); err != nil {
+		return search, err
+	}
+	if q.Fields == "" {
+		return search, nil
+	}
+	for _, field := range strings.Split(q.Fields, ",") {
+		field = strings.TrimSpace(field)
+		known := false
+		for _, f := range SearchFields {
+			if field == f {
+				known = true
+				break
+			}
+		}
+		if !known {
+			return search, fmt.Errorf("unknown search field %q", field)
+		}
+		search.Fields = append(search.Fields, field)
+	}
+	return search, nil
+}
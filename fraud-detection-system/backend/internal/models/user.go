@@ -7,18 +7,30 @@ import (
 )
 
 type User struct {
-	ID           uuid.UUID  `json:"id" db:"id"`
-	Email        string     `json:"email" db:"email"`
-	PasswordHash string     `json:"-" db:"password_hash"`
-	FullName     string     `json:"full_name" db:"full_name"`
-	PhoneNumber  string     `json:"phone_number" db:"phone_number"`
-	IsActive     bool       `json:"is_active" db:"is_active"`
-	IsVerified   bool       `json:"is_verified" db:"is_verified"`
-	CreatedAt    time.Time  `json:"created_at" db:"created_at"`
-	UpdatedAt    time.Time  `json:"updated_at" db:"updated_at"`
-	LastLoginAt  *time.Time `json:"last_login_at" db:"last_login_at"`
+	ID                uuid.UUID  `json:"id" db:"id"`
+	Email             string     `json:"email" db:"email"`
+	PasswordHash      string     `json:"-" db:"password_hash"`
+	FullName          string     `json:"full_name" db:"full_name"`
+	PhoneNumber       string     `json:"phone_number" db:"phone_number"`
+	IsActive          bool       `json:"is_active" db:"is_active"`
+	IsVerified        bool       `json:"is_verified" db:"is_verified"`
+	CreatedAt         time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt         time.Time  `json:"updated_at" db:"updated_at"`
+	LastLoginAt       *time.Time `json:"last_login_at" db:"last_login_at"`
+	PasswordChangedAt *time.Time `json:"-" db:"password_changed_at"`
+	Role              string     `json:"role" db:"role"` // USER, ANALYST, ADMIN
+	TenantID          string     `json:"tenant_id" db:"tenant_id"`
+	TOTPSecret        *string    `json:"-" db:"totp_secret"`
+	TOTPEnabled       bool       `json:"totp_enabled" db:"totp_enabled"`
+	TOTPEnabledAt     *time.Time `json:"-" db:"totp_enabled_at"`
 }
 
+const (
+	RoleUser    = "USER"
+	RoleAnalyst = "ANALYST"
+	RoleAdmin   = "ADMIN"
+)
+
 type UserRegistration struct {
 	Email       string `json:"email" binding:"required,email"`
 	Password    string `json:"password" binding:"required,min=8"`
@@ -38,6 +50,9 @@ type UserResponse struct {
 	PhoneNumber string     `json:"phone_number"`
 	IsActive    bool       `json:"is_active"`
 	IsVerified  bool       `json:"is_verified"`
+	Role        string     `json:"role"`
+	TenantID    string     `json:"tenant_id"`
+	TOTPEnabled bool       `json:"totp_enabled"`
 	CreatedAt   time.Time  `json:"created_at"`
 	LastLoginAt *time.Time `json:"last_login_at,omitempty"`
 }
@@ -49,7 +64,80 @@ type TokenPair struct {
 	TokenType    string `json:"token_type"`
 }
 
+// LoginResponse is returned by login. When two-factor authentication is
+// enabled the token pair is omitted and a challenge token is returned instead.
+type LoginResponse struct {
+	*TokenPair
+	MFARequired      bool   `json:"mfa_required"`
+	ChallengeToken   string `json:"challenge_token,omitempty"`
+	MFASetupRequired bool   `json:"mfa_setup_required,omitempty"`
+}
+
+type MFALoginRequest struct {
+	ChallengeToken string `json:"challenge_token" binding:"required"`
+	Code           string `json:"code" binding:"required"`
+}
+
+type MFACodeRequest struct {
+	Code string `json:"code" binding:"required"`
+}
+
+type MFADisableRequest struct {
+	Password string `json:"password" binding:"required"`
+	Code     string `json:"code" binding:"required"`
+}
+
+type MFAEnrollmentResponse struct {
+	Secret     string `json:"secret"`
+	OTPAuthURI string `json:"otpauth_uri"`
+}
+
+type MFAConfirmationResponse struct {
+	RecoveryCodes []string   `json:"recovery_codes"`
+	Tokens        *TokenPair `json:"tokens"`
+}
+
+type RecoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
 type RefreshTokenRequest struct {
 	RefreshToken string `json:"refresh_token" binding:"required"`
 }
 
+type ForgotPasswordRequest struct {
+	Email string `json:"email" binding:"required,email"`
+}
+
+type ResetPasswordRequest struct {
+	Token       string `json:"token" binding:"required"`
+	NewPassword string `json:"new_password" binding:"required,min=8"`
+}
+
+type ChangePasswordRequest struct {
+	OldPassword string `json:"old_password" binding:"required"`
+	NewPassword string `json:"new_password" binding:"required,min=8"`
+}
+
+type PasswordResetToken struct {
+	ID        uuid.UUID  `json:"id" db:"id"`
+	UserID    uuid.UUID  `json:"user_id" db:"user_id"`
+	TokenHash string     `json:"-" db:"token_hash"`
+	ExpiresAt time.Time  `json:"expires_at" db:"expires_at"`
+	UsedAt    *time.Time `json:"used_at,omitempty" db:"used_at"`
+	CreatedAt time.Time  `json:"created_at" db:"created_at"`
+}
+
+type DeleteAccountRequest struct {
+	Password string `json:"password" binding:"required"`
+	Code     string `json:"code"` // TOTP or recovery code, required with two-factor enabled
+}
+
+// UserDataExport is everything stored about a user, returned by the data export
+type UserDataExport struct {
+	ExportedAt    time.Time       `json:"exported_at"`
+	Profile       *UserResponse   `json:"profile"`
+	Messages      []*Message      `json:"messages"`
+	Verifications []*Verification `json:"verifications"`
+	Reports       []*Report       `json:"reports"`
+}
@@ -23,6 +23,10 @@ type Verification struct {
 	Explanation           string     `json:"explanation" db:"explanation"`
 	Recommendations       string     `json:"recommendations" db:"recommendations"` // JSON
 	ProcessingTimeMs      int        `json:"processing_time_ms" db:"processing_time_ms"`
+	Degraded              bool       `json:"degraded" db:"degraded"` // scored by the fallback scorer
+	SourceVerificationID  *uuid.UUID `json:"source_verification_id,omitempty" db:"source_verification_id"` // set when reused from the verdict cache
+	TenantID              string     `json:"tenant_id" db:"tenant_id"`
+	StageTimings          *string    `json:"stage_timings,omitempty" db:"stage_timings"` // JSON
 	CreatedAt             time.Time  `json:"created_at" db:"created_at"`
 	UpdatedAt             time.Time  `json:"updated_at" db:"updated_at"`
 }
@@ -33,23 +37,61 @@ type VerificationRequest struct {
 	ReceivedAt   *time.Time `json:"received_at,omitempty"`
 	MessageType  string     `json:"message_type" binding:"omitempty,oneof=SMS WhatsApp Email"`
 	PhoneNumber  *string    `json:"phone_number,omitempty"`
+	TenantID     string     `json:"-"` // set from the request context, not the body
 }
 
 type VerificationResponse struct {
-	ID                uuid.UUID              `json:"id"`
-	MessageID         uuid.UUID              `json:"message_id"`
-	IsFraud           bool                   `json:"is_fraud"`
-	FraudScore        float64                `json:"fraud_score"`
-	FraudType         *string                `json:"fraud_type,omitempty"`
-	Confidence        float64                `json:"confidence"`
-	RiskLevel         string                 `json:"risk_level"` // LOW, MEDIUM, HIGH, CRITICAL
-	HeaderVerified    bool                   `json:"header_verified"`
-	RBICompliant      bool                   `json:"rbi_compliant"`
-	Explanation       string                 `json:"explanation"`
-	Recommendations   []string               `json:"recommendations"`
-	ModelPredictions  map[string]interface{} `json:"model_predictions"`
-	ProcessingTimeMs  int                    `json:"processing_time_ms"`
-	VerifiedAt        time.Time              `json:"verified_at"`
+	ID                   uuid.UUID              `json:"id"`
+	MessageID            uuid.UUID              `json:"message_id"`
+	IsFraud              bool                   `json:"is_fraud"`
+	FraudScore           float64                `json:"fraud_score"`
+	FraudType            *string                `json:"fraud_type,omitempty"`
+	Confidence           float64                `json:"confidence"`
+	RiskLevel            string                 `json:"risk_level"` // LOW, MEDIUM, HIGH, CRITICAL
+	HeaderVerified       bool                   `json:"header_verified"`
+	RBICompliant         bool                   `json:"rbi_compliant"`
+	Explanation          string                 `json:"explanation"`
+	Recommendations      []string               `json:"recommendations"`
+	ModelPredictions     map[string]interface{} `json:"model_predictions"`
+	ModelVersion         string                 `json:"model_version"`
+	Degraded             bool                   `json:"degraded"`
+	Cached               bool                   `json:"cached"`
+	SourceVerificationID *uuid.UUID             `json:"source_verification_id,omitempty"`
+	ProcessingTimeMs     int                    `json:"processing_time_ms"`
+	StageTimings         map[string]StageTiming `json:"stage_timings,omitempty"`
+	VerifiedAt           time.Time              `json:"verified_at"`
+}
+
+// Verification stages whose timings are recorded
+const (
+	StageFeatures    = "features"
+	StageCacheLookup = "cache_lookup"
+	StageML          = "ml"
+	StageHeader      = "header"
+	StageRBI         = "rbi"
+	StagePersist     = "persist"
+)
+
+// Stage outcomes. A check that errors or times out is replaced by a
+// fallback result so the verification still completes.
+const (
+	StageStatusOK      = "ok"
+	StageStatusError   = "error"
+	StageStatusTimeout = "timeout"
+)
+
+// StageTiming records how long one verification stage took and how it ended
+type StageTiming struct {
+	DurationMs int    `json:"duration_ms"`
+	Status     string `json:"status"`
+}
+
+// CachedVerdict is a verification result reused for identical messages
+type CachedVerdict struct {
+	Verification     Verification           `json:"verification"`
+	RiskLevel        string                 `json:"risk_level"`
+	Recommendations  []string               `json:"recommendations"`
+	ModelPredictions map[string]interface{} `json:"model_predictions"`
 }
 
 type MLInferenceRequest struct {
@@ -69,6 +111,37 @@ type MLInferenceResponse struct {
 	ModelVersion      string                 `json:"model_version"`
 }
 
+// ModelScore is the prediction of a single named model
+type ModelScore struct {
+	Model        string  `json:"model"`
+	ModelVersion string  `json:"model_version"`
+	IsFraud      bool    `json:"is_fraud"`
+	FraudScore   float64 `json:"fraud_score"`
+	FraudType    string  `json:"fraud_type"`
+	Confidence   float64 `json:"confidence"`
+}
+
+// MLRouting records which model decided a verdict and, when shadow scoring
+// is on, what the other model predicted. It is stored in MLPredictions under "routing".
+type MLRouting struct {
+	Primary ModelScore  `json:"primary"`
+	Shadow  *ModelScore `json:"shadow,omitempty"`
+}
+
+// ModelComparison summarizes how two models agreed on the same messages
+type ModelComparison struct {
+	PrimaryModel     string  `json:"primary_model"`
+	ShadowModel      string  `json:"shadow_model"`
+	Total            int     `json:"total"`
+	Agreements       int     `json:"agreements"`
+	AgreementRate    float64 `json:"agreement_rate"`
+	PrimaryOnlyFraud int     `json:"primary_only_fraud"`
+	ShadowOnlyFraud  int     `json:"shadow_only_fraud"`
+	AvgScoreDelta    float64 `json:"avg_score_delta"` // shadow minus primary
+	AvgAbsScoreDelta float64 `json:"avg_abs_score_delta"`
+	MaxAbsScoreDelta float64 `json:"max_abs_score_delta"`
+}
+
 type VerificationStats struct {
 	TotalVerifications int     `json:"total_verifications"`
 	FraudDetected      int     `json:"fraud_detected"`
@@ -79,3 +152,82 @@ type VerificationStats struct {
 	Last7Days          int     `json:"last_7_days"`
 }
 
+
+// Risk levels reported with verdicts
+const (
+	RiskLevelLow      = "LOW"
+	RiskLevelMedium   = "MEDIUM"
+	RiskLevelHigh     = "HIGH"
+	RiskLevelCritical = "CRITICAL"
+)
+
+// RiskScoreRange returns the fraud scores [min, max) of stored verifications
+// at a risk level, matching the level the history listing reports. max is 0
+// for CRITICAL, which has no upper bound. ok is false for unknown levels.
+func RiskScoreRange(level string) (min, max float64, ok bool) {
+	switch level {
+	case RiskLevelLow:
+		return 0, 0.4, true
+	case RiskLevelMedium:
+		return 0.4, 0.6, true
+	case RiskLevelHigh:
+		return 0.6, 0.8, true
+	case RiskLevelCritical:
+		return 0.8, 0, true
+	}
+	return 0, 0, false
+}
+
+// RiskLevelForScore returns the risk level of a stored fraud score, the
+// inverse of RiskScoreRange
+func RiskLevelForScore(score float64) string {
+	switch {
+	case score >= 0.8:
+		return RiskLevelCritical
+	case score >= 0.6:
+		return RiskLevelHigh
+	case score >= 0.4:
+		return RiskLevelMedium
+	}
+	return RiskLevelLow
+}
+
+// VerificationFilter narrows a verification history listing. Zero values
+// match everything.
+type VerificationFilter struct {
+	IsFraud      *bool
+	RiskLevel    string
+	FraudType    string
+	SenderHeader string
+	MessageType  string
+	Created      TimeRange
+}
+
+// Sort keys of the verification history listing
+const (
+	VerificationSortCreatedAt  = "created_at"
+	VerificationSortFraudScore = "fraud_score"
+)
+
+// VerificationHistoryQuery holds the query parameters of the verification
+// history endpoint
+type VerificationHistoryQuery struct {
+	ListQuery
+	IsFraud      *bool  `form:"is_fraud"`
+	RiskLevel    string `form:"risk_level" binding:"omitempty,oneof=LOW MEDIUM HIGH CRITICAL"`
+	FraudType    string `form:"fraud_type"`
+	SenderHeader string `form:"sender_header"`
+	MessageType  string `form:"message_type" binding:"omitempty,oneof=SMS WhatsApp Email"`
+}
+
+// Filter returns the verification filter for the query
+func (q VerificationHistoryQuery) Filter() VerificationFilter {
+	return VerificationFilter{
+		IsFraud:      q.IsFraud,
+		RiskLevel:    q.RiskLevel,
+		FraudType:    q.FraudType,
+		SenderHeader: q.SenderHeader,
+		MessageType:  q.MessageType,
+		Created:      q.Created(),
+	}
+}
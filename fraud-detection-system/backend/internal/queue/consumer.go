@@ -3,14 +3,20 @@ package queue
 import (
 	"context"
 	"fmt"
+	"strconv"
 	"time"
 
 	"github.com/segmentio/kafka-go"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	"github.com/fraud-detection-system/backend/internal/config"
+	"github.com/fraud-detection-system/backend/internal/metrics"
+	"github.com/fraud-detection-system/backend/internal/models"
+	"github.com/fraud-detection-system/backend/internal/tracing"
 	"github.com/fraud-detection-system/backend/internal/utils"
 )
 
-type MessageHandler func(ctx context.Context, message *QueueMessage) error
+type MessageHandler func(ctx context.Context, message *models.QueueMessage) error
 
 type Consumer struct {
 	reader  *kafka.Reader
@@ -40,50 +46,68 @@ func NewConsumer(cfg *config.Config, topic string, handler MessageHandler) *Cons
 
 // Start starts consuming messages
 func (c *Consumer) Start(ctx context.Context) error {
-	utils.GetLogger().Info("Starting Kafka consumer")
+	utils.GetLoggerWithContext(ctx).Info("Starting Kafka consumer")
 
 	for {
 		select {
 		case <-ctx.Done():
-			utils.GetLogger().Info("Stopping Kafka consumer")
+			utils.GetLoggerWithContext(ctx).Info("Stopping Kafka consumer")
 			return nil
 		default:
 			if err := c.processMessage(ctx); err != nil {
-				utils.GetLogger().WithError(err).Error("Failed to process message")
+				utils.GetLoggerWithContext(ctx).WithError(err).Error("Failed to process message")
 				// Continue processing other messages
 			}
 		}
 	}
 }
 
-func (c *Consumer) processMessage(ctx context.Context) error {
+func (c *Consumer) processMessage(ctx context.Context) (err error) {
 	kafkaMsg, err := c.reader.FetchMessage(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to fetch message: %w", err)
 	}
 
-	message, err := FromJSON(kafkaMsg.Value)
+	// Continue the trace of the code that published the message
+	ctx, span := tracing.Start(tracing.ExtractKafka(ctx, &kafkaMsg), "Consumer.Process "+kafkaMsg.Topic,
+		trace.WithSpanKind(trace.SpanKindConsumer),
+		trace.WithAttributes(
+			attribute.String("messaging.system", "kafka"),
+			attribute.String("messaging.source.name", kafkaMsg.Topic),
+			attribute.Int("messaging.kafka.partition", kafkaMsg.Partition),
+			attribute.Int64("messaging.kafka.offset", kafkaMsg.Offset),
+		),
+	)
+	defer func() { tracing.End(span, err) }()
+	metrics.KafkaConsumerLag.WithLabelValues(kafkaMsg.Topic, strconv.Itoa(kafkaMsg.Partition)).
+		Set(float64(kafkaMsg.HighWaterMark - kafkaMsg.Offset - 1))
+
+	message, err := models.QueueMessageFromJSON(kafkaMsg.Value)
 	if err != nil {
-		utils.GetLogger().WithError(err).Error("Failed to unmarshal message")
+		utils.GetLoggerWithContext(ctx).WithError(err).Error("Failed to unmarshal message")
 		// Commit the message to skip it
+		metrics.KafkaConsumed.WithLabelValues(kafkaMsg.Topic, "invalid").Inc()
 		_ = c.reader.CommitMessages(ctx, kafkaMsg)
 		return fmt.Errorf("failed to unmarshal message: %w", err)
 	}
 
-	utils.GetLogger().WithField("message_id", message.ID).Info("Processing message")
+	// Handlers log with the request ID of the API call that produced the message
+	ctx = utils.ContextWithRequestID(ctx, message.RequestID)
+	utils.GetLoggerWithContext(ctx).WithField("message_id", message.ID).Info("Processing message")
 
 	// Process the message
 	if err := c.handler(ctx, message); err != nil {
-		utils.GetLogger().WithError(err).WithField("message_id", message.ID).Error("Handler failed to process message")
-		
+		metrics.KafkaConsumed.WithLabelValues(kafkaMsg.Topic, "error").Inc()
+		utils.GetLoggerWithContext(ctx).WithError(err).WithField("message_id", message.ID).Error("Handler failed to process message")
+
 		// Check if we can retry
 		if message.CanRetry() {
 			message.IncrementRetry()
-			utils.GetLogger().WithField("message_id", message.ID).WithField("retry", message.Retry).Info("Retrying message")
+			utils.GetLoggerWithContext(ctx).WithField("message_id", message.ID).WithField("retry", message.Retry).Info("Retrying message")
 			// In a real implementation, you might want to republish to a retry topic
 			// For now, we'll just commit and move on
 		}
-		
+
 		// Commit the message even if processing failed to avoid infinite retries
 		if err := c.reader.CommitMessages(ctx, kafkaMsg); err != nil {
 			return fmt.Errorf("failed to commit message: %w", err)
@@ -92,11 +116,12 @@ func (c *Consumer) processMessage(ctx context.Context) error {
 	}
 
 	// Commit the message
+	metrics.KafkaConsumed.WithLabelValues(kafkaMsg.Topic, "ok").Inc()
 	if err := c.reader.CommitMessages(ctx, kafkaMsg); err != nil {
 		return fmt.Errorf("failed to commit message: %w", err)
 	}
 
-	utils.GetLogger().WithField("message_id", message.ID).Info("Message processed successfully")
+	utils.GetLoggerWithContext(ctx).WithField("message_id", message.ID).Info("Message processed successfully")
 	return nil
 }
 
@@ -104,4 +129,3 @@ func (c *Consumer) processMessage(ctx context.Context) error {
 func (c *Consumer) Close() error {
 	return c.reader.Close()
 }
-
@@ -2,14 +2,24 @@ package queue
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
 	"github.com/segmentio/kafka-go"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	"github.com/fraud-detection-system/backend/internal/config"
+	"github.com/fraud-detection-system/backend/internal/metrics"
+	"github.com/fraud-detection-system/backend/internal/models"
+	"github.com/fraud-detection-system/backend/internal/tracing"
 	"github.com/fraud-detection-system/backend/internal/utils"
 )
 
+// ErrUnknownTopic is returned when publishing to a topic the producer has no
+// writer for. Retrying does not help.
+var ErrUnknownTopic = errors.New("unknown topic")
+
 type Producer struct {
 	writers map[string]*kafka.Writer
 	config  *config.Config
@@ -24,13 +34,14 @@ func NewProducer(cfg *config.Config) *Producer {
 		cfg.Kafka.TopicVerification,
 		cfg.Kafka.TopicReports,
 		cfg.Kafka.TopicAlerts,
+		cfg.Kafka.TopicEvents,
 	}
 
 	for _, topic := range topics {
 		writer := &kafka.Writer{
 			Addr:         kafka.TCP(cfg.Kafka.Brokers...),
 			Topic:        topic,
-			Balancer:     &kafka.LeastBytes{},
+			Balancer:     &kafka.Hash{}, // same key, same partition
 			BatchSize:    100,
 			BatchTimeout: 10 * time.Millisecond,
 			RequiredAcks: kafka.RequireOne,
@@ -45,11 +56,31 @@ func NewProducer(cfg *config.Config) *Producer {
 	}
 }
 
-// Publish publishes a message to a topic
-func (p *Producer) Publish(ctx context.Context, topic string, message *QueueMessage) error {
+// Publish publishes a message to a topic, keyed by its ID
+func (p *Producer) Publish(ctx context.Context, topic string, message *models.QueueMessage) error {
+	return p.PublishKeyed(ctx, topic, message.ID.String(), message)
+}
+
+// PublishKeyed publishes a message to a topic. Messages with the same key
+// are written to the same partition, so consumers see them in order. The
+// trace context of ctx travels in the message headers.
+func (p *Producer) PublishKeyed(ctx context.Context, topic, key string, message *models.QueueMessage) (err error) {
+	ctx, span := tracing.Start(ctx, "Producer.Publish "+topic,
+		trace.WithSpanKind(trace.SpanKindProducer),
+		trace.WithAttributes(
+			attribute.String("messaging.system", "kafka"),
+			attribute.String("messaging.destination.name", topic),
+			attribute.String("messaging.message.id", message.ID.String()),
+		),
+	)
+	defer func() { tracing.End(span, err) }()
+
 	writer, ok := p.writers[topic]
 	if !ok {
-		return fmt.Errorf("writer not found for topic: %s", topic)
+		return fmt.Errorf("%w: %s", ErrUnknownTopic, topic)
+	}
+	if message.RequestID == "" {
+		message.RequestID = utils.RequestIDFromContext(ctx)
 	}
 
 	data, err := message.ToJSON()
@@ -58,35 +89,38 @@ func (p *Producer) Publish(ctx context.Context, topic string, message *QueueMess
 	}
 
 	kafkaMsg := kafka.Message{
-		Key:   []byte(message.ID.String()),
+		Key:   []byte(key),
 		Value: data,
 		Time:  time.Now(),
 	}
+	tracing.InjectKafka(ctx, &kafkaMsg)
 
 	if err := writer.WriteMessages(ctx, kafkaMsg); err != nil {
-		utils.GetLogger().WithError(err).Error("Failed to publish message to Kafka")
+		metrics.KafkaProduced.WithLabelValues(topic, "error").Inc()
+		utils.GetLoggerWithContext(ctx).WithError(err).Error("Failed to publish message to Kafka")
 		return fmt.Errorf("failed to write message: %w", err)
 	}
+	metrics.KafkaProduced.WithLabelValues(topic, "ok").Inc()
 
-	utils.GetLogger().WithField("topic", topic).WithField("message_id", message.ID).Info("Message published to Kafka")
+	utils.GetLoggerWithContext(ctx).WithField("topic", topic).WithField("message_id", message.ID).Info("Message published to Kafka")
 	return nil
 }
 
 // PublishVerification publishes a verification request
 func (p *Producer) PublishVerification(ctx context.Context, payload map[string]interface{}) error {
-	message := NewQueueMessage(MessageTypeVerification, payload)
+	message := models.NewQueueMessage(models.MessageTypeVerification, payload)
 	return p.Publish(ctx, p.config.Kafka.TopicVerification, message)
 }
 
 // PublishReport publishes a fraud report
 func (p *Producer) PublishReport(ctx context.Context, payload map[string]interface{}) error {
-	message := NewQueueMessage(MessageTypeReport, payload)
+	message := models.NewQueueMessage(models.MessageTypeReport, payload)
 	return p.Publish(ctx, p.config.Kafka.TopicReports, message)
 }
 
 // PublishAlert publishes an alert
 func (p *Producer) PublishAlert(ctx context.Context, payload map[string]interface{}) error {
-	message := NewQueueMessage(MessageTypeAlert, payload)
+	message := models.NewQueueMessage(models.MessageTypeAlert, payload)
 	return p.Publish(ctx, p.config.Kafka.TopicAlerts, message)
 }
 
@@ -99,4 +133,3 @@ func (p *Producer) Close() error {
 	}
 	return nil
 }
-
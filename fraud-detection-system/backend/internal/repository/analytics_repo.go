@@ -0,0 +1,204 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/fraud-detection-system/backend/internal/models"
+	"github.com/fraud-detection-system/backend/internal/tracing"
+)
+
+type AnalyticsRepository struct {
+	db     DBTX
+	reader DBTX
+}
+
+func NewAnalyticsRepository(db *sql.DB) *AnalyticsRepository {
+	return &AnalyticsRepository{db: db, reader: db}
+}
+
+// WithTx returns a copy of the repository that runs its queries in tx
+func (r *AnalyticsRepository) WithTx(tx *sql.Tx) *AnalyticsRepository {
+	return &AnalyticsRepository{db: tx, reader: tx}
+}
+
+// WithReader returns a copy of the repository that runs trend queries on
+// reader, which may lag behind the primary
+func (r *AnalyticsRepository) WithReader(reader DBTX) *AnalyticsRepository {
+	return &AnalyticsRepository{db: r.db, reader: reader}
+}
+
+// RecordVerification adds a verification to its hourly rollup and, if it
+// was fraud, to the hourly top list rollups
+func (r *AnalyticsRepository) RecordVerification(ctx context.Context, update models.VerificationRollupUpdate) (err error) {
+	ctx, span := startSpan(ctx, "AnalyticsRepository.RecordVerification")
+	defer func() { tracing.End(span, err) }()
+
+	fraud := 0
+	if update.IsFraud {
+		fraud = 1
+	}
+	risk := riskCounts(update.RiskLevel)
+	query := `
+		INSERT INTO verification_rollups (tenant_id, message_type, bucket_start, verifications, fraud,
+		                                  risk_low, risk_medium, risk_high, risk_critical)
+		VALUES ($1, $2, $3, 1, $4, $5, $6, $7, $8)
+		ON CONFLICT (tenant_id, message_type, bucket_start) DO UPDATE SET
+		    verifications = verification_rollups.verifications + 1,
+		    fraud = verification_rollups.fraud + EXCLUDED.fraud,
+		    risk_low = verification_rollups.risk_low + EXCLUDED.risk_low,
+		    risk_medium = verification_rollups.risk_medium + EXCLUDED.risk_medium,
+		    risk_high = verification_rollups.risk_high + EXCLUDED.risk_high,
+		    risk_critical = verification_rollups.risk_critical + EXCLUDED.risk_critical
+	`
+	_, err = r.db.ExecContext(ctx, query,
+		update.TenantID, update.MessageType, models.BucketStart(update.VerifiedAt, models.GranularityHour),
+		fraud, risk[0], risk[1], risk[2], risk[3],
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update verification rollup: %w", err)
+	}
+
+	dims := update.Dimensions()
+	if len(dims) == 0 {
+		return nil
+	}
+	var q listQuery
+	var rows []string
+	hour := models.BucketStart(update.VerifiedAt, models.GranularityHour)
+	for _, dimension := range sortedKeys(dims) {
+		for _, value := range dims[dimension] {
+			rows = append(rows, fmt.Sprintf("(%s, %s, %s, %s, %s, 1)",
+				q.arg(update.TenantID), q.arg(update.MessageType), q.arg(dimension), q.arg(value), q.arg(hour)))
+		}
+	}
+	if len(rows) == 0 {
+		return nil
+	}
+	query = `
+		INSERT INTO verification_dimension_rollups (tenant_id, message_type, dimension, value, bucket_start, fraud)
+		VALUES ` + strings.Join(rows, ", ") + `
+		ON CONFLICT (tenant_id, dimension, bucket_start, message_type, value) DO UPDATE SET
+		    fraud = verification_dimension_rollups.fraud + 1
+	`
+	if _, err := r.db.ExecContext(ctx, query, q.args...); err != nil {
+		return fmt.Errorf("failed to update dimension rollups: %w", err)
+	}
+	return nil
+}
+
+// GetTrend returns the non-empty buckets of filter's time series, oldest
+// first
+func (r *AnalyticsRepository) GetTrend(ctx context.Context, filter models.AnalyticsFilter) (_ []*models.TrendBucket, err error) {
+	ctx, span := startSpan(ctx, "AnalyticsRepository.GetTrend")
+	defer func() { tracing.End(span, err) }()
+
+	q := analyticsQuery(filter)
+	granularity := q.arg(filter.Granularity)
+	query := `
+		SELECT date_trunc(` + granularity + `, bucket_start) AS bucket,
+		       SUM(verifications), SUM(fraud), SUM(risk_low), SUM(risk_medium), SUM(risk_high), SUM(risk_critical)
+		FROM verification_rollups
+		` + q.where() + `
+		GROUP BY bucket
+		ORDER BY bucket
+	`
+	rows, err := r.reader.QueryContext(ctx, query, q.args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get trend: %w", err)
+	}
+	defer rows.Close()
+
+	var buckets []*models.TrendBucket
+	for rows.Next() {
+		var bucket models.TrendBucket
+		var low, medium, high, critical int64
+		if err := rows.Scan(&bucket.Start, &bucket.Verifications, &bucket.Fraud, &low, &medium, &high, &critical); err != nil {
+			return nil, fmt.Errorf("failed to scan trend bucket: %w", err)
+		}
+		bucket.Start = bucket.Start.UTC()
+		bucket.RiskLevels = map[string]int64{
+			models.RiskLevelLow:      low,
+			models.RiskLevelMedium:   medium,
+			models.RiskLevelHigh:     high,
+			models.RiskLevelCritical: critical,
+		}
+		buckets = append(buckets, &bucket)
+	}
+	return buckets, rows.Err()
+}
+
+// GetTop returns the limit values of dimension with the most fraud in
+// filter's range, most first
+func (r *AnalyticsRepository) GetTop(ctx context.Context, filter models.AnalyticsFilter, dimension string, limit int) (_ []models.TopEntry, err error) {
+	ctx, span := startSpan(ctx, "AnalyticsRepository.GetTop")
+	defer func() { tracing.End(span, err) }()
+
+	q := analyticsQuery(filter)
+	q.add("dimension = ?", dimension)
+	query := `
+		SELECT value, SUM(fraud) AS total
+		FROM verification_dimension_rollups
+		` + q.where() + `
+		GROUP BY value
+		ORDER BY total DESC, value
+		LIMIT ` + q.arg(limit)
+	rows, err := r.reader.QueryContext(ctx, query, q.args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get top %s: %w", dimension, err)
+	}
+	defer rows.Close()
+
+	entries := []models.TopEntry{}
+	for rows.Next() {
+		var entry models.TopEntry
+		if err := rows.Scan(&entry.Value, &entry.Count); err != nil {
+			return nil, fmt.Errorf("failed to scan top %s: %w", dimension, err)
+		}
+		entries = append(entries, entry)
+	}
+	return entries, rows.Err()
+}
+
+// analyticsQuery filters rollups to filter's tenant, message type and range
+func analyticsQuery(filter models.AnalyticsFilter) *listQuery {
+	q := &listQuery{}
+	q.add("bucket_start >= ?", filter.From)
+	q.add("bucket_start < ?", filter.To)
+	if filter.TenantID != "" {
+		q.add("tenant_id = ?", filter.TenantID)
+	}
+	if filter.MessageType != "" {
+		q.add("message_type = ?", filter.MessageType)
+	}
+	return q
+}
+
+// riskCounts returns the low, medium, high and critical counts a
+// verification of the given risk level adds
+func riskCounts(level string) [4]int {
+	var counts [4]int
+	switch level {
+	case models.RiskLevelCritical:
+		counts[3] = 1
+	case models.RiskLevelHigh:
+		counts[2] = 1
+	case models.RiskLevelMedium:
+		counts[1] = 1
+	default:
+		counts[0] = 1
+	}
+	return counts
+}
+
+func sortedKeys(m map[string][]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,96 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/lib/pq"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"github.com/fraud-detection-system/backend/internal/tracing"
+)
+
+// DBTX is the subset of *sql.DB and *sql.Tx used by repositories, so the
+// same repository code can run inside or outside a transaction
+type DBTX interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// isUniqueViolation reports whether err is a Postgres unique constraint
+// violation
+func isUniqueViolation(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == "23505"
+}
+
+// pinner is a reader that spreads queries over several pools, like
+// database.ReadRouter
+type pinner interface {
+	Pin() *sql.DB
+}
+
+// pin returns the pool a call that runs several queries should use, so they
+// all read from the same replica
+func pin(db DBTX) DBTX {
+	if p, ok := db.(pinner); ok {
+		return p.Pin()
+	}
+	return db
+}
+
+// readSnapshot runs fn in a read-only repeatable-read transaction on the
+// pool pin picks, so all its queries see the same snapshot of the data.
+// When db is already a transaction, fn runs on it directly.
+func readSnapshot(ctx context.Context, db DBTX, fn func(DBTX) error) error {
+	sqlDB, ok := pin(db).(*sql.DB)
+	if !ok {
+		return fn(db)
+	}
+
+	tx, err := sqlDB.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+	if err != nil {
+		return fmt.Errorf("failed to begin read transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := fn(tx); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// withTx runs fn in a new transaction when db is a *sql.DB, or directly when
+// db is already a transaction owned by the caller
+func withTx(ctx context.Context, db DBTX, fn func(DBTX) error) error {
+	sqlDB, ok := db.(*sql.DB)
+	if !ok {
+		return fn(db)
+	}
+
+	tx, err := sqlDB.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := fn(tx); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// startSpan starts a span for a repository call
+func startSpan(ctx context.Context, name string) (context.Context, trace.Span) {
+	return tracing.Start(ctx, name,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attribute.String("db.system", "postgresql")),
+	)
+}
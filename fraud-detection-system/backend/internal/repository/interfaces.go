@@ -0,0 +1,142 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/fraud-detection-system/backend/internal/models"
+)
+
+// The Store interfaces are what services and handlers depend on. The
+// Postgres repositories in this package implement them; package memory has
+// in-memory implementations for tests.
+
+type UserStore interface {
+	Create(ctx context.Context, user *models.User) error
+	GetByID(ctx context.Context, id uuid.UUID) (*models.User, error)
+	GetByEmail(ctx context.Context, email string) (*models.User, error)
+	Update(ctx context.Context, user *models.User) error
+	UpdateLastLogin(ctx context.Context, id uuid.UUID) error
+	UpdatePassword(ctx context.Context, id uuid.UUID, passwordHash string) error
+	SetTOTPSecret(ctx context.Context, id uuid.UUID, secret string) error
+	EnableTOTP(ctx context.Context, id uuid.UUID) error
+	DisableTOTP(ctx context.Context, id uuid.UUID) error
+	ReencryptTOTPBatch(ctx context.Context, afterID uuid.UUID, limit int) (int, uuid.UUID, error)
+	Delete(ctx context.Context, id uuid.UUID) error
+	Exists(ctx context.Context, email string) (bool, error)
+}
+
+type PasswordResetStore interface {
+	Create(ctx context.Context, token *models.PasswordResetToken) error
+	Consume(ctx context.Context, tokenHash string) (*models.PasswordResetToken, error)
+	InvalidateForUser(ctx context.Context, userID uuid.UUID) error
+	DeleteExpired(ctx context.Context, before time.Time) (int64, error)
+}
+
+type RecoveryCodeStore interface {
+	ReplaceForUser(ctx context.Context, userID uuid.UUID, codeHashes []string) error
+	Consume(ctx context.Context, userID uuid.UUID, codeHash string) (bool, error)
+	CountUnused(ctx context.Context, userID uuid.UUID) (int, error)
+	DeleteForUser(ctx context.Context, userID uuid.UUID) error
+}
+
+type MessageStore interface {
+	Create(ctx context.Context, message *models.Message) error
+	GetByID(ctx context.Context, id uuid.UUID) (*models.Message, error)
+	GetByUserID(ctx context.Context, userID uuid.UUID, limit, offset int) ([]*models.Message, error)
+	GetByContent(ctx context.Context, tenantID, content string, limit int) ([]*models.Message, error)
+	GetByPhoneNumber(ctx context.Context, tenantID, phoneNumber string, limit int) ([]*models.Message, error)
+	Search(ctx context.Context, search models.MessageSearch) ([]*models.MessageSearchResult, int, error)
+	Delete(ctx context.Context, id uuid.UUID) error
+	DeleteOldMessages(ctx context.Context, tenantID string, cutoff time.Time, batchSize int) (int64, error)
+	AnonymizeOldMessages(ctx context.Context, tenantID string, cutoff time.Time, batchSize int) (int64, error)
+	AnonymizeByUserID(ctx context.Context, userID uuid.UUID) (int64, error)
+	ReencryptBatch(ctx context.Context, afterID uuid.UUID, limit int) (int, uuid.UUID, error)
+	IndexSearchBatch(ctx context.Context, afterID uuid.UUID, limit int) (int, uuid.UUID, error)
+}
+
+type VerificationStore interface {
+	Create(ctx context.Context, verification *models.Verification) error
+	GetByID(ctx context.Context, id uuid.UUID) (*models.Verification, error)
+	GetByMessageID(ctx context.Context, messageID uuid.UUID) (*models.Verification, error)
+	GetByUserID(ctx context.Context, userID uuid.UUID, limit, offset int) ([]*models.Verification, error)
+	ListByUserID(ctx context.Context, userID uuid.UUID, filter models.VerificationFilter, opts models.ListOptions) ([]*models.Verification, *models.PageInfo, error)
+	GetStats(ctx context.Context, userID *uuid.UUID) (*models.VerificationStats, error)
+	GetModelComparison(ctx context.Context, since time.Time) ([]*models.ModelComparison, error)
+	SetShadowScore(ctx context.Context, id uuid.UUID, score models.ModelScore) error
+	DeleteOldVerifications(ctx context.Context, tenantID string, cutoff time.Time, batchSize int) (int64, error)
+	DetachUser(ctx context.Context, userID uuid.UUID) (int64, error)
+}
+
+type ReportStore interface {
+	Create(ctx context.Context, report *models.Report) error
+	GetByID(ctx context.Context, id uuid.UUID) (*models.Report, error)
+	GetByUserID(ctx context.Context, userID uuid.UUID, limit, offset int) ([]*models.Report, error)
+	ListByUserID(ctx context.Context, userID uuid.UUID, filter models.ReportFilter, opts models.ListOptions) ([]*models.Report, *models.PageInfo, error)
+	GetByStatus(ctx context.Context, status string, limit, offset int) ([]*models.Report, error)
+	GetLabeledBatch(ctx context.Context, afterID uuid.UUID, limit int) ([]*models.Report, error)
+	Update(ctx context.Context, report *models.Report) error
+	GetStats(ctx context.Context) (*models.ReportStats, error)
+	CountByContent(ctx context.Context, content string) (int, error)
+	AnonymizeByUserID(ctx context.Context, userID uuid.UUID) (int64, error)
+	ReencryptBatch(ctx context.Context, afterID uuid.UUID, limit int) (int, uuid.UUID, error)
+}
+
+type RBIStore interface {
+	CreateCircular(ctx context.Context, circular *models.RBICircular) error
+	GetCircularByID(ctx context.Context, id uuid.UUID) (*models.RBICircular, error)
+	GetActiveCirculars(ctx context.Context) ([]*models.RBICircular, error)
+	SearchCircularsByKeywords(ctx context.Context, keywords []string) ([]*models.RBICircular, error)
+	CreateSenderRegistry(ctx context.Context, sender *models.SenderRegistry) error
+	GetSenderBySenderID(ctx context.Context, senderID string) (*models.SenderRegistry, error)
+	UpdateSenderStats(ctx context.Context, senderID string, isFraud bool) (float64, *models.SenderRegistry, error)
+	GetVerifiedSenders(ctx context.Context) ([]*models.SenderRegistry, error)
+}
+
+type OutboxStore interface {
+	Add(ctx context.Context, kind string, payload interface{}) error
+	AddMessage(ctx context.Context, topic, key string, message *models.QueueMessage) error
+	TryLock(ctx context.Context, key int64) (bool, error)
+	GetPending(ctx context.Context, kind string, limit int) ([]*models.OutboxEntry, error)
+	MarkProcessed(ctx context.Context, id int64) error
+	MarkFailed(ctx context.Context, id int64, reason string) error
+	DeleteProcessedBefore(ctx context.Context, cutoff time.Time) (int64, error)
+	MarkAbandoned(ctx context.Context, id int64, reason string) error
+	GetStats(ctx context.Context) ([]*models.OutboxStats, error)
+}
+
+type AnalyticsStore interface {
+	RecordVerification(ctx context.Context, update models.VerificationRollupUpdate) error
+	GetTrend(ctx context.Context, filter models.AnalyticsFilter) ([]*models.TrendBucket, error)
+	GetTop(ctx context.Context, filter models.AnalyticsFilter, dimension string, limit int) ([]models.TopEntry, error)
+}
+
+type RetentionStore interface {
+	ListTenants(ctx context.Context) ([]string, error)
+	CreateRun(ctx context.Context, run *models.RetentionRun) error
+	FinishRun(ctx context.Context, run *models.RetentionRun) error
+}
+
+// Transactor runs functions against repositories that share a transaction.
+// Bind attaches them to a transaction the caller already owns; in-memory
+// implementations ignore tx.
+type Transactor interface {
+	Do(ctx context.Context, fn func(repos *TxRepositories) error) error
+	Bind(tx *sql.Tx) *TxRepositories
+}
+
+var (
+	_ UserStore          = (*UserRepository)(nil)
+	_ PasswordResetStore = (*PasswordResetRepository)(nil)
+	_ RecoveryCodeStore  = (*RecoveryCodeRepository)(nil)
+	_ MessageStore       = (*MessageRepository)(nil)
+	_ VerificationStore  = (*VerificationRepository)(nil)
+	_ ReportStore        = (*ReportRepository)(nil)
+	_ RBIStore           = (*RBIRepository)(nil)
+	_ OutboxStore        = (*OutboxRepository)(nil)
+	_ AnalyticsStore     = (*AnalyticsRepository)(nil)
+	_ RetentionStore     = (*RetentionRepository)(nil)
+	_ Transactor         = (*UnitOfWork)(nil)
+)
@@ -0,0 +1,132 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/fraud-detection-system/backend/internal/models"
+)
+
+type rollupKey struct {
+	tenantID    string
+	messageType string
+	bucketStart time.Time
+}
+
+type rollup struct {
+	verifications int64
+	fraud         int64
+	riskLevels    map[string]int64
+}
+
+type dimensionKey struct {
+	rollupKey
+	dimension string
+	value     string
+}
+
+type AnalyticsRepository struct {
+	db *DB
+}
+
+// RecordVerification adds a verification to its hourly rollup and, if it
+// was fraud, to the hourly top list rollups
+func (r *AnalyticsRepository) RecordVerification(ctx context.Context, update models.VerificationRollupUpdate) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	key := rollupKey{update.TenantID, update.MessageType, models.BucketStart(update.VerifiedAt, models.GranularityHour)}
+	row := r.db.t.rollups[key]
+	riskLevels := make(map[string]int64, len(row.riskLevels)+1)
+	for level, n := range row.riskLevels {
+		riskLevels[level] = n
+	}
+	level := update.RiskLevel
+	if level != models.RiskLevelMedium && level != models.RiskLevelHigh && level != models.RiskLevelCritical {
+		level = models.RiskLevelLow
+	}
+	riskLevels[level]++
+	row.riskLevels = riskLevels
+	row.verifications++
+	if update.IsFraud {
+		row.fraud++
+	}
+	r.db.t.rollups[key] = row
+
+	for dimension, values := range update.Dimensions() {
+		for _, value := range values {
+			r.db.t.dimensionRollups[dimensionKey{key, dimension, value}]++
+		}
+	}
+	return nil
+}
+
+// GetTrend returns the non-empty buckets of filter's time series, oldest
+// first
+func (r *AnalyticsRepository) GetTrend(ctx context.Context, filter models.AnalyticsFilter) ([]*models.TrendBucket, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	byStart := make(map[time.Time]*models.TrendBucket)
+	for key, row := range r.db.t.rollups {
+		if !matchesAnalytics(key, filter) {
+			continue
+		}
+		start := models.BucketStart(key.bucketStart, filter.Granularity)
+		bucket, ok := byStart[start]
+		if !ok {
+			bucket = &models.TrendBucket{Start: start, RiskLevels: map[string]int64{
+				models.RiskLevelLow:      0,
+				models.RiskLevelMedium:   0,
+				models.RiskLevelHigh:     0,
+				models.RiskLevelCritical: 0,
+			}}
+			byStart[start] = bucket
+		}
+		bucket.Verifications += row.verifications
+		bucket.Fraud += row.fraud
+		for level, n := range row.riskLevels {
+			bucket.RiskLevels[level] += n
+		}
+	}
+
+	buckets := make([]*models.TrendBucket, 0, len(byStart))
+	for _, bucket := range byStart {
+		buckets = append(buckets, bucket)
+	}
+	sort.Slice(buckets, func(i, j int) bool { return buckets[i].Start.Before(buckets[j].Start) })
+	return buckets, nil
+}
+
+// GetTop returns the limit values of dimension with the most fraud in
+// filter's range, most first
+func (r *AnalyticsRepository) GetTop(ctx context.Context, filter models.AnalyticsFilter, dimension string, limit int) ([]models.TopEntry, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	counts := make(map[string]int64)
+	for key, n := range r.db.t.dimensionRollups {
+		if key.dimension == dimension && matchesAnalytics(key.rollupKey, filter) {
+			counts[key.value] += n
+		}
+	}
+
+	entries := make([]models.TopEntry, 0, len(counts))
+	for value, n := range counts {
+		entries = append(entries, models.TopEntry{Value: value, Count: n})
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].Count != entries[j].Count {
+			return entries[i].Count > entries[j].Count
+		}
+		return entries[i].Value < entries[j].Value
+	})
+	return page(entries, limit, 0), nil
+}
+
+func matchesAnalytics(key rollupKey, filter models.AnalyticsFilter) bool {
+	return !key.bucketStart.Before(filter.From) && key.bucketStart.Before(filter.To) &&
+		(filter.TenantID == "" || key.tenantID == filter.TenantID) &&
+		(filter.MessageType == "" || key.messageType == filter.MessageType)
+}
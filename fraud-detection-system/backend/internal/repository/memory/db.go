@@ -0,0 +1,286 @@
+// Package memory implements the repository Store interfaces in memory, for
+// tests that exercise services and handlers without Postgres.
+//
+// All repositories created from one DB share its tables, so cross-table
+// behaviour such as retention only deleting messages without verifications
+// matches the Postgres repositories. Everything is safe for concurrent use.
+package memory
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/fraud-detection-system/backend/internal/models"
+	"github.com/fraud-detection-system/backend/internal/repository"
+)
+
+type recoveryCode struct {
+	userID   uuid.UUID
+	codeHash string
+	usedAt   *time.Time
+}
+
+type tables struct {
+	users            map[uuid.UUID]models.User
+	resetTokens      map[uuid.UUID]models.PasswordResetToken
+	recoveryCodes    []recoveryCode
+	messages         map[uuid.UUID]models.Message
+	verifications    map[uuid.UUID]models.Verification
+	reports          map[uuid.UUID]models.Report
+	circulars        map[uuid.UUID]models.RBICircular
+	senders          map[string]models.SenderRegistry
+	outbox           []models.OutboxEntry
+	outboxSeq        int64
+	retentionRuns    map[uuid.UUID]models.RetentionRun
+	rollups          map[rollupKey]rollup
+	dimensionRollups map[dimensionKey]int64
+}
+
+func newTables() tables {
+	return tables{
+		users:            make(map[uuid.UUID]models.User),
+		resetTokens:      make(map[uuid.UUID]models.PasswordResetToken),
+		messages:         make(map[uuid.UUID]models.Message),
+		verifications:    make(map[uuid.UUID]models.Verification),
+		reports:          make(map[uuid.UUID]models.Report),
+		circulars:        make(map[uuid.UUID]models.RBICircular),
+		senders:          make(map[string]models.SenderRegistry),
+		retentionRuns:    make(map[uuid.UUID]models.RetentionRun),
+		rollups:          make(map[rollupKey]rollup),
+		dimensionRollups: make(map[dimensionKey]int64),
+	}
+}
+
+// clone copies every table so it can be restored on rollback
+func (t tables) clone() tables {
+	c := tables{
+		users:            make(map[uuid.UUID]models.User, len(t.users)),
+		resetTokens:      make(map[uuid.UUID]models.PasswordResetToken, len(t.resetTokens)),
+		recoveryCodes:    append([]recoveryCode(nil), t.recoveryCodes...),
+		messages:         make(map[uuid.UUID]models.Message, len(t.messages)),
+		verifications:    make(map[uuid.UUID]models.Verification, len(t.verifications)),
+		reports:          make(map[uuid.UUID]models.Report, len(t.reports)),
+		circulars:        make(map[uuid.UUID]models.RBICircular, len(t.circulars)),
+		senders:          make(map[string]models.SenderRegistry, len(t.senders)),
+		outbox:           append([]models.OutboxEntry(nil), t.outbox...),
+		outboxSeq:        t.outboxSeq,
+		retentionRuns:    make(map[uuid.UUID]models.RetentionRun, len(t.retentionRuns)),
+		rollups:          make(map[rollupKey]rollup, len(t.rollups)),
+		dimensionRollups: make(map[dimensionKey]int64, len(t.dimensionRollups)),
+	}
+	for k, v := range t.users {
+		c.users[k] = v
+	}
+	for k, v := range t.resetTokens {
+		c.resetTokens[k] = v
+	}
+	for k, v := range t.messages {
+		c.messages[k] = v
+	}
+	for k, v := range t.verifications {
+		c.verifications[k] = v
+	}
+	for k, v := range t.reports {
+		c.reports[k] = v
+	}
+	for k, v := range t.circulars {
+		c.circulars[k] = v
+	}
+	for k, v := range t.senders {
+		c.senders[k] = v
+	}
+	for k, v := range t.retentionRuns {
+		c.retentionRuns[k] = v
+	}
+	// Rollup risk level maps are copied on write, so sharing them is safe
+	for k, v := range t.rollups {
+		c.rollups[k] = v
+	}
+	for k, v := range t.dimensionRollups {
+		c.dimensionRollups[k] = v
+	}
+	return c
+}
+
+// DB holds the tables shared by the in-memory repositories
+type DB struct {
+	mu   sync.Mutex
+	txMu sync.Mutex
+	t    tables
+}
+
+// New creates an empty database
+func New() *DB {
+	return &DB{t: newTables()}
+}
+
+func (db *DB) Users() *UserRepository                   { return &UserRepository{db: db} }
+func (db *DB) PasswordResets() *PasswordResetRepository { return &PasswordResetRepository{db: db} }
+func (db *DB) RecoveryCodes() *RecoveryCodeRepository   { return &RecoveryCodeRepository{db: db} }
+func (db *DB) Messages() *MessageRepository             { return &MessageRepository{db: db} }
+func (db *DB) Verifications() *VerificationRepository   { return &VerificationRepository{db: db} }
+func (db *DB) Reports() *ReportRepository               { return &ReportRepository{db: db} }
+func (db *DB) RBI() *RBIRepository                      { return &RBIRepository{db: db} }
+func (db *DB) Outbox() *OutboxRepository                { return &OutboxRepository{db: db} }
+func (db *DB) Retention() *RetentionRepository          { return &RetentionRepository{db: db} }
+func (db *DB) Analytics() *AnalyticsRepository          { return &AnalyticsRepository{db: db} }
+
+// UnitOfWork returns a Transactor over this database's repositories
+func (db *DB) UnitOfWork() *UnitOfWork {
+	return &UnitOfWork{db: db}
+}
+
+// UnitOfWork runs functions against the in-memory repositories, restoring
+// every table if the function fails. Units of work run one at a time; writes
+// made outside one while it runs are lost if it rolls back.
+type UnitOfWork struct {
+	db *DB
+}
+
+// Do runs fn, rolling back its writes if it returns an error
+func (u *UnitOfWork) Do(ctx context.Context, fn func(repos *repository.TxRepositories) error) error {
+	u.db.txMu.Lock()
+	defer u.db.txMu.Unlock()
+
+	u.db.mu.Lock()
+	snapshot := u.db.t.clone()
+	u.db.mu.Unlock()
+
+	if err := fn(u.Bind(nil)); err != nil {
+		u.db.mu.Lock()
+		u.db.t = snapshot
+		u.db.mu.Unlock()
+		return err
+	}
+	return nil
+}
+
+// Bind returns the repositories. tx is ignored.
+func (u *UnitOfWork) Bind(tx *sql.Tx) *repository.TxRepositories {
+	return &repository.TxRepositories{
+		Users:         u.db.Users(),
+		Messages:      u.db.Messages(),
+		Verifications: u.db.Verifications(),
+		Reports:       u.db.Reports(),
+		RBI:           u.db.RBI(),
+		Outbox:        u.db.Outbox(),
+		Analytics:     u.db.Analytics(),
+	}
+}
+
+// page applies limit and offset to a sorted result
+func page[T any](items []T, limit, offset int) []T {
+	if offset >= len(items) {
+		return nil
+	}
+	items = items[offset:]
+	if limit >= 0 && limit < len(items) {
+		items = items[:limit]
+	}
+	return items
+}
+
+// sortNewestFirst orders items by created time, newest first, as the
+// Postgres repositories do with ORDER BY created_at DESC
+func sortNewestFirst[T any](items []*T, createdAt func(*T) time.Time) {
+	sort.SliceStable(items, func(i, j int) bool {
+		return createdAt(items[i]).After(createdAt(items[j]))
+	})
+}
+
+var (
+	_ repository.UserStore          = (*UserRepository)(nil)
+	_ repository.PasswordResetStore = (*PasswordResetRepository)(nil)
+	_ repository.RecoveryCodeStore  = (*RecoveryCodeRepository)(nil)
+	_ repository.MessageStore       = (*MessageRepository)(nil)
+	_ repository.VerificationStore  = (*VerificationRepository)(nil)
+	_ repository.ReportStore        = (*ReportRepository)(nil)
+	_ repository.RBIStore           = (*RBIRepository)(nil)
+	_ repository.OutboxStore        = (*OutboxRepository)(nil)
+	_ repository.RetentionStore     = (*RetentionRepository)(nil)
+	_ repository.AnalyticsStore     = (*AnalyticsRepository)(nil)
+	_ repository.Transactor         = (*UnitOfWork)(nil)
+)
+
+// keysetPage orders items by key then ID, skips to just past the cursor and
+// cuts a page, as the Postgres ListByUserID queries do. opts must already be
+// resolved; numeric says whether the sort key is a float64 or a time.Time.
+func keysetPage[T any](items []*T, opts models.ListOptions, numeric bool, key func(*T) interface{}, id func(*T) uuid.UUID) ([]*T, *models.PageInfo, error) {
+	cursor, err := repository.DecodeCursor(opts)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	less := func(a, b *T) bool {
+		if c := compareKeys(key(a), key(b)); c != 0 {
+			return c < 0
+		}
+		idA, idB := id(a), id(b)
+		return bytes.Compare(idA[:], idB[:]) < 0
+	}
+	sort.SliceStable(items, func(i, j int) bool {
+		if opts.Desc {
+			return less(items[j], items[i])
+		}
+		return less(items[i], items[j])
+	})
+
+	page := &models.PageInfo{Limit: opts.Limit, Total: len(items)}
+	if cursor != nil {
+		var value interface{}
+		if numeric {
+			value, err = cursor.Float()
+		} else {
+			value, err = cursor.Time()
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		start := len(items)
+		for i, item := range items {
+			c := compareKeys(key(item), value)
+			if c == 0 {
+				itemID := id(item)
+				c = bytes.Compare(itemID[:], cursor.ID[:])
+			}
+			if (opts.Desc && c < 0) || (!opts.Desc && c > 0) {
+				start = i
+				break
+			}
+		}
+		items = items[start:]
+	}
+
+	if len(items) > opts.Limit {
+		items = items[:opts.Limit]
+		last := items[len(items)-1]
+		page.HasMore = true
+		page.NextCursor = repository.EncodeCursor(opts, key(last), id(last))
+	}
+	page.Count = len(items)
+	return items, page, nil
+}
+
+// compareKeys compares two sort key values of the same type
+func compareKeys(a, b interface{}) int {
+	switch a := a.(type) {
+	case float64:
+		b := b.(float64)
+		switch {
+		case a < b:
+			return -1
+		case a > b:
+			return 1
+		}
+		return 0
+	case time.Time:
+		return a.Compare(b.(time.Time))
+	}
+	panic(fmt.Sprintf("unsupported sort key %T", a))
+}
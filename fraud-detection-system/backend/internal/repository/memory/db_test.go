@@ -0,0 +1,106 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/fraud-detection-system/backend/internal/models"
+	"github.com/fraud-detection-system/backend/internal/repository"
+)
+
+func TestUnitOfWorkRollback(t *testing.T) {
+	ctx := context.Background()
+	db := New()
+	user := &models.User{ID: uuid.New(), Email: "a@example.com", CreatedAt: time.Now()}
+	if err := db.Users().Create(ctx, user); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	failure := errors.New("boom")
+	err := db.UnitOfWork().Do(ctx, func(repos *repository.TxRepositories) error {
+		if err := repos.Users.Delete(ctx, user.ID); err != nil {
+			return err
+		}
+		if err := repos.Outbox.Add(ctx, "test", map[string]string{"a": "b"}); err != nil {
+			return err
+		}
+		return failure
+	})
+	if !errors.Is(err, failure) {
+		t.Fatalf("expected fn error, got %v", err)
+	}
+	if _, err := db.Users().GetByID(ctx, user.ID); err != nil {
+		t.Errorf("expected delete to be rolled back: %v", err)
+	}
+	if pending, _ := db.Outbox().GetPending(ctx, "test", 10); len(pending) != 0 {
+		t.Errorf("expected outbox entry to be rolled back, got %d", len(pending))
+	}
+
+	err = db.UnitOfWork().Do(ctx, func(repos *repository.TxRepositories) error {
+		return repos.Users.Delete(ctx, user.ID)
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := db.Users().GetByID(ctx, user.ID); err == nil {
+		t.Error("expected delete to be committed")
+	}
+}
+
+func TestRetentionOnlyDeletesUnverifiedMessages(t *testing.T) {
+	ctx := context.Background()
+	db := New()
+	old := time.Now().Add(-48 * time.Hour)
+
+	verified := &models.Message{ID: uuid.New(), Content: "kept", CreatedAt: old}
+	unverified := &models.Message{ID: uuid.New(), Content: "dropped", CreatedAt: old}
+	for _, m := range []*models.Message{verified, unverified} {
+		if err := db.Messages().Create(ctx, m); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if err := db.Verifications().Create(ctx, &models.Verification{ID: uuid.New(), MessageID: verified.ID, IsFraud: true, CreatedAt: old}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	cutoff := time.Now().Add(-time.Hour)
+	deleted, _ := db.Messages().DeleteOldMessages(ctx, models.DefaultTenantID, cutoff, 10)
+	anonymized, _ := db.Messages().AnonymizeOldMessages(ctx, models.DefaultTenantID, cutoff, 10)
+	if deleted != 1 || anonymized != 1 {
+		t.Fatalf("expected 1 deleted and 1 anonymized, got %d and %d", deleted, anonymized)
+	}
+	if m, err := db.Messages().GetByID(ctx, verified.ID); err != nil || m.Content != "[redacted]" {
+		t.Errorf("expected verified message to be redacted, got %+v, %v", m, err)
+	}
+}
+
+func TestReencryptionSkipsDeletedAccounts(t *testing.T) {
+	ctx := context.Background()
+	db := New()
+	deletedUser, otherUser := uuid.New(), uuid.New()
+
+	kept := &models.Message{ID: uuid.New(), UserID: &otherUser, Content: "kept"}
+	for _, m := range []*models.Message{
+		{ID: uuid.New(), UserID: &deletedUser, Content: "first"},
+		{ID: uuid.New(), UserID: &deletedUser, Content: "second"},
+		kept,
+	} {
+		if err := db.Messages().Create(ctx, m); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	if n, err := db.Messages().AnonymizeByUserID(ctx, deletedUser); err != nil || n != 2 {
+		t.Fatalf("expected 2 anonymized messages, got %d, %v", n, err)
+	}
+	n, lastID, err := db.Messages().ReencryptBatch(ctx, uuid.Nil, 10)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if n != 1 || lastID != kept.ID {
+		t.Errorf("expected only %s to be re-encrypted, got %d ending at %s", kept.ID, n, lastID)
+	}
+}
@@ -0,0 +1,578 @@
+package memory
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/fraud-detection-system/backend/internal/models"
+	"github.com/fraud-detection-system/backend/internal/repository"
+	"github.com/fraud-detection-system/backend/internal/utils"
+)
+
+// searchRedactor strips PII from content before it is searched, as the
+// Postgres repository does when building search vectors
+var searchRedactor = utils.NewRedactor(utils.RedactionRemove, nil)
+
+// MessageRepository stores messages in plaintext; there is nothing to
+// protect at rest in a test process.
+type MessageRepository struct {
+	db *DB
+}
+
+// Create creates a new message
+func (r *MessageRepository) Create(ctx context.Context, message *models.Message) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if _, ok := r.db.t.messages[message.ID]; ok {
+		return fmt.Errorf("failed to create message: duplicate id %s", message.ID)
+	}
+	if message.TenantID == "" {
+		message.TenantID = models.DefaultTenantID
+	}
+	r.db.t.messages[message.ID] = *message
+	return nil
+}
+
+// GetByID retrieves a message by ID
+func (r *MessageRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Message, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	message, ok := r.db.t.messages[id]
+	if !ok {
+		return nil, fmt.Errorf("message not found")
+	}
+	return &message, nil
+}
+
+// find returns the messages matching keep, newest first. The caller holds mu.
+func (r *MessageRepository) find(keep func(m *models.Message) bool) []*models.Message {
+	var messages []*models.Message
+	for _, message := range r.db.t.messages {
+		message := message
+		if keep(&message) {
+			messages = append(messages, &message)
+		}
+	}
+	sortNewestFirst(messages, func(m *models.Message) time.Time { return m.CreatedAt })
+	return messages
+}
+
+// GetByUserID retrieves messages by user ID
+func (r *MessageRepository) GetByUserID(ctx context.Context, userID uuid.UUID, limit, offset int) ([]*models.Message, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	messages := r.find(func(m *models.Message) bool {
+		return m.UserID != nil && *m.UserID == userID
+	})
+	return page(messages, limit, offset), nil
+}
+
+// GetByContent retrieves a tenant's messages with exactly the given content
+func (r *MessageRepository) GetByContent(ctx context.Context, tenantID, content string, limit int) ([]*models.Message, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	messages := r.find(func(m *models.Message) bool {
+		return m.TenantID == tenantID && m.AnonymizedAt == nil && m.Content == content
+	})
+	return page(messages, limit, 0), nil
+}
+
+// GetByPhoneNumber retrieves a tenant's messages that mention the given phone number
+func (r *MessageRepository) GetByPhoneNumber(ctx context.Context, tenantID, phoneNumber string, limit int) ([]*models.Message, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	messages := r.find(func(m *models.Message) bool {
+		return m.TenantID == tenantID && m.PhoneNumber != nil && *m.PhoneNumber == phoneNumber
+	})
+	return page(messages, limit, 0), nil
+}
+
+// Search returns a page of a tenant's messages matching search, matching
+// content and URLs and sender headers the way the Postgres repository does.
+// Rank is the number of content matches.
+func (r *MessageRepository) Search(ctx context.Context, search models.MessageSearch) ([]*models.MessageSearchResult, int, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	terms, err := utils.ParseSearchQuery(search.Query)
+	if err != nil {
+		return nil, 0, err
+	}
+	substring := strings.ToLower(utils.SearchSubstring(search.Query))
+	var results []*models.MessageSearchResult
+	for _, message := range r.find(func(m *models.Message) bool { return search.TenantID == "" || m.TenantID == search.TenantID }) {
+		if message.AnonymizedAt != nil || !search.Created.Contains(message.CreatedAt) ||
+			(search.MessageType != "" && message.MessageType != search.MessageType) ||
+			(search.SenderHeader != "" && message.SenderHeader != search.SenderHeader) {
+			continue
+		}
+
+		result := &models.MessageSearchResult{Message: *message}
+		for _, verification := range r.db.t.verifications {
+			if verification.MessageID == message.ID {
+				verification := verification
+				result.Verification = &verification
+				break
+			}
+		}
+		if (search.IsFraud != nil || search.FraudType != "") && result.Verification == nil {
+			continue
+		}
+		if search.IsFraud != nil && result.Verification.IsFraud != *search.IsFraud {
+			continue
+		}
+		if search.FraudType != "" && (result.Verification.FraudType == nil || *result.Verification.FraudType != search.FraudType) {
+			continue
+		}
+
+		matched := false
+		if search.HasField(models.SearchFieldContent) && message.Content != "[deleted]" {
+			if document := searchRedactor.String(message.Content); terms.Matches(document) {
+				matched = true
+				result.Rank = float64(len(terms.Highlight(document, math.MaxInt)))
+			}
+		}
+		if substring != "" && search.HasField(models.SearchFieldURL) {
+			for _, url := range message.ExtractedURLs {
+				matched = matched || strings.Contains(strings.ToLower(url), substring)
+			}
+		}
+		if substring != "" && search.HasField(models.SearchFieldSender) {
+			matched = matched || strings.Contains(strings.ToLower(message.SenderHeader), substring)
+		}
+		if search.HasField(models.SearchFieldPhone) {
+			if message.PhoneNumber != nil && *message.PhoneNumber == search.Query {
+				matched = true
+			}
+			if phone := utils.NormalizePhoneNumber(search.Query); phone != "" && message.Content != "[deleted]" {
+				mentioned := utils.FindPhoneNumbers(message.Content)
+				if message.PhoneNumber != nil {
+					mentioned = append(mentioned, utils.NormalizePhoneNumber(*message.PhoneNumber))
+				}
+				for _, p := range mentioned {
+					matched = matched || p == phone
+				}
+			}
+		}
+		if matched {
+			results = append(results, result)
+		}
+	}
+
+	sort.SliceStable(results, func(i, j int) bool { return results[i].Rank > results[j].Rank })
+	return page(results, search.Limit, search.Offset), len(results), nil
+}
+
+// Delete deletes a message
+func (r *MessageRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+	delete(r.db.t.messages, id)
+	return nil
+}
+
+// hasVerification reports whether a verification references the message.
+// The caller holds mu.
+func (r *MessageRepository) hasVerification(id uuid.UUID) bool {
+	for _, verification := range r.db.t.verifications {
+		if verification.MessageID == id {
+			return true
+		}
+	}
+	return false
+}
+
+// oldest returns up to limit of a tenant's messages created before cutoff
+// that match keep, oldest first. The caller holds mu.
+func (r *MessageRepository) oldest(tenantID string, cutoff time.Time, limit int, keep func(m *models.Message) bool) []*models.Message {
+	messages := r.find(func(m *models.Message) bool {
+		return m.TenantID == tenantID && m.CreatedAt.Before(cutoff) && keep(m)
+	})
+	sort.SliceStable(messages, func(i, j int) bool { return messages[i].CreatedAt.Before(messages[j].CreatedAt) })
+	return page(messages, limit, 0)
+}
+
+// DeleteOldMessages deletes up to batchSize of a tenant's messages created
+// before cutoff that no longer have a verification attached
+func (r *MessageRepository) DeleteOldMessages(ctx context.Context, tenantID string, cutoff time.Time, batchSize int) (int64, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	messages := r.oldest(tenantID, cutoff, batchSize, func(m *models.Message) bool {
+		return !r.hasVerification(m.ID)
+	})
+	for _, message := range messages {
+		delete(r.db.t.messages, message.ID)
+	}
+	return int64(len(messages)), nil
+}
+
+// AnonymizeOldMessages strips content and personal data from up to batchSize
+// of a tenant's messages created before cutoff whose verifications are still
+// retained
+func (r *MessageRepository) AnonymizeOldMessages(ctx context.Context, tenantID string, cutoff time.Time, batchSize int) (int64, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	messages := r.oldest(tenantID, cutoff, batchSize, func(m *models.Message) bool {
+		return m.AnonymizedAt == nil && r.hasVerification(m.ID)
+	})
+	now := time.Now()
+	for _, message := range messages {
+		message.Content = "[redacted]"
+		message.PhoneNumber = nil
+		message.PhoneNumberHash = nil
+		message.ExtractedURLs = nil
+		message.EncryptionKeyID = nil
+		message.AnonymizedAt = &now
+		message.UpdatedAt = now
+		r.db.t.messages[message.ID] = *message
+	}
+	return int64(len(messages)), nil
+}
+
+// AnonymizeByUserID strips content and personal data from a user's messages
+// and detaches them from the user
+func (r *MessageRepository) AnonymizeByUserID(ctx context.Context, userID uuid.UUID) (int64, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	messages := r.find(func(m *models.Message) bool {
+		return m.UserID != nil && *m.UserID == userID
+	})
+	now := time.Now()
+	for _, message := range messages {
+		message.Content = "[deleted]"
+		message.PhoneNumber = nil
+		message.PhoneNumberHash = nil
+		message.ExtractedURLs = nil
+		message.EncryptionKeyID = nil
+		message.UserID = nil
+		message.AnonymizedAt = &now
+		message.UpdatedAt = now
+		r.db.t.messages[message.ID] = *message
+	}
+	return int64(len(messages)), nil
+}
+
+// ReencryptBatch pages through the messages the Postgres repository would
+// re-encrypt, those with id greater than afterID that are not anonymized,
+// leaving them in plaintext
+func (r *MessageRepository) ReencryptBatch(ctx context.Context, afterID uuid.UUID, limit int) (int, uuid.UUID, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	messages := r.find(func(m *models.Message) bool {
+		return bytes.Compare(m.ID[:], afterID[:]) > 0 && m.AnonymizedAt == nil
+	})
+	sort.Slice(messages, func(i, j int) bool { return bytes.Compare(messages[i].ID[:], messages[j].ID[:]) < 0 })
+	messages = page(messages, limit, 0)
+	if len(messages) > 0 {
+		afterID = messages[len(messages)-1].ID
+	}
+	return len(messages), afterID, nil
+}
+
+// IndexSearchBatch has nothing to index, as Search reads content directly,
+// and reports an empty batch
+func (r *MessageRepository) IndexSearchBatch(ctx context.Context, afterID uuid.UUID, limit int) (int, uuid.UUID, error) {
+	return 0, afterID, nil
+}
+
+type VerificationRepository struct {
+	db *DB
+}
+
+// Create creates a new verification record
+func (r *VerificationRepository) Create(ctx context.Context, verification *models.Verification) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if _, ok := r.db.t.verifications[verification.ID]; ok {
+		return fmt.Errorf("failed to create verification: duplicate id %s", verification.ID)
+	}
+	if verification.TenantID == "" {
+		verification.TenantID = models.DefaultTenantID
+	}
+	r.db.t.verifications[verification.ID] = *verification
+	return nil
+}
+
+// GetByID retrieves a verification by ID
+func (r *VerificationRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Verification, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	verification, ok := r.db.t.verifications[id]
+	if !ok {
+		return nil, fmt.Errorf("verification not found")
+	}
+	return &verification, nil
+}
+
+// GetByMessageID retrieves a verification by message ID
+func (r *VerificationRepository) GetByMessageID(ctx context.Context, messageID uuid.UUID) (*models.Verification, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	for _, verification := range r.db.t.verifications {
+		if verification.MessageID == messageID {
+			return &verification, nil
+		}
+	}
+	return nil, fmt.Errorf("verification not found")
+}
+
+// find returns the verifications matching keep, newest first. The caller holds mu.
+func (r *VerificationRepository) find(keep func(v *models.Verification) bool) []*models.Verification {
+	var verifications []*models.Verification
+	for _, verification := range r.db.t.verifications {
+		verification := verification
+		if keep(&verification) {
+			verifications = append(verifications, &verification)
+		}
+	}
+	sortNewestFirst(verifications, func(v *models.Verification) time.Time { return v.CreatedAt })
+	return verifications
+}
+
+// GetByUserID retrieves verifications by user ID
+func (r *VerificationRepository) GetByUserID(ctx context.Context, userID uuid.UUID, limit, offset int) ([]*models.Verification, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	verifications := r.find(func(v *models.Verification) bool {
+		return v.UserID != nil && *v.UserID == userID
+	})
+	return page(verifications, limit, offset), nil
+}
+
+// ListByUserID returns a page of a user's verifications matching filter in
+// keyset order of opts.Sort then ID, along with the total number of matches
+func (r *VerificationRepository) ListByUserID(ctx context.Context, userID uuid.UUID, filter models.VerificationFilter, opts models.ListOptions) ([]*models.Verification, *models.PageInfo, error) {
+	opts, err := repository.ResolveListOptions(opts, models.VerificationSortCreatedAt, models.VerificationSortFraudScore)
+	if err != nil {
+		return nil, nil, err
+	}
+	minScore, maxScore := 0.0, 0.0
+	if filter.RiskLevel != "" {
+		var ok bool
+		if minScore, maxScore, ok = models.RiskScoreRange(filter.RiskLevel); !ok {
+			return nil, nil, fmt.Errorf("unknown risk level %q", filter.RiskLevel)
+		}
+	}
+
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	verifications := r.find(func(v *models.Verification) bool {
+		if v.UserID == nil || *v.UserID != userID || !filter.Created.Contains(v.CreatedAt) {
+			return false
+		}
+		if filter.IsFraud != nil && v.IsFraud != *filter.IsFraud {
+			return false
+		}
+		if filter.RiskLevel != "" && (v.FraudScore < minScore || (maxScore > 0 && v.FraudScore >= maxScore)) {
+			return false
+		}
+		if filter.FraudType != "" && (v.FraudType == nil || *v.FraudType != filter.FraudType) {
+			return false
+		}
+		if filter.SenderHeader != "" || filter.MessageType != "" {
+			message, ok := r.db.t.messages[v.MessageID]
+			if !ok {
+				return false
+			}
+			if filter.SenderHeader != "" && message.SenderHeader != filter.SenderHeader {
+				return false
+			}
+			if filter.MessageType != "" && message.MessageType != filter.MessageType {
+				return false
+			}
+		}
+		return true
+	})
+	return keysetPage(verifications, opts, opts.Sort == models.VerificationSortFraudScore,
+		func(v *models.Verification) interface{} { return repository.VerificationSortValue(v, opts.Sort) },
+		func(v *models.Verification) uuid.UUID { return v.ID })
+}
+
+// GetStats retrieves verification statistics
+func (r *VerificationRepository) GetStats(ctx context.Context, userID *uuid.UUID) (*models.VerificationStats, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	verifications := r.find(func(v *models.Verification) bool {
+		return userID == nil || (v.UserID != nil && *v.UserID == *userID)
+	})
+
+	var stats models.VerificationStats
+	var scoreSum, timeSum float64
+	now := time.Now()
+	for _, v := range verifications {
+		stats.TotalVerifications++
+		if v.IsFraud {
+			stats.FraudDetected++
+		}
+		scoreSum += v.FraudScore
+		timeSum += float64(v.ProcessingTimeMs)
+		if !v.CreatedAt.Before(now.Add(-24 * time.Hour)) {
+			stats.Last24Hours++
+		}
+		if !v.CreatedAt.Before(now.Add(-7 * 24 * time.Hour)) {
+			stats.Last7Days++
+		}
+	}
+	if stats.TotalVerifications > 0 {
+		total := float64(stats.TotalVerifications)
+		stats.AvgFraudScore = scoreSum / total
+		stats.AvgProcessingTime = timeSum / total
+		stats.FraudRate = float64(stats.FraudDetected) / total
+	}
+	return &stats, nil
+}
+
+// routedPrediction is the part of ml_predictions GetModelComparison reads
+type routedPrediction struct {
+	Routing struct {
+		Primary *struct {
+			Model      string  `json:"model"`
+			IsFraud    bool    `json:"is_fraud"`
+			FraudScore float64 `json:"fraud_score"`
+		} `json:"primary"`
+		Shadow *struct {
+			Model      string  `json:"model"`
+			IsFraud    bool    `json:"is_fraud"`
+			FraudScore float64 `json:"fraud_score"`
+		} `json:"shadow"`
+	} `json:"routing"`
+}
+
+// GetModelComparison compares the deciding model with the shadow model over
+// verifications created since the given time, grouped by model pair
+func (r *VerificationRepository) GetModelComparison(ctx context.Context, since time.Time) ([]*models.ModelComparison, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	type pair struct{ primary, shadow string }
+	groups := make(map[pair]*models.ModelComparison)
+	for _, v := range r.db.t.verifications {
+		if v.CreatedAt.Before(since) || v.SourceVerificationID != nil {
+			continue
+		}
+		var p routedPrediction
+		if err := json.Unmarshal([]byte(v.MLPredictions), &p); err != nil || p.Routing.Primary == nil || p.Routing.Shadow == nil {
+			continue
+		}
+		primary, shadow := p.Routing.Primary, p.Routing.Shadow
+		key := pair{primary.Model, shadow.Model}
+		c, ok := groups[key]
+		if !ok {
+			c = &models.ModelComparison{PrimaryModel: key.primary, ShadowModel: key.shadow}
+			groups[key] = c
+		}
+		delta := shadow.FraudScore - primary.FraudScore
+		c.Total++
+		switch {
+		case primary.IsFraud == shadow.IsFraud:
+			c.Agreements++
+		case primary.IsFraud:
+			c.PrimaryOnlyFraud++
+		default:
+			c.ShadowOnlyFraud++
+		}
+		// Sums for now; divided into averages below
+		c.AvgScoreDelta += delta
+		c.AvgAbsScoreDelta += math.Abs(delta)
+		c.MaxAbsScoreDelta = math.Max(c.MaxAbsScoreDelta, math.Abs(delta))
+	}
+
+	var comparisons []*models.ModelComparison
+	for _, c := range groups {
+		total := float64(c.Total)
+		c.AgreementRate = float64(c.Agreements) / total
+		c.AvgScoreDelta /= total
+		c.AvgAbsScoreDelta /= total
+		comparisons = append(comparisons, c)
+	}
+	sort.Slice(comparisons, func(i, j int) bool {
+		if comparisons[i].PrimaryModel != comparisons[j].PrimaryModel {
+			return comparisons[i].PrimaryModel < comparisons[j].PrimaryModel
+		}
+		return comparisons[i].ShadowModel < comparisons[j].ShadowModel
+	})
+	return comparisons, nil
+}
+
+// DeleteOldVerifications deletes up to batchSize of a tenant's non-fraud
+// verifications created before cutoff. Fraud verifications are kept for audit.
+func (r *VerificationRepository) DeleteOldVerifications(ctx context.Context, tenantID string, cutoff time.Time, batchSize int) (int64, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	verifications := r.find(func(v *models.Verification) bool {
+		return v.TenantID == tenantID && v.CreatedAt.Before(cutoff) && !v.IsFraud
+	})
+	verifications = page(verifications, batchSize, 0)
+	for _, verification := range verifications {
+		delete(r.db.t.verifications, verification.ID)
+	}
+	return int64(len(verifications)), nil
+}
+
+// SetShadowScore records the shadow model's prediction for a verification
+func (r *VerificationRepository) SetShadowScore(ctx context.Context, id uuid.UUID, score models.ModelScore) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	verification, ok := r.db.t.verifications[id]
+	if !ok {
+		return nil
+	}
+	var predictions map[string]interface{}
+	if err := json.Unmarshal([]byte(verification.MLPredictions), &predictions); err != nil {
+		return nil
+	}
+	routing, ok := predictions["routing"].(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	routing["shadow"] = score
+	encoded, err := json.Marshal(predictions)
+	if err != nil {
+		return fmt.Errorf("failed to encode shadow score: %w", err)
+	}
+	verification.MLPredictions = string(encoded)
+	verification.UpdatedAt = time.Now()
+	r.db.t.verifications[id] = verification
+	return nil
+}
+
+// DetachUser removes the user reference from a user's verifications
+func (r *VerificationRepository) DetachUser(ctx context.Context, userID uuid.UUID) (int64, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	verifications := r.find(func(v *models.Verification) bool {
+		return v.UserID != nil && *v.UserID == userID
+	})
+	now := time.Now()
+	for _, verification := range verifications {
+		verification.UserID = nil
+		verification.UpdatedAt = now
+		r.db.t.verifications[verification.ID] = *verification
+	}
+	return int64(len(verifications)), nil
+}
@@ -0,0 +1,210 @@
+package memory
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/fraud-detection-system/backend/internal/models"
+	"github.com/fraud-detection-system/backend/internal/tracing"
+	"github.com/fraud-detection-system/backend/internal/utils"
+)
+
+type OutboxRepository struct {
+	db *DB
+}
+
+// Add records an entry of the given kind with payload marshalled to JSON
+func (r *OutboxRepository) Add(ctx context.Context, kind string, payload interface{}) error {
+	return r.add(kind, nil, nil, payload)
+}
+
+// AddMessage records a message to be published to a Kafka topic, keeping
+// the trace context and request ID of ctx with it
+func (r *OutboxRepository) AddMessage(ctx context.Context, topic, key string, message *models.QueueMessage) error {
+	message.TraceContext = tracing.Inject(ctx)
+	message.RequestID = utils.RequestIDFromContext(ctx)
+	return r.add(models.OutboxKindKafka, &topic, &key, message)
+}
+
+func (r *OutboxRepository) add(kind string, topic, key *string, payload interface{}) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal outbox payload: %w", err)
+	}
+
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+	r.db.t.outboxSeq++
+	r.db.t.outbox = append(r.db.t.outbox, models.OutboxEntry{
+		ID:         r.db.t.outboxSeq,
+		Kind:       kind,
+		Topic:      topic,
+		MessageKey: key,
+		Payload:    string(data),
+		CreatedAt:  time.Now(),
+	})
+	return nil
+}
+
+// TryLock always succeeds; units of work already run one at a time
+func (r *OutboxRepository) TryLock(ctx context.Context, key int64) (bool, error) {
+	return true, nil
+}
+
+// GetPending returns up to limit unprocessed entries of kind, oldest first
+func (r *OutboxRepository) GetPending(ctx context.Context, kind string, limit int) ([]*models.OutboxEntry, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	var entries []*models.OutboxEntry
+	for _, entry := range r.db.t.outbox {
+		entry := entry
+		if entry.Kind == kind && entry.ProcessedAt == nil {
+			entries = append(entries, &entry)
+		}
+	}
+	return page(entries, limit, 0), nil
+}
+
+// update applies fn to the entry with the given id
+func (r *OutboxRepository) update(id int64, fn func(entry *models.OutboxEntry)) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	for i := range r.db.t.outbox {
+		if r.db.t.outbox[i].ID == id {
+			fn(&r.db.t.outbox[i])
+		}
+	}
+}
+
+// MarkProcessed records that an entry has been applied
+func (r *OutboxRepository) MarkProcessed(ctx context.Context, id int64) error {
+	now := time.Now()
+	r.update(id, func(entry *models.OutboxEntry) {
+		entry.ProcessedAt = &now
+		entry.Attempts++
+		entry.LastError = nil
+	})
+	return nil
+}
+
+// MarkFailed records a failed attempt, leaving the entry pending
+func (r *OutboxRepository) MarkFailed(ctx context.Context, id int64, reason string) error {
+	r.update(id, func(entry *models.OutboxEntry) {
+		entry.Attempts++
+		entry.LastError = &reason
+	})
+	return nil
+}
+
+// DeleteProcessedBefore removes entries processed before cutoff
+func (r *OutboxRepository) DeleteProcessedBefore(ctx context.Context, cutoff time.Time) (int64, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	kept := make([]models.OutboxEntry, 0, len(r.db.t.outbox))
+	for _, entry := range r.db.t.outbox {
+		if entry.ProcessedAt == nil || !entry.ProcessedAt.Before(cutoff) {
+			kept = append(kept, entry)
+		}
+	}
+	deleted := int64(len(r.db.t.outbox) - len(kept))
+	r.db.t.outbox = kept
+	return deleted, nil
+}
+
+// MarkAbandoned gives up on an entry, keeping the reason
+func (r *OutboxRepository) MarkAbandoned(ctx context.Context, id int64, reason string) error {
+	now := time.Now()
+	r.update(id, func(entry *models.OutboxEntry) {
+		entry.ProcessedAt = &now
+		entry.Attempts++
+		entry.LastError = &reason
+	})
+	return nil
+}
+
+// GetStats returns the backlog of each kind of entry
+func (r *OutboxRepository) GetStats(ctx context.Context) ([]*models.OutboxStats, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	byKind := make(map[string]*models.OutboxStats)
+	var stats []*models.OutboxStats
+	for _, entry := range r.db.t.outbox {
+		s, ok := byKind[entry.Kind]
+		if !ok {
+			s = &models.OutboxStats{Kind: entry.Kind}
+			byKind[entry.Kind] = s
+			stats = append(stats, s)
+		}
+		if entry.ProcessedAt == nil {
+			s.Pending++
+			if s.OldestPendingAt == nil || entry.CreatedAt.Before(*s.OldestPendingAt) {
+				createdAt := entry.CreatedAt
+				s.OldestPendingAt = &createdAt
+			}
+			continue
+		}
+		if entry.LastError != nil {
+			s.Abandoned++
+		}
+		if s.LastProcessedAt == nil || entry.ProcessedAt.After(*s.LastProcessedAt) {
+			processedAt := *entry.ProcessedAt
+			s.LastProcessedAt = &processedAt
+		}
+	}
+	for _, s := range stats {
+		if s.OldestPendingAt != nil {
+			s.LagSeconds = time.Since(*s.OldestPendingAt).Seconds()
+		}
+	}
+	sort.Slice(stats, func(i, j int) bool { return stats[i].Kind < stats[j].Kind })
+	return stats, nil
+}
+
+type RetentionRepository struct {
+	db *DB
+}
+
+// ListTenants returns every tenant that owns messages or verifications
+func (r *RetentionRepository) ListTenants(ctx context.Context) ([]string, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	seen := make(map[string]bool)
+	for _, message := range r.db.t.messages {
+		seen[message.TenantID] = true
+	}
+	for _, verification := range r.db.t.verifications {
+		seen[verification.TenantID] = true
+	}
+	tenants := make([]string, 0, len(seen))
+	for tenant := range seen {
+		tenants = append(tenants, tenant)
+	}
+	sort.Strings(tenants)
+	return tenants, nil
+}
+
+// CreateRun records the start of a retention run
+func (r *RetentionRepository) CreateRun(ctx context.Context, run *models.RetentionRun) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+	r.db.t.retentionRuns[run.ID] = models.RetentionRun{ID: run.ID, StartedAt: run.StartedAt, Status: run.Status}
+	return nil
+}
+
+// FinishRun records the outcome and counts of a retention run
+func (r *RetentionRepository) FinishRun(ctx context.Context, run *models.RetentionRun) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+	if _, ok := r.db.t.retentionRuns[run.ID]; ok {
+		r.db.t.retentionRuns[run.ID] = *run
+	}
+	return nil
+}
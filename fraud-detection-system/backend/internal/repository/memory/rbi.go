@@ -0,0 +1,156 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/fraud-detection-system/backend/internal/models"
+	"github.com/fraud-detection-system/backend/internal/utils"
+)
+
+type RBIRepository struct {
+	db *DB
+}
+
+// CreateCircular creates a new RBI circular
+func (r *RBIRepository) CreateCircular(ctx context.Context, circular *models.RBICircular) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	for _, existing := range r.db.t.circulars {
+		if existing.CircularNumber == circular.CircularNumber {
+			return utils.ErrAlreadyExists
+		}
+	}
+	r.db.t.circulars[circular.ID] = *circular
+	return nil
+}
+
+// GetCircularByID retrieves a circular by ID
+func (r *RBIRepository) GetCircularByID(ctx context.Context, id uuid.UUID) (*models.RBICircular, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	circular, ok := r.db.t.circulars[id]
+	if !ok {
+		return nil, fmt.Errorf("circular not found")
+	}
+	return &circular, nil
+}
+
+// activeCirculars returns the active circulars matching keep, most recently
+// issued first. The caller holds mu.
+func (r *RBIRepository) activeCirculars(keep func(c *models.RBICircular) bool) []*models.RBICircular {
+	var circulars []*models.RBICircular
+	for _, circular := range r.db.t.circulars {
+		circular := circular
+		if circular.IsActive && keep(&circular) {
+			circulars = append(circulars, &circular)
+		}
+	}
+	sort.SliceStable(circulars, func(i, j int) bool {
+		return circulars[i].IssuedDate.After(circulars[j].IssuedDate)
+	})
+	return circulars
+}
+
+// GetActiveCirculars retrieves all active circulars
+func (r *RBIRepository) GetActiveCirculars(ctx context.Context) ([]*models.RBICircular, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+	return r.activeCirculars(func(*models.RBICircular) bool { return true }), nil
+}
+
+// SearchCircularsByKeywords returns active circulars whose title or content
+// contains any keyword, ignoring case, or that are tagged with one exactly
+func (r *RBIRepository) SearchCircularsByKeywords(ctx context.Context, keywords []string) ([]*models.RBICircular, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	return r.activeCirculars(func(c *models.RBICircular) bool {
+		title, content := strings.ToLower(c.Title), strings.ToLower(c.Content)
+		for _, kw := range keywords {
+			lower := strings.ToLower(kw)
+			if strings.Contains(title, lower) || strings.Contains(content, lower) {
+				return true
+			}
+			for _, tag := range c.Keywords {
+				if tag == kw {
+					return true
+				}
+			}
+		}
+		return false
+	}), nil
+}
+
+// CreateSenderRegistry creates a new sender registry entry
+func (r *RBIRepository) CreateSenderRegistry(ctx context.Context, sender *models.SenderRegistry) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if _, ok := r.db.t.senders[sender.SenderID]; ok {
+		return utils.ErrAlreadyExists
+	}
+	r.db.t.senders[sender.SenderID] = *sender
+	return nil
+}
+
+// GetSenderBySenderID retrieves a sender by sender ID
+func (r *RBIRepository) GetSenderBySenderID(ctx context.Context, senderID string) (*models.SenderRegistry, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	sender, ok := r.db.t.senders[senderID]
+	if !ok {
+		return nil, fmt.Errorf("sender not found")
+	}
+	return &sender, nil
+}
+
+// UpdateSenderStats updates sender statistics and returns the sender's
+// reputation before the update along with the updated sender. It returns a
+// nil sender when the sender is not registered.
+func (r *RBIRepository) UpdateSenderStats(ctx context.Context, senderID string, isFraud bool) (float64, *models.SenderRegistry, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	sender, ok := r.db.t.senders[senderID]
+	if !ok {
+		return 0, nil, nil
+	}
+	previous := sender.ReputationScore
+	sender.MessageCount++
+	if isFraud {
+		sender.FraudReportCount++
+		sender.ReputationScore = math.Max(sender.ReputationScore-0.1, 0)
+	} else {
+		sender.ReputationScore = math.Min(sender.ReputationScore+0.01, 1.0)
+	}
+	sender.UpdatedAt = time.Now()
+	r.db.t.senders[senderID] = sender
+	return previous, &sender, nil
+}
+
+// GetVerifiedSenders retrieves all verified senders, most reputable first
+func (r *RBIRepository) GetVerifiedSenders(ctx context.Context) ([]*models.SenderRegistry, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	var senders []*models.SenderRegistry
+	for _, sender := range r.db.t.senders {
+		sender := sender
+		if sender.IsVerified && sender.IsActive {
+			senders = append(senders, &sender)
+		}
+	}
+	sort.SliceStable(senders, func(i, j int) bool {
+		return senders[i].ReputationScore > senders[j].ReputationScore
+	})
+	return senders, nil
+}
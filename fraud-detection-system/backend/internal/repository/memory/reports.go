@@ -0,0 +1,211 @@
+package memory
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/fraud-detection-system/backend/internal/models"
+	"github.com/fraud-detection-system/backend/internal/repository"
+)
+
+// ReportRepository stores reports in plaintext
+type ReportRepository struct {
+	db *DB
+}
+
+// Create creates a new report
+func (r *ReportRepository) Create(ctx context.Context, report *models.Report) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if _, ok := r.db.t.reports[report.ID]; ok {
+		return fmt.Errorf("failed to create report: duplicate id %s", report.ID)
+	}
+	r.db.t.reports[report.ID] = *report
+	return nil
+}
+
+// GetByID retrieves a report by ID
+func (r *ReportRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Report, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	report, ok := r.db.t.reports[id]
+	if !ok {
+		return nil, fmt.Errorf("report not found")
+	}
+	return &report, nil
+}
+
+// find returns the reports matching keep, newest first. The caller holds mu.
+func (r *ReportRepository) find(keep func(report *models.Report) bool) []*models.Report {
+	var reports []*models.Report
+	for _, report := range r.db.t.reports {
+		report := report
+		if keep(&report) {
+			reports = append(reports, &report)
+		}
+	}
+	sortNewestFirst(reports, func(report *models.Report) time.Time { return report.CreatedAt })
+	return reports
+}
+
+// GetByUserID retrieves reports by user ID
+func (r *ReportRepository) GetByUserID(ctx context.Context, userID uuid.UUID, limit, offset int) ([]*models.Report, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	reports := r.find(func(report *models.Report) bool {
+		return report.UserID != nil && *report.UserID == userID
+	})
+	return page(reports, limit, offset), nil
+}
+
+// ListByUserID returns a page of a user's reports matching filter in keyset
+// order of opts.Sort then ID, along with the total number of matches
+func (r *ReportRepository) ListByUserID(ctx context.Context, userID uuid.UUID, filter models.ReportFilter, opts models.ListOptions) ([]*models.Report, *models.PageInfo, error) {
+	opts, err := repository.ResolveListOptions(opts, models.ReportSortCreatedAt, models.ReportSortUpdatedAt)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	reports := r.find(func(report *models.Report) bool {
+		return report.UserID != nil && *report.UserID == userID &&
+			(filter.ReportType == "" || report.ReportType == filter.ReportType) &&
+			(filter.Status == "" || report.Status == filter.Status) &&
+			(filter.Priority == "" || report.Priority == filter.Priority) &&
+			(filter.SenderHeader == "" || report.SenderHeader == filter.SenderHeader) &&
+			filter.Created.Contains(report.CreatedAt)
+	})
+	return keysetPage(reports, opts, false,
+		func(report *models.Report) interface{} { return repository.ReportSortValue(report, opts.Sort) },
+		func(report *models.Report) uuid.UUID { return report.ID })
+}
+
+// GetByStatus retrieves reports by status
+func (r *ReportRepository) GetByStatus(ctx context.Context, status string, limit, offset int) ([]*models.Report, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	reports := r.find(func(report *models.Report) bool { return report.Status == status })
+	return page(reports, limit, offset), nil
+}
+
+// GetLabeledBatch returns up to limit reviewed or resolved FRAUD and
+// FALSE_POSITIVE reports with id greater than afterID, ordered by id,
+// skipping anonymized reports
+func (r *ReportRepository) GetLabeledBatch(ctx context.Context, afterID uuid.UUID, limit int) ([]*models.Report, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	reports := r.find(func(report *models.Report) bool {
+		return bytes.Compare(report.ID[:], afterID[:]) > 0 &&
+			(report.ReportType == "FRAUD" || report.ReportType == "FALSE_POSITIVE") &&
+			(report.Status == models.ReportStatusReviewed || report.Status == models.ReportStatusResolved) &&
+			report.Content != "[deleted]"
+	})
+	sort.Slice(reports, func(i, j int) bool { return bytes.Compare(reports[i].ID[:], reports[j].ID[:]) < 0 })
+	return page(reports, limit, 0), nil
+}
+
+// Update updates a report's review fields
+func (r *ReportRepository) Update(ctx context.Context, report *models.Report) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	stored, ok := r.db.t.reports[report.ID]
+	if !ok {
+		return nil
+	}
+	stored.Status = report.Status
+	stored.Priority = report.Priority
+	stored.ReviewedBy = report.ReviewedBy
+	stored.ReviewedAt = report.ReviewedAt
+	stored.ReviewNotes = report.ReviewNotes
+	stored.UpdatedAt = report.UpdatedAt
+	r.db.t.reports[report.ID] = stored
+	return nil
+}
+
+// GetStats retrieves report statistics
+func (r *ReportRepository) GetStats(ctx context.Context) (*models.ReportStats, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	stats := models.ReportStats{
+		ByType:     make(map[string]int),
+		ByPriority: make(map[string]int),
+	}
+	now := time.Now()
+	for _, report := range r.db.t.reports {
+		stats.TotalReports++
+		switch report.Status {
+		case "PENDING":
+			stats.PendingReports++
+		case "RESOLVED":
+			stats.ResolvedReports++
+		}
+		if !report.CreatedAt.Before(now.Add(-24 * time.Hour)) {
+			stats.Last24Hours++
+		}
+		if !report.CreatedAt.Before(now.Add(-7 * 24 * time.Hour)) {
+			stats.Last7Days++
+		}
+		stats.ByType[report.ReportType]++
+		stats.ByPriority[report.Priority]++
+	}
+	return &stats, nil
+}
+
+// CountByContent returns how many reports have been filed for the given
+// content. Anonymized reports no longer count.
+func (r *ReportRepository) CountByContent(ctx context.Context, content string) (int, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	count := 0
+	for _, report := range r.db.t.reports {
+		if report.Content == content && report.UserID != nil {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// AnonymizeByUserID strips the content of reports filed by a user and
+// detaches them, and clears the user as reviewer on any other reports
+func (r *ReportRepository) AnonymizeByUserID(ctx context.Context, userID uuid.UUID) (int64, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	var anonymized int64
+	now := time.Now()
+	for id, report := range r.db.t.reports {
+		if report.UserID != nil && *report.UserID == userID {
+			report.Content = "[deleted]"
+			report.Description = "[deleted]"
+			report.ContentHash = nil
+			report.EncryptionKeyID = nil
+			report.UserID = nil
+			report.UpdatedAt = now
+			anonymized++
+		}
+		if report.ReviewedBy != nil && *report.ReviewedBy == userID {
+			report.ReviewedBy = nil
+		}
+		r.db.t.reports[id] = report
+	}
+	return anonymized, nil
+}
+
+// ReencryptBatch has nothing to re-encrypt and reports an empty batch
+func (r *ReportRepository) ReencryptBatch(ctx context.Context, afterID uuid.UUID, limit int) (int, uuid.UUID, error) {
+	return 0, afterID, nil
+}
@@ -0,0 +1,282 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/fraud-detection-system/backend/internal/models"
+)
+
+type UserRepository struct {
+	db *DB
+}
+
+// Create creates a new user
+func (r *UserRepository) Create(ctx context.Context, user *models.User) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	for _, existing := range r.db.t.users {
+		if existing.Email == user.Email {
+			return fmt.Errorf("failed to create user: duplicate email %q", user.Email)
+		}
+	}
+	if _, ok := r.db.t.users[user.ID]; ok {
+		return fmt.Errorf("failed to create user: duplicate id %s", user.ID)
+	}
+	if user.Role == "" {
+		user.Role = models.RoleUser
+	}
+	if user.TenantID == "" {
+		user.TenantID = models.DefaultTenantID
+	}
+	r.db.t.users[user.ID] = *user
+	return nil
+}
+
+// GetByID retrieves a user by ID
+func (r *UserRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.User, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	user, ok := r.db.t.users[id]
+	if !ok {
+		return nil, fmt.Errorf("user not found")
+	}
+	return &user, nil
+}
+
+// GetByEmail retrieves a user by email
+func (r *UserRepository) GetByEmail(ctx context.Context, email string) (*models.User, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	for _, user := range r.db.t.users {
+		if user.Email == email {
+			return &user, nil
+		}
+	}
+	return nil, fmt.Errorf("user not found")
+}
+
+// update applies fn to a stored user, if it exists
+func (r *UserRepository) update(id uuid.UUID, fn func(user *models.User)) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if user, ok := r.db.t.users[id]; ok {
+		fn(&user)
+		r.db.t.users[id] = user
+	}
+}
+
+// Update updates a user's profile fields
+func (r *UserRepository) Update(ctx context.Context, user *models.User) error {
+	user.UpdatedAt = time.Now()
+	r.update(user.ID, func(stored *models.User) {
+		stored.Email = user.Email
+		stored.FullName = user.FullName
+		stored.PhoneNumber = user.PhoneNumber
+		stored.IsActive = user.IsActive
+		stored.IsVerified = user.IsVerified
+		stored.UpdatedAt = user.UpdatedAt
+		stored.LastLoginAt = user.LastLoginAt
+	})
+	return nil
+}
+
+// UpdateLastLogin updates the last login timestamp
+func (r *UserRepository) UpdateLastLogin(ctx context.Context, id uuid.UUID) error {
+	now := time.Now()
+	r.update(id, func(user *models.User) { user.LastLoginAt = &now })
+	return nil
+}
+
+// UpdatePassword replaces a user's password hash and records when it changed
+func (r *UserRepository) UpdatePassword(ctx context.Context, id uuid.UUID, passwordHash string) error {
+	now := time.Now()
+	r.update(id, func(user *models.User) {
+		user.PasswordHash = passwordHash
+		user.PasswordChangedAt = &now
+		user.UpdatedAt = now
+	})
+	return nil
+}
+
+// SetTOTPSecret stores a pending TOTP secret, leaving two-factor disabled until confirmed
+func (r *UserRepository) SetTOTPSecret(ctx context.Context, id uuid.UUID, secret string) error {
+	r.update(id, func(user *models.User) {
+		if !user.TOTPEnabled {
+			user.TOTPSecret = &secret
+			user.UpdatedAt = time.Now()
+		}
+	})
+	return nil
+}
+
+// EnableTOTP marks two-factor authentication as enabled
+func (r *UserRepository) EnableTOTP(ctx context.Context, id uuid.UUID) error {
+	now := time.Now()
+	r.update(id, func(user *models.User) {
+		user.TOTPEnabled = true
+		user.TOTPEnabledAt = &now
+		user.UpdatedAt = now
+	})
+	return nil
+}
+
+// DisableTOTP disables two-factor authentication and clears the secret
+func (r *UserRepository) DisableTOTP(ctx context.Context, id uuid.UUID) error {
+	r.update(id, func(user *models.User) {
+		user.TOTPEnabled = false
+		user.TOTPSecret = nil
+		user.TOTPEnabledAt = nil
+		user.UpdatedAt = time.Now()
+	})
+	return nil
+}
+
+// ReencryptTOTPBatch has nothing to re-encrypt and reports an empty batch
+func (r *UserRepository) ReencryptTOTPBatch(ctx context.Context, afterID uuid.UUID, limit int) (int, uuid.UUID, error) {
+	return 0, afterID, nil
+}
+
+// Delete deletes a user
+func (r *UserRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+	delete(r.db.t.users, id)
+	return nil
+}
+
+// Exists checks if a user exists by email
+func (r *UserRepository) Exists(ctx context.Context, email string) (bool, error) {
+	_, err := r.GetByEmail(ctx, email)
+	return err == nil, nil
+}
+
+type PasswordResetRepository struct {
+	db *DB
+}
+
+// Create stores a new password reset token
+func (r *PasswordResetRepository) Create(ctx context.Context, token *models.PasswordResetToken) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+	r.db.t.resetTokens[token.ID] = *token
+	return nil
+}
+
+// Consume marks an unused, unexpired token as used and returns it
+func (r *PasswordResetRepository) Consume(ctx context.Context, tokenHash string) (*models.PasswordResetToken, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	now := time.Now()
+	for id, token := range r.db.t.resetTokens {
+		if token.TokenHash != tokenHash || token.UsedAt != nil || !token.ExpiresAt.After(now) {
+			continue
+		}
+		token.UsedAt = &now
+		r.db.t.resetTokens[id] = token
+		return &token, nil
+	}
+	return nil, fmt.Errorf("password reset token not found")
+}
+
+// InvalidateForUser marks every outstanding token for a user as used
+func (r *PasswordResetRepository) InvalidateForUser(ctx context.Context, userID uuid.UUID) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	now := time.Now()
+	for id, token := range r.db.t.resetTokens {
+		if token.UserID == userID && token.UsedAt == nil {
+			token.UsedAt = &now
+			r.db.t.resetTokens[id] = token
+		}
+	}
+	return nil
+}
+
+// DeleteExpired removes tokens that expired before the given time
+func (r *PasswordResetRepository) DeleteExpired(ctx context.Context, before time.Time) (int64, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	var deleted int64
+	for id, token := range r.db.t.resetTokens {
+		if token.ExpiresAt.Before(before) {
+			delete(r.db.t.resetTokens, id)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+type RecoveryCodeRepository struct {
+	db *DB
+}
+
+// ReplaceForUser deletes a user's recovery codes and stores the new hashes
+func (r *RecoveryCodeRepository) ReplaceForUser(ctx context.Context, userID uuid.UUID, codeHashes []string) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	r.db.t.recoveryCodes = removeCodes(r.db.t.recoveryCodes, userID)
+	for _, hash := range codeHashes {
+		r.db.t.recoveryCodes = append(r.db.t.recoveryCodes, recoveryCode{userID: userID, codeHash: hash})
+	}
+	return nil
+}
+
+// Consume marks an unused recovery code as used. It reports false if no
+// matching unused code exists.
+func (r *RecoveryCodeRepository) Consume(ctx context.Context, userID uuid.UUID, codeHash string) (bool, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	for i, code := range r.db.t.recoveryCodes {
+		if code.userID == userID && code.codeHash == codeHash && code.usedAt == nil {
+			now := time.Now()
+			r.db.t.recoveryCodes[i].usedAt = &now
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// CountUnused returns the number of recovery codes a user has left
+func (r *RecoveryCodeRepository) CountUnused(ctx context.Context, userID uuid.UUID) (int, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	count := 0
+	for _, code := range r.db.t.recoveryCodes {
+		if code.userID == userID && code.usedAt == nil {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// DeleteForUser removes all recovery codes for a user
+func (r *RecoveryCodeRepository) DeleteForUser(ctx context.Context, userID uuid.UUID) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+	r.db.t.recoveryCodes = removeCodes(r.db.t.recoveryCodes, userID)
+	return nil
+}
+
+// removeCodes returns codes without those belonging to userID
+func removeCodes(codes []recoveryCode, userID uuid.UUID) []recoveryCode {
+	kept := make([]recoveryCode, 0, len(codes))
+	for _, code := range codes {
+		if code.userID != userID {
+			kept = append(kept, code)
+		}
+	}
+	return kept
+}
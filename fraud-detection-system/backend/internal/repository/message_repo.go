@@ -4,32 +4,148 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/lib/pq"
+	"github.com/fraud-detection-system/backend/internal/encryption"
 	"github.com/fraud-detection-system/backend/internal/models"
+	"github.com/fraud-detection-system/backend/internal/utils"
+	"github.com/fraud-detection-system/backend/internal/tracing"
 )
 
+// MessageRepository stores messages with content and phone number encrypted
+// at rest. Keyed hashes of both are stored alongside for lookups.
 type MessageRepository struct {
-	db *sql.DB
+	db     DBTX
+	cipher *encryption.FieldCipher
 }
 
-func NewMessageRepository(db *sql.DB) *MessageRepository {
-	return &MessageRepository{db: db}
+func NewMessageRepository(db *sql.DB, cipher *encryption.FieldCipher) *MessageRepository {
+	return &MessageRepository{db: db, cipher: cipher}
+}
+
+// WithTx returns a copy of the repository that runs its queries in tx
+func (r *MessageRepository) WithTx(tx *sql.Tx) *MessageRepository {
+	return &MessageRepository{db: tx, cipher: r.cipher}
+}
+
+// encrypt encrypts the sensitive fields of message, returning the stored
+// content and phone number and filling in the hashes and key ID
+func (r *MessageRepository) encrypt(message *models.Message) (string, *string, error) {
+	content, keyID, err := r.cipher.Encrypt(message.Content)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to encrypt message content: %w", err)
+	}
+	contentHash := r.cipher.Hash(message.Content)
+	message.ContentHash = &contentHash
+	message.EncryptionKeyID = &keyID
+
+	var phoneNumber *string
+	message.PhoneNumberHash = nil
+	if message.PhoneNumber != nil {
+		encrypted, _, err := r.cipher.Encrypt(*message.PhoneNumber)
+		if err != nil {
+			return "", nil, fmt.Errorf("failed to encrypt phone number: %w", err)
+		}
+		phoneHash := r.cipher.Hash(*message.PhoneNumber)
+		phoneNumber = &encrypted
+		message.PhoneNumberHash = &phoneHash
+	}
+
+	return content, phoneNumber, nil
+}
+
+// decrypt replaces the stored content and phone number of message with plaintext
+func (r *MessageRepository) decrypt(message *models.Message) error {
+	content, err := r.cipher.Decrypt(message.Content)
+	if err != nil {
+		return fmt.Errorf("failed to decrypt message content: %w", err)
+	}
+	message.Content = content
+
+	if message.PhoneNumber != nil {
+		phoneNumber, err := r.cipher.Decrypt(*message.PhoneNumber)
+		if err != nil {
+			return fmt.Errorf("failed to decrypt phone number: %w", err)
+		}
+		message.PhoneNumber = &phoneNumber
+	}
+	return nil
+}
+
+// searchRedactor strips PII from the text indexed for search, so the search
+// vector does not hold in the clear what content encryption protects
+var searchRedactor = utils.NewRedactor(utils.RedactionRemove, nil)
+
+// searchDocument returns the text of message indexed for full-text search.
+// The search vector keeps every remaining word of the content with its
+// position, so anyone who can read the messages table can rebuild most of
+// the text apart from the redacted PII. That is the price of searching
+// encrypted content; deployments that cannot accept it should restrict
+// access to the search_vector column like the content itself.
+func searchDocument(message *models.Message) string {
+	return searchRedactor.String(message.Content)
+}
+
+// searchPhoneHashes returns the keyed hashes of the normalized phone numbers
+// message mentions, in its content or its phone number field. Phone numbers
+// are redacted from the search vector, so search matches these instead.
+func (r *MessageRepository) searchPhoneHashes(message *models.Message) []string {
+	phones := utils.FindPhoneNumbers(message.Content)
+	if message.PhoneNumber != nil {
+		if phone := utils.NormalizePhoneNumber(*message.PhoneNumber); phone != "" {
+			phones = append(phones, phone)
+		}
+	}
+
+	hashes := []string{}
+	seen := make(map[string]bool, len(phones))
+	for _, phone := range phones {
+		if !seen[phone] {
+			seen[phone] = true
+			hashes = append(hashes, r.cipher.Hash(phone))
+		}
+	}
+	return hashes
+}
+
+// searchURLs returns the extracted URLs of message as indexed for substring search
+func searchURLs(message *models.Message) *string {
+	if len(message.ExtractedURLs) == 0 {
+		return nil
+	}
+	urls := strings.Join(message.ExtractedURLs, " ")
+	return &urls
 }
 
 // Create creates a new message
-func (r *MessageRepository) Create(ctx context.Context, message *models.Message) error {
+func (r *MessageRepository) Create(ctx context.Context, message *models.Message) (err error) {
+	ctx, span := startSpan(ctx, "MessageRepository.Create")
+	defer func() { tracing.End(span, err) }()
+
 	query := `
 		INSERT INTO messages (id, user_id, content, sender_header, received_at, message_type,
-		                      phone_number, has_links, link_count, extracted_urls, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+		                      phone_number, has_links, link_count, extracted_urls, tenant_id,
+		                      content_hash, features, phone_number_hash, encryption_key_id,
+		                      created_at, updated_at, search_vector, search_urls, search_phone_hashes)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17,
+		        to_tsvector('simple', $18), $19, $20)
 	`
-	_, err := r.db.ExecContext(ctx, query,
-		message.ID, message.UserID, message.Content, message.SenderHeader, message.ReceivedAt,
-		message.MessageType, message.PhoneNumber, message.HasLinks, message.LinkCount,
-		pq.Array(message.ExtractedURLs), message.CreatedAt, message.UpdatedAt,
+	if message.TenantID == "" {
+		message.TenantID = models.DefaultTenantID
+	}
+	content, phoneNumber, err := r.encrypt(message)
+	if err != nil {
+		return err
+	}
+	_, err = r.db.ExecContext(ctx, query,
+		message.ID, message.UserID, content, message.SenderHeader, message.ReceivedAt,
+		message.MessageType, phoneNumber, message.HasLinks, message.LinkCount,
+		pq.Array(message.ExtractedURLs), message.TenantID, message.ContentHash, message.Features,
+		message.PhoneNumberHash, message.EncryptionKeyID, message.CreatedAt, message.UpdatedAt,
+		searchDocument(message), searchURLs(message), pq.Array(r.searchPhoneHashes(message)),
 	)
 	if err != nil {
 		return fmt.Errorf("failed to create message: %w", err)
@@ -38,18 +154,23 @@ func (r *MessageRepository) Create(ctx context.Context, message *models.Message)
 }
 
 // GetByID retrieves a message by ID
-func (r *MessageRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Message, error) {
+func (r *MessageRepository) GetByID(ctx context.Context, id uuid.UUID) (_ *models.Message, err error) {
+	ctx, span := startSpan(ctx, "MessageRepository.GetByID")
+	defer func() { tracing.End(span, err) }()
+
 	query := `
 		SELECT id, user_id, content, sender_header, received_at, message_type,
-		       phone_number, has_links, link_count, extracted_urls, created_at, updated_at
+		       phone_number, has_links, link_count, extracted_urls, tenant_id, content_hash,
+		       features, anonymized_at, created_at, updated_at
 		FROM messages
 		WHERE id = $1
 	`
 	var message models.Message
-	err := r.db.QueryRowContext(ctx, query, id).Scan(
+	err = r.db.QueryRowContext(ctx, query, id).Scan(
 		&message.ID, &message.UserID, &message.Content, &message.SenderHeader, &message.ReceivedAt,
 		&message.MessageType, &message.PhoneNumber, &message.HasLinks, &message.LinkCount,
-		pq.Array(&message.ExtractedURLs), &message.CreatedAt, &message.UpdatedAt,
+		pq.Array(&message.ExtractedURLs), &message.TenantID, &message.ContentHash, &message.Features,
+		&message.AnonymizedAt, &message.CreatedAt, &message.UpdatedAt,
 	)
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -57,20 +178,192 @@ func (r *MessageRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.
 		}
 		return nil, fmt.Errorf("failed to get message: %w", err)
 	}
+	if err := r.decrypt(&message); err != nil {
+		return nil, err
+	}
 	return &message, nil
 }
 
 // GetByUserID retrieves messages by user ID
-func (r *MessageRepository) GetByUserID(ctx context.Context, userID uuid.UUID, limit, offset int) ([]*models.Message, error) {
+func (r *MessageRepository) GetByUserID(ctx context.Context, userID uuid.UUID, limit, offset int) (_ []*models.Message, err error) {
+	ctx, span := startSpan(ctx, "MessageRepository.GetByUserID")
+	defer func() { tracing.End(span, err) }()
+
 	query := `
 		SELECT id, user_id, content, sender_header, received_at, message_type,
-		       phone_number, has_links, link_count, extracted_urls, created_at, updated_at
+		       phone_number, has_links, link_count, extracted_urls, tenant_id, content_hash,
+		       features, anonymized_at, created_at, updated_at
 		FROM messages
 		WHERE user_id = $1
 		ORDER BY created_at DESC
 		LIMIT $2 OFFSET $3
 	`
-	rows, err := r.db.QueryContext(ctx, query, userID, limit, offset)
+	return r.queryMessages(ctx, query, userID, limit, offset)
+}
+
+// GetByContent retrieves a tenant's messages with exactly the given content
+func (r *MessageRepository) GetByContent(ctx context.Context, tenantID, content string, limit int) (_ []*models.Message, err error) {
+	ctx, span := startSpan(ctx, "MessageRepository.GetByContent")
+	defer func() { tracing.End(span, err) }()
+
+	query := `
+		SELECT id, user_id, content, sender_header, received_at, message_type,
+		       phone_number, has_links, link_count, extracted_urls, tenant_id, content_hash,
+		       features, anonymized_at, created_at, updated_at
+		FROM messages
+		WHERE tenant_id = $1 AND content_hash = $2
+		ORDER BY created_at DESC
+		LIMIT $3
+	`
+	return r.queryMessages(ctx, query, tenantID, r.cipher.Hash(content), limit)
+}
+
+// GetByPhoneNumber retrieves a tenant's messages that mention the given phone number
+func (r *MessageRepository) GetByPhoneNumber(ctx context.Context, tenantID, phoneNumber string, limit int) (_ []*models.Message, err error) {
+	ctx, span := startSpan(ctx, "MessageRepository.GetByPhoneNumber")
+	defer func() { tracing.End(span, err) }()
+
+	query := `
+		SELECT id, user_id, content, sender_header, received_at, message_type,
+		       phone_number, has_links, link_count, extracted_urls, tenant_id, content_hash,
+		       features, anonymized_at, created_at, updated_at
+		FROM messages
+		WHERE tenant_id = $1 AND phone_number_hash = $2
+		ORDER BY created_at DESC
+		LIMIT $3
+	`
+	return r.queryMessages(ctx, query, tenantID, r.cipher.Hash(phoneNumber), limit)
+}
+
+// Search returns a page of the messages matching search, best matches first,
+// along with the total number of matches. It searches one tenant's messages,
+// or every tenant's if search.TenantID is empty. Content is matched
+// against its search vector with websearch_to_tsquery, so phrases in quotes
+// and -exclusions work; URLs and sender headers are matched as substrings
+// through their trigram indexes, and phone numbers, in the phone number
+// field or the content, by the keyed hash of their normalized form.
+func (r *MessageRepository) Search(ctx context.Context, search models.MessageSearch) (_ []*models.MessageSearchResult, _ int, err error) {
+	ctx, span := startSpan(ctx, "MessageRepository.Search")
+	defer func() { tracing.End(span, err) }()
+
+	var q listQuery
+	if search.TenantID != "" {
+		q.add("m.tenant_id = ?", search.TenantID)
+	}
+
+	var match []string
+	rank := "0::real"
+	if search.HasField(models.SearchFieldContent) {
+		tsquery := fmt.Sprintf("websearch_to_tsquery('simple', %s)", q.arg(search.Query))
+		match = append(match, "m.search_vector @@ "+tsquery)
+		rank = fmt.Sprintf("ts_rank(m.search_vector, %s)", tsquery)
+	}
+	if substring := utils.SearchSubstring(search.Query); substring != "" {
+		pattern := "%" + escapeLike(substring) + "%"
+		if search.HasField(models.SearchFieldURL) {
+			match = append(match, "m.search_urls ILIKE "+q.arg(pattern))
+		}
+		if search.HasField(models.SearchFieldSender) {
+			match = append(match, "m.sender_header ILIKE "+q.arg(pattern))
+		}
+	}
+	if search.HasField(models.SearchFieldPhone) {
+		match = append(match, "m.phone_number_hash = "+q.arg(r.cipher.Hash(search.Query)))
+		if phone := utils.NormalizePhoneNumber(search.Query); phone != "" {
+			match = append(match, "m.search_phone_hashes @> ARRAY["+q.arg(r.cipher.Hash(phone))+"]::text[]")
+		}
+	}
+	if len(match) == 0 {
+		return nil, 0, nil
+	}
+	q.conds = append(q.conds, "("+strings.Join(match, " OR ")+")")
+
+	if search.MessageType != "" {
+		q.add("m.message_type = ?", search.MessageType)
+	}
+	if search.SenderHeader != "" {
+		q.add("m.sender_header = ?", search.SenderHeader)
+	}
+	if search.IsFraud != nil {
+		q.add("v.is_fraud = ?", *search.IsFraud)
+	}
+	if search.FraudType != "" {
+		q.add("v.fraud_type = ?", search.FraudType)
+	}
+	q.addTimeRange("m.created_at", search.Created)
+
+	from := "messages m LEFT JOIN verifications v ON v.message_id = m.id"
+	var total int
+	countQuery := fmt.Sprintf(`SELECT COUNT(*) FROM %s %s`, from, q.where())
+	if err := r.db.QueryRowContext(ctx, countQuery, q.args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count search results: %w", err)
+	}
+
+	query := fmt.Sprintf(`
+		SELECT m.id, m.user_id, m.content, m.sender_header, m.received_at, m.message_type,
+		       m.phone_number, m.has_links, m.link_count, m.extracted_urls, m.tenant_id,
+		       m.content_hash, m.features, m.anonymized_at, m.created_at, m.updated_at,
+		       v.id, v.is_fraud, v.fraud_score, v.fraud_type, %s AS rank
+		FROM %s
+		%s
+		ORDER BY rank DESC, m.created_at DESC, m.id DESC
+		LIMIT %d OFFSET %d
+	`, rank, from, q.where(), search.Limit, search.Offset)
+	rows, err := r.db.QueryContext(ctx, query, q.args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to search messages: %w", err)
+	}
+	defer rows.Close()
+
+	var results []*models.MessageSearchResult
+	for rows.Next() {
+		var (
+			result         models.MessageSearchResult
+			verificationID *uuid.UUID
+			isFraud        sql.NullBool
+			fraudScore     sql.NullFloat64
+			fraudType      *string
+		)
+		message := &result.Message
+		err := rows.Scan(
+			&message.ID, &message.UserID, &message.Content, &message.SenderHeader, &message.ReceivedAt,
+			&message.MessageType, &message.PhoneNumber, &message.HasLinks, &message.LinkCount,
+			pq.Array(&message.ExtractedURLs), &message.TenantID, &message.ContentHash, &message.Features,
+			&message.AnonymizedAt, &message.CreatedAt, &message.UpdatedAt,
+			&verificationID, &isFraud, &fraudScore, &fraudType, &result.Rank,
+		)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to scan search result: %w", err)
+		}
+		if err := r.decrypt(message); err != nil {
+			return nil, 0, err
+		}
+		if verificationID != nil {
+			result.Verification = &models.Verification{
+				ID:         *verificationID,
+				MessageID:  message.ID,
+				IsFraud:    isFraud.Bool,
+				FraudScore: fraudScore.Float64,
+				FraudType:  fraudType,
+			}
+		}
+		results = append(results, &result)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("failed to search messages: %w", err)
+	}
+
+	return results, total, nil
+}
+
+// escapeLike escapes the LIKE wildcards in s
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s)
+}
+
+// queryMessages runs a message query and decrypts the results
+func (r *MessageRepository) queryMessages(ctx context.Context, query string, args ...interface{}) ([]*models.Message, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get messages: %w", err)
 	}
@@ -82,11 +375,15 @@ func (r *MessageRepository) GetByUserID(ctx context.Context, userID uuid.UUID, l
 		err := rows.Scan(
 			&message.ID, &message.UserID, &message.Content, &message.SenderHeader, &message.ReceivedAt,
 			&message.MessageType, &message.PhoneNumber, &message.HasLinks, &message.LinkCount,
-			pq.Array(&message.ExtractedURLs), &message.CreatedAt, &message.UpdatedAt,
+			pq.Array(&message.ExtractedURLs), &message.TenantID, &message.ContentHash, &message.Features,
+			&message.AnonymizedAt, &message.CreatedAt, &message.UpdatedAt,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan message: %w", err)
 		}
+		if err := r.decrypt(&message); err != nil {
+			return nil, err
+		}
 		messages = append(messages, &message)
 	}
 
@@ -94,22 +391,194 @@ func (r *MessageRepository) GetByUserID(ctx context.Context, userID uuid.UUID, l
 }
 
 // Delete deletes a message
-func (r *MessageRepository) Delete(ctx context.Context, id uuid.UUID) error {
+func (r *MessageRepository) Delete(ctx context.Context, id uuid.UUID) (err error) {
+	ctx, span := startSpan(ctx, "MessageRepository.Delete")
+	defer func() { tracing.End(span, err) }()
+
 	query := `DELETE FROM messages WHERE id = $1`
-	_, err := r.db.ExecContext(ctx, query, id)
+	_, err = r.db.ExecContext(ctx, query, id)
 	if err != nil {
 		return fmt.Errorf("failed to delete message: %w", err)
 	}
 	return nil
 }
 
-// DeleteOldMessages deletes messages older than the specified duration
-func (r *MessageRepository) DeleteOldMessages(ctx context.Context, olderThan time.Duration) (int64, error) {
-	query := `DELETE FROM messages WHERE created_at < $1`
-	result, err := r.db.ExecContext(ctx, query, time.Now().Add(-olderThan))
+// DeleteOldMessages deletes up to batchSize of a tenant's messages created
+// before cutoff that no longer have a verification attached
+func (r *MessageRepository) DeleteOldMessages(ctx context.Context, tenantID string, cutoff time.Time, batchSize int) (_ int64, err error) {
+	ctx, span := startSpan(ctx, "MessageRepository.DeleteOldMessages")
+	defer func() { tracing.End(span, err) }()
+
+	query := `
+		DELETE FROM messages
+		WHERE id IN (
+			SELECT m.id FROM messages m
+			WHERE m.tenant_id = $1 AND m.created_at < $2
+			  AND NOT EXISTS (SELECT 1 FROM verifications v WHERE v.message_id = m.id)
+			LIMIT $3
+		)
+	`
+	result, err := r.db.ExecContext(ctx, query, tenantID, cutoff, batchSize)
 	if err != nil {
 		return 0, fmt.Errorf("failed to delete old messages: %w", err)
 	}
 	return result.RowsAffected()
 }
 
+// AnonymizeOldMessages strips content and personal data from up to batchSize
+// of a tenant's messages created before cutoff whose verifications are still
+// retained. The content hash and extracted features are kept for audit.
+func (r *MessageRepository) AnonymizeOldMessages(ctx context.Context, tenantID string, cutoff time.Time, batchSize int) (_ int64, err error) {
+	ctx, span := startSpan(ctx, "MessageRepository.AnonymizeOldMessages")
+	defer func() { tracing.End(span, err) }()
+
+	query := `
+		UPDATE messages
+		SET content = '[redacted]', phone_number = NULL, phone_number_hash = NULL,
+		    extracted_urls = NULL, encryption_key_id = NULL, search_vector = NULL, search_urls = NULL,
+		    search_phone_hashes = NULL, anonymized_at = $4, updated_at = $4
+		WHERE id IN (
+			SELECT m.id FROM messages m
+			WHERE m.tenant_id = $1 AND m.created_at < $2 AND m.anonymized_at IS NULL
+			  AND EXISTS (SELECT 1 FROM verifications v WHERE v.message_id = m.id)
+			LIMIT $3
+		)
+	`
+	result, err := r.db.ExecContext(ctx, query, tenantID, cutoff, batchSize, time.Now())
+	if err != nil {
+		return 0, fmt.Errorf("failed to anonymize old messages: %w", err)
+	}
+	return result.RowsAffected()
+}
+
+// AnonymizeByUserID strips content and personal data from a user's messages
+// and detaches them from the user, keeping the rows for aggregate statistics.
+// Like retention it marks them anonymized, so re-encryption skips them.
+func (r *MessageRepository) AnonymizeByUserID(ctx context.Context, userID uuid.UUID) (_ int64, err error) {
+	ctx, span := startSpan(ctx, "MessageRepository.AnonymizeByUserID")
+	defer func() { tracing.End(span, err) }()
+
+	query := `
+		UPDATE messages
+		SET content = '[deleted]', phone_number = NULL, phone_number_hash = NULL,
+		    extracted_urls = NULL, encryption_key_id = NULL, search_vector = NULL, search_urls = NULL,
+		    search_phone_hashes = NULL, user_id = NULL, anonymized_at = $2, updated_at = $2
+		WHERE user_id = $1
+	`
+	result, err := r.db.ExecContext(ctx, query, userID, time.Now())
+	if err != nil {
+		return 0, fmt.Errorf("failed to anonymize messages: %w", err)
+	}
+	return result.RowsAffected()
+}
+
+// ReencryptBatch re-encrypts up to limit messages with id greater than afterID
+// whose content is plaintext or encrypted with a key other than the active
+// one, and refreshes their keyed hashes. It returns the number of messages
+// examined and the last ID, to be passed as afterID for the next batch.
+func (r *MessageRepository) ReencryptBatch(ctx context.Context, afterID uuid.UUID, limit int) (_ int, _ uuid.UUID, err error) {
+	ctx, span := startSpan(ctx, "MessageRepository.ReencryptBatch")
+	defer func() { tracing.End(span, err) }()
+
+	query := `
+		SELECT id, content, phone_number, encryption_key_id
+		FROM messages
+		WHERE id > $1 AND anonymized_at IS NULL
+		  AND encryption_key_id IS DISTINCT FROM $2
+		ORDER BY id
+		LIMIT $3
+	`
+	rows, err := r.db.QueryContext(ctx, query, afterID, r.cipher.ActiveKeyID(), limit)
+	if err != nil {
+		return 0, afterID, fmt.Errorf("failed to get messages for re-encryption: %w", err)
+	}
+
+	var messages []*models.Message
+	for rows.Next() {
+		var message models.Message
+		if err := rows.Scan(&message.ID, &message.Content, &message.PhoneNumber, &message.EncryptionKeyID); err != nil {
+			rows.Close()
+			return 0, afterID, fmt.Errorf("failed to scan message: %w", err)
+		}
+		messages = append(messages, &message)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, afterID, fmt.Errorf("failed to get messages for re-encryption: %w", err)
+	}
+
+	update := `
+		UPDATE messages
+		SET content = $2, phone_number = $3, content_hash = $4, phone_number_hash = $5,
+		    encryption_key_id = $6, search_vector = to_tsvector('simple', $8), search_phone_hashes = $9
+		WHERE id = $1 AND encryption_key_id IS NOT DISTINCT FROM $7
+	`
+	for _, message := range messages {
+		previousKeyID := message.EncryptionKeyID
+		if err := r.decrypt(message); err != nil {
+			return 0, afterID, fmt.Errorf("message %s: %w", message.ID, err)
+		}
+		content, phoneNumber, err := r.encrypt(message)
+		if err != nil {
+			return 0, afterID, fmt.Errorf("message %s: %w", message.ID, err)
+		}
+		_, err = r.db.ExecContext(ctx, update,
+			message.ID, content, phoneNumber, message.ContentHash, message.PhoneNumberHash,
+			message.EncryptionKeyID, previousKeyID, searchDocument(message), pq.Array(r.searchPhoneHashes(message)),
+		)
+		if err != nil {
+			return 0, afterID, fmt.Errorf("failed to re-encrypt message %s: %w", message.ID, err)
+		}
+		afterID = message.ID
+	}
+
+	return len(messages), afterID, nil
+}
+
+// IndexSearchBatch builds the search vector and phone hashes of up to limit
+// messages with id greater than afterID that do not have them yet, such as
+// messages stored before search existed. It returns how many it indexed and the last id.
+func (r *MessageRepository) IndexSearchBatch(ctx context.Context, afterID uuid.UUID, limit int) (_ int, _ uuid.UUID, err error) {
+	ctx, span := startSpan(ctx, "MessageRepository.IndexSearchBatch")
+	defer func() { tracing.End(span, err) }()
+
+	query := `
+		SELECT id, content, phone_number
+		FROM messages
+		WHERE id > $1 AND anonymized_at IS NULL AND content <> '[deleted]'
+		  AND (search_vector IS NULL OR search_phone_hashes IS NULL)
+		ORDER BY id
+		LIMIT $2
+	`
+	rows, err := r.db.QueryContext(ctx, query, afterID, limit)
+	if err != nil {
+		return 0, afterID, fmt.Errorf("failed to get messages for search indexing: %w", err)
+	}
+
+	var messages []*models.Message
+	for rows.Next() {
+		var message models.Message
+		if err := rows.Scan(&message.ID, &message.Content, &message.PhoneNumber); err != nil {
+			rows.Close()
+			return 0, afterID, fmt.Errorf("failed to scan message: %w", err)
+		}
+		messages = append(messages, &message)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, afterID, fmt.Errorf("failed to get messages for search indexing: %w", err)
+	}
+
+	update := `UPDATE messages SET search_vector = to_tsvector('simple', $2), search_phone_hashes = $3 WHERE id = $1`
+	for _, message := range messages {
+		if err := r.decrypt(message); err != nil {
+			return 0, afterID, fmt.Errorf("message %s: %w", message.ID, err)
+		}
+		if _, err := r.db.ExecContext(ctx, update, message.ID, searchDocument(message), pq.Array(r.searchPhoneHashes(message))); err != nil {
+			return 0, afterID, fmt.Errorf("failed to index message %s: %w", message.ID, err)
+		}
+		afterID = message.ID
+	}
+
+	return len(messages), afterID, nil
+}
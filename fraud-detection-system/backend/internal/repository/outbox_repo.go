@@ -0,0 +1,187 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/fraud-detection-system/backend/internal/models"
+	"github.com/fraud-detection-system/backend/internal/tracing"
+	"github.com/fraud-detection-system/backend/internal/utils"
+)
+
+type OutboxRepository struct {
+	db DBTX
+}
+
+func NewOutboxRepository(db *sql.DB) *OutboxRepository {
+	return &OutboxRepository{db: db}
+}
+
+// WithTx returns a copy of the repository that runs its queries in tx
+func (r *OutboxRepository) WithTx(tx *sql.Tx) *OutboxRepository {
+	return &OutboxRepository{db: tx}
+}
+
+// Add records an entry of the given kind with payload marshalled to JSON
+func (r *OutboxRepository) Add(ctx context.Context, kind string, payload interface{}) (err error) {
+	ctx, span := startSpan(ctx, "OutboxRepository.Add")
+	defer func() { tracing.End(span, err) }()
+
+	return r.add(ctx, kind, nil, nil, payload)
+}
+
+// AddMessage records a message to be published to a Kafka topic. Messages
+// with the same key go to the same partition, so they keep their order. The
+// trace context of ctx is kept with the message for the relay.
+func (r *OutboxRepository) AddMessage(ctx context.Context, topic, key string, message *models.QueueMessage) (err error) {
+	message.TraceContext = tracing.Inject(ctx)
+	message.RequestID = utils.RequestIDFromContext(ctx)
+	ctx, span := startSpan(ctx, "OutboxRepository.AddMessage")
+	defer func() { tracing.End(span, err) }()
+
+	return r.add(ctx, models.OutboxKindKafka, &topic, &key, message)
+}
+
+func (r *OutboxRepository) add(ctx context.Context, kind string, topic, key *string, payload interface{}) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal outbox payload: %w", err)
+	}
+
+	query := `INSERT INTO outbox (kind, topic, message_key, payload, created_at) VALUES ($1, $2, $3, $4, NOW())`
+	if _, err := r.db.ExecContext(ctx, query, kind, topic, key, string(data)); err != nil {
+		return fmt.Errorf("failed to add outbox entry: %w", err)
+	}
+	return nil
+}
+
+// TryLock takes a transaction-scoped advisory lock so only one processor
+// works through the outbox at a time. It reports false if another holds it.
+// It must be called inside a transaction.
+func (r *OutboxRepository) TryLock(ctx context.Context, key int64) (_ bool, err error) {
+	ctx, span := startSpan(ctx, "OutboxRepository.TryLock")
+	defer func() { tracing.End(span, err) }()
+
+	var locked bool
+	if err := r.db.QueryRowContext(ctx, `SELECT pg_try_advisory_xact_lock($1)`, key).Scan(&locked); err != nil {
+		return false, fmt.Errorf("failed to lock outbox: %w", err)
+	}
+	return locked, nil
+}
+
+// GetPending returns up to limit unprocessed entries of kind, oldest first
+func (r *OutboxRepository) GetPending(ctx context.Context, kind string, limit int) (_ []*models.OutboxEntry, err error) {
+	ctx, span := startSpan(ctx, "OutboxRepository.GetPending")
+	defer func() { tracing.End(span, err) }()
+
+	query := `
+		SELECT id, kind, topic, message_key, payload, attempts, last_error, created_at, processed_at
+		FROM outbox
+		WHERE kind = $1 AND processed_at IS NULL
+		ORDER BY id
+		LIMIT $2
+	`
+	rows, err := r.db.QueryContext(ctx, query, kind, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pending outbox entries: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []*models.OutboxEntry
+	for rows.Next() {
+		entry := &models.OutboxEntry{}
+		if err := rows.Scan(
+			&entry.ID, &entry.Kind, &entry.Topic, &entry.MessageKey, &entry.Payload, &entry.Attempts,
+			&entry.LastError, &entry.CreatedAt, &entry.ProcessedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan outbox entry: %w", err)
+		}
+		entries = append(entries, entry)
+	}
+	return entries, rows.Err()
+}
+
+// MarkProcessed records that an entry has been applied
+func (r *OutboxRepository) MarkProcessed(ctx context.Context, id int64) (err error) {
+	ctx, span := startSpan(ctx, "OutboxRepository.MarkProcessed")
+	defer func() { tracing.End(span, err) }()
+
+	query := `UPDATE outbox SET processed_at = NOW(), attempts = attempts + 1, last_error = NULL WHERE id = $1`
+	if _, err := r.db.ExecContext(ctx, query, id); err != nil {
+		return fmt.Errorf("failed to mark outbox entry processed: %w", err)
+	}
+	return nil
+}
+
+// MarkFailed records a failed attempt so the entry is retried
+func (r *OutboxRepository) MarkFailed(ctx context.Context, id int64, reason string) (err error) {
+	ctx, span := startSpan(ctx, "OutboxRepository.MarkFailed")
+	defer func() { tracing.End(span, err) }()
+
+	query := `UPDATE outbox SET attempts = attempts + 1, last_error = $2 WHERE id = $1`
+	if _, err := r.db.ExecContext(ctx, query, id, reason); err != nil {
+		return fmt.Errorf("failed to mark outbox entry failed: %w", err)
+	}
+	return nil
+}
+
+// DeleteProcessedBefore removes entries applied before cutoff
+func (r *OutboxRepository) DeleteProcessedBefore(ctx context.Context, cutoff time.Time) (_ int64, err error) {
+	ctx, span := startSpan(ctx, "OutboxRepository.DeleteProcessedBefore")
+	defer func() { tracing.End(span, err) }()
+
+	result, err := r.db.ExecContext(ctx, `DELETE FROM outbox WHERE processed_at < $1`, cutoff)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete processed outbox entries: %w", err)
+	}
+	return result.RowsAffected()
+}
+
+// MarkAbandoned gives up on an entry that kept failing, recording why, so
+// later entries are not held up behind it
+func (r *OutboxRepository) MarkAbandoned(ctx context.Context, id int64, reason string) (err error) {
+	ctx, span := startSpan(ctx, "OutboxRepository.MarkAbandoned")
+	defer func() { tracing.End(span, err) }()
+
+	query := `UPDATE outbox SET processed_at = NOW(), attempts = attempts + 1, last_error = $2 WHERE id = $1`
+	if _, err := r.db.ExecContext(ctx, query, id, reason); err != nil {
+		return fmt.Errorf("failed to mark outbox entry abandoned: %w", err)
+	}
+	return nil
+}
+
+// GetStats returns the backlog of each kind of entry
+func (r *OutboxRepository) GetStats(ctx context.Context) (_ []*models.OutboxStats, err error) {
+	ctx, span := startSpan(ctx, "OutboxRepository.GetStats")
+	defer func() { tracing.End(span, err) }()
+
+	query := `
+		SELECT kind,
+		       COUNT(*) FILTER (WHERE processed_at IS NULL),
+		       COUNT(*) FILTER (WHERE processed_at IS NOT NULL AND last_error IS NOT NULL),
+		       MIN(created_at) FILTER (WHERE processed_at IS NULL),
+		       COALESCE(EXTRACT(EPOCH FROM NOW() - MIN(created_at) FILTER (WHERE processed_at IS NULL)), 0),
+		       MAX(processed_at)
+		FROM outbox
+		GROUP BY kind
+		ORDER BY kind
+	`
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get outbox stats: %w", err)
+	}
+	defer rows.Close()
+
+	var stats []*models.OutboxStats
+	for rows.Next() {
+		s := &models.OutboxStats{}
+		if err := rows.Scan(&s.Kind, &s.Pending, &s.Abandoned, &s.OldestPendingAt, &s.LagSeconds, &s.LastProcessedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan outbox stats: %w", err)
+		}
+		stats = append(stats, s)
+	}
+	return stats, rows.Err()
+}
@@ -0,0 +1,158 @@
+package repository
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/fraud-detection-system/backend/internal/models"
+	"github.com/fraud-detection-system/backend/internal/utils"
+)
+
+// Cursor is the decoded form of an opaque page cursor: the sort key value
+// and ID of the last row of the previous page
+type Cursor struct {
+	Sort  string    `json:"s"`
+	Desc  bool      `json:"d"`
+	Value string    `json:"v"`
+	ID    uuid.UUID `json:"id"`
+}
+
+// EncodeCursor returns the opaque cursor for the row with the given sort
+// value, which must be a time.Time or float64
+func EncodeCursor(opts models.ListOptions, value interface{}, id uuid.UUID) string {
+	c := Cursor{Sort: opts.Sort, Desc: opts.Desc, ID: id}
+	switch v := value.(type) {
+	case time.Time:
+		c.Value = v.UTC().Format(time.RFC3339Nano)
+	case float64:
+		c.Value = strconv.FormatFloat(v, 'g', -1, 64)
+	default:
+		panic(fmt.Sprintf("unsupported cursor value %T", value))
+	}
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// DecodeCursor parses opts.Cursor, checking it was issued for opts' sort. It
+// returns nil for the first page and utils.ErrInvalidCursor for a malformed
+// or mismatched cursor.
+func DecodeCursor(opts models.ListOptions) (*Cursor, error) {
+	if opts.Cursor == "" {
+		return nil, nil
+	}
+	data, err := base64.RawURLEncoding.DecodeString(opts.Cursor)
+	if err != nil {
+		return nil, utils.ErrInvalidCursor
+	}
+	var c Cursor
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, utils.ErrInvalidCursor
+	}
+	if c.Sort != opts.Sort || c.Desc != opts.Desc {
+		return nil, utils.ErrInvalidCursor
+	}
+	return &c, nil
+}
+
+// Time returns the cursor value of a timestamp sort
+func (c *Cursor) Time() (time.Time, error) {
+	t, err := time.Parse(time.RFC3339Nano, c.Value)
+	if err != nil {
+		return time.Time{}, utils.ErrInvalidCursor
+	}
+	return t, nil
+}
+
+// Float returns the cursor value of a numeric sort
+func (c *Cursor) Float() (float64, error) {
+	f, err := strconv.ParseFloat(c.Value, 64)
+	if err != nil {
+		return 0, utils.ErrInvalidCursor
+	}
+	return f, nil
+}
+
+// ResolveListOptions fills in the default sort and page size and checks the
+// sort is one of sorts, the first of which is the default
+func ResolveListOptions(opts models.ListOptions, sorts ...string) (models.ListOptions, error) {
+	if opts.Sort == "" {
+		opts.Sort = sorts[0]
+	}
+	valid := false
+	for _, s := range sorts {
+		if opts.Sort == s {
+			valid = true
+			break
+		}
+	}
+	if !valid {
+		return opts, utils.ErrInvalidSort
+	}
+	if opts.Limit <= 0 {
+		opts.Limit = models.DefaultPageSize
+	}
+	if opts.Limit > models.MaxPageSize {
+		opts.Limit = models.MaxPageSize
+	}
+	return opts, nil
+}
+
+// listQuery builds the WHERE clause of a filtered listing
+type listQuery struct {
+	conds []string
+	args  []interface{}
+}
+
+// arg binds a value and returns its placeholder
+func (q *listQuery) arg(value interface{}) string {
+	q.args = append(q.args, value)
+	return fmt.Sprintf("$%d", len(q.args))
+}
+
+// add appends a condition whose single ? placeholder is bound to arg
+func (q *listQuery) add(cond string, arg interface{}) {
+	q.conds = append(q.conds, strings.Replace(cond, "?", q.arg(arg), 1))
+}
+
+// addTimeRange bounds column to r
+func (q *listQuery) addTimeRange(column string, r models.TimeRange) {
+	if r.From != nil {
+		q.add(column+" >= ?", *r.From)
+	}
+	if r.To != nil {
+		q.add(column+" < ?", *r.To)
+	}
+}
+
+// after restricts the listing to rows past the cursor in (column, idColumn)
+// order, so the next page starts where the last one ended
+func (q *listQuery) after(column, idColumn string, desc bool, value interface{}, id uuid.UUID) {
+	op := ">"
+	if desc {
+		op = "<"
+	}
+	q.args = append(q.args, value, id)
+	n := len(q.args)
+	q.conds = append(q.conds, fmt.Sprintf("(%s, %s) %s ($%d, $%d)", column, idColumn, op, n-1, n))
+}
+
+func (q *listQuery) where() string {
+	if len(q.conds) == 0 {
+		return ""
+	}
+	return "WHERE " + strings.Join(q.conds, " AND ")
+}
+
+// orderBy returns the ORDER BY clause matching after
+func orderBy(column, idColumn string, desc bool) string {
+	dir := "ASC"
+	if desc {
+		dir = "DESC"
+	}
+	return fmt.Sprintf("ORDER BY %s %s, %s %s", column, dir, idColumn, dir)
+}
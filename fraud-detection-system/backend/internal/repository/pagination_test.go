@@ -0,0 +1,125 @@
+package repository
+
+import (
+	"encoding/base64"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/fraud-detection-system/backend/internal/models"
+	"github.com/fraud-detection-system/backend/internal/utils"
+)
+
+func TestCursorRoundTrip(t *testing.T) {
+	id := uuid.New()
+	at := time.Date(2026, 10, 19, 8, 30, 0, 123456789, time.FixedZone("IST", 19800))
+
+	opts := models.ListOptions{Sort: models.VerificationSortCreatedAt, Desc: true}
+	opts.Cursor = EncodeCursor(opts, at, id)
+	cursor, err := DecodeCursor(opts)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cursor.ID != id {
+		t.Errorf("id: got %s, want %s", cursor.ID, id)
+	}
+	if got, err := cursor.Time(); err != nil || !got.Equal(at) {
+		t.Errorf("time: got %v, %v, want %v", got, err, at)
+	}
+
+	opts = models.ListOptions{Sort: models.VerificationSortFraudScore}
+	opts.Cursor = EncodeCursor(opts, 0.735, id)
+	cursor, err = DecodeCursor(opts)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got, err := cursor.Float(); err != nil || got != 0.735 {
+		t.Errorf("float: got %v, %v, want 0.735", got, err)
+	}
+	if _, err := cursor.Time(); !errors.Is(err, utils.ErrInvalidCursor) {
+		t.Errorf("score cursor as time: expected ErrInvalidCursor, got %v", err)
+	}
+}
+
+func TestDecodeCursor(t *testing.T) {
+	if cursor, err := DecodeCursor(models.ListOptions{}); cursor != nil || err != nil {
+		t.Errorf("empty cursor: got %+v, %v, want first page", cursor, err)
+	}
+
+	issued := models.ListOptions{Sort: models.ReportSortCreatedAt, Desc: true}
+	valid := EncodeCursor(issued, time.Now(), uuid.New())
+	for name, opts := range map[string]models.ListOptions{
+		"not base64":      {Sort: issued.Sort, Desc: true, Cursor: "not a cursor!"},
+		"not json":        {Sort: issued.Sort, Desc: true, Cursor: base64.RawURLEncoding.EncodeToString([]byte("{"))},
+		"different sort":  {Sort: models.ReportSortUpdatedAt, Desc: true, Cursor: valid},
+		"different order": {Sort: issued.Sort, Desc: false, Cursor: valid},
+	} {
+		if _, err := DecodeCursor(opts); !errors.Is(err, utils.ErrInvalidCursor) {
+			t.Errorf("%s: expected ErrInvalidCursor, got %v", name, err)
+		}
+	}
+}
+
+func TestEncodeCursorUnsupportedValue(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("expected a panic for a string sort value")
+		}
+	}()
+	EncodeCursor(models.ListOptions{}, "2026-10-19", uuid.New())
+}
+
+func TestResolveListOptions(t *testing.T) {
+	opts, err := ResolveListOptions(models.ListOptions{}, "created_at", "fraud_score")
+	if err != nil || opts.Sort != "created_at" || opts.Limit != models.DefaultPageSize {
+		t.Errorf("defaults: got %+v, %v", opts, err)
+	}
+	opts, err = ResolveListOptions(models.ListOptions{Sort: "fraud_score", Limit: models.MaxPageSize + 1}, "created_at", "fraud_score")
+	if err != nil || opts.Sort != "fraud_score" || opts.Limit != models.MaxPageSize {
+		t.Errorf("capped limit: got %+v, %v", opts, err)
+	}
+	if _, err := ResolveListOptions(models.ListOptions{Sort: "content"}, "created_at"); !errors.Is(err, utils.ErrInvalidSort) {
+		t.Errorf("unknown sort: expected ErrInvalidSort, got %v", err)
+	}
+}
+
+func TestListQuery(t *testing.T) {
+	var empty listQuery
+	if where := empty.where(); where != "" {
+		t.Errorf("no conditions: got %q", where)
+	}
+
+	userID, cursorID := uuid.New(), uuid.New()
+	from := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 1, 0)
+	at := from.Add(time.Hour)
+
+	var q listQuery
+	q.add("v.user_id = ?", userID)
+	q.addTimeRange("v.created_at", models.TimeRange{From: &from})
+	q.addTimeRange("v.created_at", models.TimeRange{To: &to})
+	q.after("v.created_at", "v.id", true, at, cursorID)
+
+	want := "WHERE v.user_id = $1 AND v.created_at >= $2 AND v.created_at < $3 AND (v.created_at, v.id) < ($4, $5)"
+	if got := q.where(); got != want {
+		t.Errorf("where:\n got %s\nwant %s", got, want)
+	}
+	if wantArgs := []interface{}{userID, from, to, at, cursorID}; !reflect.DeepEqual(q.args, wantArgs) {
+		t.Errorf("args: got %v, want %v", q.args, wantArgs)
+	}
+
+	var asc listQuery
+	asc.after("fraud_score", "id", false, 0.5, cursorID)
+	if got := asc.where(); got != "WHERE (fraud_score, id) > ($1, $2)" {
+		t.Errorf("ascending after: got %s", got)
+	}
+
+	if got := orderBy("v.created_at", "v.id", true); got != "ORDER BY v.created_at DESC, v.id DESC" {
+		t.Errorf("orderBy desc: got %s", got)
+	}
+	if got := orderBy("fraud_score", "id", false); got != "ORDER BY fraud_score ASC, id ASC" {
+		t.Errorf("orderBy asc: got %s", got)
+	}
+}
@@ -0,0 +1,94 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/fraud-detection-system/backend/internal/models"
+	"github.com/fraud-detection-system/backend/internal/tracing"
+)
+
+type PasswordResetRepository struct {
+	db DBTX
+}
+
+func NewPasswordResetRepository(db *sql.DB) *PasswordResetRepository {
+	return &PasswordResetRepository{db: db}
+}
+
+// WithTx returns a copy of the repository that runs its queries in tx
+func (r *PasswordResetRepository) WithTx(tx *sql.Tx) *PasswordResetRepository {
+	return &PasswordResetRepository{db: tx}
+}
+
+// Create stores a new password reset token
+func (r *PasswordResetRepository) Create(ctx context.Context, token *models.PasswordResetToken) (err error) {
+	ctx, span := startSpan(ctx, "PasswordResetRepository.Create")
+	defer func() { tracing.End(span, err) }()
+
+	query := `
+		INSERT INTO password_reset_tokens (id, user_id, token_hash, expires_at, created_at)
+		VALUES ($1, $2, $3, $4, $5)
+	`
+	_, err = r.db.ExecContext(ctx, query,
+		token.ID, token.UserID, token.TokenHash, token.ExpiresAt, token.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create password reset token: %w", err)
+	}
+	return nil
+}
+
+// Consume marks an unused, unexpired token as used and returns it.
+// The update is a single statement so a token can only be redeemed once.
+func (r *PasswordResetRepository) Consume(ctx context.Context, tokenHash string) (_ *models.PasswordResetToken, err error) {
+	ctx, span := startSpan(ctx, "PasswordResetRepository.Consume")
+	defer func() { tracing.End(span, err) }()
+
+	query := `
+		UPDATE password_reset_tokens
+		SET used_at = $2
+		WHERE token_hash = $1 AND used_at IS NULL AND expires_at > $2
+		RETURNING id, user_id, token_hash, expires_at, used_at, created_at
+	`
+	var token models.PasswordResetToken
+	err = r.db.QueryRowContext(ctx, query, tokenHash, time.Now()).Scan(
+		&token.ID, &token.UserID, &token.TokenHash, &token.ExpiresAt, &token.UsedAt, &token.CreatedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("password reset token not found")
+		}
+		return nil, fmt.Errorf("failed to consume password reset token: %w", err)
+	}
+	return &token, nil
+}
+
+// InvalidateForUser marks every outstanding token for a user as used
+func (r *PasswordResetRepository) InvalidateForUser(ctx context.Context, userID uuid.UUID) (err error) {
+	ctx, span := startSpan(ctx, "PasswordResetRepository.InvalidateForUser")
+	defer func() { tracing.End(span, err) }()
+
+	query := `UPDATE password_reset_tokens SET used_at = $2 WHERE user_id = $1 AND used_at IS NULL`
+	_, err = r.db.ExecContext(ctx, query, userID, time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to invalidate password reset tokens: %w", err)
+	}
+	return nil
+}
+
+// DeleteExpired removes tokens that expired before the given time
+func (r *PasswordResetRepository) DeleteExpired(ctx context.Context, before time.Time) (_ int64, err error) {
+	ctx, span := startSpan(ctx, "PasswordResetRepository.DeleteExpired")
+	defer func() { tracing.End(span, err) }()
+
+	query := `DELETE FROM password_reset_tokens WHERE expires_at < $1`
+	result, err := r.db.ExecContext(ctx, query, before)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete expired password reset tokens: %w", err)
+	}
+	return result.RowsAffected()
+}
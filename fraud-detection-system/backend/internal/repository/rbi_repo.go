@@ -9,28 +9,41 @@ import (
 	"github.com/google/uuid"
 	"github.com/lib/pq"
 	"github.com/fraud-detection-system/backend/internal/models"
+	"github.com/fraud-detection-system/backend/internal/utils"
+	"github.com/fraud-detection-system/backend/internal/tracing"
 )
 
 type RBIRepository struct {
-	db *sql.DB
+	db DBTX
 }
 
 func NewRBIRepository(db *sql.DB) *RBIRepository {
 	return &RBIRepository{db: db}
 }
 
+// WithTx returns a copy of the repository that runs its queries in tx
+func (r *RBIRepository) WithTx(tx *sql.Tx) *RBIRepository {
+	return &RBIRepository{db: tx}
+}
+
 // CreateCircular creates a new RBI circular
-func (r *RBIRepository) CreateCircular(ctx context.Context, circular *models.RBICircular) error {
+func (r *RBIRepository) CreateCircular(ctx context.Context, circular *models.RBICircular) (err error) {
+	ctx, span := startSpan(ctx, "RBIRepository.CreateCircular")
+	defer func() { tracing.End(span, err) }()
+
 	query := `
 		INSERT INTO rbi_circulars (id, circular_number, title, content, issued_date, effective_date,
 		                           expiry_date, category, keywords, is_active, source_url, created_at, updated_at)
 		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
 	`
-	_, err := r.db.ExecContext(ctx, query,
+	_, err = r.db.ExecContext(ctx, query,
 		circular.ID, circular.CircularNumber, circular.Title, circular.Content, circular.IssuedDate,
 		circular.EffectiveDate, circular.ExpiryDate, circular.Category, pq.Array(circular.Keywords),
 		circular.IsActive, circular.SourceURL, circular.CreatedAt, circular.UpdatedAt,
 	)
+	if isUniqueViolation(err) {
+		return utils.ErrAlreadyExists
+	}
 	if err != nil {
 		return fmt.Errorf("failed to create circular: %w", err)
 	}
@@ -38,7 +51,10 @@ func (r *RBIRepository) CreateCircular(ctx context.Context, circular *models.RBI
 }
 
 // GetCircularByID retrieves a circular by ID
-func (r *RBIRepository) GetCircularByID(ctx context.Context, id uuid.UUID) (*models.RBICircular, error) {
+func (r *RBIRepository) GetCircularByID(ctx context.Context, id uuid.UUID) (_ *models.RBICircular, err error) {
+	ctx, span := startSpan(ctx, "RBIRepository.GetCircularByID")
+	defer func() { tracing.End(span, err) }()
+
 	query := `
 		SELECT id, circular_number, title, content, issued_date, effective_date, expiry_date,
 		       category, keywords, is_active, source_url, created_at, updated_at
@@ -46,7 +62,7 @@ func (r *RBIRepository) GetCircularByID(ctx context.Context, id uuid.UUID) (*mod
 		WHERE id = $1
 	`
 	var circular models.RBICircular
-	err := r.db.QueryRowContext(ctx, query, id).Scan(
+	err = r.db.QueryRowContext(ctx, query, id).Scan(
 		&circular.ID, &circular.CircularNumber, &circular.Title, &circular.Content, &circular.IssuedDate,
 		&circular.EffectiveDate, &circular.ExpiryDate, &circular.Category, pq.Array(&circular.Keywords),
 		&circular.IsActive, &circular.SourceURL, &circular.CreatedAt, &circular.UpdatedAt,
@@ -61,7 +77,10 @@ func (r *RBIRepository) GetCircularByID(ctx context.Context, id uuid.UUID) (*mod
 }
 
 // GetActiveCirculars retrieves all active circulars
-func (r *RBIRepository) GetActiveCirculars(ctx context.Context) ([]*models.RBICircular, error) {
+func (r *RBIRepository) GetActiveCirculars(ctx context.Context) (_ []*models.RBICircular, err error) {
+	ctx, span := startSpan(ctx, "RBIRepository.GetActiveCirculars")
+	defer func() { tracing.End(span, err) }()
+
 	query := `
 		SELECT id, circular_number, title, content, issued_date, effective_date, expiry_date,
 		       category, keywords, is_active, source_url, created_at, updated_at
@@ -93,7 +112,10 @@ func (r *RBIRepository) GetActiveCirculars(ctx context.Context) ([]*models.RBICi
 }
 
 // SearchCircularsByKeywords searches circulars by keywords
-func (r *RBIRepository) SearchCircularsByKeywords(ctx context.Context, keywords []string) ([]*models.RBICircular, error) {
+func (r *RBIRepository) SearchCircularsByKeywords(ctx context.Context, keywords []string) (_ []*models.RBICircular, err error) {
+	ctx, span := startSpan(ctx, "RBIRepository.SearchCircularsByKeywords")
+	defer func() { tracing.End(span, err) }()
+
 	query := `
 		SELECT id, circular_number, title, content, issued_date, effective_date, expiry_date,
 		       category, keywords, is_active, source_url, created_at, updated_at
@@ -105,7 +127,7 @@ func (r *RBIRepository) SearchCircularsByKeywords(ctx context.Context, keywords
 		)
 		ORDER BY issued_date DESC
 	`
-	
+
 	// Prepare LIKE patterns
 	likePatterns := make([]string, len(keywords))
 	for i, kw := range keywords {
@@ -136,18 +158,24 @@ func (r *RBIRepository) SearchCircularsByKeywords(ctx context.Context, keywords
 }
 
 // CreateSenderRegistry creates a new sender registry entry
-func (r *RBIRepository) CreateSenderRegistry(ctx context.Context, sender *models.SenderRegistry) error {
+func (r *RBIRepository) CreateSenderRegistry(ctx context.Context, sender *models.SenderRegistry) (err error) {
+	ctx, span := startSpan(ctx, "RBIRepository.CreateSenderRegistry")
+	defer func() { tracing.End(span, err) }()
+
 	query := `
 		INSERT INTO sender_registry (id, sender_id, bank_name, bank_code, is_verified, is_active,
 		                             verified_by, telecom_operator, registration_date, last_verified_at,
 		                             reputation_score, message_count, fraud_report_count, created_at, updated_at)
 		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)
 	`
-	_, err := r.db.ExecContext(ctx, query,
+	_, err = r.db.ExecContext(ctx, query,
 		sender.ID, sender.SenderID, sender.BankName, sender.BankCode, sender.IsVerified, sender.IsActive,
 		sender.VerifiedBy, sender.TelecomOperator, sender.RegistrationDate, sender.LastVerifiedAt,
 		sender.ReputationScore, sender.MessageCount, sender.FraudReportCount, sender.CreatedAt, sender.UpdatedAt,
 	)
+	if isUniqueViolation(err) {
+		return utils.ErrAlreadyExists
+	}
 	if err != nil {
 		return fmt.Errorf("failed to create sender registry: %w", err)
 	}
@@ -155,7 +183,10 @@ func (r *RBIRepository) CreateSenderRegistry(ctx context.Context, sender *models
 }
 
 // GetSenderBySenderID retrieves a sender by sender ID
-func (r *RBIRepository) GetSenderBySenderID(ctx context.Context, senderID string) (*models.SenderRegistry, error) {
+func (r *RBIRepository) GetSenderBySenderID(ctx context.Context, senderID string) (_ *models.SenderRegistry, err error) {
+	ctx, span := startSpan(ctx, "RBIRepository.GetSenderBySenderID")
+	defer func() { tracing.End(span, err) }()
+
 	query := `
 		SELECT id, sender_id, bank_name, bank_code, is_verified, is_active, verified_by,
 		       telecom_operator, registration_date, last_verified_at, reputation_score,
@@ -164,7 +195,7 @@ func (r *RBIRepository) GetSenderBySenderID(ctx context.Context, senderID string
 		WHERE sender_id = $1
 	`
 	var sender models.SenderRegistry
-	err := r.db.QueryRowContext(ctx, query, senderID).Scan(
+	err = r.db.QueryRowContext(ctx, query, senderID).Scan(
 		&sender.ID, &sender.SenderID, &sender.BankName, &sender.BankCode, &sender.IsVerified,
 		&sender.IsActive, &sender.VerifiedBy, &sender.TelecomOperator, &sender.RegistrationDate,
 		&sender.LastVerifiedAt, &sender.ReputationScore, &sender.MessageCount, &sender.FraudReportCount,
@@ -179,32 +210,49 @@ func (r *RBIRepository) GetSenderBySenderID(ctx context.Context, senderID string
 	return &sender, nil
 }
 
-// UpdateSenderStats updates sender statistics
-func (r *RBIRepository) UpdateSenderStats(ctx context.Context, senderID string, isFraud bool) error {
+// UpdateSenderStats updates sender statistics and returns the sender's
+// reputation before the update along with the updated sender. It returns a
+// nil sender when the sender is not registered.
+func (r *RBIRepository) UpdateSenderStats(ctx context.Context, senderID string, isFraud bool) (_ float64, _ *models.SenderRegistry, err error) {
+	ctx, span := startSpan(ctx, "RBIRepository.UpdateSenderStats")
+	defer func() { tracing.End(span, err) }()
+
 	query := `
-		UPDATE sender_registry
-		SET message_count = message_count + 1,
-		    fraud_report_count = fraud_report_count + $2,
+		UPDATE sender_registry s
+		SET message_count = s.message_count + 1,
+		    fraud_report_count = s.fraud_report_count + $2,
 		    reputation_score = CASE 
-		        WHEN $2 > 0 THEN GREATEST(reputation_score - 0.1, 0)
-		        ELSE LEAST(reputation_score + 0.01, 1.0)
+		        WHEN $2 > 0 THEN GREATEST(s.reputation_score - 0.1, 0)
+		        ELSE LEAST(s.reputation_score + 0.01, 1.0)
 		    END,
 		    updated_at = NOW()
-		WHERE sender_id = $1
+		FROM (SELECT id, reputation_score FROM sender_registry WHERE sender_id = $1) prev
+		WHERE s.id = prev.id
+		RETURNING prev.reputation_score, s.sender_id, s.reputation_score, s.message_count, s.fraud_report_count
 	`
 	fraudIncrement := 0
 	if isFraud {
 		fraudIncrement = 1
 	}
-	_, err := r.db.ExecContext(ctx, query, senderID, fraudIncrement)
+	var previous float64
+	var sender models.SenderRegistry
+	err = r.db.QueryRowContext(ctx, query, senderID, fraudIncrement).Scan(
+		&previous, &sender.SenderID, &sender.ReputationScore, &sender.MessageCount, &sender.FraudReportCount,
+	)
+	if err == sql.ErrNoRows {
+		return 0, nil, nil
+	}
 	if err != nil {
-		return fmt.Errorf("failed to update sender stats: %w", err)
+		return 0, nil, fmt.Errorf("failed to update sender stats: %w", err)
 	}
-	return nil
+	return previous, &sender, nil
 }
 
 // GetVerifiedSenders retrieves all verified senders
-func (r *RBIRepository) GetVerifiedSenders(ctx context.Context) ([]*models.SenderRegistry, error) {
+func (r *RBIRepository) GetVerifiedSenders(ctx context.Context) (_ []*models.SenderRegistry, err error) {
+	ctx, span := startSpan(ctx, "RBIRepository.GetVerifiedSenders")
+	defer func() { tracing.End(span, err) }()
+
 	query := `
 		SELECT id, sender_id, bank_name, bank_code, is_verified, is_active, verified_by,
 		       telecom_operator, registration_date, last_verified_at, reputation_score,
@@ -236,4 +284,3 @@ func (r *RBIRepository) GetVerifiedSenders(ctx context.Context) ([]*models.Sende
 
 	return senders, nil
 }
-
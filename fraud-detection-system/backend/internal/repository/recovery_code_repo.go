@@ -0,0 +1,95 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/fraud-detection-system/backend/internal/tracing"
+)
+
+type RecoveryCodeRepository struct {
+	db DBTX
+}
+
+func NewRecoveryCodeRepository(db *sql.DB) *RecoveryCodeRepository {
+	return &RecoveryCodeRepository{db: db}
+}
+
+// WithTx returns a copy of the repository that runs its queries in tx
+func (r *RecoveryCodeRepository) WithTx(tx *sql.Tx) *RecoveryCodeRepository {
+	return &RecoveryCodeRepository{db: tx}
+}
+
+// ReplaceForUser deletes a user's recovery codes and stores the new hashes
+func (r *RecoveryCodeRepository) ReplaceForUser(ctx context.Context, userID uuid.UUID, codeHashes []string) (err error) {
+	ctx, span := startSpan(ctx, "RecoveryCodeRepository.ReplaceForUser")
+	defer func() { tracing.End(span, err) }()
+
+	return withTx(ctx, r.db, func(tx DBTX) error {
+		if _, err := tx.ExecContext(ctx, `DELETE FROM user_recovery_codes WHERE user_id = $1`, userID); err != nil {
+			return fmt.Errorf("failed to delete recovery codes: %w", err)
+		}
+
+		query := `
+			INSERT INTO user_recovery_codes (id, user_id, code_hash, created_at)
+			VALUES ($1, $2, $3, $4)
+		`
+		now := time.Now()
+		for _, hash := range codeHashes {
+			if _, err := tx.ExecContext(ctx, query, uuid.New(), userID, hash, now); err != nil {
+				return fmt.Errorf("failed to create recovery code: %w", err)
+			}
+		}
+		return nil
+	})
+}
+
+// Consume marks an unused recovery code as used. It reports false if no
+// matching unused code exists.
+func (r *RecoveryCodeRepository) Consume(ctx context.Context, userID uuid.UUID, codeHash string) (_ bool, err error) {
+	ctx, span := startSpan(ctx, "RecoveryCodeRepository.Consume")
+	defer func() { tracing.End(span, err) }()
+
+	query := `
+		UPDATE user_recovery_codes
+		SET used_at = $3
+		WHERE user_id = $1 AND code_hash = $2 AND used_at IS NULL
+	`
+	result, err := r.db.ExecContext(ctx, query, userID, codeHash, time.Now())
+	if err != nil {
+		return false, fmt.Errorf("failed to consume recovery code: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to consume recovery code: %w", err)
+	}
+	return rows == 1, nil
+}
+
+// CountUnused returns the number of recovery codes a user has left
+func (r *RecoveryCodeRepository) CountUnused(ctx context.Context, userID uuid.UUID) (_ int, err error) {
+	ctx, span := startSpan(ctx, "RecoveryCodeRepository.CountUnused")
+	defer func() { tracing.End(span, err) }()
+
+	query := `SELECT COUNT(*) FROM user_recovery_codes WHERE user_id = $1 AND used_at IS NULL`
+	var count int
+	if err := r.db.QueryRowContext(ctx, query, userID).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count recovery codes: %w", err)
+	}
+	return count, nil
+}
+
+// DeleteForUser removes all recovery codes for a user
+func (r *RecoveryCodeRepository) DeleteForUser(ctx context.Context, userID uuid.UUID) (err error) {
+	ctx, span := startSpan(ctx, "RecoveryCodeRepository.DeleteForUser")
+	defer func() { tracing.End(span, err) }()
+
+	_, err = r.db.ExecContext(ctx, `DELETE FROM user_recovery_codes WHERE user_id = $1`, userID)
+	if err != nil {
+		return fmt.Errorf("failed to delete recovery codes: %w", err)
+	}
+	return nil
+}
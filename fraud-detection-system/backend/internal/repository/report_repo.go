@@ -4,30 +4,79 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"time"
 
 	"github.com/google/uuid"
+	"github.com/fraud-detection-system/backend/internal/encryption"
 	"github.com/fraud-detection-system/backend/internal/models"
+	"github.com/fraud-detection-system/backend/internal/tracing"
 )
 
+// ReportRepository stores reports with the reported content encrypted at
+// rest and a keyed hash of it alongside for lookups
 type ReportRepository struct {
-	db *sql.DB
+	db     DBTX
+	reader DBTX
+	cipher *encryption.FieldCipher
 }
 
-func NewReportRepository(db *sql.DB) *ReportRepository {
-	return &ReportRepository{db: db}
+func NewReportRepository(db *sql.DB, cipher *encryption.FieldCipher) *ReportRepository {
+	return &ReportRepository{db: db, reader: db, cipher: cipher}
+}
+
+// WithTx returns a copy of the repository that runs its queries in tx
+func (r *ReportRepository) WithTx(tx *sql.Tx) *ReportRepository {
+	return &ReportRepository{db: tx, reader: tx, cipher: r.cipher}
+}
+
+// WithReader returns a copy of the repository that runs listing and
+// aggregate queries on reader, which may lag behind the primary
+func (r *ReportRepository) WithReader(reader DBTX) *ReportRepository {
+	return &ReportRepository{db: r.db, reader: reader, cipher: r.cipher}
+}
+
+// encrypt returns the stored form of the report content and fills in its
+// hash and key ID
+func (r *ReportRepository) encrypt(report *models.Report) (string, error) {
+	content, keyID, err := r.cipher.Encrypt(report.Content)
+	if err != nil {
+		return "", fmt.Errorf("failed to encrypt report content: %w", err)
+	}
+	contentHash := r.cipher.Hash(report.Content)
+	report.ContentHash = &contentHash
+	report.EncryptionKeyID = &keyID
+	return content, nil
+}
+
+// decrypt replaces the stored report content with plaintext
+func (r *ReportRepository) decrypt(report *models.Report) error {
+	content, err := r.cipher.Decrypt(report.Content)
+	if err != nil {
+		return fmt.Errorf("failed to decrypt report content: %w", err)
+	}
+	report.Content = content
+	return nil
 }
 
 // Create creates a new report
-func (r *ReportRepository) Create(ctx context.Context, report *models.Report) error {
+func (r *ReportRepository) Create(ctx context.Context, report *models.Report) (err error) {
+	ctx, span := startSpan(ctx, "ReportRepository.Create")
+	defer func() { tracing.End(span, err) }()
+
 	query := `
 		INSERT INTO reports (id, user_id, message_id, verification_id, report_type, content,
-		                     sender_header, description, status, priority, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+		                     sender_header, description, status, priority, content_hash,
+		                     encryption_key_id, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
 	`
-	_, err := r.db.ExecContext(ctx, query,
+	content, err := r.encrypt(report)
+	if err != nil {
+		return err
+	}
+	_, err = r.db.ExecContext(ctx, query,
 		report.ID, report.UserID, report.MessageID, report.VerificationID, report.ReportType,
-		report.Content, report.SenderHeader, report.Description, report.Status, report.Priority,
-		report.CreatedAt, report.UpdatedAt,
+		content, report.SenderHeader, report.Description, report.Status, report.Priority,
+		report.ContentHash, report.EncryptionKeyID, report.CreatedAt, report.UpdatedAt,
 	)
 	if err != nil {
 		return fmt.Errorf("failed to create report: %w", err)
@@ -36,7 +85,10 @@ func (r *ReportRepository) Create(ctx context.Context, report *models.Report) er
 }
 
 // GetByID retrieves a report by ID
-func (r *ReportRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Report, error) {
+func (r *ReportRepository) GetByID(ctx context.Context, id uuid.UUID) (_ *models.Report, err error) {
+	ctx, span := startSpan(ctx, "ReportRepository.GetByID")
+	defer func() { tracing.End(span, err) }()
+
 	query := `
 		SELECT id, user_id, message_id, verification_id, report_type, content, sender_header,
 		       description, status, priority, reviewed_by, reviewed_at, review_notes,
@@ -45,7 +97,7 @@ func (r *ReportRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.R
 		WHERE id = $1
 	`
 	var report models.Report
-	err := r.db.QueryRowContext(ctx, query, id).Scan(
+	err = r.db.QueryRowContext(ctx, query, id).Scan(
 		&report.ID, &report.UserID, &report.MessageID, &report.VerificationID, &report.ReportType,
 		&report.Content, &report.SenderHeader, &report.Description, &report.Status, &report.Priority,
 		&report.ReviewedBy, &report.ReviewedAt, &report.ReviewNotes, &report.CreatedAt, &report.UpdatedAt,
@@ -56,11 +108,17 @@ func (r *ReportRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.R
 		}
 		return nil, fmt.Errorf("failed to get report: %w", err)
 	}
+	if err := r.decrypt(&report); err != nil {
+		return nil, err
+	}
 	return &report, nil
 }
 
 // GetByUserID retrieves reports by user ID
-func (r *ReportRepository) GetByUserID(ctx context.Context, userID uuid.UUID, limit, offset int) ([]*models.Report, error) {
+func (r *ReportRepository) GetByUserID(ctx context.Context, userID uuid.UUID, limit, offset int) (_ []*models.Report, err error) {
+	ctx, span := startSpan(ctx, "ReportRepository.GetByUserID")
+	defer func() { tracing.End(span, err) }()
+
 	query := `
 		SELECT id, user_id, message_id, verification_id, report_type, content, sender_header,
 		       description, status, priority, reviewed_by, reviewed_at, review_notes,
@@ -70,7 +128,7 @@ func (r *ReportRepository) GetByUserID(ctx context.Context, userID uuid.UUID, li
 		ORDER BY created_at DESC
 		LIMIT $2 OFFSET $3
 	`
-	rows, err := r.db.QueryContext(ctx, query, userID, limit, offset)
+	rows, err := r.reader.QueryContext(ctx, query, userID, limit, offset)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get reports: %w", err)
 	}
@@ -87,14 +145,127 @@ func (r *ReportRepository) GetByUserID(ctx context.Context, userID uuid.UUID, li
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan report: %w", err)
 		}
+		if err := r.decrypt(&report); err != nil {
+			return nil, err
+		}
 		reports = append(reports, &report)
 	}
 
 	return reports, nil
 }
 
+// ListByUserID returns a page of a user's reports matching filter in keyset
+// order of opts.Sort then ID, along with the total number of matches
+func (r *ReportRepository) ListByUserID(ctx context.Context, userID uuid.UUID, filter models.ReportFilter, opts models.ListOptions) (_ []*models.Report, _ *models.PageInfo, err error) {
+	ctx, span := startSpan(ctx, "ReportRepository.ListByUserID")
+	defer func() { tracing.End(span, err) }()
+
+	opts, err = ResolveListOptions(opts, models.ReportSortCreatedAt, models.ReportSortUpdatedAt)
+	if err != nil {
+		return nil, nil, err
+	}
+	cursor, err := DecodeCursor(opts)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var q listQuery
+	q.add("user_id = ?", userID)
+	if filter.ReportType != "" {
+		q.add("report_type = ?", filter.ReportType)
+	}
+	if filter.Status != "" {
+		q.add("status = ?", filter.Status)
+	}
+	if filter.Priority != "" {
+		q.add("priority = ?", filter.Priority)
+	}
+	if filter.SenderHeader != "" {
+		q.add("sender_header = ?", filter.SenderHeader)
+	}
+	q.addTimeRange("created_at", filter.Created)
+
+	page := &models.PageInfo{Limit: opts.Limit}
+	var reports []*models.Report
+	// The count and the page are read from one snapshot, so the total
+	// matches the rows returned
+	err = readSnapshot(ctx, r.reader, func(db DBTX) error {
+		countQuery := fmt.Sprintf(`SELECT COUNT(*) FROM reports %s`, q.where())
+		if err := db.QueryRowContext(ctx, countQuery, q.args...).Scan(&page.Total); err != nil {
+			return fmt.Errorf("failed to count reports: %w", err)
+		}
+
+		if cursor != nil {
+			value, err := cursor.Time()
+			if err != nil {
+				return err
+			}
+			q.after(opts.Sort, "id", opts.Desc, value, cursor.ID)
+		}
+
+		// One extra row tells whether there is another page
+		query := fmt.Sprintf(`
+			SELECT id, user_id, message_id, verification_id, report_type, content, sender_header,
+			       description, status, priority, reviewed_by, reviewed_at, review_notes,
+			       created_at, updated_at
+			FROM reports
+			%s
+			%s
+			LIMIT %d
+		`, q.where(), orderBy(opts.Sort, "id", opts.Desc), opts.Limit+1)
+		rows, err := db.QueryContext(ctx, query, q.args...)
+		if err != nil {
+			return fmt.Errorf("failed to list reports: %w", err)
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var report models.Report
+			err := rows.Scan(
+				&report.ID, &report.UserID, &report.MessageID, &report.VerificationID, &report.ReportType,
+				&report.Content, &report.SenderHeader, &report.Description, &report.Status, &report.Priority,
+				&report.ReviewedBy, &report.ReviewedAt, &report.ReviewNotes, &report.CreatedAt, &report.UpdatedAt,
+			)
+			if err != nil {
+				return fmt.Errorf("failed to scan report: %w", err)
+			}
+			if err := r.decrypt(&report); err != nil {
+				return err
+			}
+			reports = append(reports, &report)
+		}
+		if err := rows.Err(); err != nil {
+			return fmt.Errorf("failed to list reports: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if len(reports) > opts.Limit {
+		reports = reports[:opts.Limit]
+		last := reports[len(reports)-1]
+		page.HasMore = true
+		page.NextCursor = EncodeCursor(opts, ReportSortValue(last, opts.Sort), last.ID)
+	}
+	page.Count = len(reports)
+	return reports, page, nil
+}
+
+// ReportSortValue returns the value of a report's sort key
+func ReportSortValue(report *models.Report, sort string) interface{} {
+	if sort == models.ReportSortUpdatedAt {
+		return report.UpdatedAt
+	}
+	return report.CreatedAt
+}
+
 // GetByStatus retrieves reports by status
-func (r *ReportRepository) GetByStatus(ctx context.Context, status string, limit, offset int) ([]*models.Report, error) {
+func (r *ReportRepository) GetByStatus(ctx context.Context, status string, limit, offset int) (_ []*models.Report, err error) {
+	ctx, span := startSpan(ctx, "ReportRepository.GetByStatus")
+	defer func() { tracing.End(span, err) }()
+
 	query := `
 		SELECT id, user_id, message_id, verification_id, report_type, content, sender_header,
 		       description, status, priority, reviewed_by, reviewed_at, review_notes,
@@ -104,7 +275,7 @@ func (r *ReportRepository) GetByStatus(ctx context.Context, status string, limit
 		ORDER BY created_at DESC
 		LIMIT $2 OFFSET $3
 	`
-	rows, err := r.db.QueryContext(ctx, query, status, limit, offset)
+	rows, err := r.reader.QueryContext(ctx, query, status, limit, offset)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get reports: %w", err)
 	}
@@ -121,21 +292,71 @@ func (r *ReportRepository) GetByStatus(ctx context.Context, status string, limit
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan report: %w", err)
 		}
+		if err := r.decrypt(&report); err != nil {
+			return nil, err
+		}
 		reports = append(reports, &report)
 	}
 
 	return reports, nil
 }
 
+// GetLabeledBatch retrieves up to limit fraud and false positive reports with
+// id greater than afterID, in id order. Only reports a reviewer has confirmed
+// count, and anonymized ones have no content left. They are the labeled
+// examples for training the embedded classifier.
+func (r *ReportRepository) GetLabeledBatch(ctx context.Context, afterID uuid.UUID, limit int) (_ []*models.Report, err error) {
+	ctx, span := startSpan(ctx, "ReportRepository.GetLabeledBatch")
+	defer func() { tracing.End(span, err) }()
+
+	query := `
+		SELECT id, user_id, message_id, verification_id, report_type, content, sender_header,
+		       description, status, priority, reviewed_by, reviewed_at, review_notes,
+		       created_at, updated_at
+		FROM reports
+		WHERE id > $1 AND report_type IN ('FRAUD', 'FALSE_POSITIVE')
+		  AND status IN ('REVIEWED', 'RESOLVED') AND content_hash IS NOT NULL
+		ORDER BY id
+		LIMIT $2
+	`
+	rows, err := r.db.QueryContext(ctx, query, afterID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get labeled reports: %w", err)
+	}
+	defer rows.Close()
+
+	var reports []*models.Report
+	for rows.Next() {
+		var report models.Report
+		err := rows.Scan(
+			&report.ID, &report.UserID, &report.MessageID, &report.VerificationID, &report.ReportType,
+			&report.Content, &report.SenderHeader, &report.Description, &report.Status, &report.Priority,
+			&report.ReviewedBy, &report.ReviewedAt, &report.ReviewNotes, &report.CreatedAt, &report.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan report: %w", err)
+		}
+		if err := r.decrypt(&report); err != nil {
+			return nil, err
+		}
+		reports = append(reports, &report)
+	}
+
+	return reports, rows.Err()
+}
+
 // Update updates a report
-func (r *ReportRepository) Update(ctx context.Context, report *models.Report) error {
+func (r *ReportRepository) Update(ctx context.Context, report *models.Report) (err error) {
+	ctx, span := startSpan(ctx, "ReportRepository.Update")
+	defer func() { tracing.End(span, err) }()
+
 	query := `
 		UPDATE reports
 		SET status = $2, priority = $3, reviewed_by = $4, reviewed_at = $5,
 		    review_notes = $6, updated_at = $7
 		WHERE id = $1
 	`
-	_, err := r.db.ExecContext(ctx, query,
+	_, err = r.db.ExecContext(ctx, query,
 		report.ID, report.Status, report.Priority, report.ReviewedBy,
 		report.ReviewedAt, report.ReviewNotes, report.UpdatedAt,
 	)
@@ -146,7 +367,10 @@ func (r *ReportRepository) Update(ctx context.Context, report *models.Report) er
 }
 
 // GetStats retrieves report statistics
-func (r *ReportRepository) GetStats(ctx context.Context) (*models.ReportStats, error) {
+func (r *ReportRepository) GetStats(ctx context.Context) (_ *models.ReportStats, err error) {
+	ctx, span := startSpan(ctx, "ReportRepository.GetStats")
+	defer func() { tracing.End(span, err) }()
+
 	query := `
 		SELECT 
 			COUNT(*) as total,
@@ -160,7 +384,8 @@ func (r *ReportRepository) GetStats(ctx context.Context) (*models.ReportStats, e
 	stats.ByType = make(map[string]int)
 	stats.ByPriority = make(map[string]int)
 
-	err := r.db.QueryRowContext(ctx, query).Scan(
+	reader := pin(r.reader)
+	err = reader.QueryRowContext(ctx, query).Scan(
 		&stats.TotalReports,
 		&stats.PendingReports,
 		&stats.ResolvedReports,
@@ -173,7 +398,7 @@ func (r *ReportRepository) GetStats(ctx context.Context) (*models.ReportStats, e
 
 	// Get by type
 	typeQuery := `SELECT report_type, COUNT(*) FROM reports GROUP BY report_type`
-	rows, err := r.db.QueryContext(ctx, typeQuery)
+	rows, err := reader.QueryContext(ctx, typeQuery)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get type stats: %w", err)
 	}
@@ -190,7 +415,7 @@ func (r *ReportRepository) GetStats(ctx context.Context) (*models.ReportStats, e
 
 	// Get by priority
 	priorityQuery := `SELECT priority, COUNT(*) FROM reports GROUP BY priority`
-	rows, err = r.db.QueryContext(ctx, priorityQuery)
+	rows, err = reader.QueryContext(ctx, priorityQuery)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get priority stats: %w", err)
 	}
@@ -208,3 +433,99 @@ func (r *ReportRepository) GetStats(ctx context.Context) (*models.ReportStats, e
 	return &stats, nil
 }
 
+// CountByContent returns how many reports have been filed for the given content
+func (r *ReportRepository) CountByContent(ctx context.Context, content string) (_ int, err error) {
+	ctx, span := startSpan(ctx, "ReportRepository.CountByContent")
+	defer func() { tracing.End(span, err) }()
+
+	query := `SELECT COUNT(*) FROM reports WHERE content_hash = $1`
+	var count int
+	if err := r.db.QueryRowContext(ctx, query, r.cipher.Hash(content)).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count reports: %w", err)
+	}
+	return count, nil
+}
+
+// AnonymizeByUserID strips the content of reports filed by a user and
+// detaches them, and clears the user as reviewer on any other reports
+func (r *ReportRepository) AnonymizeByUserID(ctx context.Context, userID uuid.UUID) (_ int64, err error) {
+	ctx, span := startSpan(ctx, "ReportRepository.AnonymizeByUserID")
+	defer func() { tracing.End(span, err) }()
+
+	query := `
+		UPDATE reports
+		SET content = '[deleted]', description = '[deleted]', content_hash = NULL,
+		    encryption_key_id = NULL, user_id = NULL, updated_at = $2
+		WHERE user_id = $1
+	`
+	result, err := r.db.ExecContext(ctx, query, userID, time.Now())
+	if err != nil {
+		return 0, fmt.Errorf("failed to anonymize reports: %w", err)
+	}
+
+	reviewerQuery := `UPDATE reports SET reviewed_by = NULL WHERE reviewed_by = $1`
+	if _, err := r.db.ExecContext(ctx, reviewerQuery, userID); err != nil {
+		return 0, fmt.Errorf("failed to clear report reviewer: %w", err)
+	}
+
+	return result.RowsAffected()
+}
+
+// ReencryptBatch re-encrypts up to limit reports with id greater than afterID
+// whose content is plaintext or encrypted with a key other than the active
+// one. It returns the number of reports examined and the last ID.
+func (r *ReportRepository) ReencryptBatch(ctx context.Context, afterID uuid.UUID, limit int) (_ int, _ uuid.UUID, err error) {
+	ctx, span := startSpan(ctx, "ReportRepository.ReencryptBatch")
+	defer func() { tracing.End(span, err) }()
+
+	query := `
+		SELECT id, content, encryption_key_id
+		FROM reports
+		WHERE id > $1 AND encryption_key_id IS DISTINCT FROM $2
+		ORDER BY id
+		LIMIT $3
+	`
+	rows, err := r.db.QueryContext(ctx, query, afterID, r.cipher.ActiveKeyID(), limit)
+	if err != nil {
+		return 0, afterID, fmt.Errorf("failed to get reports for re-encryption: %w", err)
+	}
+
+	var reports []*models.Report
+	for rows.Next() {
+		var report models.Report
+		if err := rows.Scan(&report.ID, &report.Content, &report.EncryptionKeyID); err != nil {
+			rows.Close()
+			return 0, afterID, fmt.Errorf("failed to scan report: %w", err)
+		}
+		reports = append(reports, &report)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, afterID, fmt.Errorf("failed to get reports for re-encryption: %w", err)
+	}
+
+	update := `
+		UPDATE reports
+		SET content = $2, content_hash = $3, encryption_key_id = $4
+		WHERE id = $1 AND encryption_key_id IS NOT DISTINCT FROM $5
+	`
+	for _, report := range reports {
+		previousKeyID := report.EncryptionKeyID
+		if err := r.decrypt(report); err != nil {
+			return 0, afterID, fmt.Errorf("report %s: %w", report.ID, err)
+		}
+		content, err := r.encrypt(report)
+		if err != nil {
+			return 0, afterID, fmt.Errorf("report %s: %w", report.ID, err)
+		}
+		_, err = r.db.ExecContext(ctx, update,
+			report.ID, content, report.ContentHash, report.EncryptionKeyID, previousKeyID,
+		)
+		if err != nil {
+			return 0, afterID, fmt.Errorf("failed to re-encrypt report %s: %w", report.ID, err)
+		}
+		afterID = report.ID
+	}
+
+	return len(reports), afterID, nil
+}
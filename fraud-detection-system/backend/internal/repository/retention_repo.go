@@ -0,0 +1,90 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"github.com/fraud-detection-system/backend/internal/models"
+	"github.com/fraud-detection-system/backend/internal/tracing"
+)
+
+type RetentionRepository struct {
+	db DBTX
+}
+
+func NewRetentionRepository(db *sql.DB) *RetentionRepository {
+	return &RetentionRepository{db: db}
+}
+
+// WithTx returns a copy of the repository that runs its queries in tx
+func (r *RetentionRepository) WithTx(tx *sql.Tx) *RetentionRepository {
+	return &RetentionRepository{db: tx}
+}
+
+// ListTenants returns every tenant that owns messages or verifications
+func (r *RetentionRepository) ListTenants(ctx context.Context) (_ []string, err error) {
+	ctx, span := startSpan(ctx, "RetentionRepository.ListTenants")
+	defer func() { tracing.End(span, err) }()
+
+	query := `
+		SELECT tenant_id FROM messages
+		UNION
+		SELECT tenant_id FROM verifications
+	`
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tenants: %w", err)
+	}
+	defer rows.Close()
+
+	var tenants []string
+	for rows.Next() {
+		var tenant string
+		if err := rows.Scan(&tenant); err != nil {
+			return nil, fmt.Errorf("failed to scan tenant: %w", err)
+		}
+		tenants = append(tenants, tenant)
+	}
+	return tenants, rows.Err()
+}
+
+// CreateRun records the start of a retention run
+func (r *RetentionRepository) CreateRun(ctx context.Context, run *models.RetentionRun) (err error) {
+	ctx, span := startSpan(ctx, "RetentionRepository.CreateRun")
+	defer func() { tracing.End(span, err) }()
+
+	query := `INSERT INTO retention_runs (id, started_at, status) VALUES ($1, $2, $3)`
+	_, err = r.db.ExecContext(ctx, query, run.ID, run.StartedAt, run.Status)
+	if err != nil {
+		return fmt.Errorf("failed to create retention run: %w", err)
+	}
+	return nil
+}
+
+// FinishRun records the outcome and counts of a retention run
+func (r *RetentionRepository) FinishRun(ctx context.Context, run *models.RetentionRun) (err error) {
+	ctx, span := startSpan(ctx, "RetentionRepository.FinishRun")
+	defer func() { tracing.End(span, err) }()
+
+	details, err := json.Marshal(run.Details)
+	if err != nil {
+		return fmt.Errorf("failed to marshal retention details: %w", err)
+	}
+
+	query := `
+		UPDATE retention_runs
+		SET finished_at = $2, status = $3, messages_deleted = $4, messages_anonymized = $5,
+		    verifications_deleted = $6, reset_tokens_deleted = $7, details = $8, error = $9
+		WHERE id = $1
+	`
+	_, err = r.db.ExecContext(ctx, query,
+		run.ID, run.FinishedAt, run.Status, run.MessagesDeleted, run.MessagesAnonymized,
+		run.VerificationsDeleted, run.ResetTokensDeleted, details, run.Error,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to finish retention run: %w", err)
+	}
+	return nil
+}
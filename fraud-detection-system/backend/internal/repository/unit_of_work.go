@@ -0,0 +1,89 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+// TxRepositories are repositories bound to a single transaction
+type TxRepositories struct {
+	// Tx is the transaction they are bound to; nil for in-memory repositories
+	Tx *sql.Tx
+
+	Users         UserStore
+	Messages      MessageStore
+	Verifications VerificationStore
+	Reports       ReportStore
+	RBI           RBIStore
+	Outbox        OutboxStore
+	Analytics     AnalyticsStore
+}
+
+// UnitOfWork runs a function against repositories that share a transaction,
+// so the rows it writes commit or roll back together
+type UnitOfWork struct {
+	db               *sql.DB
+	userRepo         *UserRepository
+	messageRepo      *MessageRepository
+	verificationRepo *VerificationRepository
+	reportRepo       *ReportRepository
+	rbiRepo          *RBIRepository
+	outboxRepo       *OutboxRepository
+	analyticsRepo    *AnalyticsRepository
+}
+
+func NewUnitOfWork(
+	db *sql.DB,
+	userRepo *UserRepository,
+	messageRepo *MessageRepository,
+	verificationRepo *VerificationRepository,
+	reportRepo *ReportRepository,
+	rbiRepo *RBIRepository,
+	outboxRepo *OutboxRepository,
+	analyticsRepo *AnalyticsRepository,
+) *UnitOfWork {
+	return &UnitOfWork{
+		db:               db,
+		userRepo:         userRepo,
+		messageRepo:      messageRepo,
+		verificationRepo: verificationRepo,
+		reportRepo:       reportRepo,
+		rbiRepo:          rbiRepo,
+		outboxRepo:       outboxRepo,
+		analyticsRepo:    analyticsRepo,
+	}
+}
+
+// Do runs fn in a new transaction, committing if it returns nil and rolling
+// back otherwise
+func (u *UnitOfWork) Do(ctx context.Context, fn func(repos *TxRepositories) error) error {
+	tx, err := u.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := fn(u.Bind(tx)); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// Bind returns the repositories bound to a transaction the caller owns
+func (u *UnitOfWork) Bind(tx *sql.Tx) *TxRepositories {
+	return &TxRepositories{
+		Tx:            tx,
+		Users:         u.userRepo.WithTx(tx),
+		Messages:      u.messageRepo.WithTx(tx),
+		Verifications: u.verificationRepo.WithTx(tx),
+		Reports:       u.reportRepo.WithTx(tx),
+		RBI:           u.rbiRepo.WithTx(tx),
+		Outbox:        u.outboxRepo.WithTx(tx),
+		Analytics:     u.analyticsRepo.WithTx(tx),
+	}
+}
@@ -7,26 +7,56 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/fraud-detection-system/backend/internal/encryption"
 	"github.com/fraud-detection-system/backend/internal/models"
+	"github.com/fraud-detection-system/backend/internal/tracing"
 )
 
 type UserRepository struct {
-	db *sql.DB
+	db     DBTX
+	cipher *encryption.FieldCipher
 }
 
-func NewUserRepository(db *sql.DB) *UserRepository {
-	return &UserRepository{db: db}
+func NewUserRepository(db *sql.DB, cipher *encryption.FieldCipher) *UserRepository {
+	return &UserRepository{db: db, cipher: cipher}
+}
+
+// WithTx returns a copy of the repository that runs its queries in tx
+func (r *UserRepository) WithTx(tx *sql.Tx) *UserRepository {
+	return &UserRepository{db: tx, cipher: r.cipher}
+}
+
+// decrypt replaces the stored TOTP secret with plaintext
+func (r *UserRepository) decrypt(user *models.User) error {
+	if user.TOTPSecret == nil {
+		return nil
+	}
+	secret, err := r.cipher.Decrypt(*user.TOTPSecret)
+	if err != nil {
+		return fmt.Errorf("failed to decrypt TOTP secret: %w", err)
+	}
+	user.TOTPSecret = &secret
+	return nil
 }
 
 // Create creates a new user
-func (r *UserRepository) Create(ctx context.Context, user *models.User) error {
+func (r *UserRepository) Create(ctx context.Context, user *models.User) (err error) {
+	ctx, span := startSpan(ctx, "UserRepository.Create")
+	defer func() { tracing.End(span, err) }()
+
 	query := `
-		INSERT INTO users (id, email, password_hash, full_name, phone_number, is_active, is_verified, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		INSERT INTO users (id, email, password_hash, full_name, phone_number, is_active, is_verified, role, tenant_id, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
 	`
-	_, err := r.db.ExecContext(ctx, query,
+	if user.Role == "" {
+		user.Role = models.RoleUser
+	}
+	if user.TenantID == "" {
+		user.TenantID = models.DefaultTenantID
+	}
+	_, err = r.db.ExecContext(ctx, query,
 		user.ID, user.Email, user.PasswordHash, user.FullName, user.PhoneNumber,
-		user.IsActive, user.IsVerified, user.CreatedAt, user.UpdatedAt,
+		user.IsActive, user.IsVerified, user.Role, user.TenantID, user.CreatedAt, user.UpdatedAt,
 	)
 	if err != nil {
 		return fmt.Errorf("failed to create user: %w", err)
@@ -35,17 +65,22 @@ func (r *UserRepository) Create(ctx context.Context, user *models.User) error {
 }
 
 // GetByID retrieves a user by ID
-func (r *UserRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.User, error) {
+func (r *UserRepository) GetByID(ctx context.Context, id uuid.UUID) (_ *models.User, err error) {
+	ctx, span := startSpan(ctx, "UserRepository.GetByID")
+	defer func() { tracing.End(span, err) }()
+
 	query := `
 		SELECT id, email, password_hash, full_name, phone_number, is_active, is_verified,
-		       created_at, updated_at, last_login_at
+		       created_at, updated_at, last_login_at, password_changed_at,
+		       role, tenant_id, totp_secret, totp_enabled, totp_enabled_at
 		FROM users
 		WHERE id = $1
 	`
 	var user models.User
-	err := r.db.QueryRowContext(ctx, query, id).Scan(
+	err = r.db.QueryRowContext(ctx, query, id).Scan(
 		&user.ID, &user.Email, &user.PasswordHash, &user.FullName, &user.PhoneNumber,
 		&user.IsActive, &user.IsVerified, &user.CreatedAt, &user.UpdatedAt, &user.LastLoginAt,
+		&user.PasswordChangedAt, &user.Role, &user.TenantID, &user.TOTPSecret, &user.TOTPEnabled, &user.TOTPEnabledAt,
 	)
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -53,21 +88,29 @@ func (r *UserRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Use
 		}
 		return nil, fmt.Errorf("failed to get user: %w", err)
 	}
+	if err := r.decrypt(&user); err != nil {
+		return nil, err
+	}
 	return &user, nil
 }
 
 // GetByEmail retrieves a user by email
-func (r *UserRepository) GetByEmail(ctx context.Context, email string) (*models.User, error) {
+func (r *UserRepository) GetByEmail(ctx context.Context, email string) (_ *models.User, err error) {
+	ctx, span := startSpan(ctx, "UserRepository.GetByEmail")
+	defer func() { tracing.End(span, err) }()
+
 	query := `
 		SELECT id, email, password_hash, full_name, phone_number, is_active, is_verified,
-		       created_at, updated_at, last_login_at
+		       created_at, updated_at, last_login_at, password_changed_at,
+		       role, tenant_id, totp_secret, totp_enabled, totp_enabled_at
 		FROM users
 		WHERE email = $1
 	`
 	var user models.User
-	err := r.db.QueryRowContext(ctx, query, email).Scan(
+	err = r.db.QueryRowContext(ctx, query, email).Scan(
 		&user.ID, &user.Email, &user.PasswordHash, &user.FullName, &user.PhoneNumber,
 		&user.IsActive, &user.IsVerified, &user.CreatedAt, &user.UpdatedAt, &user.LastLoginAt,
+		&user.PasswordChangedAt, &user.Role, &user.TenantID, &user.TOTPSecret, &user.TOTPEnabled, &user.TOTPEnabledAt,
 	)
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -75,11 +118,17 @@ func (r *UserRepository) GetByEmail(ctx context.Context, email string) (*models.
 		}
 		return nil, fmt.Errorf("failed to get user: %w", err)
 	}
+	if err := r.decrypt(&user); err != nil {
+		return nil, err
+	}
 	return &user, nil
 }
 
 // Update updates a user
-func (r *UserRepository) Update(ctx context.Context, user *models.User) error {
+func (r *UserRepository) Update(ctx context.Context, user *models.User) (err error) {
+	ctx, span := startSpan(ctx, "UserRepository.Update")
+	defer func() { tracing.End(span, err) }()
+
 	query := `
 		UPDATE users
 		SET email = $2, full_name = $3, phone_number = $4, is_active = $5,
@@ -87,7 +136,7 @@ func (r *UserRepository) Update(ctx context.Context, user *models.User) error {
 		WHERE id = $1
 	`
 	user.UpdatedAt = time.Now()
-	_, err := r.db.ExecContext(ctx, query,
+	_, err = r.db.ExecContext(ctx, query,
 		user.ID, user.Email, user.FullName, user.PhoneNumber,
 		user.IsActive, user.IsVerified, user.UpdatedAt, user.LastLoginAt,
 	)
@@ -98,20 +147,144 @@ func (r *UserRepository) Update(ctx context.Context, user *models.User) error {
 }
 
 // UpdateLastLogin updates the last login timestamp
-func (r *UserRepository) UpdateLastLogin(ctx context.Context, id uuid.UUID) error {
+func (r *UserRepository) UpdateLastLogin(ctx context.Context, id uuid.UUID) (err error) {
+	ctx, span := startSpan(ctx, "UserRepository.UpdateLastLogin")
+	defer func() { tracing.End(span, err) }()
+
 	query := `UPDATE users SET last_login_at = $1 WHERE id = $2`
 	now := time.Now()
-	_, err := r.db.ExecContext(ctx, query, now, id)
+	_, err = r.db.ExecContext(ctx, query, now, id)
 	if err != nil {
 		return fmt.Errorf("failed to update last login: %w", err)
 	}
 	return nil
 }
 
+// UpdatePassword replaces a user's password hash and records when it changed
+func (r *UserRepository) UpdatePassword(ctx context.Context, id uuid.UUID, passwordHash string) (err error) {
+	ctx, span := startSpan(ctx, "UserRepository.UpdatePassword")
+	defer func() { tracing.End(span, err) }()
+
+	query := `
+		UPDATE users
+		SET password_hash = $2, password_changed_at = $3, updated_at = $3
+		WHERE id = $1
+	`
+	now := time.Now()
+	_, err = r.db.ExecContext(ctx, query, id, passwordHash, now)
+	if err != nil {
+		return fmt.Errorf("failed to update password: %w", err)
+	}
+	return nil
+}
+
+// SetTOTPSecret stores a pending TOTP secret, encrypted, leaving two-factor
+// disabled until confirmed
+func (r *UserRepository) SetTOTPSecret(ctx context.Context, id uuid.UUID, secret string) (err error) {
+	ctx, span := startSpan(ctx, "UserRepository.SetTOTPSecret")
+	defer func() { tracing.End(span, err) }()
+
+	encrypted, _, err := r.cipher.Encrypt(secret)
+	if err != nil {
+		return fmt.Errorf("failed to encrypt TOTP secret: %w", err)
+	}
+	query := `UPDATE users SET totp_secret = $2, updated_at = $3 WHERE id = $1 AND totp_enabled = false`
+	_, err = r.db.ExecContext(ctx, query, id, encrypted, time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to set TOTP secret: %w", err)
+	}
+	return nil
+}
+
+// EnableTOTP marks two-factor authentication as enabled
+func (r *UserRepository) EnableTOTP(ctx context.Context, id uuid.UUID) (err error) {
+	ctx, span := startSpan(ctx, "UserRepository.EnableTOTP")
+	defer func() { tracing.End(span, err) }()
+
+	query := `UPDATE users SET totp_enabled = true, totp_enabled_at = $2, updated_at = $2 WHERE id = $1`
+	_, err = r.db.ExecContext(ctx, query, id, time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to enable TOTP: %w", err)
+	}
+	return nil
+}
+
+// DisableTOTP disables two-factor authentication and clears the secret
+func (r *UserRepository) DisableTOTP(ctx context.Context, id uuid.UUID) (err error) {
+	ctx, span := startSpan(ctx, "UserRepository.DisableTOTP")
+	defer func() { tracing.End(span, err) }()
+
+	query := `
+		UPDATE users
+		SET totp_enabled = false, totp_secret = NULL, totp_enabled_at = NULL, updated_at = $2
+		WHERE id = $1
+	`
+	_, err = r.db.ExecContext(ctx, query, id, time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to disable TOTP: %w", err)
+	}
+	return nil
+}
+
+// ReencryptTOTPBatch moves a batch of TOTP secrets onto the active key,
+// encrypting any stored before secrets were encrypted. It returns how many
+// were processed and the last user ID, to resume from.
+func (r *UserRepository) ReencryptTOTPBatch(ctx context.Context, afterID uuid.UUID, limit int) (_ int, _ uuid.UUID, err error) {
+	ctx, span := startSpan(ctx, "UserRepository.ReencryptTOTPBatch")
+	defer func() { tracing.End(span, err) }()
+
+	query := `
+		SELECT id, totp_secret
+		FROM users
+		WHERE id > $1 AND totp_secret IS NOT NULL AND totp_secret NOT LIKE $2
+		ORDER BY id
+		LIMIT $3
+	`
+	rows, err := r.db.QueryContext(ctx, query, afterID, encryption.KeyPrefix(r.cipher.ActiveKeyID())+"%", limit)
+	if err != nil {
+		return 0, afterID, fmt.Errorf("failed to get TOTP secrets for re-encryption: %w", err)
+	}
+
+	var users []*models.User
+	for rows.Next() {
+		var user models.User
+		if err := rows.Scan(&user.ID, &user.TOTPSecret); err != nil {
+			rows.Close()
+			return 0, afterID, fmt.Errorf("failed to scan user: %w", err)
+		}
+		users = append(users, &user)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, afterID, fmt.Errorf("failed to get TOTP secrets for re-encryption: %w", err)
+	}
+
+	update := `UPDATE users SET totp_secret = $2 WHERE id = $1 AND totp_secret = $3`
+	for _, user := range users {
+		previous := *user.TOTPSecret
+		if err := r.decrypt(user); err != nil {
+			return 0, afterID, fmt.Errorf("user %s: %w", user.ID, err)
+		}
+		encrypted, _, err := r.cipher.Encrypt(*user.TOTPSecret)
+		if err != nil {
+			return 0, afterID, fmt.Errorf("user %s: failed to encrypt TOTP secret: %w", user.ID, err)
+		}
+		if _, err := r.db.ExecContext(ctx, update, user.ID, encrypted, previous); err != nil {
+			return 0, afterID, fmt.Errorf("failed to re-encrypt TOTP secret of user %s: %w", user.ID, err)
+		}
+		afterID = user.ID
+	}
+
+	return len(users), afterID, nil
+}
+
 // Delete deletes a user
-func (r *UserRepository) Delete(ctx context.Context, id uuid.UUID) error {
+func (r *UserRepository) Delete(ctx context.Context, id uuid.UUID) (err error) {
+	ctx, span := startSpan(ctx, "UserRepository.Delete")
+	defer func() { tracing.End(span, err) }()
+
 	query := `DELETE FROM users WHERE id = $1`
-	_, err := r.db.ExecContext(ctx, query, id)
+	_, err = r.db.ExecContext(ctx, query, id)
 	if err != nil {
 		return fmt.Errorf("failed to delete user: %w", err)
 	}
@@ -119,13 +292,15 @@ func (r *UserRepository) Delete(ctx context.Context, id uuid.UUID) error {
 }
 
 // Exists checks if a user exists by email
-func (r *UserRepository) Exists(ctx context.Context, email string) (bool, error) {
+func (r *UserRepository) Exists(ctx context.Context, email string) (_ bool, err error) {
+	ctx, span := startSpan(ctx, "UserRepository.Exists")
+	defer func() { tracing.End(span, err) }()
+
 	query := `SELECT EXISTS(SELECT 1 FROM users WHERE email = $1)`
 	var exists bool
-	err := r.db.QueryRowContext(ctx, query, email).Scan(&exists)
+	err = r.db.QueryRowContext(ctx, query, email).Scan(&exists)
 	if err != nil {
 		return false, fmt.Errorf("failed to check user existence: %w", err)
 	}
 	return exists, nil
 }
-
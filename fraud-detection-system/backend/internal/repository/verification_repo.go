@@ -3,36 +3,60 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"fmt"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/fraud-detection-system/backend/internal/models"
+	"github.com/fraud-detection-system/backend/internal/tracing"
 )
 
 type VerificationRepository struct {
-	db *sql.DB
+	db     DBTX
+	reader DBTX
 }
 
 func NewVerificationRepository(db *sql.DB) *VerificationRepository {
-	return &VerificationRepository{db: db}
+	return &VerificationRepository{db: db, reader: db}
+}
+
+// WithTx returns a copy of the repository that runs its queries in tx
+func (r *VerificationRepository) WithTx(tx *sql.Tx) *VerificationRepository {
+	return &VerificationRepository{db: tx, reader: tx}
+}
+
+// WithReader returns a copy of the repository that runs listing and
+// aggregate queries on reader, which may lag behind the primary
+func (r *VerificationRepository) WithReader(reader DBTX) *VerificationRepository {
+	return &VerificationRepository{db: r.db, reader: reader}
 }
 
 // Create creates a new verification record
-func (r *VerificationRepository) Create(ctx context.Context, verification *models.Verification) error {
+func (r *VerificationRepository) Create(ctx context.Context, verification *models.Verification) (err error) {
+	ctx, span := startSpan(ctx, "VerificationRepository.Create")
+	defer func() { tracing.End(span, err) }()
+
 	query := `
 		INSERT INTO verifications (id, message_id, user_id, is_fraud, fraud_score, fraud_type,
 		                           confidence, model_version, ml_predictions, header_verified,
 		                           header_score, rbi_compliant, rbi_verification_result,
-		                           explanation, recommendations, processing_time_ms, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18)
+		                           explanation, recommendations, processing_time_ms, degraded,
+		                           source_verification_id, tenant_id, stage_timings, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19,
+		        $20, $21, $22)
 	`
-	_, err := r.db.ExecContext(ctx, query,
+	if verification.TenantID == "" {
+		verification.TenantID = models.DefaultTenantID
+	}
+	_, err = r.db.ExecContext(ctx, query,
 		verification.ID, verification.MessageID, verification.UserID, verification.IsFraud,
 		verification.FraudScore, verification.FraudType, verification.Confidence, verification.ModelVersion,
 		verification.MLPredictions, verification.HeaderVerified, verification.HeaderScore,
 		verification.RBICompliant, verification.RBIVerificationResult, verification.Explanation,
-		verification.Recommendations, verification.ProcessingTimeMs, verification.CreatedAt, verification.UpdatedAt,
+		verification.Recommendations, verification.ProcessingTimeMs, verification.Degraded,
+		verification.SourceVerificationID, verification.TenantID, verification.StageTimings,
+		verification.CreatedAt, verification.UpdatedAt,
 	)
 	if err != nil {
 		return fmt.Errorf("failed to create verification: %w", err)
@@ -41,22 +65,27 @@ func (r *VerificationRepository) Create(ctx context.Context, verification *model
 }
 
 // GetByID retrieves a verification by ID
-func (r *VerificationRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Verification, error) {
+func (r *VerificationRepository) GetByID(ctx context.Context, id uuid.UUID) (_ *models.Verification, err error) {
+	ctx, span := startSpan(ctx, "VerificationRepository.GetByID")
+	defer func() { tracing.End(span, err) }()
+
 	query := `
 		SELECT id, message_id, user_id, is_fraud, fraud_score, fraud_type, confidence,
 		       model_version, ml_predictions, header_verified, header_score, rbi_compliant,
 		       rbi_verification_result, explanation, recommendations, processing_time_ms,
-		       created_at, updated_at
+		       degraded, source_verification_id, tenant_id, stage_timings, created_at, updated_at
 		FROM verifications
 		WHERE id = $1
 	`
 	var verification models.Verification
-	err := r.db.QueryRowContext(ctx, query, id).Scan(
+	err = r.db.QueryRowContext(ctx, query, id).Scan(
 		&verification.ID, &verification.MessageID, &verification.UserID, &verification.IsFraud,
 		&verification.FraudScore, &verification.FraudType, &verification.Confidence, &verification.ModelVersion,
 		&verification.MLPredictions, &verification.HeaderVerified, &verification.HeaderScore,
 		&verification.RBICompliant, &verification.RBIVerificationResult, &verification.Explanation,
-		&verification.Recommendations, &verification.ProcessingTimeMs, &verification.CreatedAt, &verification.UpdatedAt,
+		&verification.Recommendations, &verification.ProcessingTimeMs, &verification.Degraded,
+		&verification.SourceVerificationID, &verification.TenantID, &verification.StageTimings,
+		&verification.CreatedAt, &verification.UpdatedAt,
 	)
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -68,22 +97,27 @@ func (r *VerificationRepository) GetByID(ctx context.Context, id uuid.UUID) (*mo
 }
 
 // GetByMessageID retrieves a verification by message ID
-func (r *VerificationRepository) GetByMessageID(ctx context.Context, messageID uuid.UUID) (*models.Verification, error) {
+func (r *VerificationRepository) GetByMessageID(ctx context.Context, messageID uuid.UUID) (_ *models.Verification, err error) {
+	ctx, span := startSpan(ctx, "VerificationRepository.GetByMessageID")
+	defer func() { tracing.End(span, err) }()
+
 	query := `
 		SELECT id, message_id, user_id, is_fraud, fraud_score, fraud_type, confidence,
 		       model_version, ml_predictions, header_verified, header_score, rbi_compliant,
 		       rbi_verification_result, explanation, recommendations, processing_time_ms,
-		       created_at, updated_at
+		       degraded, source_verification_id, tenant_id, stage_timings, created_at, updated_at
 		FROM verifications
 		WHERE message_id = $1
 	`
 	var verification models.Verification
-	err := r.db.QueryRowContext(ctx, query, messageID).Scan(
+	err = r.db.QueryRowContext(ctx, query, messageID).Scan(
 		&verification.ID, &verification.MessageID, &verification.UserID, &verification.IsFraud,
 		&verification.FraudScore, &verification.FraudType, &verification.Confidence, &verification.ModelVersion,
 		&verification.MLPredictions, &verification.HeaderVerified, &verification.HeaderScore,
 		&verification.RBICompliant, &verification.RBIVerificationResult, &verification.Explanation,
-		&verification.Recommendations, &verification.ProcessingTimeMs, &verification.CreatedAt, &verification.UpdatedAt,
+		&verification.Recommendations, &verification.ProcessingTimeMs, &verification.Degraded,
+		&verification.SourceVerificationID, &verification.TenantID, &verification.StageTimings,
+		&verification.CreatedAt, &verification.UpdatedAt,
 	)
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -95,18 +129,21 @@ func (r *VerificationRepository) GetByMessageID(ctx context.Context, messageID u
 }
 
 // GetByUserID retrieves verifications by user ID
-func (r *VerificationRepository) GetByUserID(ctx context.Context, userID uuid.UUID, limit, offset int) ([]*models.Verification, error) {
+func (r *VerificationRepository) GetByUserID(ctx context.Context, userID uuid.UUID, limit, offset int) (_ []*models.Verification, err error) {
+	ctx, span := startSpan(ctx, "VerificationRepository.GetByUserID")
+	defer func() { tracing.End(span, err) }()
+
 	query := `
 		SELECT id, message_id, user_id, is_fraud, fraud_score, fraud_type, confidence,
 		       model_version, ml_predictions, header_verified, header_score, rbi_compliant,
 		       rbi_verification_result, explanation, recommendations, processing_time_ms,
-		       created_at, updated_at
+		       degraded, source_verification_id, tenant_id, stage_timings, created_at, updated_at
 		FROM verifications
 		WHERE user_id = $1
 		ORDER BY created_at DESC
 		LIMIT $2 OFFSET $3
 	`
-	rows, err := r.db.QueryContext(ctx, query, userID, limit, offset)
+	rows, err := r.reader.QueryContext(ctx, query, userID, limit, offset)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get verifications: %w", err)
 	}
@@ -120,7 +157,9 @@ func (r *VerificationRepository) GetByUserID(ctx context.Context, userID uuid.UU
 			&verification.FraudScore, &verification.FraudType, &verification.Confidence, &verification.ModelVersion,
 			&verification.MLPredictions, &verification.HeaderVerified, &verification.HeaderScore,
 			&verification.RBICompliant, &verification.RBIVerificationResult, &verification.Explanation,
-			&verification.Recommendations, &verification.ProcessingTimeMs, &verification.CreatedAt, &verification.UpdatedAt,
+			&verification.Recommendations, &verification.ProcessingTimeMs, &verification.Degraded,
+			&verification.SourceVerificationID, &verification.TenantID, &verification.StageTimings,
+			&verification.CreatedAt, &verification.UpdatedAt,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan verification: %w", err)
@@ -131,8 +170,145 @@ func (r *VerificationRepository) GetByUserID(ctx context.Context, userID uuid.UU
 	return verifications, nil
 }
 
+// ListByUserID returns a page of a user's verifications matching filter in
+// keyset order of opts.Sort then ID, along with the total number of matches
+func (r *VerificationRepository) ListByUserID(ctx context.Context, userID uuid.UUID, filter models.VerificationFilter, opts models.ListOptions) (_ []*models.Verification, _ *models.PageInfo, err error) {
+	ctx, span := startSpan(ctx, "VerificationRepository.ListByUserID")
+	defer func() { tracing.End(span, err) }()
+
+	opts, err = ResolveListOptions(opts, models.VerificationSortCreatedAt, models.VerificationSortFraudScore)
+	if err != nil {
+		return nil, nil, err
+	}
+	cursor, err := DecodeCursor(opts)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Sender and message type live on the message
+	from := "verifications v"
+	if filter.SenderHeader != "" || filter.MessageType != "" {
+		from += " JOIN messages m ON m.id = v.message_id"
+	}
+
+	var q listQuery
+	q.add("v.user_id = ?", userID)
+	if filter.IsFraud != nil {
+		q.add("v.is_fraud = ?", *filter.IsFraud)
+	}
+	if filter.RiskLevel != "" {
+		min, max, ok := models.RiskScoreRange(filter.RiskLevel)
+		if !ok {
+			return nil, nil, fmt.Errorf("unknown risk level %q", filter.RiskLevel)
+		}
+		q.add("v.fraud_score >= ?", min)
+		if max > 0 {
+			q.add("v.fraud_score < ?", max)
+		}
+	}
+	if filter.FraudType != "" {
+		q.add("v.fraud_type = ?", filter.FraudType)
+	}
+	if filter.SenderHeader != "" {
+		q.add("m.sender_header = ?", filter.SenderHeader)
+	}
+	if filter.MessageType != "" {
+		q.add("m.message_type = ?", filter.MessageType)
+	}
+	q.addTimeRange("v.created_at", filter.Created)
+
+	page := &models.PageInfo{Limit: opts.Limit}
+	var verifications []*models.Verification
+	// The count and the page are read from one snapshot, so the total
+	// matches the rows returned
+	err = readSnapshot(ctx, r.reader, func(db DBTX) error {
+		countQuery := fmt.Sprintf(`SELECT COUNT(*) FROM %s %s`, from, q.where())
+		if err := db.QueryRowContext(ctx, countQuery, q.args...).Scan(&page.Total); err != nil {
+			return fmt.Errorf("failed to count verifications: %w", err)
+		}
+
+		column := "v." + opts.Sort
+		if cursor != nil {
+			var (
+				value interface{}
+				err   error
+			)
+			if opts.Sort == models.VerificationSortFraudScore {
+				value, err = cursor.Float()
+			} else {
+				value, err = cursor.Time()
+			}
+			if err != nil {
+				return err
+			}
+			q.after(column, "v.id", opts.Desc, value, cursor.ID)
+		}
+
+		// One extra row tells whether there is another page
+		query := fmt.Sprintf(`
+			SELECT v.id, v.message_id, v.user_id, v.is_fraud, v.fraud_score, v.fraud_type, v.confidence,
+			       v.model_version, v.ml_predictions, v.header_verified, v.header_score, v.rbi_compliant,
+			       v.rbi_verification_result, v.explanation, v.recommendations, v.processing_time_ms,
+			       v.degraded, v.source_verification_id, v.tenant_id, v.stage_timings, v.created_at, v.updated_at
+			FROM %s
+			%s
+			%s
+			LIMIT %d
+		`, from, q.where(), orderBy(column, "v.id", opts.Desc), opts.Limit+1)
+		rows, err := db.QueryContext(ctx, query, q.args...)
+		if err != nil {
+			return fmt.Errorf("failed to list verifications: %w", err)
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var verification models.Verification
+			err := rows.Scan(
+				&verification.ID, &verification.MessageID, &verification.UserID, &verification.IsFraud,
+				&verification.FraudScore, &verification.FraudType, &verification.Confidence, &verification.ModelVersion,
+				&verification.MLPredictions, &verification.HeaderVerified, &verification.HeaderScore,
+				&verification.RBICompliant, &verification.RBIVerificationResult, &verification.Explanation,
+				&verification.Recommendations, &verification.ProcessingTimeMs, &verification.Degraded,
+				&verification.SourceVerificationID, &verification.TenantID, &verification.StageTimings,
+				&verification.CreatedAt, &verification.UpdatedAt,
+			)
+			if err != nil {
+				return fmt.Errorf("failed to scan verification: %w", err)
+			}
+			verifications = append(verifications, &verification)
+		}
+		if err := rows.Err(); err != nil {
+			return fmt.Errorf("failed to list verifications: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if len(verifications) > opts.Limit {
+		verifications = verifications[:opts.Limit]
+		last := verifications[len(verifications)-1]
+		page.HasMore = true
+		page.NextCursor = EncodeCursor(opts, VerificationSortValue(last, opts.Sort), last.ID)
+	}
+	page.Count = len(verifications)
+	return verifications, page, nil
+}
+
+// VerificationSortValue returns the value of a verification's sort key
+func VerificationSortValue(v *models.Verification, sort string) interface{} {
+	if sort == models.VerificationSortFraudScore {
+		return v.FraudScore
+	}
+	return v.CreatedAt
+}
+
 // GetStats retrieves verification statistics
-func (r *VerificationRepository) GetStats(ctx context.Context, userID *uuid.UUID) (*models.VerificationStats, error) {
+func (r *VerificationRepository) GetStats(ctx context.Context, userID *uuid.UUID) (_ *models.VerificationStats, err error) {
+	ctx, span := startSpan(ctx, "VerificationRepository.GetStats")
+	defer func() { tracing.End(span, err) }()
+
 	var query string
 	var args []interface{}
 
@@ -163,7 +339,7 @@ func (r *VerificationRepository) GetStats(ctx context.Context, userID *uuid.UUID
 	}
 
 	var stats models.VerificationStats
-	err := r.db.QueryRowContext(ctx, query, args...).Scan(
+	err = r.reader.QueryRowContext(ctx, query, args...).Scan(
 		&stats.TotalVerifications,
 		&stats.FraudDetected,
 		&stats.AvgFraudScore,
@@ -182,13 +358,122 @@ func (r *VerificationRepository) GetStats(ctx context.Context, userID *uuid.UUID
 	return &stats, nil
 }
 
-// DeleteOldVerifications deletes verifications older than the specified duration
-func (r *VerificationRepository) DeleteOldVerifications(ctx context.Context, olderThan time.Duration) (int64, error) {
-	query := `DELETE FROM verifications WHERE created_at < $1 AND is_fraud = false`
-	result, err := r.db.ExecContext(ctx, query, time.Now().Add(-olderThan))
+// GetModelComparison compares the deciding model with the shadow model over
+// verifications created since the given time, grouped by model pair
+func (r *VerificationRepository) GetModelComparison(ctx context.Context, since time.Time) (_ []*models.ModelComparison, err error) {
+	ctx, span := startSpan(ctx, "VerificationRepository.GetModelComparison")
+	defer func() { tracing.End(span, err) }()
+
+	query := `
+		SELECT
+			primary_model,
+			shadow_model,
+			COUNT(*) as total,
+			COUNT(*) FILTER (WHERE primary_fraud = shadow_fraud) as agreements,
+			COUNT(*) FILTER (WHERE primary_fraud AND NOT shadow_fraud) as primary_only_fraud,
+			COUNT(*) FILTER (WHERE shadow_fraud AND NOT primary_fraud) as shadow_only_fraud,
+			AVG(shadow_score - primary_score) as avg_score_delta,
+			AVG(ABS(shadow_score - primary_score)) as avg_abs_score_delta,
+			MAX(ABS(shadow_score - primary_score)) as max_abs_score_delta
+		FROM (
+			SELECT
+				ml_predictions #>> '{routing,primary,model}' as primary_model,
+				ml_predictions #>> '{routing,shadow,model}' as shadow_model,
+				(ml_predictions #>> '{routing,primary,is_fraud}')::boolean as primary_fraud,
+				(ml_predictions #>> '{routing,shadow,is_fraud}')::boolean as shadow_fraud,
+				(ml_predictions #>> '{routing,primary,fraud_score}')::float8 as primary_score,
+				(ml_predictions #>> '{routing,shadow,fraud_score}')::float8 as shadow_score
+			FROM verifications
+			WHERE created_at >= $1
+				AND source_verification_id IS NULL
+				AND ml_predictions #> '{routing,shadow}' IS NOT NULL
+		) scored
+		GROUP BY primary_model, shadow_model
+		ORDER BY primary_model, shadow_model
+	`
+
+	rows, err := r.reader.QueryContext(ctx, query, since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get model comparison: %w", err)
+	}
+	defer rows.Close()
+
+	var comparisons []*models.ModelComparison
+	for rows.Next() {
+		var c models.ModelComparison
+		if err := rows.Scan(
+			&c.PrimaryModel,
+			&c.ShadowModel,
+			&c.Total,
+			&c.Agreements,
+			&c.PrimaryOnlyFraud,
+			&c.ShadowOnlyFraud,
+			&c.AvgScoreDelta,
+			&c.AvgAbsScoreDelta,
+			&c.MaxAbsScoreDelta,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan model comparison: %w", err)
+		}
+		if c.Total > 0 {
+			c.AgreementRate = float64(c.Agreements) / float64(c.Total)
+		}
+		comparisons = append(comparisons, &c)
+	}
+
+	return comparisons, rows.Err()
+}
+
+// DeleteOldVerifications deletes up to batchSize of a tenant's non-fraud
+// verifications created before cutoff. Fraud verifications are kept for audit.
+func (r *VerificationRepository) DeleteOldVerifications(ctx context.Context, tenantID string, cutoff time.Time, batchSize int) (_ int64, err error) {
+	ctx, span := startSpan(ctx, "VerificationRepository.DeleteOldVerifications")
+	defer func() { tracing.End(span, err) }()
+
+	query := `
+		DELETE FROM verifications
+		WHERE id IN (
+			SELECT id FROM verifications
+			WHERE tenant_id = $1 AND created_at < $2 AND is_fraud = false
+			LIMIT $3
+		)
+	`
+	result, err := r.db.ExecContext(ctx, query, tenantID, cutoff, batchSize)
 	if err != nil {
 		return 0, fmt.Errorf("failed to delete old verifications: %w", err)
 	}
 	return result.RowsAffected()
 }
 
+// SetShadowScore records the shadow model's prediction for a verification,
+// which arrives after the verification is stored
+func (r *VerificationRepository) SetShadowScore(ctx context.Context, id uuid.UUID, score models.ModelScore) (err error) {
+	ctx, span := startSpan(ctx, "VerificationRepository.SetShadowScore")
+	defer func() { tracing.End(span, err) }()
+
+	scoreJSON, err := json.Marshal(score)
+	if err != nil {
+		return fmt.Errorf("failed to encode shadow score: %w", err)
+	}
+	query := `
+		UPDATE verifications
+		SET ml_predictions = jsonb_set(ml_predictions, '{routing,shadow}', $2::jsonb), updated_at = $3
+		WHERE id = $1 AND ml_predictions ? 'routing'
+	`
+	if _, err := r.db.ExecContext(ctx, query, id, string(scoreJSON), time.Now()); err != nil {
+		return fmt.Errorf("failed to record shadow score: %w", err)
+	}
+	return nil
+}
+
+// DetachUser removes the user reference from a user's verifications
+func (r *VerificationRepository) DetachUser(ctx context.Context, userID uuid.UUID) (_ int64, err error) {
+	ctx, span := startSpan(ctx, "VerificationRepository.DetachUser")
+	defer func() { tracing.End(span, err) }()
+
+	query := `UPDATE verifications SET user_id = NULL, updated_at = $2 WHERE user_id = $1`
+	result, err := r.db.ExecContext(ctx, query, userID, time.Now())
+	if err != nil {
+		return 0, fmt.Errorf("failed to detach verifications: %w", err)
+	}
+	return result.RowsAffected()
+}
@@ -0,0 +1,209 @@
+package service
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+	"github.com/fraud-detection-system/backend/internal/cache"
+	"github.com/fraud-detection-system/backend/internal/config"
+	"github.com/fraud-detection-system/backend/internal/models"
+	"github.com/fraud-detection-system/backend/internal/repository"
+	"github.com/fraud-detection-system/backend/internal/utils"
+)
+
+// exportPageSize is the page size used when collecting rows for an export
+const exportPageSize = 500
+
+// AccountService implements self-service data export and account deletion
+type AccountService struct {
+	uow              repository.Transactor
+	userRepo         repository.UserStore
+	authService      *AuthService
+	messageRepo      repository.MessageStore
+	verificationRepo repository.VerificationStore
+	reportRepo       repository.ReportStore
+	cache            cache.Cache
+	config           *config.Config
+}
+
+func NewAccountService(
+	uow repository.Transactor,
+	userRepo repository.UserStore,
+	authService *AuthService,
+	messageRepo repository.MessageStore,
+	verificationRepo repository.VerificationStore,
+	reportRepo repository.ReportStore,
+	cache cache.Cache,
+	cfg *config.Config,
+) *AccountService {
+	return &AccountService{
+		uow:              uow,
+		userRepo:         userRepo,
+		authService:      authService,
+		messageRepo:      messageRepo,
+		verificationRepo: verificationRepo,
+		reportRepo:       reportRepo,
+		cache:            cache,
+		config:           cfg,
+	}
+}
+
+// ExportUserData collects the profile, messages, verifications and reports of a user
+func (s *AccountService) ExportUserData(ctx context.Context, userID uuid.UUID) (*models.UserDataExport, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, utils.ErrUserNotFound
+	}
+
+	export := &models.UserDataExport{
+		ExportedAt: time.Now(),
+		Profile: &models.UserResponse{
+			ID:          user.ID,
+			Email:       user.Email,
+			FullName:    user.FullName,
+			PhoneNumber: user.PhoneNumber,
+			IsActive:    user.IsActive,
+			IsVerified:  user.IsVerified,
+			Role:        user.Role,
+			TOTPEnabled: user.TOTPEnabled,
+			CreatedAt:   user.CreatedAt,
+			LastLoginAt: user.LastLoginAt,
+		},
+		Messages:      []*models.Message{},
+		Verifications: []*models.Verification{},
+		Reports:       []*models.Report{},
+	}
+
+	for offset := 0; ; offset += exportPageSize {
+		page, err := s.messageRepo.GetByUserID(ctx, userID, exportPageSize, offset)
+		if err != nil {
+			return nil, err
+		}
+		export.Messages = append(export.Messages, page...)
+		if len(page) < exportPageSize {
+			break
+		}
+	}
+
+	for offset := 0; ; offset += exportPageSize {
+		page, err := s.verificationRepo.GetByUserID(ctx, userID, exportPageSize, offset)
+		if err != nil {
+			return nil, err
+		}
+		export.Verifications = append(export.Verifications, page...)
+		if len(page) < exportPageSize {
+			break
+		}
+	}
+
+	for offset := 0; ; offset += exportPageSize {
+		page, err := s.reportRepo.GetByUserID(ctx, userID, exportPageSize, offset)
+		if err != nil {
+			return nil, err
+		}
+		export.Reports = append(export.Reports, page...)
+		if len(page) < exportPageSize {
+			break
+		}
+	}
+
+	return export, nil
+}
+
+// WriteExportArchive writes an export as a zip archive with one JSON file per section
+func (s *AccountService) WriteExportArchive(w io.Writer, export *models.UserDataExport) error {
+	archive := zip.NewWriter(w)
+
+	files := []struct {
+		name string
+		data interface{}
+	}{
+		{"profile.json", export.Profile},
+		{"messages.json", export.Messages},
+		{"verifications.json", export.Verifications},
+		{"reports.json", export.Reports},
+		{"export.json", map[string]interface{}{
+			"exported_at":        export.ExportedAt,
+			"user_id":            export.Profile.ID,
+			"message_count":      len(export.Messages),
+			"verification_count": len(export.Verifications),
+			"report_count":       len(export.Reports),
+		}},
+	}
+
+	for _, file := range files {
+		f, err := archive.CreateHeader(&zip.FileHeader{
+			Name:     file.name,
+			Method:   zip.Deflate,
+			Modified: export.ExportedAt,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to create %s: %w", file.name, err)
+		}
+
+		encoder := json.NewEncoder(f)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(file.data); err != nil {
+			return fmt.Errorf("failed to write %s: %w", file.name, err)
+		}
+	}
+
+	return archive.Close()
+}
+
+// DeleteAccount erases a user after confirming their password and, when
+// two-factor is enabled, a TOTP or recovery code. Messages and
+// reports are anonymized rather than deleted so fraud statistics stay
+// accurate; everything linking them to the user is removed. All changes are
+// applied in a single transaction.
+func (s *AccountService) DeleteAccount(ctx context.Context, userID uuid.UUID, password, code string) error {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return utils.ErrUserNotFound
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(password)); err != nil {
+		return utils.ErrInvalidCredentials
+	}
+
+	if user.TOTPEnabled {
+		if err := s.authService.VerifySecondFactor(ctx, user, code); err != nil {
+			return err
+		}
+	}
+
+	var messages, verifications, reports int64
+	err = s.uow.Do(ctx, func(repos *repository.TxRepositories) error {
+		if messages, err = repos.Messages.AnonymizeByUserID(ctx, userID); err != nil {
+			return err
+		}
+		if verifications, err = repos.Verifications.DetachUser(ctx, userID); err != nil {
+			return err
+		}
+		if reports, err = repos.Reports.AnonymizeByUserID(ctx, userID); err != nil {
+			return err
+		}
+		// Reset tokens and recovery codes are removed by ON DELETE CASCADE
+		return repos.Users.Delete(ctx, userID)
+	})
+	if err != nil {
+		return err
+	}
+
+	revokeUserSessions(ctx, s.cache, s.config, userID)
+
+	utils.GetLoggerWithContext(ctx).WithFields(map[string]interface{}{
+		"user_id":       userID,
+		"messages":      messages,
+		"verifications": verifications,
+		"reports":       reports,
+	}).Info("User account deleted")
+
+	return nil
+}
@@ -2,25 +2,31 @@ package service
 
 import (
 	"context"
-	"fmt"
 
+	"github.com/fraud-detection-system/backend/internal/config"
 	"github.com/fraud-detection-system/backend/internal/models"
-	"github.com/fraud-detection-system/backend/internal/queue"
+	"github.com/fraud-detection-system/backend/internal/repository"
 	"github.com/fraud-detection-system/backend/internal/utils"
 )
 
+// highRiskAlertScore is the fraud score from which a verification raises an alert
+const highRiskAlertScore = 0.7
+
+// AlertService queues fraud alerts in the outbox, to be published to the
+// alerts topic once the verification they describe is committed. Payloads
+// are redacted, since alerts are read outside the platform.
 type AlertService struct {
-	producer *queue.Producer
+	topic string
 }
 
-func NewAlertService(producer *queue.Producer) *AlertService {
+func NewAlertService(cfg *config.Config) *AlertService {
 	return &AlertService{
-		producer: producer,
+		topic: cfg.Kafka.TopicAlerts,
 	}
 }
 
-// SendFraudAlert sends an alert for detected fraud
-func (s *AlertService) SendFraudAlert(ctx context.Context, verification *models.Verification, message *models.Message) error {
+// QueueFraudAlert queues an alert for detected fraud
+func (s *AlertService) QueueFraudAlert(ctx context.Context, outbox repository.OutboxStore, verification *models.Verification, message *models.Message) error {
 	payload := map[string]interface{}{
 		"verification_id": verification.ID.String(),
 		"message_id":      message.ID.String(),
@@ -31,18 +37,12 @@ func (s *AlertService) SendFraudAlert(ctx context.Context, verification *models.
 		"timestamp":       verification.CreatedAt,
 	}
 
-	if err := s.producer.PublishAlert(ctx, payload); err != nil {
-		utils.GetLogger().WithError(err).Error("Failed to send fraud alert")
-		return fmt.Errorf("failed to send fraud alert: %w", err)
-	}
-
-	utils.GetLogger().WithField("verification_id", verification.ID).Info("Fraud alert sent")
-	return nil
+	return outbox.AddMessage(ctx, s.topic, message.SenderHeader, models.NewQueueMessage(models.MessageTypeAlert, utils.RedactFields(payload)))
 }
 
-// SendHighRiskAlert sends an alert for high-risk messages
-func (s *AlertService) SendHighRiskAlert(ctx context.Context, verification *models.Verification) error {
-	if verification.FraudScore < 0.7 {
+// QueueHighRiskAlert queues an alert for high-risk messages
+func (s *AlertService) QueueHighRiskAlert(ctx context.Context, outbox repository.OutboxStore, verification *models.Verification) error {
+	if verification.FraudScore < highRiskAlertScore {
 		return nil // Only send alerts for high-risk messages
 	}
 
@@ -50,14 +50,9 @@ func (s *AlertService) SendHighRiskAlert(ctx context.Context, verification *mode
 		"verification_id": verification.ID.String(),
 		"fraud_score":     verification.FraudScore,
 		"risk_level":      "HIGH",
+		"tenant_id":       verification.TenantID,
 		"timestamp":       verification.CreatedAt,
 	}
 
-	if err := s.producer.PublishAlert(ctx, payload); err != nil {
-		utils.GetLogger().WithError(err).Error("Failed to send high-risk alert")
-		return fmt.Errorf("failed to send high-risk alert: %w", err)
-	}
-
-	return nil
+	return outbox.AddMessage(ctx, s.topic, verification.ID.String(), models.NewQueueMessage(models.MessageTypeAlert, utils.RedactFields(payload)))
 }
-
@@ -0,0 +1,99 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"github.com/fraud-detection-system/backend/internal/models"
+	"github.com/fraud-detection-system/backend/internal/repository"
+)
+
+// defaultTopLimit is how many entries each top list has by default
+const defaultTopLimit = 10
+
+// AnalyticsService keeps the analytics rollups up to date and serves fraud
+// trends from them
+type AnalyticsService struct {
+	uow           repository.Transactor
+	analyticsRepo repository.AnalyticsStore
+}
+
+func NewAnalyticsService(uow repository.Transactor, analyticsRepo repository.AnalyticsStore) *AnalyticsService {
+	return &AnalyticsService{uow: uow, analyticsRepo: analyticsRepo}
+}
+
+// ApplyVerification applies an analytics outbox entry recorded when a
+// message was verified, adding the verification to the rollups
+func (s *AnalyticsService) ApplyVerification(ctx context.Context, tx *sql.Tx, entry *models.OutboxEntry) error {
+	var update models.VerificationRollupUpdate
+	if err := json.Unmarshal([]byte(entry.Payload), &update); err != nil {
+		return Permanent(fmt.Errorf("invalid analytics payload: %w", err))
+	}
+	return s.uow.Bind(tx).Analytics.RecordVerification(ctx, update)
+}
+
+// GetTrends returns the time series of filter with every bucket in its
+// range, empty ones included, and the top limit fraud types, banks and
+// senders. From and To are widened to whole buckets.
+func (s *AnalyticsService) GetTrends(ctx context.Context, filter models.AnalyticsFilter, limit int) (*models.FraudTrends, error) {
+	if limit <= 0 {
+		limit = defaultTopLimit
+	}
+	filter.From = models.BucketStart(filter.From, filter.Granularity)
+	end := models.BucketStart(filter.To, filter.Granularity)
+	if end.Before(filter.To) {
+		end = models.NextBucket(end, filter.Granularity)
+	}
+	filter.To = end
+
+	stored, err := s.analyticsRepo.GetTrend(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	trends := &models.FraudTrends{
+		Granularity: filter.Granularity,
+		From:        filter.From,
+		To:          filter.To,
+		Buckets:     []*models.TrendBucket{},
+	}
+	for start := filter.From; start.Before(filter.To); start = models.NextBucket(start, filter.Granularity) {
+		bucket := &models.TrendBucket{Start: start}
+		if len(stored) > 0 && stored[0].Start.Equal(start) {
+			bucket = stored[0]
+			stored = stored[1:]
+		}
+		if bucket.RiskLevels == nil {
+			bucket.RiskLevels = map[string]int64{
+				models.RiskLevelLow:      0,
+				models.RiskLevelMedium:   0,
+				models.RiskLevelHigh:     0,
+				models.RiskLevelCritical: 0,
+			}
+		}
+		bucket.FraudRate = fraudRate(bucket.Fraud, bucket.Verifications)
+		trends.Verifications += bucket.Verifications
+		trends.Fraud += bucket.Fraud
+		trends.Buckets = append(trends.Buckets, bucket)
+	}
+	trends.FraudRate = fraudRate(trends.Fraud, trends.Verifications)
+
+	for dimension, top := range map[string]*[]models.TopEntry{
+		models.AnalyticsDimensionFraudType: &trends.TopFraudTypes,
+		models.AnalyticsDimensionBank:      &trends.TopBanks,
+		models.AnalyticsDimensionSender:    &trends.TopSenders,
+	} {
+		if *top, err = s.analyticsRepo.GetTop(ctx, filter, dimension, limit); err != nil {
+			return nil, err
+		}
+	}
+	return trends, nil
+}
+
+func fraudRate(fraud, total int64) float64 {
+	if total == 0 {
+		return 0
+	}
+	return float64(fraud) / float64(total)
+}
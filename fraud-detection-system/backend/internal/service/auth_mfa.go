@@ -0,0 +1,257 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+	"github.com/fraud-detection-system/backend/internal/models"
+	"github.com/fraud-detection-system/backend/internal/utils"
+)
+
+// VerifyMFALogin completes a two-step login by checking a TOTP or recovery
+// code against the challenge issued by Login
+func (s *AuthService) VerifyMFALogin(ctx context.Context, req *models.MFALoginRequest) (*models.TokenPair, error) {
+	claims, err := utils.ValidateChallengeToken(req.ChallengeToken, s.config.JWT.Secret)
+	if err != nil {
+		return nil, utils.ErrInvalidToken
+	}
+
+	user, err := s.userRepo.GetByID(ctx, claims.UserID)
+	if err != nil {
+		return nil, utils.ErrUserNotFound
+	}
+
+	if !user.IsActive {
+		return nil, utils.ErrAccountInactive
+	}
+
+	if !user.TOTPEnabled {
+		return nil, utils.ErrMFANotEnabled
+	}
+
+	if err := s.checkSecondFactor(ctx, user, req.Code, true); err != nil {
+		return nil, err
+	}
+
+	tokens, err := s.issueTokens(user, true)
+	if err != nil {
+		return nil, err
+	}
+
+	// Update last login
+	if err := s.userRepo.UpdateLastLogin(ctx, user.ID); err != nil {
+		utils.GetLoggerWithContext(ctx).WithError(err).Error("Failed to update last login")
+	}
+
+	return tokens, nil
+}
+
+// EnrollMFA generates a new TOTP secret for the user. Two-factor stays
+// disabled until the first code is confirmed with ConfirmMFA.
+func (s *AuthService) EnrollMFA(ctx context.Context, userID uuid.UUID) (*models.MFAEnrollmentResponse, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, utils.ErrUserNotFound
+	}
+
+	if user.TOTPEnabled {
+		return nil, utils.ErrMFAAlreadyEnabled
+	}
+
+	secret, err := utils.GenerateTOTPSecret()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate TOTP secret: %w", err)
+	}
+
+	if err := s.userRepo.SetTOTPSecret(ctx, user.ID, secret); err != nil {
+		return nil, err
+	}
+
+	return &models.MFAEnrollmentResponse{
+		Secret:     secret,
+		OTPAuthURI: utils.TOTPURI(s.config.MFA.Issuer, user.Email, secret),
+	}, nil
+}
+
+// ConfirmMFA enables two-factor authentication once the user proves their
+// authenticator produces valid codes. It returns recovery codes, shown only
+// once, and a token pair for a session that has passed two-factor.
+func (s *AuthService) ConfirmMFA(ctx context.Context, userID uuid.UUID, code string) (*models.MFAConfirmationResponse, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, utils.ErrUserNotFound
+	}
+
+	if user.TOTPEnabled {
+		return nil, utils.ErrMFAAlreadyEnabled
+	}
+
+	if user.TOTPSecret == nil {
+		return nil, utils.ErrMFANotEnabled
+	}
+
+	if err := s.checkSecondFactor(ctx, user, code, false); err != nil {
+		return nil, err
+	}
+
+	if err := s.userRepo.EnableTOTP(ctx, user.ID); err != nil {
+		return nil, err
+	}
+	user.TOTPEnabled = true
+
+	codes, err := s.replaceRecoveryCodes(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	tokens, err := s.issueTokens(user, true)
+	if err != nil {
+		return nil, err
+	}
+
+	utils.GetLoggerWithContext(ctx).WithField("user_id", user.ID).Info("Two-factor authentication enabled")
+
+	return &models.MFAConfirmationResponse{
+		RecoveryCodes: codes,
+		Tokens:        tokens,
+	}, nil
+}
+
+// DisableMFA turns off two-factor authentication after re-checking the
+// password and a current code. Roles covered by the MFA policy cannot opt out.
+func (s *AuthService) DisableMFA(ctx context.Context, userID uuid.UUID, req *models.MFADisableRequest) error {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return utils.ErrUserNotFound
+	}
+
+	if !user.TOTPEnabled {
+		return utils.ErrMFANotEnabled
+	}
+
+	if s.mfaRequired(user.Role) {
+		return utils.ErrForbidden
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(req.Password)); err != nil {
+		return utils.ErrInvalidCredentials
+	}
+
+	if err := s.checkSecondFactor(ctx, user, req.Code, true); err != nil {
+		return err
+	}
+
+	if err := s.userRepo.DisableTOTP(ctx, user.ID); err != nil {
+		return err
+	}
+
+	if err := s.recoveryRepo.DeleteForUser(ctx, user.ID); err != nil {
+		utils.GetLoggerWithContext(ctx).WithError(err).Error("Failed to delete recovery codes")
+	}
+
+	utils.GetLoggerWithContext(ctx).WithField("user_id", user.ID).Info("Two-factor authentication disabled")
+	return nil
+}
+
+// RegenerateRecoveryCodes replaces all recovery codes after checking a TOTP code
+func (s *AuthService) RegenerateRecoveryCodes(ctx context.Context, userID uuid.UUID, code string) (*models.RecoveryCodesResponse, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, utils.ErrUserNotFound
+	}
+
+	if !user.TOTPEnabled {
+		return nil, utils.ErrMFANotEnabled
+	}
+
+	if err := s.checkSecondFactor(ctx, user, code, false); err != nil {
+		return nil, err
+	}
+
+	codes, err := s.replaceRecoveryCodes(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.RecoveryCodesResponse{RecoveryCodes: codes}, nil
+}
+
+// VerifySecondFactor checks a TOTP or recovery code for a user with
+// two-factor enabled, before a sensitive action
+func (s *AuthService) VerifySecondFactor(ctx context.Context, user *models.User, code string) error {
+	return s.checkSecondFactor(ctx, user, code, true)
+}
+
+// checkSecondFactor validates a TOTP code, or a recovery code when allowed.
+// Failed attempts are counted per user and used TOTP steps cannot be replayed.
+func (s *AuthService) checkSecondFactor(ctx context.Context, user *models.User, code string, allowRecovery bool) error {
+	attemptsKey := fmt.Sprintf("auth:mfa_attempts:%s", user.ID)
+	if s.cache != nil {
+		attempts, err := s.cache.Increment(ctx, attemptsKey)
+		if err == nil {
+			if attempts == 1 {
+				_ = s.cache.SetExpire(ctx, attemptsKey, s.config.MFA.ChallengeExpiry)
+			}
+			if attempts > int64(s.config.MFA.MaxAttempts) {
+				return utils.ErrTooManyAttempts
+			}
+		}
+	}
+
+	if user.TOTPSecret != nil {
+		if step, ok := utils.ValidateTOTPCode(*user.TOTPSecret, code, time.Now()); ok {
+			if s.cache != nil {
+				usedKey := fmt.Sprintf("auth:totp_used:%s:%d", user.ID, step)
+				if used, err := s.cache.Exists(ctx, usedKey); err == nil && used {
+					return utils.ErrInvalidMFACode
+				}
+				_ = s.cache.Set(ctx, usedKey, true, 3*time.Minute)
+				_ = s.cache.Delete(ctx, attemptsKey)
+			}
+			return nil
+		}
+	}
+
+	if allowRecovery {
+		ok, err := s.recoveryRepo.Consume(ctx, user.ID, hashToken(utils.NormalizeRecoveryCode(code)))
+		if err != nil {
+			return err
+		}
+		if ok {
+			utils.GetLoggerWithContext(ctx).WithField("user_id", user.ID).Warn("Recovery code used for two-factor authentication")
+			if s.cache != nil {
+				_ = s.cache.Delete(ctx, attemptsKey)
+			}
+			return nil
+		}
+	}
+
+	return utils.ErrInvalidMFACode
+}
+
+// replaceRecoveryCodes generates and stores a fresh set of recovery codes
+func (s *AuthService) replaceRecoveryCodes(ctx context.Context, userID uuid.UUID) ([]string, error) {
+	codes, err := utils.GenerateRecoveryCodes(s.config.MFA.RecoveryCodeCount)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate recovery codes: %w", err)
+	}
+
+	hashes := make([]string, len(codes))
+	for i, code := range codes {
+		hashes[i] = hashToken(utils.NormalizeRecoveryCode(code))
+	}
+
+	if err := s.recoveryRepo.ReplaceForUser(ctx, userID, hashes); err != nil {
+		return nil, err
+	}
+
+	return codes, nil
+}
+
+// mfaRequired reports whether the MFA policy mandates two-factor for a role
+func (s *AuthService) mfaRequired(role string) bool {
+	return utils.MFARequiredForRole(s.config.MFA.RequiredRoles, role)
+}
@@ -0,0 +1,140 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/fraud-detection-system/backend/internal/cache"
+	"github.com/fraud-detection-system/backend/internal/config"
+	"github.com/fraud-detection-system/backend/internal/models"
+	"github.com/fraud-detection-system/backend/internal/repository/memory"
+	"github.com/fraud-detection-system/backend/internal/utils"
+)
+
+func newTestAuthService(db *memory.DB) *AuthService {
+	cfg := &config.Config{
+		JWT: config.JWTConfig{
+			Secret:              "test-secret",
+			Expiry:              time.Hour,
+			RefreshTokenExpiry:  24 * time.Hour,
+			PasswordResetExpiry: time.Hour,
+		},
+		MFA: config.MFAConfig{
+			Issuer:            "Test",
+			ChallengeExpiry:   5 * time.Minute,
+			RecoveryCodeCount: 3,
+			MaxAttempts:       5,
+		},
+	}
+	return NewAuthService(db.Users(), db.PasswordResets(), db.RecoveryCodes(), cache.NewMemoryCache(time.Minute), cfg)
+}
+
+func totpCode(t *testing.T, secret string, at time.Time) string {
+	t.Helper()
+	code, err := utils.GenerateTOTPCode(secret, at)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return code
+}
+
+func TestAuthServiceMFA(t *testing.T) {
+	ctx := context.Background()
+	db := memory.New()
+	s := newTestAuthService(db)
+	user, err := s.Register(ctx, &models.UserRegistration{Email: "mfa@example.com", Password: "SecurePass123!", FullName: "MFA User"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	login := &models.UserLogin{Email: "mfa@example.com", Password: "SecurePass123!"}
+
+	if _, err := s.ConfirmMFA(ctx, user.ID, "123456"); !errors.Is(err, utils.ErrMFANotEnabled) {
+		t.Errorf("confirm before enrolling: expected ErrMFANotEnabled, got %v", err)
+	}
+	enrollment, err := s.EnrollMFA(ctx, user.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp, err := s.Login(ctx, login); err != nil || resp.MFARequired || resp.TokenPair == nil {
+		t.Errorf("login before confirming: expected tokens, got %+v, %v", resp, err)
+	}
+
+	if _, err := s.ConfirmMFA(ctx, user.ID, "000000"); !errors.Is(err, utils.ErrInvalidMFACode) {
+		t.Errorf("confirm with a wrong code: expected ErrInvalidMFACode, got %v", err)
+	}
+	now := time.Now()
+	code := totpCode(t, enrollment.Secret, now)
+	confirmation, err := s.ConfirmMFA(ctx, user.ID, code)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(confirmation.RecoveryCodes) != 3 || confirmation.Tokens == nil {
+		t.Errorf("expected 3 recovery codes and tokens, got %+v", confirmation)
+	}
+	if _, err := s.EnrollMFA(ctx, user.ID); !errors.Is(err, utils.ErrMFAAlreadyEnabled) {
+		t.Errorf("enroll again: expected ErrMFAAlreadyEnabled, got %v", err)
+	}
+
+	challenge := func() string {
+		t.Helper()
+		resp, err := s.Login(ctx, login)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !resp.MFARequired || resp.ChallengeToken == "" || resp.TokenPair != nil {
+			t.Fatalf("expected a challenge instead of tokens, got %+v", resp)
+		}
+		return resp.ChallengeToken
+	}
+
+	// The code used to confirm cannot be replayed
+	if _, err := s.VerifyMFALogin(ctx, &models.MFALoginRequest{ChallengeToken: challenge(), Code: code}); !errors.Is(err, utils.ErrInvalidMFACode) {
+		t.Errorf("replayed code: expected ErrInvalidMFACode, got %v", err)
+	}
+	if _, err := s.VerifyMFALogin(ctx, &models.MFALoginRequest{ChallengeToken: "not-a-token", Code: code}); !errors.Is(err, utils.ErrInvalidToken) {
+		t.Errorf("bad challenge: expected ErrInvalidToken, got %v", err)
+	}
+	tokens, err := s.VerifyMFALogin(ctx, &models.MFALoginRequest{ChallengeToken: challenge(), Code: totpCode(t, enrollment.Secret, now.Add(30*time.Second))})
+	if err != nil {
+		t.Fatalf("next code: %v", err)
+	}
+	if claims, err := utils.ValidateToken(tokens.AccessToken, "test-secret"); err != nil || !claims.MFA {
+		t.Errorf("expected an access token that passed two-factor, got %+v, %v", claims, err)
+	}
+
+	// Recovery codes work once
+	recovery := &models.MFALoginRequest{ChallengeToken: challenge(), Code: confirmation.RecoveryCodes[0]}
+	if _, err := s.VerifyMFALogin(ctx, recovery); err != nil {
+		t.Errorf("recovery code: %v", err)
+	}
+	recovery.ChallengeToken = challenge()
+	if _, err := s.VerifyMFALogin(ctx, recovery); !errors.Is(err, utils.ErrInvalidMFACode) {
+		t.Errorf("reused recovery code: expected ErrInvalidMFACode, got %v", err)
+	}
+}
+
+func TestAuthServiceMFAAttemptLimit(t *testing.T) {
+	ctx := context.Background()
+	db := memory.New()
+	s := newTestAuthService(db)
+	user, err := s.Register(ctx, &models.UserRegistration{Email: "limit@example.com", Password: "SecurePass123!", FullName: "Limit User"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	enrollment, err := s.EnrollMFA(ctx, user.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < s.config.MFA.MaxAttempts; i++ {
+		if _, err := s.ConfirmMFA(ctx, user.ID, "000000"); !errors.Is(err, utils.ErrInvalidMFACode) {
+			t.Fatalf("attempt %d: expected ErrInvalidMFACode, got %v", i+1, err)
+		}
+	}
+	// Even the right code is refused once the limit is reached
+	if _, err := s.ConfirmMFA(ctx, user.ID, totpCode(t, enrollment.Secret, time.Now())); !errors.Is(err, utils.ErrTooManyAttempts) {
+		t.Errorf("expected ErrTooManyAttempts, got %v", err)
+	}
+}
@@ -0,0 +1,163 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	logtest "github.com/sirupsen/logrus/hooks/test"
+
+	"github.com/fraud-detection-system/backend/internal/models"
+	"github.com/fraud-detection-system/backend/internal/repository/memory"
+	"github.com/fraud-detection-system/backend/internal/utils"
+)
+
+// captureLogs routes the shared logger to a test hook until t ends
+func captureLogs(t *testing.T) *logtest.Hook {
+	t.Helper()
+	logger, hook := logtest.NewNullLogger()
+	logger.SetLevel(logrus.DebugLevel)
+	previous := utils.Log
+	utils.Log = logger
+	t.Cleanup(func() { utils.Log = previous })
+	return hook
+}
+
+// loggedResetToken returns the reset token of the last reset request logged
+func loggedResetToken(hook *logtest.Hook) string {
+	for i := len(hook.AllEntries()) - 1; i >= 0; i-- {
+		if token, ok := hook.AllEntries()[i].Data["reset_token"].(string); ok {
+			return token
+		}
+	}
+	return ""
+}
+
+func TestForgotPasswordLogging(t *testing.T) {
+	ctx := context.Background()
+	hook := captureLogs(t)
+	db := memory.New()
+	s := newTestAuthService(db)
+	if _, err := s.Register(ctx, &models.UserRegistration{Email: "reset@example.com", Password: "SecurePass123!", FullName: "Reset User"}); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, env := range []string{"development", "staging", "production"} {
+		s.config.App.Env = env
+		s.config.App.DevLogResetTokens = false
+		if err := s.ForgotPassword(ctx, "reset@example.com"); err != nil {
+			t.Fatal(err)
+		}
+		if token := loggedResetToken(hook); token != "" {
+			t.Errorf("%s: reset token logged without DevLogResetTokens", env)
+		}
+	}
+
+	// The flag has no effect outside development, even if set
+	s.config.App.Env = "production"
+	s.config.App.DevLogResetTokens = true
+	if err := s.ForgotPassword(ctx, "reset@example.com"); err != nil {
+		t.Fatal(err)
+	}
+	if token := loggedResetToken(hook); token != "" {
+		t.Error("production: reset token logged")
+	}
+
+	s.config.App.Env = "development"
+	if err := s.ForgotPassword(ctx, "reset@example.com"); err != nil {
+		t.Fatal(err)
+	}
+	if token := loggedResetToken(hook); token == "" {
+		t.Error("development with DevLogResetTokens: expected the reset token to be logged")
+	}
+}
+
+func TestPasswordReset(t *testing.T) {
+	ctx := context.Background()
+	hook := captureLogs(t)
+	db := memory.New()
+	s := newTestAuthService(db)
+	s.config.App.Env = "development"
+	s.config.App.DevLogResetTokens = true
+	user, err := s.Register(ctx, &models.UserRegistration{Email: "reset@example.com", Password: "SecurePass123!", FullName: "Reset User"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Unknown and inactive accounts get no token, and no error that would
+	// reveal whether the email is registered
+	if err := s.ForgotPassword(ctx, "nobody@example.com"); err != nil {
+		t.Errorf("unknown email: expected nil, got %v", err)
+	}
+	inactive, err := s.Register(ctx, &models.UserRegistration{Email: "inactive@example.com", Password: "SecurePass123!", FullName: "Inactive User"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	stored, err := db.Users().GetByID(ctx, inactive.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stored.IsActive = false
+	if err := db.Users().Update(ctx, stored); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.ForgotPassword(ctx, "inactive@example.com"); err != nil {
+		t.Errorf("inactive account: expected nil, got %v", err)
+	}
+	if token := loggedResetToken(hook); token != "" {
+		t.Error("unknown or inactive account: expected no token")
+	}
+
+	if err := s.ForgotPassword(ctx, "reset@example.com"); err != nil {
+		t.Fatal(err)
+	}
+	first := loggedResetToken(hook)
+	if err := s.ForgotPassword(ctx, "reset@example.com"); err != nil {
+		t.Fatal(err)
+	}
+	second := loggedResetToken(hook)
+	if first == "" || second == "" || first == second {
+		t.Fatalf("expected two distinct tokens, got %q and %q", first, second)
+	}
+
+	if err := s.ResetPassword(ctx, &models.ResetPasswordRequest{Token: second, NewPassword: "weak"}); !errors.Is(err, utils.ErrWeakPassword) {
+		t.Errorf("weak password: expected ErrWeakPassword, got %v", err)
+	}
+	if err := s.ResetPassword(ctx, &models.ResetPasswordRequest{Token: "not-a-token", NewPassword: "NewSecurePass456!"}); !errors.Is(err, utils.ErrInvalidResetToken) {
+		t.Errorf("unknown token: expected ErrInvalidResetToken, got %v", err)
+	}
+	if err := s.ResetPassword(ctx, &models.ResetPasswordRequest{Token: second, NewPassword: "NewSecurePass456!"}); err != nil {
+		t.Fatalf("reset: %v", err)
+	}
+
+	if _, err := s.Login(ctx, &models.UserLogin{Email: "reset@example.com", Password: "SecurePass123!"}); !errors.Is(err, utils.ErrInvalidCredentials) {
+		t.Errorf("old password: expected ErrInvalidCredentials, got %v", err)
+	}
+	if _, err := s.Login(ctx, &models.UserLogin{Email: "reset@example.com", Password: "NewSecurePass456!"}); err != nil {
+		t.Errorf("new password: %v", err)
+	}
+
+	// Tokens are single use, and a reset invalidates the user's other tokens
+	for name, token := range map[string]string{"used": second, "outstanding": first} {
+		if err := s.ResetPassword(ctx, &models.ResetPasswordRequest{Token: token, NewPassword: "OtherSecurePass789!"}); !errors.Is(err, utils.ErrInvalidResetToken) {
+			t.Errorf("%s token: expected ErrInvalidResetToken, got %v", name, err)
+		}
+	}
+
+	expired := "expired-reset-token"
+	if err := db.PasswordResets().Create(ctx, &models.PasswordResetToken{
+		ID:        uuid.New(),
+		UserID:    user.ID,
+		TokenHash: hashToken(expired),
+		ExpiresAt: time.Now().Add(-time.Minute),
+		CreatedAt: time.Now().Add(-time.Hour),
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.ResetPassword(ctx, &models.ResetPasswordRequest{Token: expired, NewPassword: "OtherSecurePass789!"}); !errors.Is(err, utils.ErrInvalidResetToken) {
+		t.Errorf("expired token: expected ErrInvalidResetToken, got %v", err)
+	}
+}
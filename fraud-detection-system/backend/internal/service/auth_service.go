@@ -2,11 +2,15 @@ package service
 
 import (
 	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"time"
 
 	"github.com/google/uuid"
 	"golang.org/x/crypto/bcrypt"
+	"github.com/fraud-detection-system/backend/internal/cache"
 	"github.com/fraud-detection-system/backend/internal/config"
 	"github.com/fraud-detection-system/backend/internal/models"
 	"github.com/fraud-detection-system/backend/internal/repository"
@@ -14,14 +18,26 @@ import (
 )
 
 type AuthService struct {
-	userRepo *repository.UserRepository
-	config   *config.Config
+	userRepo     repository.UserStore
+	resetRepo    repository.PasswordResetStore
+	recoveryRepo repository.RecoveryCodeStore
+	cache        cache.Cache
+	config       *config.Config
 }
 
-func NewAuthService(userRepo *repository.UserRepository, cfg *config.Config) *AuthService {
+func NewAuthService(
+	userRepo repository.UserStore,
+	resetRepo repository.PasswordResetStore,
+	recoveryRepo repository.RecoveryCodeStore,
+	cache cache.Cache,
+	cfg *config.Config,
+) *AuthService {
 	return &AuthService{
-		userRepo: userRepo,
-		config:   cfg,
+		userRepo:     userRepo,
+		resetRepo:    resetRepo,
+		recoveryRepo: recoveryRepo,
+		cache:        cache,
+		config:       cfg,
 	}
 }
 
@@ -56,6 +72,7 @@ func (s *AuthService) Register(ctx context.Context, req *models.UserRegistration
 		PhoneNumber:  req.PhoneNumber,
 		IsActive:     true,
 		IsVerified:   false,
+		Role:         models.RoleUser,
 		CreatedAt:    time.Now(),
 		UpdatedAt:    time.Now(),
 	}
@@ -71,49 +88,57 @@ func (s *AuthService) Register(ctx context.Context, req *models.UserRegistration
 		PhoneNumber: user.PhoneNumber,
 		IsActive:    user.IsActive,
 		IsVerified:  user.IsVerified,
+		Role:        user.Role,
+		TenantID:    user.TenantID,
 		CreatedAt:   user.CreatedAt,
 	}, nil
 }
 
-// Login authenticates a user and returns tokens
-func (s *AuthService) Login(ctx context.Context, req *models.UserLogin) (*models.TokenPair, error) {
+// Login authenticates a user. If two-factor authentication is enabled only a
+// challenge token is returned, to be exchanged via VerifyMFALogin.
+func (s *AuthService) Login(ctx context.Context, req *models.UserLogin) (*models.LoginResponse, error) {
 	// Get user by email
 	user, err := s.userRepo.GetByEmail(ctx, req.Email)
 	if err != nil {
 		return nil, utils.ErrInvalidCredentials
 	}
 
-	// Check if user is active
-	if !user.IsActive {
-		return nil, fmt.Errorf("user account is inactive")
-	}
-
 	// Verify password
 	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(req.Password)); err != nil {
 		return nil, utils.ErrInvalidCredentials
 	}
 
-	// Generate tokens
-	accessToken, err := utils.GenerateToken(user.ID, user.Email, s.config.JWT.Secret, s.config.JWT.Expiry)
-	if err != nil {
-		return nil, fmt.Errorf("failed to generate access token: %w", err)
+	// Checked after the password, so only the owner learns the account is
+	// inactive
+	if !user.IsActive {
+		return nil, utils.ErrAccountInactive
+	}
+
+	// Second step required
+	if user.TOTPEnabled {
+		challenge, err := utils.GenerateChallengeToken(user.ID, user.Email, s.config.JWT.Secret, s.config.MFA.ChallengeExpiry)
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate challenge token: %w", err)
+		}
+		return &models.LoginResponse{
+			MFARequired:    true,
+			ChallengeToken: challenge,
+		}, nil
 	}
 
-	refreshToken, err := utils.GenerateRefreshToken(user.ID, user.Email, s.config.JWT.Secret, s.config.JWT.RefreshTokenExpiry)
+	tokens, err := s.issueTokens(user, false)
 	if err != nil {
-		return nil, fmt.Errorf("failed to generate refresh token: %w", err)
+		return nil, err
 	}
 
 	// Update last login
 	if err := s.userRepo.UpdateLastLogin(ctx, user.ID); err != nil {
-		utils.GetLogger().WithError(err).Error("Failed to update last login")
+		utils.GetLoggerWithContext(ctx).WithError(err).Error("Failed to update last login")
 	}
 
-	return &models.TokenPair{
-		AccessToken:  accessToken,
-		RefreshToken: refreshToken,
-		ExpiresIn:    int64(s.config.JWT.Expiry.Seconds()),
-		TokenType:    "Bearer",
+	return &models.LoginResponse{
+		TokenPair:        tokens,
+		MFASetupRequired: s.mfaRequired(user.Role),
 	}, nil
 }
 
@@ -133,23 +158,34 @@ func (s *AuthService) RefreshToken(ctx context.Context, refreshToken string) (*m
 
 	// Check if user is active
 	if !user.IsActive {
-		return nil, fmt.Errorf("user account is inactive")
+		return nil, utils.ErrAccountInactive
+	}
+
+	// Reject refresh tokens issued before the last password change
+	if user.PasswordChangedAt != nil && claims.IssuedAt != nil &&
+		!claims.IssuedAt.Time.After(*user.PasswordChangedAt) {
+		return nil, utils.ErrInvalidToken
 	}
 
-	// Generate new tokens
-	accessToken, err := utils.GenerateToken(user.ID, user.Email, s.config.JWT.Secret, s.config.JWT.Expiry)
+	// Generate new tokens, keeping the second-factor state of the session
+	return s.issueTokens(user, claims.MFA && user.TOTPEnabled)
+}
+
+// issueTokens generates an access and refresh token pair for a user
+func (s *AuthService) issueTokens(user *models.User, mfa bool) (*models.TokenPair, error) {
+	accessToken, err := utils.GenerateToken(user.ID, user.Email, user.Role, user.TenantID, mfa, s.config.JWT.Secret, s.config.JWT.Expiry)
 	if err != nil {
 		return nil, fmt.Errorf("failed to generate access token: %w", err)
 	}
 
-	newRefreshToken, err := utils.GenerateRefreshToken(user.ID, user.Email, s.config.JWT.Secret, s.config.JWT.RefreshTokenExpiry)
+	refreshToken, err := utils.GenerateRefreshToken(user.ID, user.Email, user.Role, user.TenantID, mfa, s.config.JWT.Secret, s.config.JWT.RefreshTokenExpiry)
 	if err != nil {
 		return nil, fmt.Errorf("failed to generate refresh token: %w", err)
 	}
 
 	return &models.TokenPair{
 		AccessToken:  accessToken,
-		RefreshToken: newRefreshToken,
+		RefreshToken: refreshToken,
 		ExpiresIn:    int64(s.config.JWT.Expiry.Seconds()),
 		TokenType:    "Bearer",
 	}, nil
@@ -169,8 +205,152 @@ func (s *AuthService) GetUserByID(ctx context.Context, id uuid.UUID) (*models.Us
 		PhoneNumber: user.PhoneNumber,
 		IsActive:    user.IsActive,
 		IsVerified:  user.IsVerified,
+		Role:        user.Role,
+		TenantID:    user.TenantID,
+		TOTPEnabled: user.TOTPEnabled,
 		CreatedAt:   user.CreatedAt,
 		LastLoginAt: user.LastLoginAt,
 	}, nil
 }
 
+// ForgotPassword issues a single-use password reset token for the given email.
+// It returns nil for unknown or inactive accounts so callers cannot probe which
+// emails are registered.
+func (s *AuthService) ForgotPassword(ctx context.Context, email string) error {
+	user, err := s.userRepo.GetByEmail(ctx, email)
+	if err != nil || !user.IsActive {
+		return nil
+	}
+
+	rawToken, err := generateResetToken()
+	if err != nil {
+		return fmt.Errorf("failed to generate reset token: %w", err)
+	}
+
+	token := &models.PasswordResetToken{
+		ID:        uuid.New(),
+		UserID:    user.ID,
+		TokenHash: hashToken(rawToken),
+		ExpiresAt: time.Now().Add(s.config.JWT.PasswordResetExpiry),
+		CreatedAt: time.Now(),
+	}
+
+	if err := s.resetRepo.Create(ctx, token); err != nil {
+		return fmt.Errorf("failed to store reset token: %w", err)
+	}
+
+	// No mail transport is wired up yet. The token is a credential, so it is
+	// only logged when explicitly enabled for local development.
+	logger := utils.GetLoggerWithContext(ctx).WithField("user_id", user.ID)
+	if s.config.App.DevLogResetTokens && s.config.App.Env == "development" {
+		logger = logger.WithField("reset_token", rawToken)
+	}
+	logger.Info("Password reset requested")
+
+	return nil
+}
+
+// ResetPassword sets a new password using a reset token and revokes existing sessions
+func (s *AuthService) ResetPassword(ctx context.Context, req *models.ResetPasswordRequest) error {
+	if !utils.ValidatePassword(req.NewPassword) {
+		return utils.ErrWeakPassword
+	}
+
+	token, err := s.resetRepo.Consume(ctx, hashToken(req.Token))
+	if err != nil {
+		return utils.ErrInvalidResetToken
+	}
+
+	user, err := s.userRepo.GetByID(ctx, token.UserID)
+	if err != nil {
+		return utils.ErrInvalidResetToken
+	}
+
+	if err := s.setPassword(ctx, user.ID, req.NewPassword); err != nil {
+		return err
+	}
+
+	// Any other outstanding reset links for this user are no longer valid
+	if err := s.resetRepo.InvalidateForUser(ctx, user.ID); err != nil {
+		utils.GetLoggerWithContext(ctx).WithError(err).Error("Failed to invalidate reset tokens")
+	}
+
+	utils.GetLoggerWithContext(ctx).WithField("user_id", user.ID).Info("Password reset completed")
+	return nil
+}
+
+// ChangePassword changes the password of an authenticated user after checking
+// the old one. Other sessions are revoked and a fresh token pair is returned
+// carrying over whether the current session passed two-factor authentication.
+func (s *AuthService) ChangePassword(ctx context.Context, userID uuid.UUID, sessionMFA bool, req *models.ChangePasswordRequest) (*models.TokenPair, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, utils.ErrUserNotFound
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(req.OldPassword)); err != nil {
+		return nil, utils.ErrInvalidCredentials
+	}
+
+	if !utils.ValidatePassword(req.NewPassword) {
+		return nil, utils.ErrWeakPassword
+	}
+
+	if err := s.setPassword(ctx, user.ID, req.NewPassword); err != nil {
+		return nil, err
+	}
+
+	return s.issueTokens(user, sessionMFA && user.TOTPEnabled)
+}
+
+// setPassword hashes and stores a new password and revokes issued tokens
+func (s *AuthService) setPassword(ctx context.Context, userID uuid.UUID, password string) error {
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return fmt.Errorf("failed to hash password: %w", err)
+	}
+
+	if err := s.userRepo.UpdatePassword(ctx, userID, string(hashedPassword)); err != nil {
+		return fmt.Errorf("failed to update password: %w", err)
+	}
+
+	s.revokeSessions(ctx, userID)
+	return nil
+}
+
+// revokeSessions records a cut-off so access tokens issued before the current
+// second are rejected by SessionRevocationMiddleware. Refresh tokens are also
+// checked against password_changed_at, so a cache failure is only logged.
+func (s *AuthService) revokeSessions(ctx context.Context, userID uuid.UUID) {
+	revokeUserSessions(ctx, s.cache, s.config, userID)
+}
+
+func revokeUserSessions(ctx context.Context, redisCache cache.Cache, cfg *config.Config, userID uuid.UUID) {
+	if redisCache == nil {
+		return
+	}
+
+	ttl := cfg.JWT.RefreshTokenExpiry
+	if cfg.JWT.Expiry > ttl {
+		ttl = cfg.JWT.Expiry
+	}
+
+	if err := utils.RevokeSessions(ctx, redisCache, userID, ttl); err != nil {
+		utils.GetLoggerWithContext(ctx).WithError(err).WithField("user_id", userID).Error("Failed to revoke sessions")
+	}
+}
+
+// generateResetToken returns a random URL-safe reset token
+func generateResetToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// hashToken hashes a reset token or recovery code for storage and lookup
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
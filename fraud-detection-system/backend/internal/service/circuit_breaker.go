@@ -0,0 +1,113 @@
+package service
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// Circuit breaker states
+const (
+	CircuitClosed   = "closed"
+	CircuitOpen     = "open"
+	CircuitHalfOpen = "half_open"
+)
+
+// ErrCircuitOpen is returned when a call is rejected by an open circuit breaker
+var ErrCircuitOpen = errors.New("circuit breaker is open")
+
+// CircuitBreaker stops calls to a failing dependency after a run of
+// consecutive failures, and lets a single trial call through once the open
+// timeout has passed
+type CircuitBreaker struct {
+	mu               sync.Mutex
+	state            string
+	failures         int
+	failureThreshold int
+	openTimeout      time.Duration
+	openedAt         time.Time
+	trialInFlight    bool
+	now              func() time.Time
+}
+
+func NewCircuitBreaker(failureThreshold int, openTimeout time.Duration) *CircuitBreaker {
+	if failureThreshold < 1 {
+		failureThreshold = 1
+	}
+	return &CircuitBreaker{
+		state:            CircuitClosed,
+		failureThreshold: failureThreshold,
+		openTimeout:      openTimeout,
+		now:              time.Now,
+	}
+}
+
+// Allow reports whether a call may proceed, returning ErrCircuitOpen if not.
+// Every allowed call must be followed by Success, Failure or Ignore.
+func (b *CircuitBreaker) Allow() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case CircuitOpen:
+		if b.now().Sub(b.openedAt) < b.openTimeout {
+			return ErrCircuitOpen
+		}
+		b.state = CircuitHalfOpen
+		b.trialInFlight = true
+		return nil
+	case CircuitHalfOpen:
+		if b.trialInFlight {
+			return ErrCircuitOpen
+		}
+		b.trialInFlight = true
+		return nil
+	default:
+		return nil
+	}
+}
+
+// Success records a successful call and closes the circuit
+func (b *CircuitBreaker) Success() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.state = CircuitClosed
+	b.failures = 0
+	b.trialInFlight = false
+}
+
+// Failure records a failed call, opening the circuit when the threshold is
+// reached or when the half-open trial call fails
+func (b *CircuitBreaker) Failure() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.failures++
+	b.trialInFlight = false
+	if b.state == CircuitHalfOpen || b.failures >= b.failureThreshold {
+		b.state = CircuitOpen
+		b.openedAt = b.now()
+	}
+}
+
+// Ignore ends an allowed call without counting it, for outcomes that say
+// nothing about the dependency's health, such as a rejected request or a
+// caller that gave up. A half-open circuit lets the next trial call through.
+func (b *CircuitBreaker) Ignore() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.trialInFlight = false
+}
+
+// State returns the current state of the circuit
+func (b *CircuitBreaker) State() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == CircuitOpen && b.now().Sub(b.openedAt) >= b.openTimeout {
+		return CircuitHalfOpen
+	}
+	return b.state
+}
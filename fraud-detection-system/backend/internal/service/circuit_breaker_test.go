@@ -0,0 +1,84 @@
+package service
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCircuitBreaker(t *testing.T) {
+	now := time.Unix(0, 0)
+	b := NewCircuitBreaker(2, 30*time.Second)
+	b.now = func() time.Time { return now }
+
+	// Opens after the failure threshold
+	for i := 0; i < 2; i++ {
+		if err := b.Allow(); err != nil {
+			t.Fatalf("call %d rejected while closed", i)
+		}
+		b.Failure()
+	}
+	if b.State() != CircuitOpen || b.Allow() != ErrCircuitOpen {
+		t.Fatalf("expected open circuit, got %s", b.State())
+	}
+
+	// Lets a single trial call through after the timeout
+	now = now.Add(30 * time.Second)
+	if b.State() != CircuitHalfOpen {
+		t.Fatalf("expected half-open circuit, got %s", b.State())
+	}
+	if err := b.Allow(); err != nil {
+		t.Fatal("trial call rejected")
+	}
+	if b.Allow() != ErrCircuitOpen {
+		t.Fatal("second concurrent trial call allowed")
+	}
+
+	// A failed trial reopens the circuit
+	b.Failure()
+	if b.State() != CircuitOpen {
+		t.Fatalf("expected open circuit after failed trial, got %s", b.State())
+	}
+
+	// A successful trial closes it
+	now = now.Add(30 * time.Second)
+	if err := b.Allow(); err != nil {
+		t.Fatal("trial call rejected")
+	}
+	b.Success()
+	if b.State() != CircuitClosed || b.Allow() != nil {
+		t.Fatalf("expected closed circuit, got %s", b.State())
+	}
+}
+
+func TestCircuitBreakerIgnore(t *testing.T) {
+	now := time.Unix(0, 0)
+	b := NewCircuitBreaker(2, 30*time.Second)
+	b.now = func() time.Time { return now }
+
+	// Ignored calls neither open the circuit nor reset the failure count
+	b.Allow()
+	b.Failure()
+	b.Allow()
+	b.Ignore()
+	if b.State() != CircuitClosed {
+		t.Fatalf("expected closed circuit, got %s", b.State())
+	}
+	b.Allow()
+	b.Failure()
+	if b.State() != CircuitOpen {
+		t.Fatalf("expected the second failure to open the circuit, got %s", b.State())
+	}
+
+	// An ignored trial keeps the circuit half-open and frees the trial slot
+	now = now.Add(30 * time.Second)
+	if err := b.Allow(); err != nil {
+		t.Fatal("trial call rejected")
+	}
+	b.Ignore()
+	if b.State() != CircuitHalfOpen {
+		t.Fatalf("expected half-open circuit, got %s", b.State())
+	}
+	if err := b.Allow(); err != nil {
+		t.Fatal("next trial call rejected")
+	}
+}
@@ -0,0 +1,81 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+
+	"github.com/google/uuid"
+	"github.com/fraud-detection-system/backend/internal/classifier"
+	"github.com/fraud-detection-system/backend/internal/repository"
+	"github.com/fraud-detection-system/backend/internal/utils"
+)
+
+// ClassifierTrainingService trains the embedded classifier from user reports:
+// fraud reports are positive examples and false positive reports negative ones
+type ClassifierTrainingService struct {
+	reportRepo repository.ReportStore
+	batchSize  int
+}
+
+func NewClassifierTrainingService(reportRepo repository.ReportStore, batchSize int) *ClassifierTrainingService {
+	return &ClassifierTrainingService{
+		reportRepo: reportRepo,
+		batchSize:  batchSize,
+	}
+}
+
+// LoadExamples reads every labeled report
+func (s *ClassifierTrainingService) LoadExamples(ctx context.Context) ([]classifier.Example, error) {
+	var examples []classifier.Example
+	afterID := uuid.Nil
+	for {
+		reports, err := s.reportRepo.GetLabeledBatch(ctx, afterID, s.batchSize)
+		if err != nil {
+			return nil, err
+		}
+		for _, report := range reports {
+			examples = append(examples, classifier.Example{
+				Content:  report.Content,
+				Features: ExtractFeatures(report.Content, report.SenderHeader),
+				IsFraud:  report.ReportType == "FRAUD",
+			})
+		}
+		if len(reports) < s.batchSize {
+			return examples, nil
+		}
+		afterID = reports[len(reports)-1].ID
+	}
+}
+
+// Train fits a classifier on the labeled reports, holding out a fraction of
+// them to measure it. holdout must be in [0, 1).
+func (s *ClassifierTrainingService) Train(ctx context.Context, opts classifier.TrainOptions, holdout float64) (*classifier.Model, classifier.Metrics, error) {
+	if holdout < 0 || holdout >= 1 {
+		return nil, classifier.Metrics{}, fmt.Errorf("holdout must be at least 0 and less than 1, got %v", holdout)
+	}
+
+	examples, err := s.LoadExamples(ctx)
+	if err != nil {
+		return nil, classifier.Metrics{}, err
+	}
+	if len(examples) == 0 {
+		return nil, classifier.Metrics{}, fmt.Errorf("no labeled reports to train on")
+	}
+
+	rng := rand.New(rand.NewSource(opts.Seed))
+	rng.Shuffle(len(examples), func(i, j int) { examples[i], examples[j] = examples[j], examples[i] })
+	split := len(examples) - int(float64(len(examples))*holdout)
+	train, test := examples[:split], examples[split:]
+
+	utils.GetLoggerWithContext(ctx).WithField("train", len(train)).WithField("test", len(test)).Info("Training embedded classifier")
+	model, err := classifier.Train(train, opts)
+	if err != nil {
+		return nil, classifier.Metrics{}, err
+	}
+
+	if len(test) == 0 {
+		test = train
+	}
+	return model, model.Evaluate(test), nil
+}
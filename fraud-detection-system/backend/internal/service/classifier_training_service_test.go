@@ -0,0 +1,61 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/fraud-detection-system/backend/internal/classifier"
+	"github.com/fraud-detection-system/backend/internal/models"
+	"github.com/fraud-detection-system/backend/internal/repository/memory"
+)
+
+func TestClassifierTrainingLoadExamples(t *testing.T) {
+	ctx := context.Background()
+	db := memory.New()
+	userID := uuid.New()
+	for _, report := range []models.Report{
+		{ReportType: "FRAUD", Status: models.ReportStatusReviewed, Content: "Your KYC is pending, click http://bit.ly/x"},
+		{ReportType: "FALSE_POSITIVE", Status: models.ReportStatusResolved, Content: "Your OTP is 123456"},
+		{ReportType: "FRAUD", Status: models.ReportStatusPending, Content: "unreviewed"},
+		{ReportType: "FRAUD", Status: models.ReportStatusDismissed, Content: "dismissed"},
+		{ReportType: "SPAM", Status: models.ReportStatusReviewed, Content: "spam"},
+		{ReportType: "FRAUD", Status: models.ReportStatusReviewed, Content: "anonymized", UserID: &userID},
+	} {
+		report := report
+		report.ID = uuid.New()
+		if err := db.Reports().Create(ctx, &report); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := db.Reports().AnonymizeByUserID(ctx, userID); err != nil {
+		t.Fatal(err)
+	}
+
+	// A batch size of 1 exercises paging
+	examples, err := NewClassifierTrainingService(db.Reports(), 1).LoadExamples(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(examples) != 2 {
+		t.Fatalf("expected the 2 reviewed fraud and false positive reports, got %+v", examples)
+	}
+	fraud := 0
+	for _, example := range examples {
+		if example.IsFraud {
+			fraud++
+		}
+	}
+	if fraud != 1 {
+		t.Errorf("expected 1 fraud example, got %d", fraud)
+	}
+}
+
+func TestClassifierTrainingHoldout(t *testing.T) {
+	service := NewClassifierTrainingService(memory.New().Reports(), 10)
+	for _, holdout := range []float64{-0.1, 1, 1.5} {
+		if _, _, err := service.Train(context.Background(), classifier.DefaultTrainOptions(), holdout); err == nil {
+			t.Errorf("holdout %v: expected an error", holdout)
+		}
+	}
+}
@@ -0,0 +1,43 @@
+package service
+
+import (
+	"context"
+
+	"github.com/fraud-detection-system/backend/internal/config"
+	"github.com/fraud-detection-system/backend/internal/events"
+	"github.com/fraud-detection-system/backend/internal/repository"
+	"github.com/fraud-detection-system/backend/internal/utils"
+)
+
+// EventQueue checks domain events against the schema registry and queues
+// them in the outbox for the events topic, so they are published only if
+// the change they describe commits
+type EventQueue struct {
+	registry *events.Registry
+	topic    string
+}
+
+func NewEventQueue(cfg *config.Config) *EventQueue {
+	return &EventQueue{
+		registry: events.DefaultRegistry(),
+		topic:    cfg.Kafka.TopicEvents,
+	}
+}
+
+// Queue adds event to outbox, which should be bound to the transaction that
+// makes the change
+func (q *EventQueue) Queue(ctx context.Context, outbox repository.OutboxStore, event events.Event) error {
+	message, err := q.registry.NewMessage(event)
+	if err != nil {
+		return err
+	}
+	// Only free-text fields are redacted, so IDs and timestamps still match
+	// their schema
+	schema, _ := q.registry.Lookup(message.Type, message.Version)
+	for _, f := range schema.Fields {
+		if value, ok := message.Payload[f.Name]; ok && f.IsText() {
+			message.Payload[f.Name] = utils.RedactField(f.Name, value)
+		}
+	}
+	return outbox.AddMessage(ctx, q.topic, event.Key(), message)
+}
@@ -0,0 +1,86 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/fraud-detection-system/backend/internal/config"
+	"github.com/fraud-detection-system/backend/internal/events"
+	"github.com/fraud-detection-system/backend/internal/models"
+	"github.com/fraud-detection-system/backend/internal/repository/memory"
+)
+
+func TestEventQueueRedaction(t *testing.T) {
+	ctx := context.Background()
+	db := memory.New()
+	q := NewEventQueue(&config.Config{Kafka: config.KafkaConfig{TopicEvents: "events"}})
+
+	// The last group of these UUIDs and the fractional seconds are digit
+	// runs that look like account numbers
+	sourceID := uuid.MustParse("123e4567-e89b-12d3-a456-426614174000")
+	reviewerID := uuid.MustParse("9b2f1c3d-0a4e-4f6b-8c7d-000123456789")
+	at := time.Date(2026, 1, 2, 3, 4, 5, 123456789, time.FixedZone("IST", 19800))
+
+	queued := []events.Event{
+		&events.VerificationCompleted{
+			VerificationID:       uuid.New(),
+			MessageID:            uuid.New(),
+			TenantID:             models.DefaultTenantID,
+			SenderHeader:         "a/c 123456789012",
+			ModelVersion:         "v1",
+			SourceVerificationID: &sourceID,
+			CompletedAt:          at,
+		},
+		&events.ReportResolved{
+			ReportID:     uuid.New(),
+			ReportType:   "FRAUD",
+			SenderHeader: "VM-BANK",
+			Status:       models.ReportStatusResolved,
+			ReviewedBy:   reviewerID,
+			ResolvedAt:   at,
+		},
+	}
+	for _, event := range queued {
+		if err := q.Queue(ctx, db.Outbox(), event); err != nil {
+			t.Fatalf("queue %s: %v", event.EventType(), err)
+		}
+	}
+
+	entries, err := db.Outbox().GetPending(ctx, models.OutboxKindKafka, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != len(queued) {
+		t.Fatalf("expected %d entries, got %d", len(queued), len(entries))
+	}
+	for _, entry := range entries {
+		var message models.QueueMessage
+		if err := json.Unmarshal([]byte(entry.Payload), &message); err != nil {
+			t.Fatal(err)
+		}
+		if err := events.DefaultRegistry().Validate(&message); err != nil {
+			t.Errorf("queued message no longer matches its schema: %v", err)
+		}
+
+		switch message.Type {
+		case events.TypeVerificationCompleted:
+			if message.Payload["source_verification_id"] != sourceID.String() {
+				t.Errorf("source_verification_id changed to %v", message.Payload["source_verification_id"])
+			}
+			if message.Payload["completed_at"] != at.Format(time.RFC3339Nano) {
+				t.Errorf("completed_at changed to %v", message.Payload["completed_at"])
+			}
+			// Free text is still redacted
+			if message.Payload["sender_header"] != "a/c ********9012" {
+				t.Errorf("expected sender_header to be redacted, got %v", message.Payload["sender_header"])
+			}
+		case events.TypeReportResolved:
+			if message.Payload["reviewed_by"] != reviewerID.String() {
+				t.Errorf("reviewed_by changed to %v", message.Payload["reviewed_by"])
+			}
+		}
+	}
+}
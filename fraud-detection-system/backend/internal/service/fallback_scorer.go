@@ -0,0 +1,94 @@
+package service
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/fraud-detection-system/backend/internal/models"
+)
+
+// FallbackModelVersion is recorded on verifications scored without the ML service
+const FallbackModelVersion = "heuristic-fallback-v1"
+
+// FallbackScorer is an in-process heuristic used when the ML service is
+// unavailable. It only looks at the extracted message features.
+type FallbackScorer struct{}
+
+func NewFallbackScorer() *FallbackScorer {
+	return &FallbackScorer{}
+}
+
+// Score returns a prediction in the same shape as the ML service response
+func (f *FallbackScorer) Score(features models.MessageFeatures) *models.MLInferenceResponse {
+	score := 0.0
+	var signals []string
+
+	if features.HasKYCKeywords {
+		score += 0.3
+		signals = append(signals, "KYC or account update request")
+	}
+	if features.HasUrgentWords {
+		score += math.Min(0.1*float64(features.UrgentWordCount), 0.25)
+		signals = append(signals, "urgent language")
+	}
+	if features.HasLinks {
+		score += 0.2
+		signals = append(signals, "contains links")
+	}
+	if features.HasPhoneNumber {
+		score += 0.1
+		signals = append(signals, "asks to call a phone number")
+	}
+	if features.HasBankNames && (features.HasLinks || features.HasPhoneNumber) {
+		score += 0.1
+		signals = append(signals, "mentions a bank alongside a link or number")
+	}
+	if features.CapitalRatio > 0.3 {
+		score += 0.05
+	}
+	if features.SpecialCharRatio > 0.15 {
+		score += 0.05
+	}
+	score = math.Min(score, 1.0)
+
+	isFraud := score >= 0.5
+
+	return &models.MLInferenceResponse{
+		IsFraud:    isFraud,
+		FraudScore: score,
+		FraudType:  fallbackFraudType(features, isFraud),
+		// Heuristics are less certain than the trained models
+		Confidence: 0.5,
+		ModelPredictions: map[string]interface{}{
+			"heuristic": score,
+		},
+		Explanation:  fallbackExplanation(signals),
+		ModelVersion: FallbackModelVersion,
+	}
+}
+
+// fallbackFraudType uses the same fraud types as the ML service
+func fallbackFraudType(features models.MessageFeatures, isFraud bool) string {
+	switch {
+	case !isFraud:
+		return "none"
+	case features.HasKYCKeywords && features.HasUrgentWords:
+		return "kyc_fraud"
+	case features.HasLinks && features.HasUrgentWords:
+		return "phishing"
+	case features.HasPhoneNumber && features.HasUrgentWords:
+		return "vishing"
+	case features.HasUrgentWords:
+		return "urgency_scam"
+	default:
+		return "generic_fraud"
+	}
+}
+
+func fallbackExplanation(signals []string) string {
+	if len(signals) == 0 {
+		return "Heuristic analysis found no strong fraud indicators."
+	}
+	return fmt.Sprintf("Heuristic analysis found: %s.", strings.Join(signals, ", "))
+}
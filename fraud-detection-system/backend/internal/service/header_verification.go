@@ -2,20 +2,30 @@ package service
 
 import (
 	"context"
+	"database/sql"
+	"encoding/json"
 	"fmt"
+	"time"
 
+	"github.com/fraud-detection-system/backend/internal/config"
+	"github.com/fraud-detection-system/backend/internal/events"
 	"github.com/fraud-detection-system/backend/internal/models"
 	"github.com/fraud-detection-system/backend/internal/repository"
-	"github.com/fraud-detection-system/backend/internal/utils"
 )
 
 type HeaderVerificationService struct {
-	rbiRepo *repository.RBIRepository
+	rbiRepo      repository.RBIStore
+	uow          repository.Transactor
+	events       *EventQueue
+	verdictCache *VerdictCache
 }
 
-func NewHeaderVerificationService(rbiRepo *repository.RBIRepository) *HeaderVerificationService {
+func NewHeaderVerificationService(rbiRepo repository.RBIStore, uow repository.Transactor, verdictCache *VerdictCache, cfg *config.Config) *HeaderVerificationService {
 	return &HeaderVerificationService{
-		rbiRepo: rbiRepo,
+		rbiRepo:      rbiRepo,
+		uow:          uow,
+		events:       NewEventQueue(cfg),
+		verdictCache: verdictCache,
 	}
 }
 
@@ -61,29 +71,88 @@ func (s *HeaderVerificationService) VerifyHeader(ctx context.Context, senderHead
 	result.IsVerified = true
 
 	// Determine risk level based on reputation score and fraud reports
-	if sender.ReputationScore >= 0.8 && sender.FraudReportCount < 5 {
-		result.RiskLevel = "LOW"
+	result.RiskLevel = senderRiskLevel(sender.ReputationScore, sender.FraudReportCount)
+	switch result.RiskLevel {
+	case "LOW":
 		result.Explanation = fmt.Sprintf("Sender ID '%s' is verified and has a good reputation (%.2f)", senderHeader, sender.ReputationScore)
-	} else if sender.ReputationScore >= 0.5 && sender.FraudReportCount < 20 {
-		result.RiskLevel = "MEDIUM"
-		result.Explanation = fmt.Sprintf("Sender ID '%s' is verified but has moderate reputation (%.2f) with %d fraud reports", 
+	case "MEDIUM":
+		result.Explanation = fmt.Sprintf("Sender ID '%s' is verified but has moderate reputation (%.2f) with %d fraud reports",
 			senderHeader, sender.ReputationScore, sender.FraudReportCount)
-	} else {
-		result.RiskLevel = "HIGH"
-		result.Explanation = fmt.Sprintf("Sender ID '%s' is verified but has low reputation (%.2f) with %d fraud reports", 
+	default:
+		result.Explanation = fmt.Sprintf("Sender ID '%s' is verified but has low reputation (%.2f) with %d fraud reports",
 			senderHeader, sender.ReputationScore, sender.FraudReportCount)
 	}
 
 	return result, nil
 }
 
-// UpdateSenderStats updates sender statistics after verification
-func (s *HeaderVerificationService) UpdateSenderStats(ctx context.Context, senderHeader string, isFraud bool) error {
-	err := s.rbiRepo.UpdateSenderStats(ctx, senderHeader, isFraud)
+// senderRiskLevel buckets a verified, active sender by reputation and fraud reports
+func senderRiskLevel(reputationScore float64, fraudReportCount int) string {
+	switch {
+	case reputationScore >= 0.8 && fraudReportCount < 5:
+		return "LOW"
+	case reputationScore >= 0.5 && fraudReportCount < 20:
+		return "MEDIUM"
+	default:
+		return "HIGH"
+	}
+}
+
+// ApplySenderStats applies a sender_stats outbox entry recorded when a
+// message was verified, and queues an event if the sender's reputation moved.
+// Cached verdicts are dropped when the sender changes risk level, since they
+// carry the old one.
+func (s *HeaderVerificationService) ApplySenderStats(ctx context.Context, tx *sql.Tx, entry *models.OutboxEntry) error {
+	var update models.SenderStatsUpdate
+	if err := json.Unmarshal([]byte(entry.Payload), &update); err != nil {
+		return Permanent(fmt.Errorf("invalid sender stats payload: %w", err))
+	}
+
+	repos := s.uow.Bind(tx)
+	previous, sender, err := repos.RBI.UpdateSenderStats(ctx, update.SenderHeader, update.IsFraud)
+	if err != nil || sender == nil || sender.ReputationScore == previous {
+		return err
+	}
+
+	previousReports := sender.FraudReportCount
+	if update.IsFraud {
+		previousReports--
+	}
+	if senderRiskLevel(previous, previousReports) != senderRiskLevel(sender.ReputationScore, sender.FraudReportCount) {
+		s.verdictCache.Invalidate(ctx, "sender "+sender.SenderID+" risk level changed")
+	}
+
+	return s.events.Queue(ctx, repos.Outbox, &events.SenderReputationChanged{
+		SenderID:         sender.SenderID,
+		PreviousScore:    &previous,
+		ReputationScore:  sender.ReputationScore,
+		MessageCount:     sender.MessageCount,
+		FraudReportCount: sender.FraudReportCount,
+		Reason:           events.ReputationReasonVerification,
+		ChangedAt:        time.Now(),
+	})
+}
+
+// RegisterSender adds a sender to the registry with its
+// SenderReputationChanged event. Cached verdicts are dropped since they may
+// have been computed while the sender was unknown.
+func (s *HeaderVerificationService) RegisterSender(ctx context.Context, sender *models.SenderRegistry) error {
+	err := s.uow.Do(ctx, func(repos *repository.TxRepositories) error {
+		if err := repos.RBI.CreateSenderRegistry(ctx, sender); err != nil {
+			return err
+		}
+		return s.events.Queue(ctx, repos.Outbox, &events.SenderReputationChanged{
+			SenderID:         sender.SenderID,
+			ReputationScore:  sender.ReputationScore,
+			MessageCount:     sender.MessageCount,
+			FraudReportCount: sender.FraudReportCount,
+			Reason:           events.ReputationReasonRegistered,
+			ChangedAt:        time.Now(),
+		})
+	})
 	if err != nil {
-		utils.GetLogger().WithError(err).WithField("sender", senderHeader).Error("Failed to update sender stats")
-		// Don't fail the operation, just log the error
+		return err
 	}
+	s.verdictCache.Invalidate(ctx, "sender registry changed")
 	return nil
 }
-
@@ -0,0 +1,44 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/fraud-detection-system/backend/internal/cache"
+	"github.com/fraud-detection-system/backend/internal/config"
+	"github.com/fraud-detection-system/backend/internal/models"
+	"github.com/fraud-detection-system/backend/internal/repository/memory"
+)
+
+func TestApplySenderStatsInvalidatesVerdicts(t *testing.T) {
+	ctx := context.Background()
+	db := memory.New()
+	cfg := &config.Config{Kafka: config.KafkaConfig{TopicEvents: "events"}}
+	verdicts := NewVerdictCache(cache.NewMemoryCache(time.Minute), cfg)
+	s := NewHeaderVerificationService(db.RBI(), db.UnitOfWork(), verdicts, cfg)
+
+	sender := &models.SenderRegistry{SenderID: "VM-BANK", IsVerified: true, IsActive: true, ReputationScore: 0.85, FraudReportCount: 4}
+	if err := db.RBI().CreateSenderRegistry(ctx, sender); err != nil {
+		t.Fatal(err)
+	}
+	apply := func(payload string) {
+		t.Helper()
+		if err := s.ApplySenderStats(ctx, nil, &models.OutboxEntry{Payload: payload}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	key := verdicts.Key(ctx, "", "hello", "VM-BANK")
+
+	// 0.85 -> 0.86 keeps the sender at LOW risk
+	apply(`{"sender_header":"VM-BANK","is_fraud":false}`)
+	if got := verdicts.Key(ctx, "", "hello", "VM-BANK"); got != key {
+		t.Error("expected verdicts to be kept while the risk level is unchanged")
+	}
+
+	// A fifth fraud report moves it to MEDIUM
+	apply(`{"sender_header":"VM-BANK","is_fraud":true}`)
+	if got := verdicts.Key(ctx, "", "hello", "VM-BANK"); got == key {
+		t.Error("expected verdicts to be invalidated when the risk level changed")
+	}
+}
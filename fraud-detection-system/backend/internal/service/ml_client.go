@@ -1,104 +1,325 @@
 package service
 
 import (
-	"bytes"
 	"context"
-	"encoding/json"
 	"fmt"
-	"io"
+	"hash/fnv"
+	"math/rand"
 	"net/http"
+	"strings"
 	"time"
 
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	"github.com/fraud-detection-system/backend/internal/config"
+	"github.com/fraud-detection-system/backend/internal/metrics"
 	"github.com/fraud-detection-system/backend/internal/models"
+	"github.com/fraud-detection-system/backend/internal/tracing"
 	"github.com/fraud-detection-system/backend/internal/utils"
 )
 
+// mlEndpoint is a named model service with its own circuit breaker
+type mlEndpoint struct {
+	name      string
+	transport mlTransport
+	breaker   *CircuitBreaker
+}
+
+// MLClient routes predictions between named model endpoints: a champion
+// that serves by default, and an optional challenger that receives a share
+// of traffic or specific tenants and otherwise scores in shadow
 type MLClient struct {
-	baseURL    string
-	httpClient *http.Client
+	endpoints  map[string]*mlEndpoint
+	champion   *mlEndpoint
+	challenger *mlEndpoint
 	config     *config.Config
 }
 
-func NewMLClient(cfg *config.Config) *MLClient {
-	return &MLClient{
-		baseURL: cfg.ML.ServiceURL,
-		httpClient: &http.Client{
-			Timeout: cfg.ML.InferenceTimeout,
-		},
-		config: cfg,
+// NewMLClient creates a client for the configured models, over JSON/HTTP or
+// gRPC depending on ML_TRANSPORT. Models with an "embedded:" URL are scored
+// in-process by the embedded classifier.
+func NewMLClient(cfg *config.Config) (*MLClient, error) {
+	c := &MLClient{
+		endpoints: make(map[string]*mlEndpoint),
+		config:    cfg,
+	}
+	httpClient := &http.Client{
+		Timeout: cfg.ML.InferenceTimeout,
 	}
+	for _, model := range cfg.ML.Models {
+		var transport mlTransport
+		var err error
+		switch {
+		case strings.HasPrefix(model.URL, config.MLEmbeddedScheme):
+			transport, err = newEmbeddedTransport(strings.TrimPrefix(model.URL, config.MLEmbeddedScheme))
+		case cfg.ML.Transport == config.MLTransportGRPC:
+			transport, err = newGRPCTransport(model.URL, cfg.ML.GRPCPoolSize, cfg.ML.InferenceTimeout)
+		default:
+			transport = newHTTPTransport(model.URL, httpClient)
+		}
+		if err != nil {
+			c.Close()
+			return nil, fmt.Errorf("failed to initialize model %s: %w", model.Name, err)
+		}
+		c.endpoints[model.Name] = &mlEndpoint{
+			name:      model.Name,
+			transport: transport,
+			breaker:   NewCircuitBreaker(cfg.ML.BreakerFailureThreshold, cfg.ML.BreakerOpenTimeout),
+		}
+	}
+	c.champion = c.endpoints[cfg.ML.Champion]
+	c.challenger = c.endpoints[cfg.ML.Challenger]
+	return c, nil
 }
 
-// Predict sends a prediction request to the ML service
-func (c *MLClient) Predict(ctx context.Context, req *models.MLInferenceRequest) (*models.MLInferenceResponse, error) {
-	url := fmt.Sprintf("%s/api/v1/predict", c.baseURL)
-
-	// Marshal request
-	jsonData, err := json.Marshal(req)
-	if err != nil {
-		return nil, fmt.Errorf("failed to marshal request: %w", err)
+// Close releases the connections to the model services
+func (c *MLClient) Close() error {
+	for _, endpoint := range c.endpoints {
+		if err := endpoint.transport.Close(); err != nil {
+			return err
+		}
 	}
+	return nil
+}
 
-	// Create HTTP request
-	httpReq, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(jsonData))
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+// CircuitState returns the state of the circuit breaker guarding the champion model
+func (c *MLClient) CircuitState() string {
+	return c.champion.breaker.State()
+}
+
+// CircuitStates returns the circuit breaker state of every model
+func (c *MLClient) CircuitStates() map[string]string {
+	states := make(map[string]string, len(c.endpoints))
+	for name, endpoint := range c.endpoints {
+		states[name] = endpoint.breaker.State()
 	}
+	return states
+}
 
-	httpReq.Header.Set("Content-Type", "application/json")
+// FallbackEnabled reports whether callers may fall back to the local scorer
+// when predictions fail
+func (c *MLClient) FallbackEnabled() bool {
+	return c.config.ML.FallbackEnabled
+}
 
-	// Send request
-	startTime := time.Now()
-	resp, err := c.httpClient.Do(httpReq)
-	if err != nil {
-		utils.GetLogger().WithError(err).Error("Failed to call ML service")
-		return nil, fmt.Errorf("failed to call ML service: %w", err)
+// route picks the model that decides the verdict for a request, and the
+// model to score it in shadow, if any. The traffic split is keyed on the
+// message so identical messages always go to the same model.
+func (c *MLClient) route(req *models.MLInferenceRequest, tenantID string) (*mlEndpoint, *mlEndpoint) {
+	primary := c.champion
+	if name, ok := c.config.ML.TenantModels[tenantID]; ok {
+		primary = c.endpoints[name]
+	} else if c.challenger != nil && c.config.ML.ChallengerPercent > 0 {
+		h := fnv.New32a()
+		h.Write([]byte(req.SenderHeader + "\x00" + req.Content))
+		if int(h.Sum32()%100) < c.config.ML.ChallengerPercent {
+			primary = c.challenger
+		}
 	}
-	defer resp.Body.Close()
 
-	// Check status code
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		utils.GetLogger().WithField("status", resp.StatusCode).WithField("body", string(body)).Error("ML service returned error")
-		return nil, fmt.Errorf("ML service returned status %d", resp.StatusCode)
+	if c.challenger == nil || !c.config.ML.ShadowEnabled {
+		return primary, nil
+	}
+	switch primary {
+	case c.champion:
+		return primary, c.challenger
+	case c.challenger:
+		return primary, c.champion
+	default:
+		return primary, nil
 	}
+}
+
+// ShadowPrediction is a shadow model's prediction, scored in the background
+// under its own timeout so it never delays the verdict
+type ShadowPrediction struct {
+	Model string
+	done  chan struct{}
+	resp  *models.MLInferenceResponse
+}
+
+// Wait blocks until the shadow model has answered, failed or timed out, and
+// reports whether it produced a score
+func (p *ShadowPrediction) Wait() (models.ModelScore, bool) {
+	<-p.done
+	if p.resp == nil {
+		return models.ModelScore{}, false
+	}
+	return modelScore(p.Model, p.resp), true
+}
+
+// Predict scores a request with the model routed for the tenant. When shadow
+// scoring is on, the other model scores the same request in the background
+// and is returned as a ShadowPrediction; it never affects the verdict. If a
+// challenger fails, the champion is used instead.
+func (c *MLClient) Predict(ctx context.Context, req *models.MLInferenceRequest, tenantID string) (*models.MLInferenceResponse, *models.MLRouting, *ShadowPrediction, error) {
+	ctx, span := tracing.Start(ctx, "MLClient.Predict", trace.WithAttributes(attribute.String("tenant_id", tenantID)))
+	defer span.End()
+
+	primary, shadow := c.route(req, tenantID)
 
-	// Parse response
-	var mlResp models.MLInferenceResponse
-	if err := json.NewDecoder(resp.Body).Decode(&mlResp); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
+	var shadowPrediction *ShadowPrediction
+	if shadow != nil {
+		shadowPrediction = c.predictShadow(ctx, shadow, req)
 	}
 
-	// Add inference time if not set
-	if mlResp.InferenceTimeMs == 0 {
-		mlResp.InferenceTimeMs = int(time.Since(startTime).Milliseconds())
+	mlResp, err := c.predictWith(ctx, primary, req)
+	if err != nil && primary != c.champion {
+		utils.GetLoggerWithContext(ctx).WithError(err).WithField("model", primary.name).Warn("Model failed, using champion")
+		primary = c.champion
+		if shadow == c.champion {
+			// The champion is already scoring in shadow, so use its answer.
+			// The shadow runs under its own timeout, so stop waiting once
+			// the request is done.
+			select {
+			case <-shadowPrediction.done:
+				if shadowPrediction.resp != nil {
+					mlResp, err = shadowPrediction.resp, nil
+				}
+			case <-ctx.Done():
+				err = ctx.Err()
+			}
+		} else {
+			mlResp, err = c.predictWith(ctx, primary, req)
+		}
+		shadowPrediction = nil
 	}
+	if err != nil {
+		tracing.RecordError(span, err)
+		return nil, nil, nil, err
+	}
+	span.SetAttributes(
+		attribute.String("ml.model", primary.name),
+		attribute.String("ml.model_version", mlResp.ModelVersion),
+		attribute.Float64("ml.fraud_score", mlResp.FraudScore),
+	)
 
-	utils.GetLogger().WithField("inference_time_ms", mlResp.InferenceTimeMs).Info("ML prediction completed")
+	return mlResp, &models.MLRouting{Primary: modelScore(primary.name, mlResp)}, shadowPrediction, nil
+}
 
-	return &mlResp, nil
+// predictShadow starts scoring a request with the shadow model. It is not
+// cancelled with the request, only by ML.ShadowTimeout, so a verdict that
+// returns early does not cut the shadow short.
+func (c *MLClient) predictShadow(ctx context.Context, shadow *mlEndpoint, req *models.MLInferenceRequest) *ShadowPrediction {
+	p := &ShadowPrediction{Model: shadow.name, done: make(chan struct{})}
+	timeout := c.config.ML.ShadowTimeout
+	if timeout <= 0 {
+		timeout = c.config.ML.InferenceTimeout
+	}
+	go func() {
+		defer close(p.done)
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), timeout)
+		defer cancel()
+		resp, err := c.predictWith(ctx, shadow, req)
+		if err != nil {
+			utils.GetLoggerWithContext(ctx).WithError(err).WithField("model", shadow.name).Warn("Shadow prediction failed")
+			return
+		}
+		p.resp = resp
+	}()
+	return p
 }
 
-// HealthCheck checks if the ML service is healthy
-func (c *MLClient) HealthCheck(ctx context.Context) error {
-	url := fmt.Sprintf("%s/health", c.baseURL)
+func modelScore(model string, resp *models.MLInferenceResponse) models.ModelScore {
+	return models.ModelScore{
+		Model:        model,
+		ModelVersion: resp.ModelVersion,
+		IsFraud:      resp.IsFraud,
+		FraudScore:   resp.FraudScore,
+		FraudType:    resp.FraudType,
+		Confidence:   resp.Confidence,
+	}
+}
 
-	httpReq, err := http.NewRequestWithContext(ctx, "GET", url, nil)
-	if err != nil {
-		return fmt.Errorf("failed to create request: %w", err)
+// predictWith sends a prediction request to one model, retrying transient
+// failures with jittered backoff. Calls fail fast with ErrCircuitOpen while
+// the model's circuit breaker is open.
+func (c *MLClient) predictWith(ctx context.Context, endpoint *mlEndpoint, req *models.MLInferenceRequest) (mlResp *models.MLInferenceResponse, err error) {
+	ctx, span := tracing.Start(ctx, "ml.predict "+endpoint.name,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attribute.String("ml.model", endpoint.name)),
+	)
+	defer func() { tracing.End(span, err) }()
+
+	if err := endpoint.breaker.Allow(); err != nil {
+		metrics.MLRequests.WithLabelValues(endpoint.name, "circuit_open").Inc()
+		return nil, fmt.Errorf("model %s: %w", endpoint.name, err)
 	}
 
-	resp, err := c.httpClient.Do(httpReq)
-	if err != nil {
-		return fmt.Errorf("failed to call ML service: %w", err)
+	for attempt := 0; ; attempt++ {
+		span.SetAttributes(attribute.Int("ml.attempts", attempt+1))
+		start := time.Now()
+		mlResp, retryable, err := endpoint.transport.Predict(ctx, req)
+		outcome := "ok"
+		if err != nil {
+			outcome = "error"
+		}
+		metrics.MLRequests.WithLabelValues(endpoint.name, outcome).Inc()
+		metrics.MLRequestDuration.WithLabelValues(endpoint.name, outcome).Observe(time.Since(start).Seconds())
+		if err == nil {
+			endpoint.breaker.Success()
+			return mlResp, nil
+		}
+
+		if !retryable || attempt >= c.config.ML.MaxRetries || ctx.Err() != nil {
+			// Rejected requests and callers that gave up say nothing about
+			// the health of the service
+			if retryable && ctx.Err() == nil {
+				endpoint.breaker.Failure()
+			} else {
+				endpoint.breaker.Ignore()
+			}
+			return nil, fmt.Errorf("model %s: %w", endpoint.name, err)
+		}
+
+		delay := c.retryDelay(attempt)
+		utils.GetLoggerWithContext(ctx).WithError(err).WithField("model", endpoint.name).WithField("attempt", attempt+1).WithField("delay", delay).Warn("Retrying ML prediction")
+		select {
+		case <-ctx.Done():
+			endpoint.breaker.Ignore()
+			return nil, fmt.Errorf("model %s: %w", endpoint.name, err)
+		case <-time.After(delay):
+		}
 	}
-	defer resp.Body.Close()
+}
 
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("ML service returned status %d", resp.StatusCode)
+// retryDelay returns a full-jitter exponential backoff delay for attempt
+func (c *MLClient) retryDelay(attempt int) time.Duration {
+	backoff := c.config.ML.RetryBaseDelay << uint(attempt)
+	if backoff <= 0 || backoff > c.config.ML.RetryMaxDelay {
+		backoff = c.config.ML.RetryMaxDelay
+	}
+	if backoff <= 0 {
+		return 0
 	}
+	return time.Duration(rand.Int63n(int64(backoff)) + 1)
+}
 
-	return nil
+// PredictBatch scores several requests with the champion model in one call.
+// Responses are in request order. Batches are not retried or shadow scored.
+func (c *MLClient) PredictBatch(ctx context.Context, reqs []*models.MLInferenceRequest) ([]*models.MLInferenceResponse, error) {
+	if len(reqs) == 0 {
+		return nil, nil
+	}
+	if err := c.champion.breaker.Allow(); err != nil {
+		return nil, fmt.Errorf("model %s: %w", c.champion.name, err)
+	}
+
+	resps, retryable, err := c.champion.transport.PredictBatch(ctx, reqs)
+	if err != nil {
+		// As in predictWith, only transient failures count against the model
+		if retryable && ctx.Err() == nil {
+			c.champion.breaker.Failure()
+		} else {
+			c.champion.breaker.Ignore()
+		}
+		return nil, fmt.Errorf("model %s: %w", c.champion.name, err)
+	}
+	c.champion.breaker.Success()
+	return resps, nil
 }
 
+// HealthCheck checks if the champion model service is healthy
+func (c *MLClient) HealthCheck(ctx context.Context) error {
+	return c.champion.transport.HealthCheck(ctx)
+}
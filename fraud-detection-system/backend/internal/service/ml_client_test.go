@@ -0,0 +1,273 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/fraud-detection-system/backend/internal/config"
+	"github.com/fraud-detection-system/backend/internal/models"
+	"github.com/fraud-detection-system/backend/internal/repository/memory"
+)
+
+func newModelServer(t *testing.T, status int, score float64) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if status != http.StatusOK {
+			w.WriteHeader(status)
+			return
+		}
+		json.NewEncoder(w).Encode(models.MLInferenceResponse{IsFraud: score >= 0.5, FraudScore: score, ModelVersion: "test"})
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func newTestMLClient(t *testing.T, champion, challenger string, tenantModels map[string]string) *MLClient {
+	t.Helper()
+	client, err := NewMLClient(&config.Config{ML: config.MLConfig{
+		InferenceTimeout:        time.Second,
+		BreakerFailureThreshold: 5,
+		BreakerOpenTimeout:      time.Minute,
+		Models: []config.MLModelEndpoint{
+			{Name: "champion", URL: champion},
+			{Name: "challenger", URL: challenger},
+		},
+		Champion:      "champion",
+		Challenger:    "challenger",
+		ShadowEnabled: true,
+		TenantModels:  tenantModels,
+	}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return client
+}
+
+func TestMLClientShadowScoring(t *testing.T) {
+	champion := newModelServer(t, http.StatusOK, 0.9)
+	challenger := newModelServer(t, http.StatusOK, 0.2)
+	client := newTestMLClient(t, champion.URL, challenger.URL, map[string]string{"acme": "challenger"})
+
+	resp, routing, shadow, err := client.Predict(context.Background(), &models.MLInferenceRequest{Content: "hello"}, "default")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.FraudScore != 0.9 || routing.Primary.Model != "champion" {
+		t.Errorf("expected champion verdict, got %+v", routing.Primary)
+	}
+	if shadow == nil {
+		t.Fatal("expected a shadow prediction")
+	}
+	if score, ok := shadow.Wait(); !ok || score.Model != "challenger" || score.FraudScore != 0.2 {
+		t.Errorf("expected challenger shadow score, got %+v, %v", score, ok)
+	}
+
+	_, routing, shadow, err = client.Predict(context.Background(), &models.MLInferenceRequest{Content: "hello"}, "acme")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if routing.Primary.Model != "challenger" || shadow == nil || shadow.Model != "champion" {
+		t.Errorf("expected tenant routed to challenger, got %+v", routing)
+	}
+}
+
+func TestMLClientSlowShadowDoesNotDelayVerdict(t *testing.T) {
+	champion := newModelServer(t, http.StatusOK, 0.9)
+	release := make(chan struct{})
+	challenger := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	t.Cleanup(challenger.Close)
+	t.Cleanup(func() { close(release) })
+	client := newTestMLClient(t, champion.URL, challenger.URL, nil)
+	client.config.ML.ShadowTimeout = 500 * time.Millisecond
+
+	ctx, cancel := context.WithCancel(context.Background())
+	start := time.Now()
+	resp, _, shadow, err := client.Predict(ctx, &models.MLInferenceRequest{Content: "hello"}, "default")
+	cancel()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.FraudScore != 0.9 {
+		t.Errorf("expected champion verdict, got %+v", resp)
+	}
+	if elapsed := time.Since(start); elapsed > 250*time.Millisecond {
+		t.Errorf("verdict waited for the shadow, took %s", elapsed)
+	}
+	if _, ok := shadow.Wait(); ok {
+		t.Error("expected the shadow to time out")
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("shadow timeout not honoured, took %s", elapsed)
+	}
+}
+
+func TestMLClientChallengerFailureUsesChampion(t *testing.T) {
+	champion := newModelServer(t, http.StatusOK, 0.9)
+	challenger := newModelServer(t, http.StatusBadRequest, 0)
+	client := newTestMLClient(t, champion.URL, challenger.URL, map[string]string{"acme": "challenger"})
+
+	resp, routing, shadow, err := client.Predict(context.Background(), &models.MLInferenceRequest{Content: "hello"}, "acme")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.FraudScore != 0.9 || routing.Primary.Model != "champion" || shadow != nil {
+		t.Errorf("expected champion verdict without shadow, got %+v", routing)
+	}
+}
+
+func TestMLClientChampionFallbackHonoursRequestContext(t *testing.T) {
+	release := make(chan struct{})
+	champion := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	t.Cleanup(champion.Close)
+	t.Cleanup(func() { close(release) })
+	challenger := newModelServer(t, http.StatusBadRequest, 0)
+	client := newTestMLClient(t, champion.URL, challenger.URL, map[string]string{"acme": "challenger"})
+	client.config.ML.ShadowTimeout = 5 * time.Second
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	_, _, _, err := client.Predict(ctx, &models.MLInferenceRequest{Content: "hello"}, "acme")
+	if err == nil {
+		t.Fatal("expected an error once the request timed out")
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("fallback waited past the request deadline, took %s", elapsed)
+	}
+}
+
+func TestRecordShadow(t *testing.T) {
+	ctx := context.Background()
+	db := memory.New()
+	s := &VerificationService{verificationRepo: db.Verifications()}
+	predictions, _ := json.Marshal(map[string]interface{}{
+		"routing": models.MLRouting{Primary: models.ModelScore{Model: "champion", IsFraud: true, FraudScore: 0.9}},
+	})
+	verification := &models.Verification{ID: uuid.New(), MLPredictions: string(predictions), CreatedAt: time.Now()}
+	if err := db.Verifications().Create(ctx, verification); err != nil {
+		t.Fatal(err)
+	}
+
+	shadow := &ShadowPrediction{Model: "challenger", done: make(chan struct{}), resp: &models.MLInferenceResponse{FraudScore: 0.2}}
+	close(shadow.done)
+	s.recordShadow(ctx, verification.ID, shadow)
+
+	comparisons, err := db.Verifications().GetModelComparison(ctx, time.Now().Add(-time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(comparisons) != 1 || comparisons[0].ShadowModel != "challenger" || comparisons[0].PrimaryOnlyFraud != 1 {
+		t.Errorf("expected the shadow prediction in the comparison, got %+v", comparisons)
+	}
+}
+
+func TestMLClientBreakerAccounting(t *testing.T) {
+	var status atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(int(status.Load()))
+	}))
+	t.Cleanup(server.Close)
+	client := newTestMLClient(t, server.URL, server.URL, nil)
+	champion := client.champion
+	champion.breaker = NewCircuitBreaker(2, time.Minute)
+	req := &models.MLInferenceRequest{Content: "hello"}
+
+	status.Store(http.StatusInternalServerError)
+	if _, err := client.predictWith(context.Background(), champion, req); err == nil {
+		t.Fatal("expected an error")
+	}
+	// A rejected request neither counts as a failure nor resets the count
+	status.Store(http.StatusBadRequest)
+	if _, err := client.predictWith(context.Background(), champion, req); err == nil {
+		t.Fatal("expected an error")
+	}
+	if champion.breaker.State() != CircuitClosed {
+		t.Fatalf("expected closed circuit after a 4xx, got %s", champion.breaker.State())
+	}
+	// Nor does a caller that gave up
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	status.Store(http.StatusInternalServerError)
+	if _, err := client.predictWith(ctx, champion, req); err == nil {
+		t.Fatal("expected an error")
+	}
+	if champion.breaker.State() != CircuitClosed {
+		t.Fatalf("expected closed circuit after a cancelled call, got %s", champion.breaker.State())
+	}
+
+	if _, err := client.predictWith(context.Background(), champion, req); err == nil {
+		t.Fatal("expected an error")
+	}
+	if champion.breaker.State() != CircuitOpen {
+		t.Errorf("expected the second server error to open the circuit, got %s", champion.breaker.State())
+	}
+}
+
+func TestMLClientBatchBreakerAccounting(t *testing.T) {
+	var status atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(int(status.Load()))
+	}))
+	t.Cleanup(server.Close)
+	client := newTestMLClient(t, server.URL, server.URL, nil)
+	breaker := NewCircuitBreaker(2, time.Minute)
+	now := time.Unix(0, 0)
+	breaker.now = func() time.Time { return now }
+	client.champion.breaker = breaker
+	reqs := []*models.MLInferenceRequest{{Content: "hello"}, {Content: "world"}}
+
+	status.Store(http.StatusInternalServerError)
+	if _, err := client.PredictBatch(context.Background(), reqs); err == nil {
+		t.Fatal("expected an error")
+	}
+	// A rejected batch neither counts as a failure nor resets the count
+	status.Store(http.StatusBadRequest)
+	if _, err := client.PredictBatch(context.Background(), reqs); err == nil {
+		t.Fatal("expected an error")
+	}
+	// Nor does a caller that gave up
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	status.Store(http.StatusInternalServerError)
+	if _, err := client.PredictBatch(ctx, reqs); err == nil {
+		t.Fatal("expected an error")
+	}
+	if breaker.State() != CircuitClosed {
+		t.Fatalf("expected closed circuit, got %s", breaker.State())
+	}
+	if _, err := client.PredictBatch(context.Background(), reqs); err == nil {
+		t.Fatal("expected an error")
+	}
+	if breaker.State() != CircuitOpen {
+		t.Fatalf("expected the second server error to open the circuit, got %s", breaker.State())
+	}
+
+	// A rejected trial batch leaves the circuit half-open for the next trial
+	now = now.Add(time.Minute)
+	status.Store(http.StatusBadRequest)
+	if _, err := client.PredictBatch(context.Background(), reqs); err == nil {
+		t.Fatal("expected an error")
+	}
+	if breaker.State() != CircuitHalfOpen {
+		t.Errorf("expected a rejected trial batch to keep the circuit half-open, got %s", breaker.State())
+	}
+	if err := breaker.Allow(); err != nil {
+		t.Errorf("expected the next trial call to be allowed, got %v", err)
+	}
+}
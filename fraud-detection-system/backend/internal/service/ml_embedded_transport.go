@@ -0,0 +1,70 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/fraud-detection-system/backend/internal/classifier"
+	"github.com/fraud-detection-system/backend/internal/models"
+)
+
+// embeddedTransport scores requests in-process with the embedded classifier,
+// so verification works without the ML service
+type embeddedTransport struct {
+	model *classifier.Model
+}
+
+func newEmbeddedTransport(path string) (*embeddedTransport, error) {
+	model, err := classifier.Load(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load embedded classifier from %s: %w", path, err)
+	}
+	return &embeddedTransport{model: model}, nil
+}
+
+// Predict scores a single request
+func (t *embeddedTransport) Predict(ctx context.Context, req *models.MLInferenceRequest) (*models.MLInferenceResponse, bool, error) {
+	prediction := t.model.Predict(req.Content, req.Features)
+
+	explanation := "Embedded classifier found no strong fraud indicators."
+	if prediction.IsFraud && len(prediction.TopTokens) > 0 {
+		explanation = fmt.Sprintf("Embedded classifier flagged the words: %s.", strings.Join(prediction.TopTokens, ", "))
+	}
+
+	return &models.MLInferenceResponse{
+		IsFraud:    prediction.IsFraud,
+		FraudScore: prediction.Score,
+		FraudType:  fallbackFraudType(req.Features, prediction.IsFraud),
+		Confidence: math.Max(prediction.Score, 1-prediction.Score),
+		ModelPredictions: map[string]interface{}{
+			"embedded_classifier": map[string]interface{}{
+				"fraud_score": prediction.Score,
+				"is_fraud":    prediction.IsFraud,
+				"top_tokens":  prediction.TopTokens,
+			},
+		},
+		Explanation:  explanation,
+		ModelVersion: t.model.Version,
+	}, false, nil
+}
+
+// PredictBatch scores each request in turn
+func (t *embeddedTransport) PredictBatch(ctx context.Context, reqs []*models.MLInferenceRequest) ([]*models.MLInferenceResponse, bool, error) {
+	resps := make([]*models.MLInferenceResponse, len(reqs))
+	for i, req := range reqs {
+		resps[i], _, _ = t.Predict(ctx, req)
+	}
+	return resps, false, nil
+}
+
+// HealthCheck always succeeds; the weights were loaded at startup
+func (t *embeddedTransport) HealthCheck(ctx context.Context) error {
+	return nil
+}
+
+// Close is a no-op
+func (t *embeddedTransport) Close() error {
+	return nil
+}
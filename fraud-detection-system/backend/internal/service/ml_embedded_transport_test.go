@@ -0,0 +1,70 @@
+package service
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/fraud-detection-system/backend/internal/classifier"
+	"github.com/fraud-detection-system/backend/internal/config"
+	"github.com/fraud-detection-system/backend/internal/models"
+)
+
+func TestMLClientEmbeddedModel(t *testing.T) {
+	var examples []classifier.Example
+	for _, content := range []string{
+		"Update your KYC now or your account will be blocked http://kyc.in",
+		"URGENT verify your account immediately http://verify.in",
+	} {
+		examples = append(examples, classifier.Example{Content: content, Features: ExtractFeatures(content, ""), IsFraud: true})
+	}
+	for _, content := range []string{
+		"Rs 500 debited from your account for groceries",
+		"Your order has been shipped",
+	} {
+		examples = append(examples, classifier.Example{Content: content, Features: ExtractFeatures(content, "")})
+	}
+
+	opts := classifier.DefaultTrainOptions()
+	opts.Version = "embedded-test"
+	opts.MinTokenCount = 1
+	model, err := classifier.Train(examples, opts)
+	if err != nil {
+		t.Fatalf("train: %v", err)
+	}
+	path := filepath.Join(t.TempDir(), "classifier.json")
+	if err := model.Save(path); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+
+	client, err := NewMLClient(&config.Config{ML: config.MLConfig{
+		Transport: config.MLTransportEmbedded,
+		Models:    []config.MLModelEndpoint{{Name: "default", URL: config.MLEmbeddedScheme + path}},
+		Champion:  "default",
+	}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	content := "URGENT update your KYC http://kyc-verify.in"
+	resp, routing, _, err := client.Predict(context.Background(), &models.MLInferenceRequest{Content: content, Features: ExtractFeatures(content, "")}, "default")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !resp.IsFraud || resp.FraudType != "kyc_fraud" || resp.ModelVersion != "embedded-test" || routing.Primary.Model != "default" {
+		t.Errorf("unexpected response %+v", resp)
+	}
+	if err := client.HealthCheck(context.Background()); err != nil {
+		t.Errorf("health check: %v", err)
+	}
+}
+
+func TestMLClientEmbeddedModelMissing(t *testing.T) {
+	_, err := NewMLClient(&config.Config{ML: config.MLConfig{
+		Models:   []config.MLModelEndpoint{{Name: "default", URL: config.MLEmbeddedScheme + "/nonexistent/classifier.json"}},
+		Champion: "default",
+	}})
+	if err == nil {
+		t.Error("expected error for missing weights file")
+	}
+}
@@ -0,0 +1,215 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+	"github.com/fraud-detection-system/backend/internal/mlpb"
+	"github.com/fraud-detection-system/backend/internal/models"
+	"github.com/fraud-detection-system/backend/internal/tracing"
+	"github.com/fraud-detection-system/backend/internal/utils"
+)
+
+// grpcTransport calls the ML service over gRPC. Calls are spread round-robin
+// over a fixed pool of connections so one HTTP/2 connection's stream limit
+// does not cap throughput.
+type grpcTransport struct {
+	conns   []*grpc.ClientConn
+	clients []mlpb.InferenceServiceClient
+	next    atomic.Uint32
+	timeout time.Duration
+}
+
+func newGRPCTransport(target string, poolSize int, timeout time.Duration) (*grpcTransport, error) {
+	t := &grpcTransport{
+		timeout: timeout,
+	}
+	for i := 0; i < poolSize; i++ {
+		conn, err := grpc.Dial(target, grpc.WithTransportCredentials(insecure.NewCredentials()))
+		if err != nil {
+			t.Close()
+			return nil, fmt.Errorf("failed to dial %s: %w", target, err)
+		}
+		t.conns = append(t.conns, conn)
+		t.clients = append(t.clients, mlpb.NewInferenceServiceClient(conn))
+	}
+	return t, nil
+}
+
+// pick returns the next pooled connection's client
+func (t *grpcTransport) pick() (mlpb.InferenceServiceClient, *grpc.ClientConn) {
+	i := int(t.next.Add(1) % uint32(len(t.clients)))
+	return t.clients[i], t.conns[i]
+}
+
+// withDeadline applies the inference timeout unless the caller's context
+// already carries a deadline, which gRPC then propagates to the server
+func (t *grpcTransport) withDeadline(ctx context.Context) (context.Context, context.CancelFunc) {
+	if _, ok := ctx.Deadline(); ok || t.timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, t.timeout)
+}
+
+// Predict makes a single prediction call
+func (t *grpcTransport) Predict(ctx context.Context, req *models.MLInferenceRequest) (*models.MLInferenceResponse, bool, error) {
+	ctx, cancel := t.withDeadline(tracing.InjectGRPC(ctx))
+	defer cancel()
+
+	client, _ := t.pick()
+	startTime := time.Now()
+	resp, err := client.Predict(ctx, toPredictRequest("", req))
+	if err != nil {
+		utils.GetLoggerWithContext(ctx).WithError(err).Error("Failed to call ML service")
+		return nil, isRetryableGRPCError(err), fmt.Errorf("failed to call ML service: %w", err)
+	}
+
+	mlResp := fromPredictResponse(resp)
+	if mlResp.InferenceTimeMs == 0 {
+		mlResp.InferenceTimeMs = int(time.Since(startTime).Milliseconds())
+	}
+
+	utils.GetLoggerWithContext(ctx).WithField("inference_time_ms", mlResp.InferenceTimeMs).Info("ML prediction completed")
+
+	return mlResp, false, nil
+}
+
+// PredictBatch scores all requests over a single PredictStream call
+func (t *grpcTransport) PredictBatch(ctx context.Context, reqs []*models.MLInferenceRequest) ([]*models.MLInferenceResponse, bool, error) {
+	ctx, cancel := t.withDeadline(tracing.InjectGRPC(ctx))
+	defer cancel()
+
+	client, _ := t.pick()
+	stream, err := client.PredictStream(ctx)
+	if err != nil {
+		return nil, isRetryableGRPCError(err), fmt.Errorf("failed to open prediction stream: %w", err)
+	}
+
+	// Send while receiving so large batches don't stall on flow control
+	sendErr := make(chan error, 1)
+	go func() {
+		for i, req := range reqs {
+			if err := stream.Send(toPredictRequest(strconv.Itoa(i), req)); err != nil {
+				sendErr <- err
+				return
+			}
+		}
+		sendErr <- stream.CloseSend()
+	}()
+
+	resps := make([]*models.MLInferenceResponse, len(reqs))
+	received := 0
+	for {
+		resp, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, isRetryableGRPCError(err), fmt.Errorf("failed to receive prediction: %w", err)
+		}
+
+		i, err := strconv.Atoi(resp.GetId())
+		if err != nil || i < 0 || i >= len(reqs) || resps[i] != nil {
+			return nil, false, fmt.Errorf("unexpected prediction id %q", resp.GetId())
+		}
+		if resp.GetError() != "" {
+			return nil, true, fmt.Errorf("prediction %d failed: %s", i, resp.GetError())
+		}
+		resps[i] = fromPredictResponse(resp)
+		received++
+	}
+
+	if err := <-sendErr; err != nil && err != io.EOF {
+		return nil, isRetryableGRPCError(err), fmt.Errorf("failed to send prediction request: %w", err)
+	}
+	if received != len(reqs) {
+		return nil, true, fmt.Errorf("received %d of %d predictions", received, len(reqs))
+	}
+
+	return resps, false, nil
+}
+
+// HealthCheck uses the standard gRPC health checking protocol
+func (t *grpcTransport) HealthCheck(ctx context.Context) error {
+	ctx, cancel := t.withDeadline(ctx)
+	defer cancel()
+
+	_, conn := t.pick()
+	resp, err := healthpb.NewHealthClient(conn).Check(ctx, &healthpb.HealthCheckRequest{})
+	if err != nil {
+		return fmt.Errorf("failed to call ML service: %w", err)
+	}
+	if resp.GetStatus() != healthpb.HealthCheckResponse_SERVING {
+		return fmt.Errorf("ML service is %s", resp.GetStatus())
+	}
+	return nil
+}
+
+// Close closes the pooled connections
+func (t *grpcTransport) Close() error {
+	var firstErr error
+	for _, conn := range t.conns {
+		if err := conn.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// isRetryableGRPCError reports whether a call may succeed if repeated
+func isRetryableGRPCError(err error) bool {
+	switch status.Code(err) {
+	case codes.Unavailable, codes.DeadlineExceeded, codes.ResourceExhausted, codes.Aborted, codes.Internal, codes.Unknown:
+		return true
+	default:
+		return false
+	}
+}
+
+func toPredictRequest(id string, req *models.MLInferenceRequest) *mlpb.PredictRequest {
+	f := req.Features
+	return &mlpb.PredictRequest{
+		Id:           id,
+		Content:      req.Content,
+		SenderHeader: req.SenderHeader,
+		Features: &mlpb.MessageFeatures{
+			Content:          f.Content,
+			SenderHeader:     f.SenderHeader,
+			MessageLength:    int32(f.MessageLength),
+			HasLinks:         f.HasLinks,
+			LinkCount:        int32(f.LinkCount),
+			ExtractedUrls:    f.ExtractedURLs,
+			HasPhoneNumber:   f.HasPhoneNumber,
+			PhoneNumberCount: int32(f.PhoneNumberCount),
+			HasUrgentWords:   f.HasUrgentWords,
+			UrgentWordCount:  int32(f.UrgentWordCount),
+			SpecialCharRatio: f.SpecialCharRatio,
+			CapitalRatio:     f.CapitalRatio,
+			NumberRatio:      f.NumberRatio,
+			HasKycKeywords:   f.HasKYCKeywords,
+			HasBankNames:     f.HasBankNames,
+		},
+	}
+}
+
+func fromPredictResponse(resp *mlpb.PredictResponse) *models.MLInferenceResponse {
+	return &models.MLInferenceResponse{
+		IsFraud:          resp.GetIsFraud(),
+		FraudScore:       resp.GetFraudScore(),
+		FraudType:        resp.GetFraudType(),
+		Confidence:       resp.GetConfidence(),
+		ModelPredictions: resp.GetModelPredictions().AsMap(),
+		Explanation:      resp.GetExplanation(),
+		InferenceTimeMs:  int(resp.GetInferenceTimeMs()),
+		ModelVersion:     resp.GetModelVersion(),
+	}
+}
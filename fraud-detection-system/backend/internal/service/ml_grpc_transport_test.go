@@ -0,0 +1,106 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/fraud-detection-system/backend/internal/config"
+	"github.com/fraud-detection-system/backend/internal/mlpb"
+	"github.com/fraud-detection-system/backend/internal/mlstub"
+	"github.com/fraud-detection-system/backend/internal/models"
+)
+
+func newGRPCTestMLClient(t *testing.T, predict mlstub.PredictFunc) (*MLClient, *mlstub.Server) {
+	t.Helper()
+	server := mlstub.NewServer(predict)
+	addr, err := server.Start()
+	if err != nil {
+		t.Fatalf("failed to start stub server: %v", err)
+	}
+	t.Cleanup(server.Stop)
+
+	client, err := NewMLClient(&config.Config{ML: config.MLConfig{
+		InferenceTimeout:        time.Second,
+		BreakerFailureThreshold: 5,
+		BreakerOpenTimeout:      time.Minute,
+		Models:                  []config.MLModelEndpoint{{Name: "default", URL: addr}},
+		Champion:                "default",
+		Transport:               config.MLTransportGRPC,
+		GRPCPoolSize:            2,
+	}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	t.Cleanup(func() { client.Close() })
+	return client, server
+}
+
+func TestMLClientGRPCPredict(t *testing.T) {
+	client, server := newGRPCTestMLClient(t, nil)
+
+	req := &models.MLInferenceRequest{
+		Content:  "Update KYC now",
+		Features: models.MessageFeatures{HasLinks: true, HasKYCKeywords: true},
+	}
+	resp, routing, _, err := client.Predict(context.Background(), req, "default")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !resp.IsFraud || resp.ModelVersion != mlstub.ModelVersion || routing.Primary.Model != "default" {
+		t.Errorf("unexpected response %+v", resp)
+	}
+	if _, ok := resp.ModelPredictions["stub"]; !ok {
+		t.Errorf("model predictions not converted: %+v", resp.ModelPredictions)
+	}
+
+	if err := client.HealthCheck(context.Background()); err != nil {
+		t.Errorf("health check: %v", err)
+	}
+	server.SetServing(false)
+	if err := client.HealthCheck(context.Background()); err == nil {
+		t.Error("expected health check to fail while not serving")
+	}
+}
+
+func TestMLClientGRPCPredictBatch(t *testing.T) {
+	client, server := newGRPCTestMLClient(t, nil)
+
+	reqs := make([]*models.MLInferenceRequest, 50)
+	for i := range reqs {
+		reqs[i] = &models.MLInferenceRequest{Features: models.MessageFeatures{HasLinks: i%2 == 0, HasKYCKeywords: true}}
+	}
+	resps, err := client.PredictBatch(context.Background(), reqs)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(resps) != len(reqs) || server.Calls() != int64(len(reqs)) {
+		t.Fatalf("got %d responses for %d requests", len(resps), len(reqs))
+	}
+	for i, resp := range resps {
+		if resp.IsFraud != (i%2 == 0) {
+			t.Errorf("response %d out of order: %+v", i, resp)
+		}
+	}
+}
+
+func TestMLClientGRPCDeadline(t *testing.T) {
+	client, _ := newGRPCTestMLClient(t, func(ctx context.Context, req *mlpb.PredictRequest) (*mlpb.PredictResponse, error) {
+		if _, ok := ctx.Deadline(); !ok {
+			return nil, errors.New("no deadline propagated")
+		}
+		<-ctx.Done()
+		return nil, ctx.Err()
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	if _, _, _, err := client.Predict(ctx, &models.MLInferenceRequest{}, "default"); err == nil {
+		t.Fatal("expected deadline error")
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("deadline not honoured, took %s", elapsed)
+	}
+}
@@ -0,0 +1,162 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/fraud-detection-system/backend/internal/models"
+	"github.com/fraud-detection-system/backend/internal/tracing"
+	"github.com/fraud-detection-system/backend/internal/utils"
+)
+
+// httpBatchConcurrency bounds the parallel calls the HTTP transport makes for
+// a batch, since the JSON API has no batch endpoint
+const httpBatchConcurrency = 4
+
+// mlTransport sends requests to one model service. Predict and PredictBatch
+// report whether a failure is worth retrying.
+type mlTransport interface {
+	Predict(ctx context.Context, req *models.MLInferenceRequest) (*models.MLInferenceResponse, bool, error)
+	PredictBatch(ctx context.Context, reqs []*models.MLInferenceRequest) ([]*models.MLInferenceResponse, bool, error)
+	HealthCheck(ctx context.Context) error
+	Close() error
+}
+
+// httpTransport calls the JSON API of the ML service
+type httpTransport struct {
+	baseURL    string
+	httpClient *http.Client
+}
+
+func newHTTPTransport(baseURL string, httpClient *http.Client) *httpTransport {
+	return &httpTransport{
+		baseURL:    baseURL,
+		httpClient: httpClient,
+	}
+}
+
+// Predict makes a single prediction call
+func (t *httpTransport) Predict(ctx context.Context, req *models.MLInferenceRequest) (*models.MLInferenceResponse, bool, error) {
+	// Marshal request
+	jsonData, err := json.Marshal(req)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	url := fmt.Sprintf("%s/api/v1/predict", t.baseURL)
+
+	// Create HTTP request
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	httpReq.Header.Set("Content-Type", "application/json")
+	tracing.InjectHTTP(ctx, httpReq.Header)
+	if requestID := utils.RequestIDFromContext(ctx); requestID != "" {
+		httpReq.Header.Set("X-Request-ID", requestID)
+	}
+
+	// Send request
+	startTime := time.Now()
+	resp, err := t.httpClient.Do(httpReq)
+	if err != nil {
+		utils.GetLoggerWithContext(ctx).WithError(err).Error("Failed to call ML service")
+		return nil, true, fmt.Errorf("failed to call ML service: %w", err)
+	}
+	defer resp.Body.Close()
+
+	// Check status code
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		utils.GetLoggerWithContext(ctx).WithField("status", resp.StatusCode).WithField("body", string(body)).Error("ML service returned error")
+		retryable := resp.StatusCode >= http.StatusInternalServerError || resp.StatusCode == http.StatusTooManyRequests
+		return nil, retryable, fmt.Errorf("ML service returned status %d", resp.StatusCode)
+	}
+
+	// Parse response
+	var mlResp models.MLInferenceResponse
+	if err := json.NewDecoder(resp.Body).Decode(&mlResp); err != nil {
+		return nil, true, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	// Add inference time if not set
+	if mlResp.InferenceTimeMs == 0 {
+		mlResp.InferenceTimeMs = int(time.Since(startTime).Milliseconds())
+	}
+
+	utils.GetLoggerWithContext(ctx).WithField("inference_time_ms", mlResp.InferenceTimeMs).Info("ML prediction completed")
+
+	return &mlResp, false, nil
+}
+
+// PredictBatch makes one prediction call per request, a few at a time
+func (t *httpTransport) PredictBatch(ctx context.Context, reqs []*models.MLInferenceRequest) ([]*models.MLInferenceResponse, bool, error) {
+	resps := make([]*models.MLInferenceResponse, len(reqs))
+	sem := make(chan struct{}, httpBatchConcurrency)
+
+	var (
+		wg        sync.WaitGroup
+		mu        sync.Mutex
+		firstErr  error
+		retryable bool
+	)
+	for i, req := range reqs {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, req *models.MLInferenceRequest) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			resp, canRetry, err := t.Predict(ctx, req)
+			if err != nil {
+				mu.Lock()
+				if firstErr == nil {
+					firstErr, retryable = err, canRetry
+				}
+				mu.Unlock()
+				return
+			}
+			resps[i] = resp
+		}(i, req)
+	}
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, retryable, firstErr
+	}
+	return resps, false, nil
+}
+
+// HealthCheck checks if the ML service is healthy
+func (t *httpTransport) HealthCheck(ctx context.Context) error {
+	url := fmt.Sprintf("%s/health", t.baseURL)
+
+	httpReq, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := t.httpClient.Do(httpReq)
+	if err != nil {
+		return fmt.Errorf("failed to call ML service: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("ML service returned status %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+// Close is a no-op; the HTTP client is shared between models
+func (t *httpTransport) Close() error {
+	return nil
+}
@@ -0,0 +1,245 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"sort"
+	"time"
+
+	"github.com/fraud-detection-system/backend/internal/config"
+	"github.com/fraud-detection-system/backend/internal/metrics"
+	"github.com/fraud-detection-system/backend/internal/models"
+	"github.com/fraud-detection-system/backend/internal/repository"
+	"github.com/fraud-detection-system/backend/internal/utils"
+)
+
+// outboxCleanupInterval is how often processed entries past retention are deleted
+const outboxCleanupInterval = time.Hour
+
+// outboxLagInterval is how often the backlog gauges are refreshed
+const outboxLagInterval = 15 * time.Second
+
+// OutboxHandler applies one outbox entry. Database work should go through tx
+// so it commits together with the entry being marked processed.
+type OutboxHandler func(ctx context.Context, tx *sql.Tx, entry *models.OutboxEntry) error
+
+// permanentError is a handler error that retrying cannot fix
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string { return e.err.Error() }
+func (e *permanentError) Unwrap() error { return e.err }
+
+// Permanent marks err as one retrying cannot fix, such as a malformed
+// payload. The processor abandons the entry at once, whatever its stream's
+// attempt limit.
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &permanentError{err: err}
+}
+
+// isPermanent reports whether err was marked with Permanent
+func isPermanent(err error) bool {
+	var permanent *permanentError
+	return errors.As(err, &permanent)
+}
+
+// outboxStream is the handler for one kind of entry
+type outboxStream struct {
+	handler     OutboxHandler
+	maxAttempts int
+	lockKey     int64
+}
+
+// OutboxProcessor applies outbox entries. Each kind is an independent stream
+// applied in the order it was written, so a failing Kafka publish does not
+// hold up sender stats and vice versa.
+type OutboxProcessor struct {
+	uow        repository.Transactor
+	outboxRepo repository.OutboxStore
+	streams    map[string]*outboxStream
+	config     config.OutboxConfig
+}
+
+func NewOutboxProcessor(uow repository.Transactor, outboxRepo repository.OutboxStore, cfg *config.Config) *OutboxProcessor {
+	return &OutboxProcessor{
+		uow:        uow,
+		outboxRepo: outboxRepo,
+		streams:    make(map[string]*outboxStream),
+		config:     cfg.Outbox,
+	}
+}
+
+// Handle registers the handler for entries of kind. An entry that fails
+// maxAttempts times is abandoned; zero retries it until it succeeds. Entries
+// whose handler returns a Permanent error are abandoned at once.
+func (p *OutboxProcessor) Handle(kind string, handler OutboxHandler, maxAttempts int) {
+	h := fnv.New64a()
+	h.Write([]byte("outbox:" + kind))
+	p.streams[kind] = &outboxStream{
+		handler:     handler,
+		maxAttempts: maxAttempts,
+		lockKey:     int64(h.Sum64()),
+	}
+}
+
+// Start processes the outbox every poll interval until ctx is cancelled
+func (p *OutboxProcessor) Start(ctx context.Context) {
+	logger := utils.GetLogger()
+	ticker := time.NewTicker(p.config.PollInterval)
+	defer ticker.Stop()
+
+	kinds := make([]string, 0, len(p.streams))
+	for kind := range p.streams {
+		kinds = append(kinds, kind)
+	}
+	sort.Strings(kinds)
+
+	var lastCleanup, lastLag time.Time
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		for _, kind := range kinds {
+			// Keep going while batches come back full
+			for {
+				n, err := p.ProcessBatch(ctx, kind)
+				if err != nil && ctx.Err() == nil {
+					logger.WithError(err).WithField("kind", kind).Error("Outbox processing failed")
+				}
+				if err != nil || n < p.config.BatchSize {
+					break
+				}
+			}
+		}
+
+		if time.Since(lastLag) >= outboxLagInterval {
+			lastLag = time.Now()
+			p.updateLag(ctx, kinds)
+		}
+
+		if p.config.Retention > 0 && time.Since(lastCleanup) >= outboxCleanupInterval {
+			lastCleanup = time.Now()
+			deleted, err := p.outboxRepo.DeleteProcessedBefore(ctx, time.Now().Add(-p.config.Retention))
+			if err != nil {
+				logger.WithError(err).Error("Outbox cleanup failed")
+			} else if deleted > 0 {
+				logger.WithField("deleted", deleted).Info("Outbox cleanup completed")
+			}
+		}
+	}
+}
+
+// ProcessBatch applies up to one batch of pending entries of kind and returns
+// how many were handled. It stops at the first entry that fails, so entries
+// are never applied out of order; that entry is retried on the next batch
+// until it runs out of attempts or fails permanently.
+func (p *OutboxProcessor) ProcessBatch(ctx context.Context, kind string) (int, error) {
+	stream, ok := p.streams[kind]
+	if !ok {
+		return 0, fmt.Errorf("no handler for outbox entry kind %q", kind)
+	}
+
+	handled := 0
+	err := p.uow.Do(ctx, func(repos *repository.TxRepositories) error {
+		outbox := repos.Outbox
+		locked, err := outbox.TryLock(ctx, stream.lockKey)
+		if err != nil || !locked {
+			// If not locked, another worker is processing this stream
+			return err
+		}
+
+		entries, err := outbox.GetPending(ctx, kind, p.config.BatchSize)
+		if err != nil {
+			return err
+		}
+
+		for _, entry := range entries {
+			applyErr := p.apply(ctx, repos.Tx, stream.handler, entry)
+			if applyErr == nil {
+				if err := outbox.MarkProcessed(ctx, entry.ID); err != nil {
+					return err
+				}
+				metrics.OutboxProcessed.WithLabelValues(kind, "ok").Inc()
+				handled++
+				continue
+			}
+
+			permanent := isPermanent(applyErr)
+			logger := utils.GetLoggerWithContext(ctx).WithError(applyErr).
+				WithField("outbox_id", entry.ID).
+				WithField("kind", entry.Kind).
+				WithField("attempts", entry.Attempts+1).
+				WithField("permanent", permanent)
+			if permanent || (stream.maxAttempts > 0 && entry.Attempts+1 >= stream.maxAttempts) {
+				logger.Error("Abandoning outbox entry")
+				if err := outbox.MarkAbandoned(ctx, entry.ID, applyErr.Error()); err != nil {
+					return err
+				}
+				metrics.OutboxProcessed.WithLabelValues(kind, "abandoned").Inc()
+				handled++
+				continue
+			}
+
+			logger.Warn("Failed to apply outbox entry")
+			if err := outbox.MarkFailed(ctx, entry.ID, applyErr.Error()); err != nil {
+				return err
+			}
+			metrics.OutboxProcessed.WithLabelValues(kind, "failed").Inc()
+			break
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return handled, nil
+}
+
+// updateLag refreshes the backlog gauges of each stream
+func (p *OutboxProcessor) updateLag(ctx context.Context, kinds []string) {
+	stats, err := p.outboxRepo.GetStats(ctx)
+	if err != nil {
+		utils.GetLoggerWithContext(ctx).WithError(err).Warn("Failed to get outbox stats")
+		return
+	}
+	for _, kind := range kinds {
+		metrics.OutboxPending.WithLabelValues(kind).Set(0)
+		metrics.OutboxLag.WithLabelValues(kind).Set(0)
+	}
+	for _, stat := range stats {
+		metrics.OutboxPending.WithLabelValues(stat.Kind).Set(float64(stat.Pending))
+		metrics.OutboxLag.WithLabelValues(stat.Kind).Set(stat.LagSeconds)
+	}
+}
+
+// apply runs handler inside a savepoint, so a failed handler leaves the
+// batch transaction usable. In-memory repositories have no transaction, so
+// the handler runs directly.
+func (p *OutboxProcessor) apply(ctx context.Context, tx *sql.Tx, handler OutboxHandler, entry *models.OutboxEntry) error {
+	if tx == nil {
+		return handler(ctx, tx, entry)
+	}
+	if _, err := tx.ExecContext(ctx, "SAVEPOINT outbox_entry"); err != nil {
+		return fmt.Errorf("failed to create savepoint: %w", err)
+	}
+	if err := handler(ctx, tx, entry); err != nil {
+		if _, rbErr := tx.ExecContext(ctx, "ROLLBACK TO SAVEPOINT outbox_entry"); rbErr != nil {
+			return fmt.Errorf("failed to roll back savepoint: %w", rbErr)
+		}
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, "RELEASE SAVEPOINT outbox_entry"); err != nil {
+		return fmt.Errorf("failed to release savepoint: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,131 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/fraud-detection-system/backend/internal/config"
+	"github.com/fraud-detection-system/backend/internal/models"
+	"github.com/fraud-detection-system/backend/internal/repository/memory"
+)
+
+const testOutboxKind = "test"
+
+// outboxStats returns the stats of testOutboxKind
+func outboxStats(t *testing.T, db *memory.DB) models.OutboxStats {
+	t.Helper()
+	stats, err := db.Outbox().GetStats(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range stats {
+		if s.Kind == testOutboxKind {
+			return *s
+		}
+	}
+	return models.OutboxStats{Kind: testOutboxKind}
+}
+
+func TestOutboxProcessBatch(t *testing.T) {
+	errTransient := errors.New("broker unavailable")
+	tests := []struct {
+		name        string
+		maxAttempts int
+		// fail returns the error for the entry with the given payload
+		fail          map[string]error
+		wantHandled   int
+		wantApplied   []string
+		wantPending   int64
+		wantAbandoned int64
+	}{
+		{
+			name:        "all applied",
+			wantHandled: 3,
+			wantApplied: []string{`"a"`, `"b"`, `"c"`},
+		},
+		{
+			name:        "transient failure stops the batch",
+			fail:        map[string]error{`"b"`: errTransient},
+			wantHandled: 1,
+			wantApplied: []string{`"a"`},
+			wantPending: 2,
+		},
+		{
+			name:          "out of attempts abandons",
+			maxAttempts:   1,
+			fail:          map[string]error{`"b"`: errTransient},
+			wantHandled:   3,
+			wantApplied:   []string{`"a"`, `"c"`},
+			wantAbandoned: 1,
+		},
+		{
+			name:          "permanent failure abandons without a limit",
+			fail:          map[string]error{`"b"`: Permanent(errors.New("no topic"))},
+			wantHandled:   3,
+			wantApplied:   []string{`"a"`, `"c"`},
+			wantAbandoned: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			db := memory.New()
+			for _, payload := range []string{"a", "b", "c"} {
+				if err := db.Outbox().Add(ctx, testOutboxKind, payload); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			var applied []string
+			processor := NewOutboxProcessor(db.UnitOfWork(), db.Outbox(), &config.Config{Outbox: config.OutboxConfig{BatchSize: 10}})
+			processor.Handle(testOutboxKind, func(ctx context.Context, tx *sql.Tx, entry *models.OutboxEntry) error {
+				if err := tt.fail[entry.Payload]; err != nil {
+					return err
+				}
+				applied = append(applied, entry.Payload)
+				return nil
+			}, tt.maxAttempts)
+
+			handled, err := processor.ProcessBatch(ctx, testOutboxKind)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if handled != tt.wantHandled {
+				t.Errorf("expected %d handled, got %d", tt.wantHandled, handled)
+			}
+			if len(applied) != len(tt.wantApplied) {
+				t.Fatalf("expected %v applied, got %v", tt.wantApplied, applied)
+			}
+			for i := range applied {
+				if applied[i] != tt.wantApplied[i] {
+					t.Errorf("expected %v applied in order, got %v", tt.wantApplied, applied)
+				}
+			}
+
+			stats := outboxStats(t, db)
+			if stats.Pending != tt.wantPending || stats.Abandoned != tt.wantAbandoned {
+				t.Errorf("expected %d pending and %d abandoned, got %+v", tt.wantPending, tt.wantAbandoned, stats)
+			}
+			if tt.wantPending > 0 {
+				pending, err := db.Outbox().GetPending(ctx, testOutboxKind, 10)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if pending[0].Attempts != 1 || pending[0].LastError == nil || pending[1].Attempts != 0 {
+					t.Errorf("expected only the failed entry to record an attempt, got %+v, %+v", pending[0], pending[1])
+				}
+			}
+		})
+	}
+}
+
+func TestOutboxProcessBatchUnknownKind(t *testing.T) {
+	db := memory.New()
+	processor := NewOutboxProcessor(db.UnitOfWork(), db.Outbox(), &config.Config{Outbox: config.OutboxConfig{BatchSize: 10}})
+	if _, err := processor.ProcessBatch(context.Background(), testOutboxKind); err == nil {
+		t.Error("expected an error for a kind without a handler")
+	}
+}
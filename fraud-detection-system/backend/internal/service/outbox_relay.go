@@ -0,0 +1,52 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/fraud-detection-system/backend/internal/models"
+	"github.com/fraud-detection-system/backend/internal/queue"
+	"github.com/fraud-detection-system/backend/internal/tracing"
+)
+
+// KafkaRelay publishes Kafka outbox entries. An entry is marked sent only
+// after Kafka acknowledges it, so a crash in between publishes it again;
+// consumers should deduplicate on the message ID.
+type KafkaRelay struct {
+	producer kafkaPublisher
+}
+
+// kafkaPublisher is the part of queue.Producer the relay uses
+type kafkaPublisher interface {
+	PublishKeyed(ctx context.Context, topic, key string, message *models.QueueMessage) error
+}
+
+func NewKafkaRelay(producer *queue.Producer) *KafkaRelay {
+	return &KafkaRelay{producer: producer}
+}
+
+// Publish is the outbox handler for Kafka entries. Entries that can never be
+// published, such as ones without a topic or with an unknown one, fail
+// permanently so they are abandoned instead of blocking the stream.
+func (r *KafkaRelay) Publish(ctx context.Context, tx *sql.Tx, entry *models.OutboxEntry) error {
+	if entry.Topic == nil {
+		return Permanent(fmt.Errorf("outbox entry %d has no topic", entry.ID))
+	}
+
+	message, err := models.QueueMessageFromJSON([]byte(entry.Payload))
+	if err != nil {
+		return Permanent(fmt.Errorf("invalid queue message in outbox entry %d: %w", entry.ID, err))
+	}
+
+	key := message.ID.String()
+	if entry.MessageKey != nil && *entry.MessageKey != "" {
+		key = *entry.MessageKey
+	}
+	err = r.producer.PublishKeyed(tracing.Extract(ctx, message.TraceContext), *entry.Topic, key, message)
+	if errors.Is(err, queue.ErrUnknownTopic) {
+		return Permanent(err)
+	}
+	return err
+}
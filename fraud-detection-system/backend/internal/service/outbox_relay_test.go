@@ -0,0 +1,103 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/fraud-detection-system/backend/internal/models"
+	"github.com/fraud-detection-system/backend/internal/queue"
+)
+
+// fakePublisher records published messages, failing with err if set
+type fakePublisher struct {
+	err       error
+	topic     string
+	key       string
+	published *models.QueueMessage
+}
+
+func (p *fakePublisher) PublishKeyed(ctx context.Context, topic, key string, message *models.QueueMessage) error {
+	if p.err != nil {
+		return p.err
+	}
+	p.topic, p.key, p.published = topic, key, message
+	return nil
+}
+
+func TestKafkaRelayPublish(t *testing.T) {
+	message := models.NewQueueMessage(models.MessageTypeAlert, map[string]interface{}{"level": "high"})
+	data, err := message.ToJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	topic, key := "alerts", "sender-1"
+
+	tests := []struct {
+		name          string
+		entry         models.OutboxEntry
+		publishErr    error
+		wantErr       bool
+		wantPermanent bool
+		wantKey       string
+	}{
+		{
+			name:    "keyed",
+			entry:   models.OutboxEntry{ID: 1, Topic: &topic, MessageKey: &key, Payload: string(data)},
+			wantKey: key,
+		},
+		{
+			name:    "keyed by message ID",
+			entry:   models.OutboxEntry{ID: 1, Topic: &topic, Payload: string(data)},
+			wantKey: message.ID.String(),
+		},
+		{
+			name:          "no topic",
+			entry:         models.OutboxEntry{ID: 1, Payload: string(data)},
+			wantErr:       true,
+			wantPermanent: true,
+		},
+		{
+			name:          "invalid payload",
+			entry:         models.OutboxEntry{ID: 1, Topic: &topic, Payload: "{"},
+			wantErr:       true,
+			wantPermanent: true,
+		},
+		{
+			name:          "unknown topic",
+			entry:         models.OutboxEntry{ID: 1, Topic: &topic, Payload: string(data)},
+			publishErr:    fmt.Errorf("%w: %s", queue.ErrUnknownTopic, topic),
+			wantErr:       true,
+			wantPermanent: true,
+		},
+		{
+			name:       "broker unavailable",
+			entry:      models.OutboxEntry{ID: 1, Topic: &topic, Payload: string(data)},
+			publishErr: errors.New("connection refused"),
+			wantErr:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			publisher := &fakePublisher{err: tt.publishErr}
+			relay := &KafkaRelay{producer: publisher}
+			entry := tt.entry
+
+			err := relay.Publish(context.Background(), nil, &entry)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+			if isPermanent(err) != tt.wantPermanent {
+				t.Errorf("expected permanent %v, got %v", tt.wantPermanent, err)
+			}
+			if err != nil {
+				return
+			}
+			if publisher.topic != topic || publisher.key != tt.wantKey || publisher.published.ID != message.ID {
+				t.Errorf("expected message %s on %s keyed %s, got %+v", message.ID, topic, tt.wantKey, publisher)
+			}
+		})
+	}
+}
@@ -6,18 +6,26 @@ import (
 	"strings"
 	"time"
 
+	"github.com/fraud-detection-system/backend/internal/config"
+	"github.com/fraud-detection-system/backend/internal/events"
 	"github.com/fraud-detection-system/backend/internal/models"
 	"github.com/fraud-detection-system/backend/internal/repository"
 	"github.com/fraud-detection-system/backend/internal/utils"
 )
 
 type RBIComplianceService struct {
-	rbiRepo *repository.RBIRepository
+	rbiRepo      repository.RBIStore
+	uow          repository.Transactor
+	events       *EventQueue
+	verdictCache *VerdictCache
 }
 
-func NewRBIComplianceService(rbiRepo *repository.RBIRepository) *RBIComplianceService {
+func NewRBIComplianceService(rbiRepo repository.RBIStore, uow repository.Transactor, verdictCache *VerdictCache, cfg *config.Config) *RBIComplianceService {
 	return &RBIComplianceService{
-		rbiRepo: rbiRepo,
+		rbiRepo:      rbiRepo,
+		uow:          uow,
+		events:       NewEventQueue(cfg),
+		verdictCache: verdictCache,
 	}
 }
 
@@ -42,7 +50,7 @@ func (s *RBIComplianceService) VerifyCompliance(ctx context.Context, content str
 	// Search for matching circulars
 	circulars, err := s.rbiRepo.SearchCircularsByKeywords(ctx, keywords)
 	if err != nil {
-		utils.GetLogger().WithError(err).Error("Failed to search RBI circulars")
+		utils.GetLoggerWithContext(ctx).WithError(err).Error("Failed to search RBI circulars")
 		// Don't fail the verification, just log the error
 		result.Explanation = "Unable to verify RBI compliance due to service error"
 		return result, nil
@@ -129,3 +137,30 @@ func (s *RBIComplianceService) GetActiveCirculars(ctx context.Context) ([]*model
 	return s.rbiRepo.GetActiveCirculars(ctx)
 }
 
+// AddCircular records a new RBI circular with its CircularAdded event and
+// drops cached verdicts, which were checked against the previous set of
+// circulars
+func (s *RBIComplianceService) AddCircular(ctx context.Context, circular *models.RBICircular) error {
+	err := s.uow.Do(ctx, func(repos *repository.TxRepositories) error {
+		if err := repos.RBI.CreateCircular(ctx, circular); err != nil {
+			return err
+		}
+		return s.events.Queue(ctx, repos.Outbox, &events.CircularAdded{
+			CircularID:     circular.ID,
+			CircularNumber: circular.CircularNumber,
+			Title:          circular.Title,
+			Category:       circular.Category,
+			Keywords:       circular.Keywords,
+			IssuedDate:     circular.IssuedDate,
+			EffectiveDate:  circular.EffectiveDate,
+			IsActive:       circular.IsActive,
+			SourceURL:      circular.SourceURL,
+			AddedAt:        time.Now(),
+		})
+	})
+	if err != nil {
+		return err
+	}
+	s.verdictCache.Invalidate(ctx, "RBI circulars changed")
+	return nil
+}
@@ -0,0 +1,84 @@
+package service
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/fraud-detection-system/backend/internal/repository"
+	"github.com/fraud-detection-system/backend/internal/utils"
+)
+
+// ReencryptionService moves encrypted columns onto the active encryption key,
+// encrypting any legacy plaintext on the way
+type ReencryptionService struct {
+	messageRepo repository.MessageStore
+	reportRepo  repository.ReportStore
+	userRepo    repository.UserStore
+	batchSize   int
+}
+
+func NewReencryptionService(
+	messageRepo repository.MessageStore,
+	reportRepo repository.ReportStore,
+	userRepo repository.UserStore,
+	batchSize int,
+) *ReencryptionService {
+	return &ReencryptionService{
+		messageRepo: messageRepo,
+		reportRepo:  reportRepo,
+		userRepo:    userRepo,
+		batchSize:   batchSize,
+	}
+}
+
+// Run re-encrypts all messages and reports not yet on the active key and
+// returns how many of each were processed
+func (s *ReencryptionService) Run(ctx context.Context) (int, int, error) {
+	messages, err := s.inBatches(ctx, "messages", s.messageRepo.ReencryptBatch)
+	if err != nil {
+		return messages, 0, err
+	}
+
+	reports, err := s.inBatches(ctx, "reports", s.reportRepo.ReencryptBatch)
+	return messages, reports, err
+}
+
+// ReencryptTOTPSecrets moves users' TOTP secrets onto the active key and
+// returns how many it processed
+func (s *ReencryptionService) ReencryptTOTPSecrets(ctx context.Context) (int, error) {
+	return s.inBatches(ctx, "totp_secrets", s.userRepo.ReencryptTOTPBatch)
+}
+
+// IndexSearch builds the search vectors of messages that do not have one,
+// such as those stored before search existed, and returns how many it built
+func (s *ReencryptionService) IndexSearch(ctx context.Context) (int, error) {
+	return s.inBatches(ctx, "indexed_messages", s.messageRepo.IndexSearchBatch)
+}
+
+func (s *ReencryptionService) inBatches(
+	ctx context.Context,
+	entity string,
+	batch func(ctx context.Context, afterID uuid.UUID, limit int) (int, uuid.UUID, error),
+) (int, error) {
+	total := 0
+	afterID := uuid.Nil
+	for {
+		if err := ctx.Err(); err != nil {
+			return total, err
+		}
+
+		n, lastID, err := batch(ctx, afterID, s.batchSize)
+		if err != nil {
+			return total, err
+		}
+		total += n
+		afterID = lastID
+
+		if n > 0 {
+			utils.GetLoggerWithContext(ctx).WithField(entity, total).Info("Batch progress")
+		}
+		if n < s.batchSize {
+			return total, nil
+		}
+	}
+}
@@ -0,0 +1,103 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/fraud-detection-system/backend/internal/config"
+	"github.com/fraud-detection-system/backend/internal/events"
+	"github.com/fraud-detection-system/backend/internal/models"
+	"github.com/fraud-detection-system/backend/internal/repository"
+	"github.com/fraud-detection-system/backend/internal/utils"
+)
+
+// ReportService records user reports and their reviews, queueing the
+// messages and events about them in the same transaction
+type ReportService struct {
+	uow    repository.Transactor
+	events *EventQueue
+	config *config.Config
+}
+
+func NewReportService(uow repository.Transactor, cfg *config.Config) *ReportService {
+	return &ReportService{
+		uow:    uow,
+		events: NewEventQueue(cfg),
+		config: cfg,
+	}
+}
+
+// CreateReport stores a report. The message and ReportFiled event published
+// for it leave out the reported content, which is only kept encrypted.
+func (s *ReportService) CreateReport(ctx context.Context, report *models.Report) error {
+	return s.uow.Do(ctx, func(repos *repository.TxRepositories) error {
+		if err := repos.Reports.Create(ctx, report); err != nil {
+			return err
+		}
+
+		payload := map[string]interface{}{
+			"report_id":       report.ID.String(),
+			"report_type":     report.ReportType,
+			"sender_header":   report.SenderHeader,
+			"message_id":      report.MessageID,
+			"verification_id": report.VerificationID,
+			"status":          report.Status,
+			"priority":        report.Priority,
+			"timestamp":       report.CreatedAt,
+		}
+		message := models.NewQueueMessage(models.MessageTypeReport, utils.RedactFields(payload))
+		if err := repos.Outbox.AddMessage(ctx, s.config.Kafka.TopicReports, report.SenderHeader, message); err != nil {
+			return err
+		}
+
+		return s.events.Queue(ctx, repos.Outbox, &events.ReportFiled{
+			ReportID:       report.ID,
+			ReportType:     report.ReportType,
+			SenderHeader:   report.SenderHeader,
+			MessageID:      report.MessageID,
+			VerificationID: report.VerificationID,
+			Priority:       report.Priority,
+			FiledAt:        report.CreatedAt,
+		})
+	})
+}
+
+// ReviewReport records a reviewer's decision on a report. Resolving or
+// dismissing it publishes ReportResolved.
+func (s *ReportService) ReviewReport(ctx context.Context, id, reviewerID uuid.UUID, review *models.ReportReviewInput) (*models.Report, error) {
+	var report *models.Report
+	err := s.uow.Do(ctx, func(repos *repository.TxRepositories) error {
+		var err error
+		report, err = repos.Reports.GetByID(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		now := time.Now()
+		report.Status = review.Status
+		report.ReviewedBy = &reviewerID
+		report.ReviewedAt = &now
+		report.ReviewNotes = review.Notes
+		report.UpdatedAt = now
+		if err := repos.Reports.Update(ctx, report); err != nil {
+			return err
+		}
+
+		if report.Status != models.ReportStatusResolved && report.Status != models.ReportStatusDismissed {
+			return nil
+		}
+		return s.events.Queue(ctx, repos.Outbox, &events.ReportResolved{
+			ReportID:     report.ID,
+			ReportType:   report.ReportType,
+			SenderHeader: report.SenderHeader,
+			Status:       report.Status,
+			ReviewedBy:   reviewerID,
+			ResolvedAt:   now,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return report, nil
+}
@@ -0,0 +1,210 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/fraud-detection-system/backend/internal/cache"
+	"github.com/fraud-detection-system/backend/internal/config"
+	"github.com/fraud-detection-system/backend/internal/models"
+	"github.com/fraud-detection-system/backend/internal/repository"
+	"github.com/fraud-detection-system/backend/internal/utils"
+)
+
+// retentionLockKey ensures only one worker runs a retention pass at a time
+const retentionLockKey = "retention:lock"
+
+// RetentionService enforces the configured data retention policies
+type RetentionService struct {
+	messageRepo      repository.MessageStore
+	verificationRepo repository.VerificationStore
+	resetRepo        repository.PasswordResetStore
+	retentionRepo    repository.RetentionStore
+	cache            cache.Cache
+	config           config.RetentionConfig
+}
+
+func NewRetentionService(
+	messageRepo repository.MessageStore,
+	verificationRepo repository.VerificationStore,
+	resetRepo repository.PasswordResetStore,
+	retentionRepo repository.RetentionStore,
+	cache cache.Cache,
+	cfg *config.Config,
+) *RetentionService {
+	return &RetentionService{
+		messageRepo:      messageRepo,
+		verificationRepo: verificationRepo,
+		resetRepo:        resetRepo,
+		retentionRepo:    retentionRepo,
+		cache:            cache,
+		config:           cfg.Retention,
+	}
+}
+
+// Start runs a retention pass immediately and then on every interval until ctx is cancelled
+func (s *RetentionService) Start(ctx context.Context) {
+	logger := utils.GetLogger()
+	ticker := time.NewTicker(s.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := s.RunOnce(ctx); err != nil && ctx.Err() == nil {
+			logger.WithError(err).Error("Retention run failed")
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunOnce applies the retention policy of every tenant and records the run.
+// It returns nil without doing anything if another worker holds the lock.
+func (s *RetentionService) RunOnce(ctx context.Context) (*models.RetentionRun, error) {
+	// The lock holds a token unique to this run, so a run that outlives the
+	// lock cannot release the one another worker has taken since
+	token := uuid.New().String()
+	acquired, err := s.cache.SetNX(ctx, retentionLockKey, token, s.config.Interval)
+	if err != nil {
+		return nil, fmt.Errorf("failed to acquire retention lock: %w", err)
+	}
+	if !acquired {
+		utils.GetLoggerWithContext(ctx).Info("Retention run skipped, another worker holds the lock")
+		return nil, nil
+	}
+	defer func() {
+		released, err := s.cache.CompareAndDelete(context.Background(), retentionLockKey, token)
+		if err != nil {
+			utils.GetLoggerWithContext(ctx).WithError(err).Error("Failed to release retention lock")
+		} else if !released {
+			utils.GetLoggerWithContext(ctx).Warn("Retention lock expired before the run finished")
+		}
+	}()
+
+	run := &models.RetentionRun{
+		ID:        uuid.New(),
+		StartedAt: time.Now(),
+		Status:    "RUNNING",
+		Details:   make(map[string]*models.TenantRetentionStats),
+	}
+	if err := s.retentionRepo.CreateRun(ctx, run); err != nil {
+		return nil, err
+	}
+
+	runErr := s.run(ctx, run)
+
+	finishedAt := time.Now()
+	run.FinishedAt = &finishedAt
+	run.Status = "COMPLETED"
+	if runErr != nil {
+		run.Status = "FAILED"
+		msg := runErr.Error()
+		run.Error = &msg
+	}
+
+	// Record the outcome even if ctx was cancelled mid-run
+	if err := s.retentionRepo.FinishRun(context.Background(), run); err != nil {
+		utils.GetLoggerWithContext(ctx).WithError(err).Error("Failed to record retention run")
+	}
+
+	utils.GetLoggerWithContext(ctx).WithFields(map[string]interface{}{
+		"run_id":                run.ID,
+		"status":                run.Status,
+		"messages_deleted":      run.MessagesDeleted,
+		"messages_anonymized":   run.MessagesAnonymized,
+		"verifications_deleted": run.VerificationsDeleted,
+		"reset_tokens_deleted":  run.ResetTokensDeleted,
+	}).Info("Retention run finished")
+
+	return run, runErr
+}
+
+func (s *RetentionService) run(ctx context.Context, run *models.RetentionRun) error {
+	tenants, err := s.retentionRepo.ListTenants(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, tenantID := range tenants {
+		stats := &models.TenantRetentionStats{}
+		run.Details[tenantID] = stats
+
+		err := s.applyPolicy(ctx, tenantID, s.config.PolicyFor(tenantID), stats)
+		run.MessagesDeleted += stats.MessagesDeleted
+		run.MessagesAnonymized += stats.MessagesAnonymized
+		run.VerificationsDeleted += stats.VerificationsDeleted
+		if err != nil {
+			return fmt.Errorf("tenant %s: %w", tenantID, err)
+		}
+	}
+
+	deleted, err := s.resetRepo.DeleteExpired(ctx, time.Now())
+	if err != nil {
+		return err
+	}
+	run.ResetTokensDeleted = deleted
+
+	return nil
+}
+
+// applyPolicy removes a tenant's expired data. Verifications go first so that
+// messages whose verifications were just removed can be deleted outright;
+// messages that still back a retained verification are anonymized instead.
+func (s *RetentionService) applyPolicy(ctx context.Context, tenantID string, policy config.RetentionPolicy, stats *models.TenantRetentionStats) error {
+	now := time.Now()
+
+	if policy.Verifications > 0 {
+		cutoff := now.Add(-policy.Verifications)
+		err := s.inBatches(ctx, func() (int64, error) {
+			return s.verificationRepo.DeleteOldVerifications(ctx, tenantID, cutoff, s.config.BatchSize)
+		}, &stats.VerificationsDeleted)
+		if err != nil {
+			return err
+		}
+	}
+
+	if policy.Messages > 0 {
+		cutoff := now.Add(-policy.Messages)
+		err := s.inBatches(ctx, func() (int64, error) {
+			return s.messageRepo.DeleteOldMessages(ctx, tenantID, cutoff, s.config.BatchSize)
+		}, &stats.MessagesDeleted)
+		if err != nil {
+			return err
+		}
+
+		err = s.inBatches(ctx, func() (int64, error) {
+			return s.messageRepo.AnonymizeOldMessages(ctx, tenantID, cutoff, s.config.BatchSize)
+		}, &stats.MessagesAnonymized)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// inBatches calls batch until it affects fewer rows than the batch size or
+// the batch limit is reached, adding the affected rows to total
+func (s *RetentionService) inBatches(ctx context.Context, batch func() (int64, error), total *int64) error {
+	for i := 0; s.config.MaxBatches <= 0 || i < s.config.MaxBatches; i++ {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		affected, err := batch()
+		if err != nil {
+			return err
+		}
+		*total += affected
+
+		if affected < int64(s.config.BatchSize) {
+			return nil
+		}
+	}
+	return nil
+}
@@ -0,0 +1,91 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/fraud-detection-system/backend/internal/cache"
+	"github.com/fraud-detection-system/backend/internal/config"
+	"github.com/fraud-detection-system/backend/internal/models"
+	"github.com/fraud-detection-system/backend/internal/repository"
+	"github.com/fraud-detection-system/backend/internal/repository/memory"
+)
+
+func newTestRetentionService(db *memory.DB, c cache.Cache, retentionRepo repository.RetentionStore) *RetentionService {
+	cfg := &config.Config{Retention: config.RetentionConfig{
+		Interval:  time.Hour,
+		BatchSize: 10,
+		Default:   config.RetentionPolicy{Messages: 24 * time.Hour, Verifications: 24 * time.Hour},
+	}}
+	return NewRetentionService(db.Messages(), db.Verifications(), db.PasswordResets(), retentionRepo, c, cfg)
+}
+
+func TestRetentionRunOnce(t *testing.T) {
+	ctx := context.Background()
+	db := memory.New()
+	c := cache.NewMemoryCache(time.Minute)
+	old := time.Now().Add(-48 * time.Hour)
+	if err := db.Messages().Create(ctx, &models.Message{ID: uuid.New(), Content: "old", SenderHeader: "AD-TEST", CreatedAt: old}); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Messages().Create(ctx, &models.Message{ID: uuid.New(), Content: "new", SenderHeader: "AD-TEST", CreatedAt: time.Now()}); err != nil {
+		t.Fatal(err)
+	}
+
+	run, err := newTestRetentionService(db, c, db.Retention()).RunOnce(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if run == nil || run.Status != "COMPLETED" || run.MessagesDeleted != 1 {
+		t.Errorf("expected the old message to be deleted, got %+v", run)
+	}
+	if exists, _ := c.Exists(ctx, retentionLockKey); exists {
+		t.Error("expected the lock to be released")
+	}
+}
+
+func TestRetentionRunOnceSkipsWhenLocked(t *testing.T) {
+	ctx := context.Background()
+	db := memory.New()
+	c := cache.NewMemoryCache(time.Minute)
+	if _, err := c.SetNX(ctx, retentionLockKey, "other-worker", time.Hour); err != nil {
+		t.Fatal(err)
+	}
+
+	run, err := newTestRetentionService(db, c, db.Retention()).RunOnce(ctx)
+	if run != nil || err != nil {
+		t.Errorf("expected the run to be skipped, got %+v, %v", run, err)
+	}
+	if exists, _ := c.Exists(ctx, retentionLockKey); !exists {
+		t.Error("expected the other worker's lock to be kept")
+	}
+}
+
+// takeoverRetentionStore simulates the lock expiring mid-run and another
+// worker taking it
+type takeoverRetentionStore struct {
+	repository.RetentionStore
+	cache cache.Cache
+}
+
+func (s takeoverRetentionStore) ListTenants(ctx context.Context) ([]string, error) {
+	s.cache.Delete(ctx, retentionLockKey)
+	s.cache.SetNX(ctx, retentionLockKey, "other-worker", time.Hour)
+	return s.RetentionStore.ListTenants(ctx)
+}
+
+func TestRetentionRunOnceKeepsTakenOverLock(t *testing.T) {
+	ctx := context.Background()
+	db := memory.New()
+	c := cache.NewMemoryCache(time.Minute)
+
+	service := newTestRetentionService(db, c, takeoverRetentionStore{db.Retention(), c})
+	if _, err := service.RunOnce(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if released, _ := c.CompareAndDelete(ctx, retentionLockKey, "other-worker"); !released {
+		t.Error("expected the run not to release the lock another worker took")
+	}
+}
@@ -0,0 +1,106 @@
+package service
+
+import (
+	"context"
+
+	"github.com/fraud-detection-system/backend/internal/models"
+	"github.com/fraud-detection-system/backend/internal/repository"
+	"github.com/fraud-detection-system/backend/internal/utils"
+)
+
+// maxHighlightFragments caps the content fragments returned per hit
+const maxHighlightFragments = 3
+
+// SearchService searches stored messages and their verdicts for analysts
+type SearchService struct {
+	messageRepo repository.MessageStore
+}
+
+func NewSearchService(messageRepo repository.MessageStore) *SearchService {
+	return &SearchService{messageRepo: messageRepo}
+}
+
+// SearchMessages returns a page of messages matching search, with the
+// matches in each highlighted, and the total number of matches
+func (s *SearchService) SearchMessages(ctx context.Context, search models.MessageSearch) ([]*models.MessageSearchHit, int, error) {
+	terms, err := utils.ParseSearchQuery(search.Query)
+	substring := utils.SearchSubstring(search.Query)
+	if err != nil || (terms.Empty() && substring == "") {
+		return nil, 0, utils.ErrBadRequest
+	}
+	if search.Limit <= 0 {
+		search.Limit = models.DefaultPageSize
+	}
+	if search.Limit > models.MaxPageSize {
+		search.Limit = models.MaxPageSize
+	}
+
+	results, total, err := s.messageRepo.Search(ctx, search)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	hits := make([]*models.MessageSearchHit, len(results))
+	for i, result := range results {
+		message := result.Message
+		hit := &models.MessageSearchHit{
+			MessageID:     message.ID,
+			Content:       message.Content,
+			SenderHeader:  message.SenderHeader,
+			MessageType:   message.MessageType,
+			TenantID:      message.TenantID,
+			ExtractedURLs: message.ExtractedURLs,
+			Rank:          result.Rank,
+			Highlights:    []models.SearchHighlight{},
+			CreatedAt:     message.CreatedAt,
+		}
+		if v := result.Verification; v != nil {
+			hit.VerificationID = &v.ID
+			hit.IsFraud = &v.IsFraud
+			hit.FraudScore = &v.FraudScore
+			hit.FraudType = v.FraudType
+			hit.RiskLevel = models.RiskLevelForScore(v.FraudScore)
+		}
+
+		if search.HasField(models.SearchFieldContent) {
+			if fragments := terms.Highlight(message.Content, maxHighlightFragments); len(fragments) > 0 {
+				hit.Highlights = append(hit.Highlights, models.SearchHighlight{Field: models.SearchFieldContent, Fragments: fragments})
+			}
+		}
+		if search.HasField(models.SearchFieldURL) {
+			var fragments []string
+			for _, url := range message.ExtractedURLs {
+				if fragment := utils.HighlightSubstring(url, substring); fragment != "" {
+					fragments = append(fragments, fragment)
+				}
+			}
+			if len(fragments) > 0 {
+				hit.Highlights = append(hit.Highlights, models.SearchHighlight{Field: models.SearchFieldURL, Fragments: fragments})
+			}
+		}
+		if search.HasField(models.SearchFieldSender) {
+			if fragment := utils.HighlightSubstring(message.SenderHeader, substring); fragment != "" {
+				hit.Highlights = append(hit.Highlights, models.SearchHighlight{Field: models.SearchFieldSender, Fragments: []string{fragment}})
+			}
+		}
+		if search.HasField(models.SearchFieldPhone) && message.PhoneNumber != nil && samePhoneNumber(*message.PhoneNumber, search.Query) {
+			hit.Highlights = append(hit.Highlights, models.SearchHighlight{
+				Field:     models.SearchFieldPhone,
+				Fragments: []string{utils.HighlightSubstring(*message.PhoneNumber, *message.PhoneNumber)},
+			})
+		}
+		hits[i] = hit
+	}
+
+	return hits, total, nil
+}
+
+// samePhoneNumber reports whether a and b are the same phone number, as
+// written or once normalized
+func samePhoneNumber(a, b string) bool {
+	if a == b {
+		return true
+	}
+	normalized := utils.NormalizePhoneNumber(a)
+	return normalized != "" && normalized == utils.NormalizePhoneNumber(b)
+}
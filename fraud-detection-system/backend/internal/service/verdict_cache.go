@@ -0,0 +1,111 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/fraud-detection-system/backend/internal/cache"
+	"github.com/fraud-detection-system/backend/internal/config"
+	"github.com/fraud-detection-system/backend/internal/models"
+	"github.com/fraud-detection-system/backend/internal/utils"
+)
+
+// scoringPolicyVersion must be bumped whenever the way verdicts are derived
+// from the ML, header and RBI results changes, so cached verdicts are dropped
+const scoringPolicyVersion = "1"
+
+const (
+	verdictKeyPrefix       = "verdict:"
+	verdictGenerationKey   = "verdict:generation"
+	verdictModelVersionKey = "verdict:model_version"
+)
+
+// VerdictCache caches verification verdicts for identical messages. Keys
+// include a generation counter, so bumping it invalidates every verdict at once.
+type VerdictCache struct {
+	cache   cache.Cache
+	ttl     time.Duration
+	enabled bool
+}
+
+func NewVerdictCache(cache cache.Cache, cfg *config.Config) *VerdictCache {
+	return &VerdictCache{
+		cache:   cache,
+		ttl:     cfg.VerdictCache.TTL,
+		enabled: cfg.VerdictCache.Enabled,
+	}
+}
+
+// Enabled reports whether verdict caching is turned on
+func (v *VerdictCache) Enabled() bool {
+	return v.enabled
+}
+
+// Key returns the cache key for a message. Content is used exactly as sent:
+// case, length and punctuation are model features, so messages that differ
+// only in them can score differently.
+func (v *VerdictCache) Key(ctx context.Context, tenantID, content, senderHeader string) string {
+	var generation int64
+	if err := v.cache.Get(ctx, verdictGenerationKey, &generation); err != nil {
+		generation = 0
+	}
+
+	sum := sha256.Sum256([]byte(strings.Join([]string{
+		tenantID,
+		content,
+		strings.ToUpper(strings.TrimSpace(senderHeader)),
+		scoringPolicyVersion,
+		strconv.FormatInt(generation, 10),
+	}, "\x00")))
+
+	return verdictKeyPrefix + hex.EncodeToString(sum[:])
+}
+
+// Get returns the cached verdict for key, if any
+func (v *VerdictCache) Get(ctx context.Context, key string) (*models.CachedVerdict, bool) {
+	var verdict models.CachedVerdict
+	if err := v.cache.Get(ctx, key, &verdict); err != nil {
+		return nil, false
+	}
+	return &verdict, true
+}
+
+// Set caches a verdict under key
+func (v *VerdictCache) Set(ctx context.Context, key string, verdict *models.CachedVerdict) {
+	if err := v.cache.Set(ctx, key, verdict, v.ttl); err != nil {
+		utils.GetLoggerWithContext(ctx).WithError(err).Warn("Failed to cache verdict")
+	}
+}
+
+// Invalidate drops all cached verdicts
+func (v *VerdictCache) Invalidate(ctx context.Context, reason string) {
+	if _, err := v.cache.Increment(ctx, verdictGenerationKey); err != nil {
+		utils.GetLoggerWithContext(ctx).WithError(err).Error("Failed to invalidate verdict cache")
+		return
+	}
+	utils.GetLoggerWithContext(ctx).WithField("reason", reason).Info("Verdict cache invalidated")
+}
+
+// ObserveModelVersion invalidates cached verdicts when a model starts
+// answering with a different version. Versions are tracked per model so
+// routing between a champion and a challenger does not invalidate the cache.
+func (v *VerdictCache) ObserveModelVersion(ctx context.Context, model, modelVersion string) {
+	key := verdictModelVersionKey + ":" + model
+	var previous string
+	known := v.cache.Get(ctx, key, &previous) == nil
+	if known && previous == modelVersion {
+		return
+	}
+
+	if err := v.cache.Set(ctx, key, modelVersion, 30*24*time.Hour); err != nil {
+		utils.GetLoggerWithContext(ctx).WithError(err).Warn("Failed to record model version")
+		return
+	}
+	if known {
+		v.Invalidate(ctx, "model "+model+" version changed to "+modelVersion)
+	}
+}
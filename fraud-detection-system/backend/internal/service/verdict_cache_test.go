@@ -0,0 +1,33 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/fraud-detection-system/backend/internal/cache"
+	"github.com/fraud-detection-system/backend/internal/config"
+)
+
+func TestVerdictCacheKey(t *testing.T) {
+	ctx := context.Background()
+	v := NewVerdictCache(cache.NewMemoryCache(time.Minute), &config.Config{})
+	content := "URGENT: Your account is BLOCKED, update KYC now"
+
+	key := v.Key(ctx, "", content, "vm-alerts")
+	if got := v.Key(ctx, "", content, " VM-ALERTS "); got != key {
+		t.Error("expected the sender header to be normalized")
+	}
+	// Case and spacing are model features, so they must not share a verdict
+	for _, other := range []string{
+		"urgent: your account is blocked, update kyc now",
+		"URGENT:  Your account is BLOCKED, update KYC now",
+	} {
+		if v.Key(ctx, "", other, "VM-ALERTS") == key {
+			t.Errorf("expected %q to get its own key", other)
+		}
+	}
+	if v.Key(ctx, "other-bank", content, "VM-ALERTS") == key {
+		t.Error("expected tenants not to share verdicts")
+	}
+}
@@ -5,48 +5,89 @@ import (
 	"encoding/json"
 	"fmt"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/fraud-detection-system/backend/internal/cache"
+	"github.com/fraud-detection-system/backend/internal/config"
+	"github.com/fraud-detection-system/backend/internal/events"
+	"github.com/fraud-detection-system/backend/internal/metrics"
 	"github.com/fraud-detection-system/backend/internal/models"
 	"github.com/fraud-detection-system/backend/internal/repository"
 	"github.com/fraud-detection-system/backend/internal/utils"
 )
 
 type VerificationService struct {
-	messageRepo       *repository.MessageRepository
-	verificationRepo  *repository.VerificationRepository
-	mlClient          *MLClient
-	rbiService        *RBIComplianceService
-	headerService     *HeaderVerificationService
-	cache             *cache.RedisCache
+	verificationRepo repository.VerificationStore
+	uow              repository.Transactor
+	mlClient         *MLClient
+	rbiService       *RBIComplianceService
+	headerService    *HeaderVerificationService
+	fallbackScorer   *FallbackScorer
+	alertService     *AlertService
+	events           *EventQueue
+	verdictCache     *VerdictCache
+	cache            cache.Cache
+	config           *config.Config
 }
 
 func NewVerificationService(
-	messageRepo *repository.MessageRepository,
-	verificationRepo *repository.VerificationRepository,
+	verificationRepo repository.VerificationStore,
+	uow repository.Transactor,
 	mlClient *MLClient,
 	rbiService *RBIComplianceService,
 	headerService *HeaderVerificationService,
-	cache *cache.RedisCache,
+	verdictCache *VerdictCache,
+	cache cache.Cache,
+	cfg *config.Config,
 ) *VerificationService {
 	return &VerificationService{
-		messageRepo:      messageRepo,
 		verificationRepo: verificationRepo,
+		uow:              uow,
 		mlClient:         mlClient,
 		rbiService:       rbiService,
 		headerService:    headerService,
+		fallbackScorer:   NewFallbackScorer(),
+		alertService:     NewAlertService(cfg),
+		events:           NewEventQueue(cfg),
+		verdictCache:     verdictCache,
 		cache:            cache,
+		config:           cfg,
 	}
 }
 
+// shadowRecordTimeout bounds storing a shadow prediction after the response
+const shadowRecordTimeout = 5 * time.Second
+
+// mlPrediction is the result of the ML check
+type mlPrediction struct {
+	resp    *models.MLInferenceResponse
+	routing *models.MLRouting
+	shadow  *ShadowPrediction
+}
+
 // VerifyMessage performs comprehensive fraud verification on a message
 func (s *VerificationService) VerifyMessage(ctx context.Context, req *models.VerificationRequest, userID *uuid.UUID) (*models.VerificationResponse, error) {
 	startTime := time.Now()
+	if req.TenantID == "" {
+		req.TenantID = models.DefaultTenantID
+	}
+
+	timings := make(map[string]models.StageTiming)
 
 	// Extract message features
-	features := s.extractFeatures(req.Content, req.SenderHeader)
+	stageStart := time.Now()
+	features := ExtractFeatures(req.Content, req.SenderHeader)
+	timings[models.StageFeatures] = stageTiming(stageStart)
+
+	// Keep the content-free features so the message stays auditable after
+	// retention anonymizes its content
+	auditFeatures := features
+	auditFeatures.Content = ""
+	auditFeatures.ExtractedURLs = nil
+	featuresJSON, _ := json.Marshal(auditFeatures)
+	featuresStr := string(featuresJSON)
 
 	// Create message record
 	message := &models.Message{
@@ -60,13 +101,22 @@ func (s *VerificationService) VerifyMessage(ctx context.Context, req *models.Ver
 		HasLinks:      features.HasLinks,
 		LinkCount:     features.LinkCount,
 		ExtractedURLs: features.ExtractedURLs,
+		TenantID:      req.TenantID,
+		Features:      &featuresStr,
 		CreatedAt:     time.Now(),
 		UpdatedAt:     time.Now(),
 	}
 
-	if err := s.messageRepo.Create(ctx, message); err != nil {
-		utils.GetLogger().WithError(err).Error("Failed to create message")
-		return nil, fmt.Errorf("failed to create message: %w", err)
+	// Reuse the verdict of an identical recent message
+	var verdictKey string
+	if s.verdictCache.Enabled() {
+		stageStart = time.Now()
+		verdictKey = s.verdictCache.Key(ctx, req.TenantID, req.Content, req.SenderHeader)
+		verdict, ok := s.verdictCache.Get(ctx, verdictKey)
+		timings[models.StageCacheLookup] = stageTiming(stageStart)
+		if ok {
+			return s.respondFromCache(ctx, message, verdict, userID, startTime, timings)
+		}
 	}
 
 	// Perform ML inference
@@ -76,16 +126,63 @@ func (s *VerificationService) VerifyMessage(ctx context.Context, req *models.Ver
 		Features:     features,
 	}
 
-	mlResp, err := s.mlClient.Predict(ctx, mlReq)
-	if err != nil {
-		utils.GetLogger().WithError(err).Error("ML prediction failed")
-		return nil, fmt.Errorf("ML prediction failed: %w", err)
+	// The ML, header and RBI checks are independent, so run them in parallel
+	// under a shared deadline, each with its own timeout
+	checkCtx, cancel := context.WithTimeout(ctx, s.config.Verification.Deadline)
+	defer cancel()
+
+	var (
+		wg                                sync.WaitGroup
+		prediction                        mlPrediction
+		headerResult                      *models.HeaderVerificationResult
+		rbiResult                         *models.RBIComplianceCheck
+		mlErr, headerErr, rbiErr          error
+		mlTiming, headerTiming, rbiTiming models.StageTiming
+	)
+	wg.Add(3)
+	go func() {
+		defer wg.Done()
+		prediction, mlTiming, mlErr = runCheck(checkCtx, s.config.Verification.MLTimeout, func(ctx context.Context) (mlPrediction, error) {
+			resp, routing, shadow, err := s.mlClient.Predict(ctx, mlReq, req.TenantID)
+			return mlPrediction{resp: resp, routing: routing, shadow: shadow}, err
+		})
+	}()
+	go func() {
+		defer wg.Done()
+		headerResult, headerTiming, headerErr = runCheck(checkCtx, s.config.Verification.HeaderTimeout, func(ctx context.Context) (*models.HeaderVerificationResult, error) {
+			return s.headerService.VerifyHeader(ctx, req.SenderHeader)
+		})
+	}()
+	go func() {
+		defer wg.Done()
+		rbiResult, rbiTiming, rbiErr = runCheck(checkCtx, s.config.Verification.RBITimeout, func(ctx context.Context) (*models.RBIComplianceCheck, error) {
+			return s.rbiService.VerifyCompliance(ctx, req.Content)
+		})
+	}()
+	wg.Wait()
+	timings[models.StageML] = mlTiming
+	timings[models.StageHeader] = headerTiming
+	timings[models.StageRBI] = rbiTiming
+	metrics.HeaderChecks.WithLabelValues(headerOutcome(headerResult, headerTiming)).Inc()
+	metrics.RBIChecks.WithLabelValues(rbiOutcome(rbiResult, rbiTiming)).Inc()
+
+	degraded := false
+	mlResp, routing := prediction.resp, prediction.routing
+	if mlErr != nil {
+		if !s.mlClient.FallbackEnabled() {
+			utils.GetLoggerWithContext(ctx).WithError(mlErr).Error("ML prediction failed")
+			return nil, fmt.Errorf("ML prediction failed: %w", mlErr)
+		}
+		// Degrade to the local heuristic rather than failing the request
+		utils.GetLoggerWithContext(ctx).WithError(mlErr).Warn("ML prediction failed, using fallback scorer")
+		mlResp, routing = s.fallbackScorer.Score(features), nil
+		degraded = true
+	} else if verdictKey != "" {
+		s.verdictCache.ObserveModelVersion(ctx, routing.Primary.Model, mlResp.ModelVersion)
 	}
 
-	// Verify header
-	headerResult, err := s.headerService.VerifyHeader(ctx, req.SenderHeader)
-	if err != nil {
-		utils.GetLogger().WithError(err).Error("Header verification failed")
+	if headerErr != nil {
+		utils.GetLoggerWithContext(ctx).WithError(headerErr).Error("Header verification failed")
 		// Continue with default values
 		headerResult = &models.HeaderVerificationResult{
 			IsVerified:      false,
@@ -94,10 +191,8 @@ func (s *VerificationService) VerifyMessage(ctx context.Context, req *models.Ver
 		}
 	}
 
-	// Verify RBI compliance
-	rbiResult, err := s.rbiService.VerifyCompliance(ctx, req.Content)
-	if err != nil {
-		utils.GetLogger().WithError(err).Error("RBI compliance check failed")
+	if rbiErr != nil {
+		utils.GetLoggerWithContext(ctx).WithError(rbiErr).Error("RBI compliance check failed")
 		// Continue with default values
 		rbiResult = &models.RBIComplianceCheck{
 			IsCompliant: true,
@@ -121,9 +216,20 @@ func (s *VerificationService) VerifyMessage(ctx context.Context, req *models.Ver
 	recommendations := s.generateRecommendations(isFraud, headerResult, rbiResult)
 
 	// Marshal JSON fields
-	mlPredictionsJSON, _ := json.Marshal(mlResp.ModelPredictions)
+	mlPredictions := mlResp.ModelPredictions
+	if routing != nil {
+		// Keep which model decided, and the shadow prediction, for model comparison
+		mlPredictions = make(map[string]interface{}, len(mlResp.ModelPredictions)+1)
+		for k, v := range mlResp.ModelPredictions {
+			mlPredictions[k] = v
+		}
+		mlPredictions["routing"] = routing
+	}
+	mlPredictionsJSON, _ := json.Marshal(mlPredictions)
 	rbiResultJSON, _ := json.Marshal(rbiResult)
 	recommendationsJSON, _ := json.Marshal(recommendations)
+	timingsJSON, _ := json.Marshal(timings)
+	timingsStr := string(timingsJSON)
 
 	// Create verification record
 	verification := &models.Verification{
@@ -143,21 +249,44 @@ func (s *VerificationService) VerifyMessage(ctx context.Context, req *models.Ver
 		Explanation:           explanation,
 		Recommendations:       string(recommendationsJSON),
 		ProcessingTimeMs:      int(time.Since(startTime).Milliseconds()),
+		Degraded:              degraded,
+		TenantID:              req.TenantID,
+		StageTimings:          &timingsStr,
 		CreatedAt:             time.Now(),
 		UpdatedAt:             time.Now(),
 	}
 
-	if err := s.verificationRepo.Create(ctx, verification); err != nil {
-		utils.GetLogger().WithError(err).Error("Failed to create verification")
-		return nil, fmt.Errorf("failed to create verification: %w", err)
-	}
-
-	// Update sender stats
-	go s.headerService.UpdateSenderStats(context.Background(), req.SenderHeader, isFraud)
-
 	// Determine risk level
 	riskLevel := s.determineRiskLevel(finalScore, headerResult.RiskLevel)
 
+	stageStart = time.Now()
+	if err := s.persist(ctx, message, verification, riskLevel); err != nil {
+		return nil, err
+	}
+	// Persisting happens after the timings are stored, so only the response has it
+	timings[models.StagePersist] = stageTiming(stageStart)
+	if prediction.shadow != nil && !degraded {
+		go s.recordShadow(ctx, verification.ID, prediction.shadow)
+	}
+
+	source := "scored"
+	if degraded {
+		source = "fallback"
+	}
+	recordVerification(isFraud, riskLevel, &fraudType, source)
+	observeStages(timings)
+
+	// Fallback verdicts are not cached so the ML service scores the message
+	// once it is back
+	if verdictKey != "" && !degraded {
+		s.verdictCache.Set(ctx, verdictKey, &models.CachedVerdict{
+			Verification:     *verification,
+			RiskLevel:        riskLevel,
+			Recommendations:  recommendations,
+			ModelPredictions: mlResp.ModelPredictions,
+		})
+	}
+
 	return &models.VerificationResponse{
 		ID:               verification.ID,
 		MessageID:        message.ID,
@@ -171,13 +300,145 @@ func (s *VerificationService) VerifyMessage(ctx context.Context, req *models.Ver
 		Explanation:      explanation,
 		Recommendations:  recommendations,
 		ModelPredictions: mlResp.ModelPredictions,
+		ModelVersion:     mlResp.ModelVersion,
+		Degraded:         degraded,
 		ProcessingTimeMs: verification.ProcessingTimeMs,
+		StageTimings:     timings,
 		VerifiedAt:       verification.CreatedAt,
 	}, nil
 }
 
-// extractFeatures extracts features from message content
-func (s *VerificationService) extractFeatures(content, senderHeader string) models.MessageFeatures {
+// recordShadow stores the shadow model's prediction with a verification once
+// it arrives. It runs after the response is sent, so it is not cancelled with
+// the request.
+func (s *VerificationService) recordShadow(ctx context.Context, verificationID uuid.UUID, shadow *ShadowPrediction) {
+	score, ok := shadow.Wait()
+	if !ok {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), shadowRecordTimeout)
+	defer cancel()
+	if err := s.verificationRepo.SetShadowScore(ctx, verificationID, score); err != nil {
+		utils.GetLoggerWithContext(ctx).WithError(err).WithField("model", shadow.Model).Warn("Failed to record shadow prediction")
+	}
+}
+
+// respondFromCache records the message and a copy of a cached verdict,
+// linked to the verification it was first computed for
+func (s *VerificationService) respondFromCache(ctx context.Context, message *models.Message, verdict *models.CachedVerdict, userID *uuid.UUID, startTime time.Time, timings map[string]models.StageTiming) (*models.VerificationResponse, error) {
+	timingsJSON, _ := json.Marshal(timings)
+	timingsStr := string(timingsJSON)
+
+	source := verdict.Verification
+	verification := source
+	verification.ID = uuid.New()
+	verification.MessageID = message.ID
+	verification.UserID = userID
+	verification.TenantID = message.TenantID
+	verification.SourceVerificationID = &source.ID
+	verification.ProcessingTimeMs = int(time.Since(startTime).Milliseconds())
+	verification.StageTimings = &timingsStr
+	verification.CreatedAt = time.Now()
+	verification.UpdatedAt = time.Now()
+
+	stageStart := time.Now()
+	if err := s.persist(ctx, message, &verification, verdict.RiskLevel); err != nil {
+		return nil, err
+	}
+	timings[models.StagePersist] = stageTiming(stageStart)
+	recordVerification(verification.IsFraud, verdict.RiskLevel, verification.FraudType, "cached")
+	observeStages(timings)
+
+	return &models.VerificationResponse{
+		ID:                   verification.ID,
+		MessageID:            message.ID,
+		IsFraud:              verification.IsFraud,
+		FraudScore:           verification.FraudScore,
+		FraudType:            verification.FraudType,
+		Confidence:           verification.Confidence,
+		RiskLevel:            verdict.RiskLevel,
+		HeaderVerified:       verification.HeaderVerified,
+		RBICompliant:         verification.RBICompliant,
+		Explanation:          verification.Explanation,
+		Recommendations:      verdict.Recommendations,
+		ModelPredictions:     verdict.ModelPredictions,
+		ModelVersion:         verification.ModelVersion,
+		Cached:               true,
+		SourceVerificationID: &source.ID,
+		ProcessingTimeMs:     verification.ProcessingTimeMs,
+		StageTimings:         timings,
+		VerifiedAt:           verification.CreatedAt,
+	}, nil
+}
+
+// persist writes the message and its verification in one transaction,
+// along with the outbox entries that update the sender's stats and the
+// analytics rollups, publish the VerificationCompleted event and raise a
+// high-risk alert
+func (s *VerificationService) persist(ctx context.Context, message *models.Message, verification *models.Verification, riskLevel string) error {
+	return s.uow.Do(ctx, func(repos *repository.TxRepositories) error {
+		if err := repos.Messages.Create(ctx, message); err != nil {
+			utils.GetLoggerWithContext(ctx).WithError(err).Error("Failed to create message")
+			return fmt.Errorf("failed to create message: %w", err)
+		}
+		if err := repos.Verifications.Create(ctx, verification); err != nil {
+			utils.GetLoggerWithContext(ctx).WithError(err).Error("Failed to create verification")
+			return fmt.Errorf("failed to create verification: %w", err)
+		}
+		if err := repos.Outbox.Add(ctx, models.OutboxKindSenderStats, models.SenderStatsUpdate{
+			SenderHeader: message.SenderHeader,
+			IsFraud:      verification.IsFraud,
+		}); err != nil {
+			return err
+		}
+		if err := repos.Outbox.Add(ctx, models.OutboxKindAnalytics, rollupUpdate(message, verification, riskLevel)); err != nil {
+			return err
+		}
+		if err := s.events.Queue(ctx, repos.Outbox, &events.VerificationCompleted{
+			VerificationID:       verification.ID,
+			MessageID:            message.ID,
+			TenantID:             verification.TenantID,
+			SenderHeader:         message.SenderHeader,
+			IsFraud:              verification.IsFraud,
+			FraudScore:           verification.FraudScore,
+			FraudType:            verification.FraudType,
+			Confidence:           verification.Confidence,
+			ModelVersion:         verification.ModelVersion,
+			Degraded:             verification.Degraded,
+			SourceVerificationID: verification.SourceVerificationID,
+			CompletedAt:          verification.CreatedAt,
+		}); err != nil {
+			return err
+		}
+		return s.alertService.QueueHighRiskAlert(ctx, repos.Outbox, verification)
+	})
+}
+
+// rollupUpdate returns the analytics rollup update for a verification
+func rollupUpdate(message *models.Message, verification *models.Verification, riskLevel string) models.VerificationRollupUpdate {
+	update := models.VerificationRollupUpdate{
+		TenantID:     verification.TenantID,
+		MessageType:  message.MessageType,
+		SenderHeader: message.SenderHeader,
+		IsFraud:      verification.IsFraud,
+		RiskLevel:    riskLevel,
+		FraudType:    verification.FraudType,
+		VerifiedAt:   verification.CreatedAt,
+	}
+	if update.TenantID == "" {
+		update.TenantID = models.DefaultTenantID
+	}
+	if update.MessageType == "" {
+		update.MessageType = models.DefaultMessageType
+	}
+	if verification.IsFraud {
+		update.Banks = utils.MentionedBanks(message.Content)
+	}
+	return update
+}
+
+// ExtractFeatures extracts features from message content
+func ExtractFeatures(content, senderHeader string) models.MessageFeatures {
 	urls := utils.ExtractURLs(content)
 	phoneNumbers := utils.ExtractPhoneNumbers(content)
 
@@ -258,15 +519,15 @@ func (s *VerificationService) generateRecommendations(isFraud bool, headerResult
 		recommendations = append(recommendations, "Do not click on any links in this message")
 		recommendations = append(recommendations, "Do not share personal or financial information")
 		recommendations = append(recommendations, "Do not call any phone numbers provided in the message")
-		
+
 		if !headerResult.IsVerified {
 			recommendations = append(recommendations, "The sender is not verified - this is likely a spoofed message")
 		}
-		
+
 		if !rbiResult.IsCompliant {
 			recommendations = append(recommendations, "This message references fake or expired regulatory requirements")
 		}
-		
+
 		recommendations = append(recommendations, "Report this message to your bank and regulatory authorities")
 		recommendations = append(recommendations, "Delete this message immediately")
 	} else {
@@ -304,31 +565,42 @@ func (s *VerificationService) GetVerificationByID(ctx context.Context, id uuid.U
 	var recommendations []string
 	json.Unmarshal([]byte(verification.Recommendations), &recommendations)
 
+	var stageTimings map[string]models.StageTiming
+	if verification.StageTimings != nil {
+		json.Unmarshal([]byte(*verification.StageTimings), &stageTimings)
+	}
+
 	riskLevel := s.determineRiskLevel(verification.FraudScore, "")
 
 	return &models.VerificationResponse{
-		ID:               verification.ID,
-		MessageID:        verification.MessageID,
-		IsFraud:          verification.IsFraud,
-		FraudScore:       verification.FraudScore,
-		FraudType:        verification.FraudType,
-		Confidence:       verification.Confidence,
-		RiskLevel:        riskLevel,
-		HeaderVerified:   verification.HeaderVerified,
-		RBICompliant:     verification.RBICompliant,
-		Explanation:      verification.Explanation,
-		Recommendations:  recommendations,
-		ModelPredictions: modelPredictions,
-		ProcessingTimeMs: verification.ProcessingTimeMs,
-		VerifiedAt:       verification.CreatedAt,
+		ID:                   verification.ID,
+		MessageID:            verification.MessageID,
+		IsFraud:              verification.IsFraud,
+		FraudScore:           verification.FraudScore,
+		FraudType:            verification.FraudType,
+		Confidence:           verification.Confidence,
+		RiskLevel:            riskLevel,
+		HeaderVerified:       verification.HeaderVerified,
+		RBICompliant:         verification.RBICompliant,
+		Explanation:          verification.Explanation,
+		Recommendations:      recommendations,
+		ModelPredictions:     modelPredictions,
+		ModelVersion:         verification.ModelVersion,
+		Degraded:             verification.Degraded,
+		Cached:               verification.SourceVerificationID != nil,
+		SourceVerificationID: verification.SourceVerificationID,
+		ProcessingTimeMs:     verification.ProcessingTimeMs,
+		StageTimings:         stageTimings,
+		VerifiedAt:           verification.CreatedAt,
 	}, nil
 }
 
-// GetVerificationHistory retrieves verification history for a user
-func (s *VerificationService) GetVerificationHistory(ctx context.Context, userID uuid.UUID, limit, offset int) ([]*models.VerificationResponse, error) {
-	verifications, err := s.verificationRepo.GetByUserID(ctx, userID, limit, offset)
+// GetVerificationHistory retrieves a page of a user's verification history
+// matching filter
+func (s *VerificationService) GetVerificationHistory(ctx context.Context, userID uuid.UUID, filter models.VerificationFilter, opts models.ListOptions) ([]*models.VerificationResponse, *models.PageInfo, error) {
+	verifications, page, err := s.verificationRepo.ListByUserID(ctx, userID, filter, opts)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	responses := make([]*models.VerificationResponse, len(verifications))
@@ -339,27 +611,37 @@ func (s *VerificationService) GetVerificationHistory(ctx context.Context, userID
 		var recommendations []string
 		json.Unmarshal([]byte(v.Recommendations), &recommendations)
 
+		var stageTimings map[string]models.StageTiming
+		if v.StageTimings != nil {
+			json.Unmarshal([]byte(*v.StageTimings), &stageTimings)
+		}
+
 		riskLevel := s.determineRiskLevel(v.FraudScore, "")
 
 		responses[i] = &models.VerificationResponse{
-			ID:               v.ID,
-			MessageID:        v.MessageID,
-			IsFraud:          v.IsFraud,
-			FraudScore:       v.FraudScore,
-			FraudType:        v.FraudType,
-			Confidence:       v.Confidence,
-			RiskLevel:        riskLevel,
-			HeaderVerified:   v.HeaderVerified,
-			RBICompliant:     v.RBICompliant,
-			Explanation:      v.Explanation,
-			Recommendations:  recommendations,
-			ModelPredictions: modelPredictions,
-			ProcessingTimeMs: v.ProcessingTimeMs,
-			VerifiedAt:       v.CreatedAt,
+			ID:                   v.ID,
+			MessageID:            v.MessageID,
+			IsFraud:              v.IsFraud,
+			FraudScore:           v.FraudScore,
+			FraudType:            v.FraudType,
+			Confidence:           v.Confidence,
+			RiskLevel:            riskLevel,
+			HeaderVerified:       v.HeaderVerified,
+			RBICompliant:         v.RBICompliant,
+			Explanation:          v.Explanation,
+			Recommendations:      recommendations,
+			ModelPredictions:     modelPredictions,
+			ModelVersion:         v.ModelVersion,
+			Degraded:             v.Degraded,
+			Cached:               v.SourceVerificationID != nil,
+			SourceVerificationID: v.SourceVerificationID,
+			ProcessingTimeMs:     v.ProcessingTimeMs,
+			StageTimings:         stageTimings,
+			VerifiedAt:           v.CreatedAt,
 		}
 	}
 
-	return responses, nil
+	return responses, page, nil
 }
 
 // GetStats retrieves verification statistics
@@ -367,3 +649,8 @@ func (s *VerificationService) GetStats(ctx context.Context, userID *uuid.UUID) (
 	return s.verificationRepo.GetStats(ctx, userID)
 }
 
+// GetModelComparison reports how the champion and challenger models agreed
+// on the verifications they both scored since the given time
+func (s *VerificationService) GetModelComparison(ctx context.Context, since time.Time) ([]*models.ModelComparison, error) {
+	return s.verificationRepo.GetModelComparison(ctx, since)
+}
@@ -0,0 +1,124 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/fraud-detection-system/backend/internal/metrics"
+	"github.com/fraud-detection-system/backend/internal/models"
+)
+
+// runCheck runs one verification check with its own timeout inside ctx. It
+// returns once the timeout passes even if the check ignores its context, and
+// reports how long the check took and how it ended.
+func runCheck[T any](ctx context.Context, timeout time.Duration, check func(context.Context) (T, error)) (T, models.StageTiming, error) {
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	type result struct {
+		value T
+		err   error
+	}
+	done := make(chan result, 1)
+	start := time.Now()
+	go func() {
+		value, err := check(ctx)
+		done <- result{value: value, err: err}
+	}()
+
+	var r result
+	select {
+	case r = <-done:
+	case <-ctx.Done():
+		r.err = ctx.Err()
+	}
+
+	timing := stageTiming(start)
+	switch {
+	case r.err == nil:
+	case errors.Is(r.err, context.DeadlineExceeded):
+		timing.Status = models.StageStatusTimeout
+	default:
+		timing.Status = models.StageStatusError
+	}
+	return r.value, timing, r.err
+}
+
+// stageTiming records a stage that started at start and succeeded
+func stageTiming(start time.Time) models.StageTiming {
+	return models.StageTiming{
+		DurationMs: int(time.Since(start).Milliseconds()),
+		Status:     models.StageStatusOK,
+	}
+}
+
+// observeStages records the duration of each stage of a verification
+func observeStages(timings map[string]models.StageTiming) {
+	for stage, timing := range timings {
+		metrics.VerificationStageDuration.WithLabelValues(stage, timing.Status).Observe(float64(timing.DurationMs) / 1000)
+	}
+}
+
+// recordVerification counts a verdict. source is scored, fallback or cached.
+func recordVerification(isFraud bool, riskLevel string, fraudType *string, source string) {
+	verdict := "legitimate"
+	if isFraud {
+		verdict = "fraud"
+	}
+	metrics.Verifications.WithLabelValues(verdict, riskLevel, fraudTypeLabel(fraudType), source).Inc()
+}
+
+// knownFraudTypes are the fraud types the ML service and the fallback scorer
+// produce. Anything else is labelled other, so a misbehaving model cannot
+// create unbounded metric series.
+var knownFraudTypes = map[string]bool{
+	"none":          true,
+	"kyc_fraud":     true,
+	"phishing":      true,
+	"vishing":       true,
+	"urgency_scam":  true,
+	"impersonation": true,
+	"generic_fraud": true,
+}
+
+// fraudTypeLabel maps a fraud type to a bounded label value
+func fraudTypeLabel(fraudType *string) string {
+	switch {
+	case fraudType == nil || *fraudType == "":
+		return "none"
+	case knownFraudTypes[*fraudType]:
+		return *fraudType
+	default:
+		return "other"
+	}
+}
+
+// headerOutcome labels how a sender header check ended
+func headerOutcome(result *models.HeaderVerificationResult, timing models.StageTiming) string {
+	switch {
+	case timing.Status != models.StageStatusOK:
+		return timing.Status
+	case !result.SenderExists:
+		return "unknown_sender"
+	case result.IsVerified:
+		return "verified"
+	default:
+		return "unverified"
+	}
+}
+
+// rbiOutcome labels how an RBI compliance check ended
+func rbiOutcome(result *models.RBIComplianceCheck, timing models.StageTiming) string {
+	switch {
+	case timing.Status != models.StageStatusOK:
+		return timing.Status
+	case result.IsCompliant:
+		return "compliant"
+	default:
+		return "non_compliant"
+	}
+}
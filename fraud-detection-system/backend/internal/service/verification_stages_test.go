@@ -0,0 +1,156 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	"github.com/fraud-detection-system/backend/internal/metrics"
+	"github.com/fraud-detection-system/backend/internal/models"
+)
+
+func TestRunCheck(t *testing.T) {
+	value, timing, err := runCheck(context.Background(), time.Second, func(ctx context.Context) (string, error) {
+		return "ok", nil
+	})
+	if err != nil || value != "ok" || timing.Status != models.StageStatusOK {
+		t.Errorf("got %q, %+v, %v", value, timing, err)
+	}
+
+	_, timing, err = runCheck(context.Background(), time.Second, func(ctx context.Context) (string, error) {
+		return "", errors.New("boom")
+	})
+	if err == nil || timing.Status != models.StageStatusError {
+		t.Errorf("expected error status, got %+v, %v", timing, err)
+	}
+}
+
+func TestRunCheckTimeout(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+
+	// The check ignores its context; runCheck must still return on time
+	start := time.Now()
+	_, timing, err := runCheck(context.Background(), 20*time.Millisecond, func(ctx context.Context) (int, error) {
+		<-release
+		return 1, nil
+	})
+	if !errors.Is(err, context.DeadlineExceeded) || timing.Status != models.StageStatusTimeout {
+		t.Errorf("expected timeout, got %+v, %v", timing, err)
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("timeout not honoured, took %s", elapsed)
+	}
+
+	// The shared deadline applies even when the check's own timeout is longer
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	_, timing, _ = runCheck(ctx, time.Minute, func(ctx context.Context) (int, error) {
+		<-release
+		return 1, nil
+	})
+	if timing.Status != models.StageStatusTimeout {
+		t.Errorf("expected shared deadline to time out the check, got %+v", timing)
+	}
+}
+
+func TestHeaderOutcome(t *testing.T) {
+	ok := models.StageTiming{Status: models.StageStatusOK}
+	tests := []struct {
+		result *models.HeaderVerificationResult
+		timing models.StageTiming
+		want   string
+	}{
+		{nil, models.StageTiming{Status: models.StageStatusTimeout}, "timeout"},
+		{nil, models.StageTiming{Status: models.StageStatusError}, "error"},
+		{&models.HeaderVerificationResult{}, ok, "unknown_sender"},
+		{&models.HeaderVerificationResult{SenderExists: true, IsVerified: true}, ok, "verified"},
+		{&models.HeaderVerificationResult{SenderExists: true}, ok, "unverified"},
+	}
+	for _, tt := range tests {
+		if got := headerOutcome(tt.result, tt.timing); got != tt.want {
+			t.Errorf("headerOutcome(%+v, %s) = %q, want %q", tt.result, tt.timing.Status, got, tt.want)
+		}
+	}
+}
+
+func TestRBIOutcome(t *testing.T) {
+	ok := models.StageTiming{Status: models.StageStatusOK}
+	if got := rbiOutcome(&models.RBIComplianceCheck{IsCompliant: true}, ok); got != "compliant" {
+		t.Errorf("got %q, want compliant", got)
+	}
+	if got := rbiOutcome(&models.RBIComplianceCheck{}, ok); got != "non_compliant" {
+		t.Errorf("got %q, want non_compliant", got)
+	}
+	if got := rbiOutcome(nil, models.StageTiming{Status: models.StageStatusTimeout}); got != "timeout" {
+		t.Errorf("got %q, want timeout", got)
+	}
+}
+
+func TestFraudTypeLabel(t *testing.T) {
+	str := func(s string) *string { return &s }
+	tests := []struct {
+		fraudType *string
+		want      string
+	}{
+		{nil, "none"},
+		{str(""), "none"},
+		{str("none"), "none"},
+		{str("phishing"), "phishing"},
+		{str("kyc_fraud"), "kyc_fraud"},
+		{str("generic_fraud"), "generic_fraud"},
+		{str("Phishing"), "other"},
+		{str("made-up-by-a-new-model"), "other"},
+	}
+	for _, tt := range tests {
+		if got := fraudTypeLabel(tt.fraudType); got != tt.want {
+			t.Errorf("fraudTypeLabel(%v) = %q, want %q", tt.fraudType, got, tt.want)
+		}
+	}
+}
+
+func TestRecordVerification(t *testing.T) {
+	other := metrics.Verifications.WithLabelValues("fraud", "high", "other", "scored")
+	legit := metrics.Verifications.WithLabelValues("legitimate", "low", "none", "cached")
+	otherBefore, legitBefore := testutil.ToFloat64(other), testutil.ToFloat64(legit)
+
+	unknown := "unlisted_type"
+	recordVerification(true, "high", &unknown, "scored")
+	recordVerification(false, "low", nil, "cached")
+
+	if got := testutil.ToFloat64(other) - otherBefore; got != 1 {
+		t.Errorf("other fraud type counted %v times, want 1", got)
+	}
+	if got := testutil.ToFloat64(legit) - legitBefore; got != 1 {
+		t.Errorf("legitimate verdict counted %v times, want 1", got)
+	}
+
+	families, err := prometheus.DefaultGatherer.Gather()
+	if err != nil {
+		t.Fatalf("failed to gather metrics: %v", err)
+	}
+	for _, family := range families {
+		for _, m := range family.GetMetric() {
+			for _, label := range m.GetLabel() {
+				if label.GetValue() == unknown {
+					t.Errorf("%s has a series labelled %s=%q", family.GetName(), label.GetName(), unknown)
+				}
+			}
+		}
+	}
+}
+
+func TestObserveStages(t *testing.T) {
+	before := testutil.CollectAndCount(metrics.VerificationStageDuration)
+	observeStages(map[string]models.StageTiming{
+		"test_stage_ok":      {DurationMs: 12, Status: models.StageStatusOK},
+		"test_stage_timeout": {DurationMs: 50, Status: models.StageStatusTimeout},
+	})
+	if got := testutil.CollectAndCount(metrics.VerificationStageDuration) - before; got != 2 {
+		t.Errorf("observeStages added %d series, want 2", got)
+	}
+}
@@ -0,0 +1,94 @@
+package tracing
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/segmentio/kafka-go"
+	"go.opentelemetry.io/otel/propagation"
+	"google.golang.org/grpc/metadata"
+)
+
+// propagator writes W3C traceparent and baggage headers. It is used directly
+// rather than through the global, so context is passed on even when Init was
+// not called.
+var propagator = propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{})
+
+// Inject returns the trace context of ctx as a map, for payloads that are
+// delivered later, such as outbox entries. It returns nil if there is none.
+func Inject(ctx context.Context) map[string]string {
+	carrier := propagation.MapCarrier{}
+	propagator.Inject(ctx, carrier)
+	if len(carrier) == 0 {
+		return nil
+	}
+	return carrier
+}
+
+// Extract returns ctx carrying the trace context returned by Inject
+func Extract(ctx context.Context, carrier map[string]string) context.Context {
+	if len(carrier) == 0 {
+		return ctx
+	}
+	return propagator.Extract(ctx, propagation.MapCarrier(carrier))
+}
+
+// InjectHTTP adds the trace context of ctx to outgoing request headers
+func InjectHTTP(ctx context.Context, header http.Header) {
+	propagator.Inject(ctx, propagation.HeaderCarrier(header))
+}
+
+// ExtractHTTP returns ctx carrying the trace context of incoming request headers
+func ExtractHTTP(ctx context.Context, header http.Header) context.Context {
+	return propagator.Extract(ctx, propagation.HeaderCarrier(header))
+}
+
+// InjectGRPC returns ctx with its trace context added to outgoing gRPC metadata
+func InjectGRPC(ctx context.Context) context.Context {
+	for key, value := range Inject(ctx) {
+		ctx = metadata.AppendToOutgoingContext(ctx, key, value)
+	}
+	return ctx
+}
+
+// InjectKafka adds the trace context of ctx to the headers of msg
+func InjectKafka(ctx context.Context, msg *kafka.Message) {
+	propagator.Inject(ctx, kafkaCarrier{msg})
+}
+
+// ExtractKafka returns ctx carrying the trace context in the headers of msg
+func ExtractKafka(ctx context.Context, msg *kafka.Message) context.Context {
+	return propagator.Extract(ctx, kafkaCarrier{msg})
+}
+
+// kafkaCarrier adapts Kafka message headers to propagation.TextMapCarrier
+type kafkaCarrier struct {
+	msg *kafka.Message
+}
+
+func (c kafkaCarrier) Get(key string) string {
+	for _, h := range c.msg.Headers {
+		if h.Key == key {
+			return string(h.Value)
+		}
+	}
+	return ""
+}
+
+func (c kafkaCarrier) Set(key, value string) {
+	for i, h := range c.msg.Headers {
+		if h.Key == key {
+			c.msg.Headers[i].Value = []byte(value)
+			return
+		}
+	}
+	c.msg.Headers = append(c.msg.Headers, kafka.Header{Key: key, Value: []byte(value)})
+}
+
+func (c kafkaCarrier) Keys() []string {
+	keys := make([]string, len(c.msg.Headers))
+	for i, h := range c.msg.Headers {
+		keys[i] = h.Key
+	}
+	return keys
+}
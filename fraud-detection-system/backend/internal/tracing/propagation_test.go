@@ -0,0 +1,49 @@
+package tracing
+
+import (
+	"context"
+	"testing"
+
+	"github.com/segmentio/kafka-go"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func spanContext() trace.SpanContext {
+	traceID, _ := trace.TraceIDFromHex("4bf92f3577b34da6a3ce929d0e0e4736")
+	spanID, _ := trace.SpanIDFromHex("00f067aa0ba902b7")
+	return trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    traceID,
+		SpanID:     spanID,
+		TraceFlags: trace.FlagsSampled,
+		Remote:     true,
+	})
+}
+
+func TestKafkaRoundTrip(t *testing.T) {
+	sc := spanContext()
+	ctx := trace.ContextWithSpanContext(context.Background(), sc)
+
+	msg := &kafka.Message{Headers: []kafka.Header{{Key: "traceparent", Value: []byte("stale")}}}
+	InjectKafka(ctx, msg)
+	if len(msg.Headers) != 1 {
+		t.Errorf("expected the traceparent header to be replaced, got %v", msg.Headers)
+	}
+
+	got := trace.SpanContextFromContext(ExtractKafka(context.Background(), msg))
+	if got.TraceID() != sc.TraceID() || got.SpanID() != sc.SpanID() {
+		t.Errorf("got %v, want %v", got, sc)
+	}
+}
+
+func TestMapRoundTrip(t *testing.T) {
+	if carrier := Inject(context.Background()); carrier != nil {
+		t.Errorf("expected no carrier without a span, got %v", carrier)
+	}
+
+	sc := spanContext()
+	carrier := Inject(trace.ContextWithSpanContext(context.Background(), sc))
+	got := trace.SpanContextFromContext(Extract(context.Background(), carrier))
+	if got.TraceID() != sc.TraceID() {
+		t.Errorf("got trace %s, want %s", got.TraceID(), sc.TraceID())
+	}
+}
@@ -22,20 +22,33 @@ var (
 	ErrCacheError       = errors.New("cache error")
 	ErrMLServiceError   = errors.New("ML service error")
 	ErrKafkaError       = errors.New("kafka error")
+	ErrWeakPassword     = errors.New("weak password")
+	ErrInvalidResetToken = errors.New("invalid or expired reset token")
+	ErrInvalidMFACode   = errors.New("invalid two-factor code")
+	ErrMFAAlreadyEnabled = errors.New("two-factor authentication already enabled")
+	ErrMFANotEnabled    = errors.New("two-factor authentication not enabled")
+	ErrTooManyAttempts  = errors.New("too many attempts")
+	ErrInvalidCursor    = errors.New("invalid cursor")
+	ErrInvalidSort      = errors.New("invalid sort")
+	ErrAlreadyExists    = errors.New("already exists")
+	ErrAccountInactive  = errors.New("account inactive")
 )
 
 type ErrorResponse struct {
-	Error   string `json:"error"`
-	Message string `json:"message"`
-	Code    int    `json:"code"`
+	Error     string `json:"error"`
+	Message   string `json:"message"`
+	Code      int    `json:"code"`
+	RequestID string `json:"request_id,omitempty"`
 }
 
-// RespondWithError sends an error response
+// RespondWithError sends an error response, with the request ID so users can
+// quote it when reporting a problem
 func RespondWithError(c *gin.Context, statusCode int, err error, message string) {
 	c.JSON(statusCode, ErrorResponse{
-		Error:   err.Error(),
-		Message: message,
-		Code:    statusCode,
+		Error:     err.Error(),
+		Message:   message,
+		Code:      statusCode,
+		RequestID: c.GetString("request_id"),
 	})
 }
 
@@ -50,16 +63,22 @@ func RespondWithSuccess(c *gin.Context, statusCode int, data interface{}) {
 // HandleError handles different types of errors
 func HandleError(c *gin.Context, err error) {
 	switch err {
-	case ErrUnauthorized, ErrInvalidToken, ErrExpiredToken:
+	case ErrUnauthorized, ErrInvalidToken, ErrExpiredToken, ErrInvalidMFACode:
 		RespondWithError(c, http.StatusUnauthorized, err, "Authentication failed")
 	case ErrForbidden:
 		RespondWithError(c, http.StatusForbidden, err, "Access denied")
+	case ErrAccountInactive:
+		RespondWithError(c, http.StatusForbidden, err, "This account has been deactivated")
 	case ErrNotFound, ErrUserNotFound:
 		RespondWithError(c, http.StatusNotFound, err, "Resource not found")
-	case ErrBadRequest, ErrInvalidCredentials:
+	case ErrBadRequest, ErrInvalidCredentials, ErrInvalidResetToken, ErrMFANotEnabled, ErrInvalidCursor, ErrInvalidSort:
 		RespondWithError(c, http.StatusBadRequest, err, "Invalid request")
-	case ErrUserExists:
+	case ErrWeakPassword:
+		RespondWithError(c, http.StatusBadRequest, err, "Password must be at least 8 characters and contain uppercase, lowercase, number, and special character")
+	case ErrUserExists, ErrMFAAlreadyEnabled, ErrAlreadyExists:
 		RespondWithError(c, http.StatusConflict, err, "Resource already exists")
+	case ErrTooManyAttempts:
+		RespondWithError(c, http.StatusTooManyRequests, err, "Too many attempts, please try again later")
 	case ErrDatabaseError, ErrCacheError, ErrMLServiceError, ErrKafkaError:
 		RespondWithError(c, http.StatusInternalServerError, err, "Service unavailable")
 	default:
@@ -1,24 +1,39 @@
 package utils
 
 import (
+	"context"
 	"errors"
+	"fmt"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
+	"github.com/fraud-detection-system/backend/internal/cache"
 )
 
+// Token timestamps keep microseconds, so a session revoked in the same second
+// as a token was issued is still told apart from a login right after it
+func init() {
+	jwt.TimePrecision = time.Microsecond
+}
+
 type Claims struct {
 	UserID uuid.UUID `json:"user_id"`
 	Email  string    `json:"email"`
+	Role   string    `json:"role,omitempty"`
+	Tenant string    `json:"tenant_id,omitempty"`
+	MFA    bool      `json:"mfa,omitempty"` // second factor was verified for this session
 	jwt.RegisteredClaims
 }
 
 // GenerateToken generates a JWT token for a user
-func GenerateToken(userID uuid.UUID, email string, secret string, expiry time.Duration) (string, error) {
+func GenerateToken(userID uuid.UUID, email, role, tenantID string, mfa bool, secret string, expiry time.Duration) (string, error) {
 	claims := Claims{
 		UserID: userID,
 		Email:  email,
+		Role:   role,
+		Tenant: tenantID,
+		MFA:    mfa,
 		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(time.Now().Add(expiry)),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
@@ -51,10 +66,13 @@ func ValidateToken(tokenString string, secret string) (*Claims, error) {
 }
 
 // GenerateRefreshToken generates a refresh token
-func GenerateRefreshToken(userID uuid.UUID, email string, secret string, expiry time.Duration) (string, error) {
+func GenerateRefreshToken(userID uuid.UUID, email, role, tenantID string, mfa bool, secret string, expiry time.Duration) (string, error) {
 	claims := Claims{
 		UserID: userID,
 		Email:  email,
+		Role:   role,
+		Tenant: tenantID,
+		MFA:    mfa,
 		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(time.Now().Add(expiry)),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
@@ -86,3 +104,62 @@ func ValidateRefreshToken(tokenString string, secret string) (*Claims, error) {
 	return nil, errors.New("invalid refresh token")
 }
 
+// GenerateChallengeToken generates a short-lived token proving the password
+// step of login succeeded, to be exchanged once the second factor is verified
+func GenerateChallengeToken(userID uuid.UUID, email string, secret string, expiry time.Duration) (string, error) {
+	claims := Claims{
+		UserID: userID,
+		Email:  email,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(expiry)),
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			NotBefore: jwt.NewNumericDate(time.Now()),
+		},
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	return token.SignedString([]byte(secret + "-mfa"))
+}
+
+// ValidateChallengeToken validates a login challenge token
+func ValidateChallengeToken(tokenString string, secret string) (*Claims, error) {
+	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, errors.New("invalid signing method")
+		}
+		return []byte(secret + "-mfa"), nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	if claims, ok := token.Claims.(*Claims); ok && token.Valid {
+		return claims, nil
+	}
+
+	return nil, errors.New("invalid challenge token")
+}
+
+// TokenRevocationKey returns the cache key holding the time, in microseconds
+// since the epoch, up to which a user's tokens are no longer accepted
+func TokenRevocationKey(userID uuid.UUID) string {
+	return fmt.Sprintf("auth:revoked_before:%s", userID)
+}
+
+// RevokeSessions rejects every token issued to the user so far. The
+// revocation is kept for ttl, which must cover the longest token lifetime.
+func RevokeSessions(ctx context.Context, store cache.Cache, userID uuid.UUID, ttl time.Duration) error {
+	return store.Set(ctx, TokenRevocationKey(userID), time.Now().UnixMicro(), ttl)
+}
+
+// SessionRevoked reports whether a token issued at issuedAt was revoked by a
+// later call to RevokeSessions. Without a recorded revocation (or a
+// reachable cache) the token is accepted.
+func SessionRevoked(ctx context.Context, store cache.Cache, userID uuid.UUID, issuedAt time.Time) bool {
+	var revokedBefore int64
+	if err := store.Get(ctx, TokenRevocationKey(userID), &revokedBefore); err != nil {
+		return false
+	}
+	return issuedAt.IsZero() || issuedAt.UnixMicro() <= revokedBefore
+}
@@ -0,0 +1,40 @@
+package utils
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/fraud-detection-system/backend/internal/cache"
+)
+
+func TestSessionRevokedSameSecond(t *testing.T) {
+	ctx := context.Background()
+	store := cache.NewMemoryCache(time.Minute)
+	userID := uuid.New()
+
+	token, err := GenerateToken(userID, "user@example.com", "user", "", false, "secret", time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	claims, err := ValidateToken(token, "secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	issuedAt := claims.IssuedAt.Time
+	if SessionRevoked(ctx, store, userID, issuedAt) {
+		t.Fatal("expected the session to be valid before revocation")
+	}
+
+	// Revoking right away lands in the same second as the token was issued
+	if err := RevokeSessions(ctx, store, userID, time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	if !SessionRevoked(ctx, store, userID, issuedAt) {
+		t.Error("expected a token issued in the same second to be revoked")
+	}
+	if SessionRevoked(ctx, store, userID, time.Now().Add(time.Millisecond)) {
+		t.Error("expected a token issued after revocation to be accepted")
+	}
+}
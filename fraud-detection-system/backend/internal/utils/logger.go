@@ -1,9 +1,11 @@
 package utils
 
 import (
+	"context"
 	"os"
 
 	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/trace"
 )
 
 var Log *logrus.Logger
@@ -12,6 +14,8 @@ func InitLogger(level string) {
 	Log = logrus.New()
 	Log.SetFormatter(&logrus.JSONFormatter{})
 	Log.SetOutput(os.Stdout)
+	Log.AddHook(contextHook{})
+	Log.AddHook(redactHook{})
 
 	logLevel, err := logrus.ParseLevel(level)
 	if err != nil {
@@ -27,3 +31,45 @@ func GetLogger() *logrus.Logger {
 	return Log
 }
 
+// GetLoggerWithContext returns a logger whose entries carry the request ID
+// and trace ID of ctx
+func GetLoggerWithContext(ctx context.Context) *logrus.Entry {
+	return GetLogger().WithContext(ctx)
+}
+
+type requestIDKey struct{}
+
+// ContextWithRequestID returns ctx carrying requestID
+func ContextWithRequestID(ctx context.Context, requestID string) context.Context {
+	if requestID == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, requestIDKey{}, requestID)
+}
+
+// RequestIDFromContext returns the request ID of ctx, or "" if it has none
+func RequestIDFromContext(ctx context.Context) string {
+	requestID, _ := ctx.Value(requestIDKey{}).(string)
+	return requestID
+}
+
+// contextHook adds the request ID and trace ID of an entry's context to its
+// fields, so log lines can be matched to requests and traces
+type contextHook struct{}
+
+func (contextHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+func (contextHook) Fire(entry *logrus.Entry) error {
+	if entry.Context == nil {
+		return nil
+	}
+	if requestID := RequestIDFromContext(entry.Context); requestID != "" {
+		entry.Data["request_id"] = requestID
+	}
+	if span := trace.SpanContextFromContext(entry.Context); span.IsValid() {
+		entry.Data["trace_id"] = span.TraceID().String()
+	}
+	return nil
+}
@@ -0,0 +1,31 @@
+package utils
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+)
+
+func TestLoggerAddsRequestID(t *testing.T) {
+	InitLogger("info")
+	var buf bytes.Buffer
+	Log.SetOutput(&buf)
+
+	ctx := ContextWithRequestID(context.Background(), "req-123")
+	GetLoggerWithContext(ctx).Info("hello")
+
+	var entry map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("invalid log line %q: %v", buf.String(), err)
+	}
+	if entry["request_id"] != "req-123" {
+		t.Errorf("got request_id %v, want req-123", entry["request_id"])
+	}
+
+	buf.Reset()
+	GetLoggerWithContext(context.Background()).Info("hello")
+	if bytes.Contains(buf.Bytes(), []byte("request_id")) {
+		t.Errorf("expected no request_id without one in the context, got %s", buf.String())
+	}
+}
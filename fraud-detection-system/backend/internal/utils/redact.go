@@ -0,0 +1,295 @@
+package utils
+
+import (
+	"regexp"
+	"strings"
+	"sync/atomic"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Redaction modes
+const (
+	RedactionOff    = "off"    // log as is; not allowed in production
+	RedactionMask   = "mask"   // keep the last four characters, e.g. ******3210
+	RedactionRemove = "remove" // replace with the kind of data, e.g. [PHONE]
+)
+
+// defaultSafeFields never hold PII, so their values are not scanned. IDs are
+// listed because long digit runs in them look like account numbers.
+var defaultSafeFields = []string{
+	"request_id", "trace_id", "message_id", "verification_id", "report_id",
+	"user_id", "tenant_id", "circular_id", "outbox_id", "active_key_id",
+	"model", "model_version", "topic", "kind", "status", "method", "route",
+	"path", "version",
+}
+
+// piiPattern finds one kind of PII. valid, when set, filters out matches
+// that only look like it. standalone patterns skip matches joined to the
+// rest of a hyphenated or dotted token, such as the digits of a UUID or of
+// fractional seconds.
+type piiPattern struct {
+	kind       string
+	re         *regexp.Regexp
+	valid      func(match string) bool
+	standalone bool
+}
+
+// phonePattern finds Indian mobile numbers, with or without a country code
+var phonePattern = regexp.MustCompile(`(?:\+\d{1,3}[ -]?)?\b[6-9]\d{9}\b`)
+
+// piiPatterns are applied in order, most specific first, so a card number is
+// not also taken for an account number
+var piiPatterns = []piiPattern{
+	{kind: "EMAIL", re: regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`)},
+	{kind: "PAN", re: regexp.MustCompile(`\b[A-Z]{5}[0-9]{4}[A-Z]\b`)},
+	{kind: "CARD", re: regexp.MustCompile(`\b(?:\d[ -]?){12,18}\d\b`), valid: luhnValid, standalone: true},
+	{kind: "AADHAAR", re: regexp.MustCompile(`\b[2-9]\d{3}[ -]?\d{4}[ -]?\d{4}\b`), standalone: true},
+	{kind: "PHONE", re: phonePattern},
+	{kind: "ACCOUNT", re: regexp.MustCompile(`\b\d{9,18}\b`), standalone: true},
+}
+
+// Redactor hides phone numbers, emails, card and account numbers, Aadhaar
+// and PAN numbers in log lines and queue payloads
+type Redactor struct {
+	mode string
+	safe map[string]bool
+}
+
+// NewRedactor creates a redactor for mode. safeFields are logged as is, in
+// addition to the default safe fields.
+func NewRedactor(mode string, safeFields []string) *Redactor {
+	safe := make(map[string]bool, len(defaultSafeFields)+len(safeFields))
+	for _, field := range defaultSafeFields {
+		safe[field] = true
+	}
+	for _, field := range safeFields {
+		safe[field] = true
+	}
+	return &Redactor{mode: mode, safe: safe}
+}
+
+// String redacts the PII in s
+func (r *Redactor) String(s string) string {
+	if r.mode == RedactionOff || s == "" {
+		return s
+	}
+	for _, p := range piiPatterns {
+		s = r.replace(p, s)
+	}
+	return s
+}
+
+// replace hides the matches of p in s
+func (r *Redactor) replace(p piiPattern, s string) string {
+	matches := p.re.FindAllStringIndex(s, -1)
+	if matches == nil {
+		return s
+	}
+	var b strings.Builder
+	last := 0
+	for _, m := range matches {
+		match := s[m[0]:m[1]]
+		if p.valid != nil && !p.valid(match) || p.standalone && joined(s, m[0], m[1]) {
+			continue
+		}
+		b.WriteString(s[last:m[0]])
+		if r.mode == RedactionRemove {
+			b.WriteString("[" + p.kind + "]")
+		} else {
+			b.WriteString(maskMatch(p.kind, match))
+		}
+		last = m[1]
+	}
+	b.WriteString(s[last:])
+	return b.String()
+}
+
+// joined reports whether s[start:end] is part of a longer token: it follows
+// a hyphen or dot, or is followed by one and more of the token. A sentence
+// ending just after the match does not count.
+func joined(s string, start, end int) bool {
+	if start > 0 && (s[start-1] == '-' || s[start-1] == '.') {
+		return true
+	}
+	return end+1 < len(s) && (s[end] == '-' || s[end] == '.') && isAlnum(s[end+1])
+}
+
+func isAlnum(c byte) bool {
+	return c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
+
+// Field redacts the value of a log field or payload key. Strings and errors
+// are scanned, maps and slices redacted recursively, and other values kept.
+func (r *Redactor) Field(key string, value interface{}) interface{} {
+	if r.mode == RedactionOff || r.safe[key] {
+		return value
+	}
+	switch v := value.(type) {
+	case string:
+		return r.String(v)
+	case *string:
+		if v == nil {
+			return v
+		}
+		redacted := r.String(*v)
+		return &redacted
+	case error:
+		return r.String(v.Error())
+	case []string:
+		redacted := make([]string, len(v))
+		for i, s := range v {
+			redacted[i] = r.String(s)
+		}
+		return redacted
+	case []interface{}:
+		redacted := make([]interface{}, len(v))
+		for i, item := range v {
+			redacted[i] = r.Field(key, item)
+		}
+		return redacted
+	case map[string]interface{}:
+		return r.Fields(v)
+	default:
+		return value
+	}
+}
+
+// Fields returns a copy of fields with each value redacted
+func (r *Redactor) Fields(fields map[string]interface{}) map[string]interface{} {
+	if fields == nil {
+		return nil
+	}
+	redacted := make(map[string]interface{}, len(fields))
+	for key, value := range fields {
+		redacted[key] = r.Field(key, value)
+	}
+	return redacted
+}
+
+// maskMatch keeps the last four characters of a match, or the first two and
+// the domain of an email
+func maskMatch(kind, match string) string {
+	if kind == "EMAIL" {
+		at := strings.LastIndex(match, "@")
+		local := match[:at]
+		if len(local) <= 2 {
+			return strings.Repeat("*", len(local)) + match[at:]
+		}
+		return local[:2] + strings.Repeat("*", len(local)-2) + match[at:]
+	}
+
+	masked := []byte(match)
+	kept := 0
+	for i := len(masked) - 1; i >= 0; i-- {
+		if masked[i] == ' ' || masked[i] == '-' || masked[i] == '+' {
+			continue
+		}
+		if kept < 4 {
+			kept++
+			continue
+		}
+		masked[i] = '*'
+	}
+	return string(masked)
+}
+
+// FindPhoneNumbers returns the phone numbers in text that a redactor hides,
+// normalized with NormalizePhoneNumber
+func FindPhoneNumbers(text string) []string {
+	var phones []string
+	for _, match := range phonePattern.FindAllString(text, -1) {
+		if phone := NormalizePhoneNumber(match); phone != "" {
+			phones = append(phones, phone)
+		}
+	}
+	return phones
+}
+
+// NormalizePhoneNumber reduces a phone number to + and its digits with the
+// country code, so +91 98765 43210, 098765-43210 and 9876543210 are the same
+// number. Ten digit numbers are taken to be Indian. It returns "" if s does
+// not look like a phone number.
+func NormalizePhoneNumber(s string) string {
+	digits := strings.Map(func(r rune) rune {
+		if r >= '0' && r <= '9' {
+			return r
+		}
+		return -1
+	}, s)
+	if len(digits) == 11 && digits[0] == '0' {
+		digits = digits[1:]
+	}
+	if len(digits) == 10 {
+		digits = "91" + digits
+	}
+	if len(digits) < 11 || len(digits) > 15 {
+		return ""
+	}
+	return "+" + digits
+}
+
+// luhnValid reports whether the digits of s pass the Luhn check used by
+// card numbers
+func luhnValid(s string) bool {
+	var sum, n int
+	for i := len(s) - 1; i >= 0; i-- {
+		c := s[i]
+		if c < '0' || c > '9' {
+			continue
+		}
+		d := int(c - '0')
+		if n%2 == 1 {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		n++
+	}
+	return n >= 13 && sum%10 == 0
+}
+
+var redactor atomic.Pointer[Redactor]
+
+func init() {
+	redactor.Store(NewRedactor(RedactionMask, nil))
+}
+
+// SetRedactor sets the redactor used by the logger and by RedactFields
+func SetRedactor(r *Redactor) {
+	redactor.Store(r)
+}
+
+// Redact redacts the PII in s with the configured redactor
+func Redact(s string) string {
+	return redactor.Load().String(s)
+}
+
+// RedactField redacts one field value with the configured redactor
+func RedactField(key string, value interface{}) interface{} {
+	return redactor.Load().Field(key, value)
+}
+
+// RedactFields returns a copy of fields redacted with the configured
+// redactor, for queue payloads that leave the service
+func RedactFields(fields map[string]interface{}) map[string]interface{} {
+	return redactor.Load().Fields(fields)
+}
+
+// redactHook redacts the message and fields of every log entry
+type redactHook struct{}
+
+func (redactHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+func (redactHook) Fire(entry *logrus.Entry) error {
+	r := redactor.Load()
+	entry.Message = r.String(entry.Message)
+	for key, value := range entry.Data {
+		entry.Data[key] = r.Field(key, value)
+	}
+	return nil
+}
@@ -0,0 +1,91 @@
+package utils
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestRedactorString(t *testing.T) {
+	tests := []struct {
+		name   string
+		mode   string
+		input  string
+		output string
+	}{
+		{"phone", RedactionRemove, "call +91 9876543210 now", "call [PHONE] now"},
+		{"phone masked", RedactionMask, "call 9876543210 now", "call ******3210 now"},
+		{"email", RedactionRemove, "mail rahul.k@example.com", "mail [EMAIL]"},
+		{"email masked", RedactionMask, "mail rahul.k@example.com", "mail ra*****@example.com"},
+		{"card", RedactionRemove, "card 4111 1111 1111 1111 charged", "card [CARD] charged"},
+		{"aadhaar", RedactionRemove, "aadhaar 2345 6789 0123", "aadhaar [AADHAAR]"},
+		{"pan", RedactionMask, "PAN ABCDE1234F", "PAN ******234F"},
+		{"account", RedactionRemove, "a/c 123456789012345 debited", "a/c [ACCOUNT] debited"},
+		{"account at sentence end", RedactionRemove, "a/c 123456789012. Call us", "a/c [ACCOUNT]. Call us"},
+		{"uuid kept", RedactionMask, "id 123e4567-e89b-12d3-a456-426614174000", "id 123e4567-e89b-12d3-a456-426614174000"},
+		{"fractional seconds kept", RedactionMask, "at 2026-01-02T03:04:05.123456789+05:30", "at 2026-01-02T03:04:05.123456789+05:30"},
+		{"dotted version kept", RedactionMask, "build 1.123456789.2", "build 1.123456789.2"},
+		{"hyphenated aadhaar", RedactionRemove, "aadhaar 2345-6789-0123.", "aadhaar [AADHAAR]."},
+		{"short numbers kept", RedactionRemove, "Rs 5000 debited, OTP 123456", "Rs 5000 debited, OTP 123456"},
+		{"off", RedactionOff, "call 9876543210", "call 9876543210"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NewRedactor(tt.mode, nil).String(tt.input); got != tt.output {
+				t.Errorf("got %q, want %q", got, tt.output)
+			}
+		})
+	}
+}
+
+func TestRedactorFields(t *testing.T) {
+	r := NewRedactor(RedactionRemove, []string{"sender_header"})
+	fields := map[string]interface{}{
+		"content":       "Your a/c 123456789012 is blocked, call 9876543210",
+		"message_id":    "123456789012",
+		"sender_header": "9876543210",
+		"fraud_score":   0.9,
+		"error":         errors.New("no user rahul@example.com"),
+		"nested":        map[string]interface{}{"phone": "9876543210"},
+	}
+
+	got := r.Fields(fields)
+	if got["content"] != "Your a/c [ACCOUNT] is blocked, call [PHONE]" {
+		t.Errorf("content not redacted: %v", got["content"])
+	}
+	if got["message_id"] != "123456789012" || got["sender_header"] != "9876543210" {
+		t.Errorf("safe fields changed: %v, %v", got["message_id"], got["sender_header"])
+	}
+	if got["fraud_score"] != 0.9 {
+		t.Errorf("non-string value changed: %v", got["fraud_score"])
+	}
+	if got["error"] != "no user [EMAIL]" {
+		t.Errorf("error not redacted: %v", got["error"])
+	}
+	if nested := got["nested"].(map[string]interface{}); nested["phone"] != "[PHONE]" {
+		t.Errorf("nested value not redacted: %v", nested["phone"])
+	}
+	if fields["content"] == got["content"] {
+		t.Error("expected a copy, input was modified")
+	}
+}
+
+func TestFindPhoneNumbers(t *testing.T) {
+	got := FindPhoneNumbers("call +91 9876543210 or 8765432109, OTP 123456, a/c 123456789012345")
+	if len(got) != 2 || got[0] != "+919876543210" || got[1] != "+918765432109" {
+		t.Errorf("got %v", got)
+	}
+
+	for input, want := range map[string]string{
+		"9876543210":       "+919876543210",
+		"+91 98765 43210":  "+919876543210",
+		"098765-43210":     "+919876543210",
+		"+1 415 555 0100":  "+14155550100",
+		"12345":            "",
+		"not a phone":      "",
+		"1234567890123456": "",
+	} {
+		if got := NormalizePhoneNumber(input); got != want {
+			t.Errorf("NormalizePhoneNumber(%q) = %q, want %q", input, got, want)
+		}
+	}
+}
@@ -0,0 +1,296 @@
+package utils
+
+import (
+	"errors"
+	"html"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// Highlight markers wrapped around matches in search fragments
+const (
+	HighlightStart = "<mark>"
+	HighlightEnd   = "</mark>"
+)
+
+// fragmentContext is how many words around a match a fragment keeps
+const fragmentContext = 8
+
+// ErrSearchOrExclusion is returned for queries that join an excluded word
+// with OR, which SearchTerms cannot express
+var ErrSearchOrExclusion = errors.New("OR cannot be used next to an excluded -word")
+
+// SearchTerms is a parsed web search style query, as understood by
+// Postgres' websearch_to_tsquery: bare words and "quoted phrases" must all
+// appear, in any order, except that ones joined by OR are alternatives, and
+// -words must not appear
+type SearchTerms struct {
+	Include [][][]string // groups of alternative phrases of one or more lowercased words
+	Exclude []string
+}
+
+// ParseSearchQuery parses a search query. Words are runs of letters and
+// digits, so "kyc-update" is the phrase kyc update. OR, in any case, makes
+// the words or phrases on either side alternatives; at the start or end of
+// the query it is ignored.
+func ParseSearchQuery(query string) (SearchTerms, error) {
+	var terms SearchTerms
+	// or is set after an OR that follows an included word or phrase, excluded
+	// after an excluded word
+	var or, excluded bool
+	include := func(words []string) {
+		if or {
+			last := len(terms.Include) - 1
+			terms.Include[last] = append(terms.Include[last], words)
+		} else {
+			terms.Include = append(terms.Include, [][]string{words})
+		}
+		or, excluded = false, false
+	}
+	for query != "" {
+		query = strings.TrimLeftFunc(query, unicode.IsSpace)
+		switch {
+		case query == "":
+		case query[0] == '"':
+			end := strings.IndexByte(query[1:], '"')
+			phrase := query[1:]
+			query = ""
+			if end >= 0 {
+				phrase, query = phrase[:end], phrase[end+1:]
+			}
+			if words := searchWords(phrase); len(words) > 0 {
+				include(words)
+			}
+		default:
+			end := strings.IndexFunc(query, unicode.IsSpace)
+			if end < 0 {
+				end = len(query)
+			}
+			word := query[:end]
+			query = query[end:]
+			if strings.EqualFold(word, "or") {
+				if excluded {
+					return terms, ErrSearchOrExclusion
+				}
+				or = len(terms.Include) > 0
+				continue
+			}
+			if strings.HasPrefix(word, "-") {
+				words := searchWords(word[1:])
+				if len(words) == 0 {
+					continue
+				}
+				if or {
+					return terms, ErrSearchOrExclusion
+				}
+				terms.Exclude = append(terms.Exclude, words...)
+				excluded = true
+				continue
+			}
+			if words := searchWords(word); len(words) > 0 {
+				include(words)
+			}
+		}
+	}
+	return terms, nil
+}
+
+// Empty reports whether the query has nothing to look for
+func (t SearchTerms) Empty() bool {
+	return len(t.Include) == 0
+}
+
+// Matches reports whether text contains a word or phrase of every included
+// group and none of the excluded words
+func (t SearchTerms) Matches(text string) bool {
+	if t.Empty() {
+		return false
+	}
+	words := tokenize(text)
+	for _, group := range t.Include {
+		found := false
+		for _, phrase := range group {
+			found = found || len(findPhrase(words, phrase)) > 0
+		}
+		if !found {
+			return false
+		}
+	}
+	for _, excluded := range t.Exclude {
+		if len(findPhrase(words, []string{excluded})) > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// Highlight returns up to max fragments of text around matches of the
+// included words and phrases, HTML escaped, with each match wrapped in
+// HighlightStart and HighlightEnd. It returns nil if nothing matches.
+func (t SearchTerms) Highlight(text string, max int) []string {
+	words := tokenize(text)
+	var spans [][2]int // word index ranges [start, end)
+	for _, group := range t.Include {
+		for _, phrase := range group {
+			for _, start := range findPhrase(words, phrase) {
+				spans = append(spans, [2]int{start, start + len(phrase)})
+			}
+		}
+	}
+	if len(spans) == 0 {
+		return nil
+	}
+	spans = mergeSpans(spans)
+
+	// Group matches close enough to share a fragment
+	var fragments []string
+	for i := 0; i < len(spans) && len(fragments) < max; {
+		j := i
+		for j+1 < len(spans) && spans[j+1][0]-spans[j][1] <= 2*fragmentContext {
+			j++
+		}
+		first := spans[i][0] - fragmentContext
+		if first < 0 {
+			first = 0
+		}
+		last := spans[j][1] + fragmentContext
+		if last > len(words) {
+			last = len(words)
+		}
+		from, to := words[first].start, words[last-1].end
+		if first == 0 {
+			from = 0
+		}
+		if last == len(words) {
+			to = len(text)
+		}
+
+		var b strings.Builder
+		if from > 0 {
+			b.WriteString("… ")
+		}
+		pos := from
+		for _, span := range spans[i : j+1] {
+			start, end := words[span[0]].start, words[span[1]-1].end
+			b.WriteString(html.EscapeString(text[pos:start]))
+			b.WriteString(HighlightStart + html.EscapeString(text[start:end]) + HighlightEnd)
+			pos = end
+		}
+		b.WriteString(html.EscapeString(text[pos:to]))
+		if to < len(text) {
+			b.WriteString(" …")
+		}
+		fragments = append(fragments, strings.TrimSpace(b.String()))
+		i = j + 1
+	}
+	return fragments
+}
+
+// HighlightSubstring returns text, HTML escaped, with every case-insensitive
+// occurrence of needle wrapped in HighlightStart and HighlightEnd, or "" if
+// there is none
+func HighlightSubstring(text, needle string) string {
+	if needle == "" {
+		return ""
+	}
+	lower, lowerNeedle := strings.ToLower(text), strings.ToLower(needle)
+	if len(lower) != len(text) {
+		// Offsets into the folded text would not line up; match exactly
+		lower, lowerNeedle = text, needle
+	}
+	if !strings.Contains(lower, lowerNeedle) {
+		return ""
+	}
+
+	var b strings.Builder
+	pos := 0
+	for {
+		i := strings.Index(lower[pos:], lowerNeedle)
+		if i < 0 {
+			break
+		}
+		start := pos + i
+		end := start + len(lowerNeedle)
+		b.WriteString(html.EscapeString(text[pos:start]))
+		b.WriteString(HighlightStart + html.EscapeString(text[start:end]) + HighlightEnd)
+		pos = end
+	}
+	b.WriteString(html.EscapeString(text[pos:]))
+	return b.String()
+}
+
+// SearchSubstring returns the text a substring search for query looks for:
+// the query with quotes dropped and whitespace collapsed
+func SearchSubstring(query string) string {
+	return strings.Join(strings.Fields(strings.ReplaceAll(query, `"`, " ")), " ")
+}
+
+type word struct {
+	text       string // lowercased
+	start, end int    // byte offsets in the original text
+}
+
+// tokenize splits text into lowercased words of letters and digits
+func tokenize(text string) []word {
+	var words []word
+	start := -1
+	for i, r := range text {
+		isWord := unicode.IsLetter(r) || unicode.IsDigit(r)
+		switch {
+		case isWord && start < 0:
+			start = i
+		case !isWord && start >= 0:
+			words = append(words, word{text: strings.ToLower(text[start:i]), start: start, end: i})
+			start = -1
+		}
+	}
+	if start >= 0 {
+		words = append(words, word{text: strings.ToLower(text[start:]), start: start, end: len(text)})
+	}
+	return words
+}
+
+func searchWords(s string) []string {
+	tokens := tokenize(s)
+	words := make([]string, len(tokens))
+	for i, token := range tokens {
+		words[i] = token.text
+	}
+	return words
+}
+
+// findPhrase returns the indexes of words at which phrase starts
+func findPhrase(words []word, phrase []string) []int {
+	var starts []int
+	for i := 0; i+len(phrase) <= len(words); i++ {
+		matched := true
+		for j, w := range phrase {
+			if words[i+j].text != w {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			starts = append(starts, i)
+		}
+	}
+	return starts
+}
+
+// mergeSpans sorts spans and joins overlapping ones
+func mergeSpans(spans [][2]int) [][2]int {
+	sort.Slice(spans, func(i, j int) bool { return spans[i][0] < spans[j][0] })
+	merged := spans[:1]
+	for _, span := range spans[1:] {
+		last := &merged[len(merged)-1]
+		if span[0] <= last[1] {
+			if span[1] > last[1] {
+				last[1] = span[1]
+			}
+			continue
+		}
+		merged = append(merged, span)
+	}
+	return merged
+}
@@ -0,0 +1,90 @@
+package utils
+
+import (
+	"reflect"
+	"testing"
+)
+
+// mustParse parses query, failing the test if it is invalid
+func mustParse(t *testing.T, query string) SearchTerms {
+	t.Helper()
+	terms, err := ParseSearchQuery(query)
+	if err != nil {
+		t.Fatalf("%s: %v", query, err)
+	}
+	return terms
+}
+
+func TestParseSearchQuery(t *testing.T) {
+	got := mustParse(t, `or kyc "account will be blocked" OR refund -otp pay@okaxis or`)
+	want := SearchTerms{
+		Include: [][][]string{{{"kyc"}}, {{"account", "will", "be", "blocked"}, {"refund"}}, {{"pay", "okaxis"}}},
+		Exclude: []string{"otp"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+	if !mustParse(t, `"" -x`).Empty() {
+		t.Error("expected a query of only exclusions to be empty")
+	}
+	for _, query := range []string{"kyc or -otp", "-otp OR kyc"} {
+		if _, err := ParseSearchQuery(query); err != ErrSearchOrExclusion {
+			t.Errorf("%s: expected ErrSearchOrExclusion, got %v", query, err)
+		}
+	}
+}
+
+func TestSearchTermsMatches(t *testing.T) {
+	text := "Dear customer, your KYC is pending. Your account will be blocked today."
+	tests := []struct {
+		query string
+		want  bool
+	}{
+		{"kyc", true},
+		{"KYC pending", true},
+		{`"account will be blocked"`, true},
+		{`"will account"`, false},
+		{"kyc -blocked", false},
+		{"refund", false},
+		{"refund or kyc", true},
+		{`refund or "will account"`, false},
+		{"refund OR kyc today", true},
+	}
+	for _, tt := range tests {
+		if got := mustParse(t, tt.query).Matches(text); got != tt.want {
+			t.Errorf("%s: got %v, want %v", tt.query, got, tt.want)
+		}
+	}
+}
+
+func TestSearchTermsHighlight(t *testing.T) {
+	terms := mustParse(t, `"kyc update" <b>`)
+	got := terms.Highlight("Your KYC update is due <now>", 3)
+	want := []string{"Your <mark>KYC update</mark> is due &lt;now&gt;"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	long := "one two three four five six seven eight nine ten eleven twelve KYC thirteen fourteen fifteen sixteen seventeen eighteen nineteen twenty"
+	got = mustParse(t, "kyc").Highlight(long, 3)
+	want = []string{"… five six seven eight nine ten eleven twelve <mark>KYC</mark> thirteen fourteen fifteen sixteen seventeen eighteen nineteen twenty"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	if got := mustParse(t, "refund or twelve").Highlight(long, 3); len(got) != 1 {
+		t.Errorf("expected the alternative that matched to be highlighted, got %q", got)
+	}
+	if got := mustParse(t, "refund").Highlight(long, 3); got != nil {
+		t.Errorf("expected no fragments, got %q", got)
+	}
+}
+
+func TestHighlightSubstring(t *testing.T) {
+	if got, want := HighlightSubstring("http://Bit.ly/kyc?a=1&b=2", "bit.ly"), "http://<mark>Bit.ly</mark>/kyc?a=1&amp;b=2"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	if got := HighlightSubstring("VM-HDFCBK", "icici"); got != "" {
+		t.Errorf("expected no match, got %q", got)
+	}
+}
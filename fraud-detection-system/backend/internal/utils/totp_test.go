@@ -0,0 +1,97 @@
+package utils
+
+import (
+	"encoding/base32"
+	"strings"
+	"testing"
+	"time"
+)
+
+// RFC 6238 appendix B secret for HMAC-SHA1
+var rfcSecret = base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString([]byte("12345678901234567890"))
+
+func TestGenerateTOTPCode(t *testing.T) {
+	// Last six digits of the RFC 6238 SHA1 test vectors
+	cases := map[int64]string{
+		59:          "287082",
+		1111111109:  "081804",
+		1111111111:  "050471",
+		1234567890:  "005924",
+		2000000000:  "279037",
+		20000000000: "353130",
+	}
+
+	for ts, want := range cases {
+		got, err := GenerateTOTPCode(rfcSecret, time.Unix(ts, 0))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got != want {
+			t.Errorf("time %d: got %s, want %s", ts, got, want)
+		}
+	}
+}
+
+func TestValidateTOTPCode(t *testing.T) {
+	now := time.Unix(1111111111, 0)
+	code, _ := GenerateTOTPCode(rfcSecret, now)
+
+	t.Run("current step", func(t *testing.T) {
+		step, ok := ValidateTOTPCode(rfcSecret, code, now)
+		if !ok || step != now.Unix()/30 {
+			t.Fatalf("expected code to validate at current step, got step=%d ok=%v", step, ok)
+		}
+	})
+
+	t.Run("within skew", func(t *testing.T) {
+		if _, ok := ValidateTOTPCode(rfcSecret, code, now.Add(30*time.Second)); !ok {
+			t.Fatal("expected code from previous step to validate")
+		}
+	})
+
+	t.Run("outside skew", func(t *testing.T) {
+		if _, ok := ValidateTOTPCode(rfcSecret, code, now.Add(2*time.Minute)); ok {
+			t.Fatal("expected stale code to be rejected")
+		}
+	})
+
+	t.Run("malformed code", func(t *testing.T) {
+		if _, ok := ValidateTOTPCode(rfcSecret, "12345", now); ok {
+			t.Fatal("expected short code to be rejected")
+		}
+	})
+}
+
+func TestGenerateRecoveryCodes(t *testing.T) {
+	codes, err := GenerateRecoveryCodes(10)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(codes) != 10 {
+		t.Fatalf("expected 10 codes, got %d", len(codes))
+	}
+
+	seen := map[string]bool{}
+	for _, code := range codes {
+		if len(code) != 11 || code[5] != '-' {
+			t.Errorf("unexpected code format %q", code)
+		}
+		if seen[code] {
+			t.Errorf("duplicate code %q", code)
+		}
+		seen[code] = true
+		if NormalizeRecoveryCode(strings.ToUpper(code)) != strings.Replace(code, "-", "", 1) {
+			t.Errorf("normalization failed for %q", code)
+		}
+	}
+}
+
+func TestTOTPURI(t *testing.T) {
+	uri := TOTPURI("FraudShield", "user@example.com", "ABC")
+	if !strings.HasPrefix(uri, "otpauth://totp/FraudShield:user@example.com?") {
+		t.Fatalf("unexpected URI %s", uri)
+	}
+	if !strings.Contains(uri, "secret=ABC") || !strings.Contains(uri, "issuer=FraudShield") {
+		t.Fatalf("URI missing parameters: %s", uri)
+	}
+}
@@ -10,6 +10,8 @@ var (
 	emailRegex = regexp.MustCompile(`^[a-zA-Z0-9._%+-]+@[a-zA-Z0-9.-]+\.[a-zA-Z]{2,}$`)
 	phoneRegex = regexp.MustCompile(`\+?[0-9]{10,15}`)
 	urlRegex   = regexp.MustCompile(`https?://[^\s]+`)
+
+	maskRedactor = NewRedactor(RedactionMask, nil)
 )
 
 // ValidateEmail checks if an email is valid
@@ -114,21 +116,44 @@ func HasKYCKeywords(text string) bool {
 	return false
 }
 
+// banks maps the names fraudsters use for banks to the bank they mean
+var banks = []struct {
+	name     string
+	keywords []string
+}{
+	{"HDFC", []string{"hdfc"}},
+	{"ICICI", []string{"icici"}},
+	{"SBI", []string{"sbi"}},
+	{"Axis", []string{"axis"}},
+	{"Kotak", []string{"kotak"}},
+	{"Yes Bank", []string{"yes bank"}},
+	{"PNB", []string{"pnb"}},
+	{"Bank of Baroda", []string{"bank of baroda"}},
+	{"Canara", []string{"canara"}},
+	{"Union Bank", []string{"union bank"}},
+	{"IDBI", []string{"idbi"}},
+	{"Indian Bank", []string{"indian bank"}},
+	{"RBI", []string{"rbi", "reserve bank"}},
+}
+
 // HasBankNames checks if text contains bank names
 func HasBankNames(text string) bool {
-	bankNames := []string{
-		"hdfc", "icici", "sbi", "axis", "kotak", "yes bank",
-		"pnb", "bank of baroda", "canara", "union bank",
-		"idbi", "indian bank", "rbi", "reserve bank",
-	}
+	return len(MentionedBanks(text)) > 0
+}
 
+// MentionedBanks returns the banks text names, each once
+func MentionedBanks(text string) []string {
 	lowerText := strings.ToLower(text)
-	for _, bank := range bankNames {
-		if strings.Contains(lowerText, bank) {
-			return true
+	var mentioned []string
+	for _, bank := range banks {
+		for _, keyword := range bank.keywords {
+			if strings.Contains(lowerText, keyword) {
+				mentioned = append(mentioned, bank.name)
+				break
+			}
 		}
 	}
-	return false
+	return mentioned
 }
 
 // CalculateSpecialCharRatio calculates the ratio of special characters
@@ -196,25 +221,9 @@ func SanitizeInput(input string) string {
 	return input
 }
 
-// MaskPII masks personally identifiable information
+// MaskPII masks personally identifiable information, keeping the last few
+// characters of each match
 func MaskPII(text string) string {
-	// Mask phone numbers
-	text = phoneRegex.ReplaceAllStringFunc(text, func(match string) string {
-		if len(match) > 4 {
-			return match[:2] + strings.Repeat("*", len(match)-4) + match[len(match)-2:]
-		}
-		return match
-	})
-
-	// Mask email addresses
-	text = emailRegex.ReplaceAllStringFunc(text, func(match string) string {
-		parts := strings.Split(match, "@")
-		if len(parts) == 2 && len(parts[0]) > 2 {
-			return parts[0][:2] + strings.Repeat("*", len(parts[0])-2) + "@" + parts[1]
-		}
-		return match
-	})
-
-	return text
+	return maskRedactor.String(text)
 }
 
@@ -133,3 +133,95 @@ CREATE INDEX IF NOT EXISTS idx_sender_registry_bank_name ON sender_registry(bank
 CREATE INDEX IF NOT EXISTS idx_sender_registry_is_verified ON sender_registry(is_verified);
 CREATE INDEX IF NOT EXISTS idx_sender_registry_is_active ON sender_registry(is_active);
 CREATE INDEX IF NOT EXISTS idx_sender_registry_reputation_score ON sender_registry(reputation_score);
+
+-- 007_create_password_reset_tokens.sql
+ALTER TABLE users ADD COLUMN IF NOT EXISTS password_changed_at TIMESTAMP;
+CREATE TABLE IF NOT EXISTS password_reset_tokens (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    token_hash VARCHAR(64) UNIQUE NOT NULL,
+    expires_at TIMESTAMP NOT NULL,
+    used_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX IF NOT EXISTS idx_password_reset_tokens_user_id ON password_reset_tokens(user_id);
+CREATE INDEX IF NOT EXISTS idx_password_reset_tokens_expires_at ON password_reset_tokens(expires_at);
+
+-- 008_add_user_roles_and_totp.sql
+ALTER TABLE users ADD COLUMN IF NOT EXISTS role VARCHAR(20) NOT NULL DEFAULT 'USER' CHECK (role IN ('USER', 'ANALYST', 'ADMIN'));
+ALTER TABLE users ADD COLUMN IF NOT EXISTS totp_secret VARCHAR(64);
+ALTER TABLE users ADD COLUMN IF NOT EXISTS totp_enabled BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS totp_enabled_at TIMESTAMP;
+CREATE INDEX IF NOT EXISTS idx_users_role ON users(role);
+CREATE TABLE IF NOT EXISTS user_recovery_codes (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    code_hash VARCHAR(64) NOT NULL,
+    used_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX IF NOT EXISTS idx_user_recovery_codes_user_id ON user_recovery_codes(user_id);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_user_recovery_codes_user_code ON user_recovery_codes(user_id, code_hash);
+
+-- 009_add_tenants_and_retention.sql
+ALTER TABLE messages ADD COLUMN IF NOT EXISTS tenant_id VARCHAR(64) NOT NULL DEFAULT 'default';
+ALTER TABLE messages ADD COLUMN IF NOT EXISTS content_hash VARCHAR(64);
+ALTER TABLE messages ADD COLUMN IF NOT EXISTS features JSONB;
+ALTER TABLE messages ADD COLUMN IF NOT EXISTS anonymized_at TIMESTAMP;
+ALTER TABLE verifications ADD COLUMN IF NOT EXISTS tenant_id VARCHAR(64) NOT NULL DEFAULT 'default';
+CREATE INDEX IF NOT EXISTS idx_messages_tenant_created_at ON messages(tenant_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_messages_content_hash ON messages(content_hash);
+CREATE INDEX IF NOT EXISTS idx_verifications_tenant_created_at ON verifications(tenant_id, created_at);
+CREATE TABLE IF NOT EXISTS retention_runs (
+    id UUID PRIMARY KEY,
+    started_at TIMESTAMP NOT NULL,
+    finished_at TIMESTAMP,
+    status VARCHAR(20) NOT NULL CHECK (status IN ('RUNNING', 'COMPLETED', 'FAILED')),
+    messages_deleted BIGINT NOT NULL DEFAULT 0,
+    messages_anonymized BIGINT NOT NULL DEFAULT 0,
+    verifications_deleted BIGINT NOT NULL DEFAULT 0,
+    reset_tokens_deleted BIGINT NOT NULL DEFAULT 0,
+    details JSONB,
+    error TEXT
+);
+CREATE INDEX IF NOT EXISTS idx_retention_runs_started_at ON retention_runs(started_at);
+
+-- 010_add_field_encryption.sql
+ALTER TABLE messages ALTER COLUMN phone_number TYPE TEXT;
+ALTER TABLE messages ADD COLUMN IF NOT EXISTS phone_number_hash VARCHAR(64);
+ALTER TABLE messages ADD COLUMN IF NOT EXISTS encryption_key_id VARCHAR(64);
+ALTER TABLE reports ADD COLUMN IF NOT EXISTS content_hash VARCHAR(64);
+ALTER TABLE reports ADD COLUMN IF NOT EXISTS encryption_key_id VARCHAR(64);
+CREATE INDEX IF NOT EXISTS idx_messages_phone_number_hash ON messages(phone_number_hash);
+CREATE INDEX IF NOT EXISTS idx_messages_encryption_key_id ON messages(encryption_key_id);
+CREATE INDEX IF NOT EXISTS idx_reports_content_hash ON reports(content_hash);
+CREATE INDEX IF NOT EXISTS idx_reports_encryption_key_id ON reports(encryption_key_id);
+
+-- 011_add_verification_degraded.sql
+ALTER TABLE verifications ADD COLUMN IF NOT EXISTS degraded BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- 012_add_verification_source.sql
+ALTER TABLE verifications ADD COLUMN IF NOT EXISTS source_verification_id UUID REFERENCES verifications(id) ON DELETE SET NULL;
+CREATE INDEX IF NOT EXISTS idx_verifications_source_verification_id ON verifications(source_verification_id);
+
+-- 013_add_verification_stage_timings.sql
+ALTER TABLE verifications ADD COLUMN IF NOT EXISTS stage_timings JSONB;
+
+-- 014_create_outbox.sql
+CREATE TABLE IF NOT EXISTS outbox (
+    id BIGSERIAL PRIMARY KEY,
+    kind VARCHAR(64) NOT NULL,
+    payload JSONB NOT NULL,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    last_error TEXT,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    processed_at TIMESTAMP
+);
+CREATE INDEX IF NOT EXISTS idx_outbox_pending ON outbox(id) WHERE processed_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_outbox_processed_at ON outbox(processed_at);
+
+-- 015_add_outbox_topic.sql
+ALTER TABLE outbox ADD COLUMN IF NOT EXISTS topic VARCHAR(255);
+ALTER TABLE outbox ADD COLUMN IF NOT EXISTS message_key VARCHAR(255);
+DROP INDEX IF EXISTS idx_outbox_pending;
+CREATE INDEX IF NOT EXISTS idx_outbox_kind_pending ON outbox(kind, id) WHERE processed_at IS NULL;
@@ -41,6 +41,11 @@ class Settings(BaseSettings):
     # Logging
     log_level: str = os.getenv("LOG_LEVEL", "INFO")
     
+    # Tracing: none, stdout or otlp
+    tracing_exporter: str = os.getenv("TRACING_EXPORTER", "none")
+    tracing_otlp_endpoint: str = os.getenv("TRACING_OTLP_ENDPOINT", "localhost:4318")
+    tracing_sample_ratio: float = float(os.getenv("TRACING_SAMPLE_RATIO", "1.0"))
+    
     class Config:
         env_file = ".env"
         case_sensitive = False
@@ -6,6 +6,7 @@ from fastapi.middleware.cors import CORSMiddleware
 from app.config import settings
 from app.api.routes import inference_router, health_router
 from app.utils.logger import log
+from app.utils.tracing import setup_tracing
 
 # Create FastAPI app
 app = FastAPI(
@@ -23,6 +24,9 @@ app.add_middleware(
     allow_headers=["*"],
 )
 
+# Continue traces started by the backend
+setup_tracing(app)
+
 # Include routers
 app.include_router(health_router, tags=["Health"])
 app.include_router(inference_router, tags=["Inference"])
@@ -0,0 +1,39 @@
+"""
+Tracing utilities
+"""
+from app.config import settings
+from app.utils.logger import log
+
+
+def setup_tracing(app):
+    """Export OpenTelemetry spans for each request, continuing the trace of
+    the backend call from its traceparent header"""
+    exporter_name = settings.tracing_exporter.lower()
+    if exporter_name == "none":
+        return
+
+    from opentelemetry import trace
+    from opentelemetry.instrumentation.fastapi import FastAPIInstrumentor
+    from opentelemetry.sdk.resources import Resource
+    from opentelemetry.sdk.trace import TracerProvider
+    from opentelemetry.sdk.trace.export import BatchSpanProcessor, ConsoleSpanExporter
+    from opentelemetry.sdk.trace.sampling import ParentBased, TraceIdRatioBased
+
+    if exporter_name == "stdout":
+        exporter = ConsoleSpanExporter()
+    elif exporter_name == "otlp":
+        from opentelemetry.exporter.otlp.proto.http.trace_exporter import OTLPSpanExporter
+
+        exporter = OTLPSpanExporter(endpoint=f"http://{settings.tracing_otlp_endpoint}/v1/traces")
+    else:
+        log.warning(f"Unknown tracing exporter {exporter_name}, tracing disabled")
+        return
+
+    provider = TracerProvider(
+        resource=Resource.create({"service.name": "ml-service"}),
+        sampler=ParentBased(TraceIdRatioBased(settings.tracing_sample_ratio)),
+    )
+    provider.add_span_processor(BatchSpanProcessor(exporter))
+    trace.set_tracer_provider(provider)
+    FastAPIInstrumentor.instrument_app(app, tracer_provider=provider)
+    log.info(f"Tracing enabled with {exporter_name} exporter")
@@ -1,12 +1,20 @@
 #!/bin/bash
 
 # Database migration script
-# Usage: ./scripts/migrate.sh [up|down|create]
+# Usage: ./scripts/migrate.sh [up|down [n]|status|baseline [version]|create <name>]
+#
+# Migrations are embedded in the service binaries and applied by their
+# "migrate" subcommand, which records them in schema_migrations.
 
 set -e
 
 COMMAND=${1:-up}
 MIGRATION_NAME=${2:-}
+MIGRATIONS_DIR=backend/internal/database/migrations
+
+migrate() {
+    docker-compose run --rm api-gateway ./app migrate "$@"
+}
 
 echo "📊 Database Migration Tool"
 echo ""
@@ -14,44 +22,43 @@ echo ""
 case $COMMAND in
     up)
         echo "⬆️  Running migrations..."
-        docker-compose exec -T postgres psql -U frauddetection -d frauddetection_db << EOF
-$(cat backend/internal/database/migrations/001_create_users.sql)
-$(cat backend/internal/database/migrations/002_create_messages.sql)
-$(cat backend/internal/database/migrations/003_create_verifications.sql)
-$(cat backend/internal/database/migrations/004_create_reports.sql)
-$(cat backend/internal/database/migrations/005_create_rbi_circulars.sql)
-$(cat backend/internal/database/migrations/006_create_sender_registry.sql)
-EOF
+        migrate up
         echo "✅ Migrations completed"
         ;;
-    
+
     down)
-        echo "⬇️  Rolling back last migration..."
-        echo "⚠️  This will drop tables!"
+        STEPS=${2:-1}
+        echo "⬇️  Rolling back last $STEPS migration(s)..."
+        echo "⚠️  This may drop tables!"
         read -p "Are you sure? (yes/no): " confirm
         if [ "$confirm" != "yes" ]; then
             echo "Rollback cancelled."
             exit 0
         fi
-        
-        # TODO: Implement proper rollback
-        echo "❌ Rollback not implemented"
-        exit 1
+
+        migrate down "$STEPS"
+        echo "✅ Rollback completed"
         ;;
-    
+
+    baseline)
+        echo "📌 Marking existing schema as migrated..."
+        migrate baseline ${2:-}
+        ;;
+
     create)
         if [ -z "$MIGRATION_NAME" ]; then
             echo "❌ Migration name required"
             echo "Usage: ./scripts/migrate.sh create migration_name"
             exit 1
         fi
-        
+
         # Get next migration number
-        LAST_NUM=$(ls backend/internal/database/migrations/*.sql 2>/dev/null | tail -1 | grep -o '[0-9]\+' | head -1)
+        LAST_NUM=$(ls $MIGRATIONS_DIR/*.sql 2>/dev/null | grep -v '\.down\.sql$' | tail -1 | xargs basename | grep -o '^[0-9]\+')
         NEXT_NUM=$(printf "%03d" $((10#$LAST_NUM + 1)))
-        
-        MIGRATION_FILE="backend/internal/database/migrations/${NEXT_NUM}_${MIGRATION_NAME}.sql"
-        
+
+        MIGRATION_FILE="$MIGRATIONS_DIR/${NEXT_NUM}_${MIGRATION_NAME}.sql"
+        DOWN_FILE="$MIGRATIONS_DIR/${NEXT_NUM}_${MIGRATION_NAME}.down.sql"
+
         cat > "$MIGRATION_FILE" << EOF
 -- Migration: $MIGRATION_NAME
 -- Created: $(date)
@@ -65,29 +72,35 @@ EOF
 --     created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
 -- );
 EOF
-        
+
+        cat > "$DOWN_FILE" << EOF
+-- Revert: $MIGRATION_NAME
+
+-- TODO: Undo the changes made by ${NEXT_NUM}_${MIGRATION_NAME}.sql
+EOF
+
         echo "✅ Created migration: $MIGRATION_FILE"
-        echo "📝 Edit the file and add your SQL"
+        echo "✅ Created rollback:  $DOWN_FILE"
+        echo "📝 Edit the files, then append the up SQL to database/migrations/all.sql"
         ;;
-    
+
     status)
         echo "📋 Migration Status:"
         echo ""
-        echo "Applied migrations:"
-        docker-compose exec -T postgres psql -U frauddetection -d frauddetection_db -c "\dt"
+        migrate status
         ;;
-    
+
     *)
         echo "❌ Unknown command: $COMMAND"
         echo ""
         echo "Usage: ./scripts/migrate.sh [command]"
         echo ""
         echo "Commands:"
-        echo "  up              - Run all migrations"
-        echo "  down            - Rollback last migration"
+        echo "  up              - Run all pending migrations"
+        echo "  down [n]        - Rollback the last n migrations (default 1)"
+        echo "  baseline [ver]  - Mark an existing schema as migrated up to ver"
         echo "  create <name>   - Create new migration"
         echo "  status          - Show migration status"
         exit 1
         ;;
 esac
-